@@ -3,34 +3,206 @@ package main
 
 import (
 	"gofinancialsystem/internal/api"
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/calendar"
+	"gofinancialsystem/internal/config"
+	"gofinancialsystem/internal/db"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/fees"
+	"gofinancialsystem/internal/fx"
+	"gofinancialsystem/internal/interest"
+	"gofinancialsystem/internal/limits"
 	"gofinancialsystem/internal/repository"
+	"gofinancialsystem/internal/schedule"
 	"gofinancialsystem/internal/service"
-	"net/http"
+	"log"
+	"time"
 )
 
 func main() {
-	// Repository ve servisleri başlat
-	userRepo := repository.NewUserRepository()
-	balanceRepo := repository.NewBalanceRepository()
-	transactionRepo := repository.NewTransactionRepository()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Config yüklenemedi: %v", err)
+	}
+
+	// Repository'leri başlat: DATABASE_URL verilmişse PostgreSQL, yoksa in-memory
+	var (
+		userRepo         domain.UserRepository
+		balanceRepo      domain.BalanceRepository
+		accountRepo      domain.AccountRepository
+		transactionRepo  domain.TransactionRepository
+		ledgerRepo       domain.LedgerRepository
+		holdRepo         domain.HoldRepository
+		interestRepo     domain.InterestRepository
+		interestRateRepo domain.InterestRateRepository
+		scheduleRepo     domain.ScheduleRepository
+		limitRepo        domain.LimitOverrideRepository
+		idempotencyRepo  domain.IdempotencyRepository
+		sessionRepo      domain.SessionRepository
+		delegationRepo   domain.DelegationRepository
+		unitOfWork       domain.UnitOfWork
+	)
+	if cfg.DBUrl != "" {
+		conn, err := db.Open(cfg.DBUrl)
+		if err != nil {
+			log.Fatalf("Veritabanına bağlanılamadı: %v", err)
+		}
+		defer conn.Close()
+		db.RunMigrations(cfg.DBUrl)
+
+		userRepo = repository.NewPostgresUserRepository(conn)
+		balanceRepo = repository.NewPostgresBalanceRepository(conn)
+		accountRepo = repository.NewPostgresAccountRepository(conn)
+		transactionRepo = repository.NewPostgresTransactionRepository(conn)
+		ledgerRepo = repository.NewPostgresLedgerRepository(conn)
+		holdRepo = repository.NewPostgresHoldRepository(conn)
+		interestRepo = repository.NewPostgresInterestRepository(conn)
+		interestRateRepo = repository.NewPostgresInterestRateRepository(conn)
+		scheduleRepo = repository.NewPostgresScheduleRepository(conn)
+		limitRepo = repository.NewPostgresLimitOverrideRepository(conn)
+		idempotencyRepo = repository.NewPostgresIdempotencyRepository(conn)
+		sessionRepo = repository.NewPostgresSessionRepository(conn)
+		delegationRepo = repository.NewPostgresDelegationRepository(conn)
+		unitOfWork = repository.NewPostgresUnitOfWork(conn)
+	} else {
+		memBalances := repository.NewBalanceRepository()
+		memTransactions := repository.NewTransactionRepository()
+		memLedger := repository.NewLedgerRepository()
+		memHolds := repository.NewHoldRepository()
+		memInterest := repository.NewInterestRepository()
+		userRepo = repository.NewUserRepository()
+		balanceRepo = memBalances
+		accountRepo = repository.NewAccountRepository()
+		transactionRepo = memTransactions
+		ledgerRepo = memLedger
+		holdRepo = memHolds
+		interestRepo = memInterest
+		interestRateRepo = repository.NewInterestRateRepository()
+		scheduleRepo = repository.NewScheduleRepository()
+		limitRepo = repository.NewLimitOverrideRepository()
+		idempotencyRepo = repository.NewIdempotencyRepository()
+		sessionRepo = repository.NewSessionRepository()
+		delegationRepo = repository.NewDelegationRepository()
+		unitOfWork = repository.NewMemoryUnitOfWork(memBalances, memTransactions, memLedger, memHolds, memInterest)
+	}
 
+	// İşlem limitleri: LIMITS_FILE verilmişse varsayılanlar dosyadan okunur
+	limitRules := limits.DefaultRules()
+	if cfg.LimitsFile != "" {
+		if limitRules, err = limits.LoadFile(cfg.LimitsFile); err != nil {
+			log.Fatalf("Limit dosyası yüklenemedi: %v", err)
+		}
+	}
+	limitEngine := limits.NewEngine(limitRules, limitRepo, userRepo, transactionRepo)
+
+	// Ücret tarifesi: FEES_FILE verilmişse açılışta dosyadan yüklenir
+	var feeRules []domain.FeeRule
+	if cfg.FeesFile != "" {
+		if feeRules, err = fees.LoadFile(cfg.FeesFile); err != nil {
+			log.Fatalf("Ücret dosyası yüklenemedi: %v", err)
+		}
+	}
+	feeEngine := fees.NewEngine(feeRules, transactionRepo)
+
+	// Faiz oranları: INTEREST_FILE verilmişse açılışta dosyadan yüklenir; yönetim API'siyle eklenen oranlar repository'de saklanır
+	var interestRates []domain.InterestRate
+	if cfg.InterestFile != "" {
+		if interestRates, err = interest.LoadFile(cfg.InterestFile); err != nil {
+			log.Fatalf("Faiz dosyası yüklenemedi: %v", err)
+		}
+	}
+
+	// İş günü takvimi: CALENDAR_FILE verilmişse tatiller ve kesim saatleri dosyadan okunur
+	businessCalendar := calendar.Default()
+	if cfg.CalendarFile != "" {
+		if businessCalendar, err = calendar.LoadFile(cfg.CalendarFile); err != nil {
+			log.Fatalf("Takvim dosyası yüklenemedi: %v", err)
+		}
+	}
+
+	// Servisleri başlat
 	userService := service.NewUserService(userRepo)
-	balanceService := service.NewBalanceService(balanceRepo)
-	transactionService := service.NewTransactionService(transactionRepo, balanceRepo)
+	accountService := service.NewAccountService(accountRepo, balanceRepo)
+	balanceService := service.NewBalanceService(balanceRepo, accountRepo, ledgerRepo, unitOfWork, businessCalendar)
+	transactionService := service.NewTransactionService(transactionRepo, accountRepo, unitOfWork, limitEngine, feeEngine, businessCalendar)
+	holdService := service.NewHoldService(holdRepo, accountRepo, unitOfWork, limitEngine, cfg.HoldTTL, businessCalendar)
+	interestService := service.NewInterestService(interestRates, interestRateRepo, accountRepo, balanceService, interestRepo, unitOfWork, businessCalendar)
+	scheduleService := schedule.NewService(scheduleRepo, accountRepo, transactionService,
+		domain.ScheduleRetryPolicy{MaxAttempts: cfg.ScheduleRetries, Interval: cfg.ScheduleRetry}, time.Now)
+
+	// Döviz kurları: FX_RATES_FILE verilmişse ilk sürüm dosyadan yüklenir
+	rateStore := fx.NewRateStore()
+	if cfg.FXRatesFile != "" {
+		table, err := fx.LoadFile(cfg.FXRatesFile, time.Now())
+		if err != nil {
+			log.Fatalf("Kur dosyası yüklenemedi: %v", err)
+		}
+		if err := rateStore.Publish(table); err != nil {
+			log.Fatalf("Kur tablosu yayınlanamadı: %v", err)
+		}
+	}
+	fxService := fx.NewService(rateStore, cfg.FXSpreadBps, cfg.FXQuoteTTL)
+
+	// JWT imza anahtarları: JWT_KEYS verilmemişse geliştirme için geçici anahtar üretilir
+	var keyRing *auth.KeyRing
+	if cfg.JWTKeys != "" {
+		keyRing, err = auth.ParseKeyRing(cfg.JWTKeys, cfg.JWTActiveKeyID)
+	} else {
+		log.Println("JWT_KEYS tanımlı değil, geçici imza anahtarı kullanılıyor")
+		keyRing, err = auth.NewEphemeralKeyRing()
+	}
+	if err != nil {
+		log.Fatalf("JWT anahtarları yüklenemedi: %v", err)
+	}
+	tokenService := auth.NewTokenService(keyRing, cfg.AccessTokenTTL, cfg.JWTIssuer)
+	sessionService := auth.NewSessionService(sessionRepo, userService, tokenService, cfg.RefreshTokenTTL)
+
+	// Rol -> yetki eşlemesi (ROLE_PERMISSIONS verilmemişse varsayılan)
+	rolePermissions := cfg.RolePermissions
+	if rolePermissions == "" {
+		rolePermissions = auth.DefaultRolePermissions
+	}
+	authorizer, err := auth.ParseRolePermissions(rolePermissions)
+	if err != nil {
+		log.Fatalf("Rol yetkileri yüklenemedi: %v", err)
+	}
+	access := &api.AccessControl{Users: userService, Permissions: authorizer}
+
+	// Kullanıcılar sadece kendi hesaplarına veya yetki devri aldıkları hesaplara erişebilir
+	guard := &api.OwnershipGuard{Delegations: delegationRepo}
 
 	// Handler'ları oluştur
-	authHandler := &api.AuthHandler{UserService: userService}
-	userHandler := &api.UserHandler{UserService: userService}
-	transactionHandler := &api.TransactionHandler{
-		TransactionService: transactionService,
-		BalanceService:     balanceService,
+	handlers := &api.Handlers{
+		Auth: &api.AuthHandler{UserService: userService, Sessions: sessionService},
+		User: &api.UserHandler{UserService: userService, Guard: guard},
+		Transaction: &api.TransactionHandler{
+			TransactionService: transactionService,
+			BalanceService:     balanceService,
+			Accounts:           accountService,
+			Guard:              guard,
+			FX:                 fxService,
+		},
+		Balance:        &api.BalanceHandler{BalanceService: balanceService, Accounts: accountService, Guard: guard},
+		Account:        &api.AccountHandler{Accounts: accountService, Guard: guard},
+		Hold:           &api.HoldHandler{Holds: holdService, Accounts: accountService, Guard: guard},
+		Delegation:     &api.DelegationHandler{Delegations: delegationRepo, UserService: userService},
+		FX:             &api.FXHandler{FX: fxService},
+		Limit:          &api.LimitHandler{Limits: limitEngine, Guard: guard},
+		Fee:            &api.FeeHandler{Fees: feeEngine, Accounts: accountService, Guard: guard},
+		Interest:       &api.InterestHandler{Interest: interestService, Accounts: accountService, Guard: guard},
+		Schedule:       &api.ScheduleHandler{Schedules: scheduleService, Accounts: accountService, Guard: guard},
+		Authenticated:  api.AuthMiddleware(sessionService),
+		Access:         access,
+		Idempotency:    idempotencyRepo,
+		IdempotencyTTL: cfg.IdempotencyTTL,
 	}
-	balanceHandler := &api.BalanceHandler{BalanceService: balanceService}
 
 	// Router oluştur
 	router := api.NewRouter()
 
 	// Middleware'leri ekle (sıralama önemli)
+	router.Use(api.RequestIDMiddleware)
+	router.Use(api.LocaleMiddleware)
 	router.Use(api.ErrorHandlingMiddleware)
 	router.Use(api.PerformanceMonitoringMiddleware)
 	router.Use(api.LoggingMiddleware)
@@ -40,34 +212,62 @@ func main() {
 	router.Use(api.ValidationMiddleware)
 	router.Use(api.RequestSizeMiddleware(1024 * 1024)) // 1MB limit
 
-	// Health endpoint
-	router.Handle("GET", "/api/v1/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
-
-	// Auth endpointleri (auth middleware yok)
-	router.Handle("POST", "/api/v1/auth/register", authHandler.Register)
-	router.Handle("POST", "/api/v1/auth/login", authHandler.Login)
-
-	// User Management endpointleri (auth gerekli)
-	router.Handle("GET", "/api/v1/users", api.AuthMiddleware(userHandler.ListUsers))
-	router.Handle("GET", "/api/v1/users/get", api.AuthMiddleware(userHandler.GetUser))
-	router.Handle("PUT", "/api/v1/users/update", api.AuthMiddleware(userHandler.UpdateUser))
-	router.Handle("DELETE", "/api/v1/users/delete", api.AdminOnlyMiddleware(userHandler.DeleteUser))
-
-	// Transaction endpointleri (auth gerekli)
-	router.Handle("POST", "/api/v1/transactions/credit", api.AuthMiddleware(transactionHandler.Credit))
-	router.Handle("POST", "/api/v1/transactions/debit", api.AuthMiddleware(transactionHandler.Debit))
-	router.Handle("POST", "/api/v1/transactions/transfer", api.AuthMiddleware(transactionHandler.Transfer))
-	router.Handle("GET", "/api/v1/transactions/history", api.AuthMiddleware(transactionHandler.GetHistory))
-	router.Handle("GET", "/api/v1/transactions/get", api.AuthMiddleware(transactionHandler.GetTransaction))
-
-	// Balance endpointleri (auth gerekli)
-	router.Handle("GET", "/api/v1/balances/current", api.AuthMiddleware(balanceHandler.GetCurrentBalance))
-	router.Handle("GET", "/api/v1/balances/historical", api.AuthMiddleware(balanceHandler.GetBalanceHistory))
-	router.Handle("GET", "/api/v1/balances/at-time", api.AuthMiddleware(balanceHandler.GetBalanceAtTime))
-	router.Handle("GET", "/api/v1/balances/calculate", api.AuthMiddleware(balanceHandler.CalculateBalance))
+	api.RegisterRoutes(router, handlers)
+
+	// Süresi dolan idempotency anahtarlarını periyodik olarak temizle
+	go func() {
+		for now := range time.Tick(time.Hour) {
+			idempotencyRepo.DeleteExpired(now)
+		}
+	}()
+
+	// Süresi dolan kur tekliflerini temizle
+	go func() {
+		for now := range time.Tick(time.Minute) {
+			fxService.DeleteExpired(now)
+		}
+	}()
+
+	// Süresi dolan provizyonları serbest bırak
+	go func() {
+		for now := range time.Tick(time.Minute) {
+			if n, err := holdService.ExpireDue(now); err != nil {
+				log.Printf("Provizyon süre aşımı hatası: %v", err)
+			} else if n > 0 {
+				log.Printf("%d provizyonun süresi doldu", n)
+			}
+		}
+	}()
+
+	// Günlük faizi tahakkuk ettir, kapanan ayların tahakkuklarını bakiyelere ekle
+	go func() {
+		for now := range time.Tick(time.Hour) {
+			if n, err := interestService.AccrueDue(now); err != nil {
+				log.Printf("Faiz tahakkuk hatası: %v", err)
+			} else if n > 0 {
+				log.Printf("%d günlük faiz tahakkuku yapıldı", n)
+			}
+			if n, err := interestService.PostDue(now); err != nil {
+				log.Printf("Faiz aktarım hatası: %v", err)
+			} else if n > 0 {
+				log.Printf("%d hesaba faiz aktarıldı", n)
+			}
+		}
+	}()
+
+	// Zamanı gelen transfer talimatlarını çalıştır
+	go func() {
+		for now := range time.Tick(time.Minute) {
+			// Bir talimatın hatası diğerlerini durdurmaz; çalışanlar hata olsa da raporlanır
+			n, err := scheduleService.RunDue(now)
+			if err != nil {
+				log.Printf("Talimat çalıştırma hatası: %v", err)
+			}
+			if n > 0 {
+				log.Printf("%d talimat çalıştırıldı", n)
+			}
+		}
+	}()
 
 	// Sunucuyu başlat
 	api.StartServer(":8080", router)
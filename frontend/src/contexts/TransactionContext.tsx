@@ -13,8 +13,11 @@ interface Transaction {
 }
 
 interface Balance {
+  account_id: number;
   user_id: number;
-  amount: number;
+  ledger: number;
+  held: number;
+  available: number;
   last_updated_at: string;
 }
 
@@ -53,7 +56,7 @@ export const TransactionProvider: React.FC<TransactionProviderProps> = ({ childr
     try {
       setLoading(true);
       const response = await api.get(`/api/v1/transactions/history?user_id=${user.id}`);
-      setTransactions(response.data || []);
+      setTransactions(response.data?.transactions || []);
     } catch (error) {
       console.error('Error fetching transactions:', error);
     } finally {
@@ -66,8 +69,9 @@ export const TransactionProvider: React.FC<TransactionProviderProps> = ({ childr
     
     try {
       setLoading(true);
-      const response = await api.get(`/api/v1/balances/current?user_id=${user.id}`);
-      setBalance(response.data);
+      const response = await api.get(`/api/v1/balances/current?user_id=${user.id}&currency=TRY`);
+      // Sunucu cüzdan listesi döner; arayüz varsayılan (TRY) cüzdanı gösterir
+      setBalance(response.data?.[0] ?? null);
     } catch (error) {
       console.error('Error fetching balance:', error);
     } finally {
@@ -57,7 +57,7 @@ const Admin: React.FC = () => {
       const response = await api.get('/api/v1/users');
       setUsers(response.data || []);
     } catch (error: any) {
-      setError(error.response?.data || 'Kullanıcılar yüklenirken bir hata oluştu');
+      setError(error.response?.data?.message || 'Kullanıcılar yüklenirken bir hata oluştu');
     } finally {
       setLoading(false);
     }
@@ -67,12 +67,12 @@ const Admin: React.FC = () => {
     if (!selectedUser) return;
 
     try {
-      await api.delete(`/api/v1/users/delete?id=${selectedUser.id}`);
+      await api.delete(`/api/v1/users/${selectedUser.id}`);
       setUsers(users.filter(u => u.id !== selectedUser.id));
       setDeleteDialogOpen(false);
       setSelectedUser(null);
     } catch (error: any) {
-      setError(error.response?.data || 'Kullanıcı silinirken bir hata oluştu');
+      setError(error.response?.data?.message || 'Kullanıcı silinirken bir hata oluştu');
     }
   };
 
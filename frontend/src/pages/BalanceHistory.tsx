@@ -50,7 +50,7 @@ const BalanceHistory: React.FC = () => {
       const response = await api.get(`/api/v1/balances/historical?user_id=${user.id}`);
       setHistory(response.data || []);
     } catch (error: any) {
-      setError(error.response?.data || 'Bakiye geçmişi yüklenirken bir hata oluştu');
+      setError(error.response?.data?.message || 'Bakiye geçmişi yüklenirken bir hata oluştu');
     } finally {
       setLoading(false);
     }
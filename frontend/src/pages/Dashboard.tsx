@@ -97,7 +97,7 @@ const Dashboard: React.FC = () => {
       await performTransaction(transactionType, data);
       handleCloseDialog();
     } catch (error: any) {
-      setError(error.response?.data || 'İşlem sırasında bir hata oluştu');
+      setError(error.response?.data?.message || 'İşlem sırasında bir hata oluştu');
     } finally {
       setTransactionLoading(false);
     }
@@ -172,7 +172,7 @@ const Dashboard: React.FC = () => {
                 </Tooltip>
               </Box>
               <Typography variant="h4" component="div" color="primary" gutterBottom>
-                {balance ? formatCurrency(balance.amount) : formatCurrency(0)}
+                {balance ? formatCurrency(balance.available) : formatCurrency(0)}
               </Typography>
               <Typography variant="body2" color="text.secondary">
                 Son güncelleme: {balance ? formatDate(balance.last_updated_at) : 'Bilgi yok'}
@@ -81,7 +81,7 @@ const Login: React.FC = () => {
       await login(loginData.username, loginData.password);
       navigate('/dashboard');
     } catch (error: any) {
-      setError(error.response?.data || 'Giriş yapılırken bir hata oluştu');
+      setError(error.response?.data?.message || 'Giriş yapılırken bir hata oluştu');
     } finally {
       setLoading(false);
     }
@@ -98,7 +98,7 @@ const Login: React.FC = () => {
       setTabValue(0);
       setRegisterData({ username: '', email: '', password: '' });
     } catch (error: any) {
-      setError(error.response?.data || 'Kayıt olurken bir hata oluştu');
+      setError(error.response?.data?.message || 'Kayıt olurken bir hata oluştu');
     } finally {
       setLoading(false);
     }
@@ -46,7 +46,7 @@ const Profile: React.FC = () => {
       setSuccess('Profil başarıyla güncellendi!');
       setIsEditing(false);
     } catch (error: any) {
-      setError(error.response?.data || 'Profil güncellenirken bir hata oluştu');
+      setError(error.response?.data?.message || 'Profil güncellenirken bir hata oluştu');
     } finally {
       setLoading(false);
     }
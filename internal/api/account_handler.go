@@ -0,0 +1,207 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/domain"
+	"net/http"
+	"strconv"
+)
+
+// AccountHandler, kullanıcı hesaplarının açılması, güncellenmesi ve kapatılması işlemlerini yönetir
+type AccountHandler struct {
+	Accounts domain.AccountService
+	Guard    *OwnershipGuard
+}
+
+// Yeni hesap açar (POST /api/v1/accounts)
+// owner_id verilmezse hesap principal adına açılır.
+func (h *AccountHandler) Create(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthorized)
+		return
+	}
+
+	var req struct {
+		OwnerID  int64              `json:"owner_id"`
+		Type     domain.AccountType `json:"type"`
+		Currency string             `json:"currency"`
+		Nickname string             `json:"nickname"`
+		ParentID *int64             `json:"parent_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, decodeError(err))
+		return
+	}
+	if req.OwnerID == 0 {
+		req.OwnerID = principal.UserID
+	}
+	if !h.Guard.authorize(w, r, domain.DelegationWrite, auth.PermAccountsWriteAny, req.OwnerID) {
+		return
+	}
+
+	account := &domain.Account{
+		OwnerID:  req.OwnerID,
+		ParentID: req.ParentID,
+		Type:     req.Type,
+		Currency: req.Currency,
+		Nickname: req.Nickname,
+	}
+	if err := h.Accounts.Open(account); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(account)
+}
+
+// Kullanıcının hesaplarını listeler (GET /api/v1/accounts?user_id=)
+// user_id verilmezse principal'ın hesapları döner.
+func (h *AccountHandler) List(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthorized)
+		return
+	}
+
+	ownerID := principal.UserID
+	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
+		id, err := strconv.ParseInt(userIDStr, 10, 64)
+		if err != nil {
+			writeError(w, r, errInvalidRequest.WithMessage("invalid_user_id", "geçersiz kullanıcı ID"))
+			return
+		}
+		ownerID = id
+	}
+	if !h.Guard.authorize(w, r, domain.DelegationRead, auth.PermAccountsReadAny, ownerID) {
+		return
+	}
+
+	accounts, err := h.Accounts.ListByOwner(ownerID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if accounts == nil {
+		accounts = []*domain.Account{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(accounts)
+}
+
+// Belirli bir hesabı getirir (GET /api/v1/accounts/{id})
+func (h *AccountHandler) Get(w http.ResponseWriter, r *http.Request) {
+	account, ok := h.accountFromPath(w, r, domain.DelegationRead, auth.PermAccountsReadAny)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(account)
+}
+
+// Hesabın adını veya durumunu (active/frozen) günceller (PUT /api/v1/accounts/{id})
+func (h *AccountHandler) Update(w http.ResponseWriter, r *http.Request) {
+	account, ok := h.accountFromPath(w, r, domain.DelegationWrite, auth.PermAccountsWriteAny)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Nickname *string               `json:"nickname"`
+		Status   *domain.AccountStatus `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, decodeError(err))
+		return
+	}
+
+	updated, err := h.Accounts.Update(account.ID, req.Nickname, req.Status)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(updated)
+}
+
+// Bakiyesi sıfır olan hesabı kapatır (DELETE /api/v1/accounts/{id})
+func (h *AccountHandler) Close(w http.ResponseWriter, r *http.Request) {
+	account, ok := h.accountFromPath(w, r, domain.DelegationWrite, auth.PermAccountsWriteAny)
+	if !ok {
+		return
+	}
+
+	closed, err := h.Accounts.Close(account.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(closed)
+}
+
+func (h *AccountHandler) accountFromPath(w http.ResponseWriter, r *http.Request, scope domain.DelegationScope, anyPerm auth.Permission) (*domain.Account, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, r, errInvalidRequest.WithMessage("invalid_account_id", "geçersiz hesap ID"))
+		return nil, false
+	}
+	return resolveAccount(w, r, h.Accounts, h.Guard, accountRef{AccountID: id}, false, scope, anyPerm)
+}
+
+// accountRef, isteklerde işlem yapılacak hesabı belirtir. AccountID verilirse o hesap kullanılır;
+// verilmezse UserID'nin Currency para birimindeki varsayılan hesabı kullanılır (hesap ID'si
+// göndermeyen eski istemcilerle uyumluluk için).
+type accountRef struct {
+	AccountID int64
+	UserID    int64
+	Currency  string
+}
+
+// Hesap referansını hesaba çevirir; create true ise varsayılan hesap yoksa açılır
+func lookupAccount(accounts domain.AccountService, ref accountRef, create bool) (*domain.Account, error) {
+	if ref.AccountID != 0 {
+		return accounts.GetByID(ref.AccountID)
+	}
+	if ref.UserID == 0 {
+		return nil, errInvalidRequest.WithMessage("account_required", "hesap ID veya kullanıcı ID gerekli")
+	}
+	account, err := accounts.DefaultAccount(ref.UserID, ref.Currency, create)
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		currency := domain.NormalizeCurrency(ref.Currency)
+		return nil, domain.ErrAccountNotFound.WithMessage("no_default_account", "kullanıcının bu para biriminde varsayılan hesabı yok: "+currency).
+			WithParams(map[string]interface{}{"currency": currency})
+	}
+	return account, err
+}
+
+// Hesap referansını çözer ve principal'ın hesap sahibine scope kapsamında erişimini kontrol eder.
+// Varsayılan hesap açılacaksa sahiplik kontrolü hesap açılmadan önce yapılır.
+// Hata durumunda cevabı yazar ve false döner; handler'lar bu durumda hemen dönmelidir.
+func resolveAccount(w http.ResponseWriter, r *http.Request, accounts domain.AccountService, guard *OwnershipGuard,
+	ref accountRef, create bool, scope domain.DelegationScope, anyPerm auth.Permission) (*domain.Account, bool) {
+	if ref.AccountID == 0 && ref.UserID != 0 && !guard.authorize(w, r, scope, anyPerm, ref.UserID) {
+		return nil, false
+	}
+	account, err := lookupAccount(accounts, ref, create)
+	if err != nil {
+		writeError(w, r, err)
+		return nil, false
+	}
+	if ref.AccountID != 0 && !guard.authorize(w, r, scope, anyPerm, account.OwnerID) {
+		return nil, false
+	}
+	return account, true
+}
@@ -2,14 +2,16 @@ package api
 
 import (
 	"encoding/json"
-	"fmt"
+	"gofinancialsystem/internal/auth"
 	"gofinancialsystem/internal/domain"
 	"net/http"
+	"time"
 )
 
 // AuthHandler, auth işlemleri için servisleri tutar
 type AuthHandler struct {
 	UserService domain.UserService
+	Sessions    *auth.SessionService
 }
 
 // Kullanıcı kaydı endpoint'i (POST /api/v1/auth/register)
@@ -18,10 +20,15 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		Username string `json:"username"`
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		Locale   string `json:"locale"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Geçersiz istek"))
+		writeError(w, r, decodeError(err))
+		return
+	}
+	locale, err := supportedLocale(req.Locale)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 	user := &domain.User{
@@ -29,20 +36,21 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		Email:    req.Email,
 		Password: req.Password,
 		Role:     "user",
+		Locale:   locale,
 	}
 	if err := h.UserService.Register(user); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Kayıt başarısız: " + err.Error()))
+		writeError(w, r, err)
 		return
 	}
 
 	response := map[string]interface{}{
-		"message": "Kayıt başarılı",
+		"message": localize(r, "user.registered", nil),
 		"user": map[string]interface{}{
 			"id":       user.ID,
 			"username": user.Username,
 			"email":    user.Email,
 			"role":     user.Role,
+			"locale":   user.Locale,
 		},
 	}
 
@@ -58,27 +66,34 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		Password string `json:"password"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Geçersiz istek"))
+		writeError(w, r, decodeError(err))
 		return
 	}
 	user, err := h.UserService.Authenticate(req.Username, req.Password)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Giriş başarısız: " + err.Error()))
+		writeError(w, r, err)
 		return
 	}
 
-	// Generate a simple token (in production, use JWT)
-	token := fmt.Sprintf("token_%s_%d", user.Username, user.ID)
+	// Yeni oturum aç: imzalı JWT access token + tek kullanımlık refresh token
+	tokens, err := h.Sessions.Login(user)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
 	response := map[string]interface{}{
-		"token": token,
+		"token":              tokens.AccessToken,
+		"token_type":         tokens.TokenType,
+		"expires_at":         tokens.AccessExpiresAt.Format(time.RFC3339),
+		"refresh_token":      tokens.RefreshToken,
+		"refresh_expires_at": tokens.RefreshExpiresAt.Format(time.RFC3339),
 		"user": map[string]interface{}{
 			"id":       user.ID,
 			"username": user.Username,
 			"email":    user.Email,
 			"role":     user.Role,
+			"locale":   user.Locale,
 		},
 	}
 
@@ -86,3 +101,56 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+// Access token yenileme endpoint'i (POST /api/v1/auth/refresh)
+// Her kullanımda yeni bir refresh token döner; eskisi bir daha kullanılamaz
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		writeError(w, r, errInvalidRequest)
+		return
+	}
+	tokens, err := h.Sessions.Refresh(req.RefreshToken)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tokens)
+}
+
+// Çıkış endpoint'i (POST /api/v1/auth/logout)
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		writeError(w, r, errInvalidRequest)
+		return
+	}
+	if err := h.Sessions.Logout(req.RefreshToken); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Kullanıcının tüm oturumlarını kapatır (POST /api/v1/admin/sessions/revoke)
+func (h *AuthHandler) RevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID int64 `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID == 0 {
+		writeError(w, r, errInvalidRequest)
+		return
+	}
+	if err := h.Sessions.RevokeAll(req.UserID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
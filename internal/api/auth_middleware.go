@@ -1,131 +1,126 @@
 package api
 
 import (
-	"context"
-	"fmt"
+	"gofinancialsystem/internal/auth"
 	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/i18n"
 	"net/http"
 	"strings"
 )
 
-// AuthMiddleware, JWT token kontrolü yapar
-func AuthMiddleware(next HandlerFunc) HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// Authorization header'ını kontrol et
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte("Authorization header gerekli"))
-			return
-		}
-
-		// Bearer token formatını kontrol et
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte("Geçersiz token formatı"))
-			return
-		}
-
-		// Token'ı çıkar
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token == "" {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte("Token boş olamaz"))
-			return
-		}
-
-		// Token'ı doğrula (basit implementasyon - gerçek JWT validation yapılmalı)
-		userID, err := validateToken(token)
-		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte("Geçersiz token"))
-			return
-		}
-
-		// User ID'yi context'e ekle
-		ctx := context.WithValue(r.Context(), "user_id", userID)
-		next(w, r.WithContext(ctx))
-	}
+// TokenVerifier, access token'ı doğrulayıp kimliği döndüren bileşendir
+// (auth.TokenService ya da oturum kontrolü de yapan auth.SessionService)
+type TokenVerifier interface {
+	Verify(token string) (*auth.Principal, error)
 }
 
-// RoleMiddleware, belirli roller için erişim kontrolü yapar
-func RoleMiddleware(requiredRoles ...string) func(HandlerFunc) HandlerFunc {
+// AuthMiddleware, Bearer JWT'nin imzasını ve süresini doğrular, kimliği context'e ekler
+func AuthMiddleware(tokens TokenVerifier) func(HandlerFunc) HandlerFunc {
 	return func(next HandlerFunc) HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
-			// User ID'yi context'ten al
-			userID, ok := r.Context().Value("user_id").(int64)
-			if !ok {
-				w.WriteHeader(http.StatusUnauthorized)
-				w.Write([]byte("Kullanıcı kimliği bulunamadı"))
+			// Authorization header'ını kontrol et
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				writeError(w, r, errUnauthorized.WithMessage("missing_authorization", "Authorization header gerekli"))
 				return
 			}
 
-			// User'ı getir (gerçek implementasyonda database'den alınmalı)
-			user, err := getUserByID(userID)
-			if err != nil {
-				w.WriteHeader(http.StatusUnauthorized)
-				w.Write([]byte("Kullanıcı bulunamadı"))
+			// Bearer token formatını kontrol et
+			if !strings.HasPrefix(authHeader, "Bearer ") {
+				writeError(w, r, auth.ErrInvalidToken.WithMessage("malformed", "geçersiz token formatı"))
 				return
 			}
 
-			// Role kontrolü
-			hasRequiredRole := false
-			for _, requiredRole := range requiredRoles {
-				if user.Role == requiredRole {
-					hasRequiredRole = true
-					break
-				}
+			// Token'ı çıkar
+			token := strings.TrimPrefix(authHeader, "Bearer ")
+			if token == "" {
+				writeError(w, r, auth.ErrInvalidToken.WithMessage("empty", "token boş olamaz"))
+				return
 			}
 
-			if !hasRequiredRole {
-				w.WriteHeader(http.StatusForbidden)
-				w.Write([]byte("Bu işlem için yetkiniz yok"))
+			// İmza, algoritma, süre ve oturum kontrolü
+			principal, err := tokens.Verify(token)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				if _, ok := domain.AsError(err); !ok {
+					err = auth.ErrInvalidToken
+				}
+				writeError(w, r, err)
 				return
 			}
 
-			next(w, r)
+			// Principal'ı context'e ekle
+			next(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
 		}
 	}
 }
 
-// AdminOnlyMiddleware, sadece admin kullanıcılar için
-func AdminOnlyMiddleware(next HandlerFunc) HandlerFunc {
-	return RoleMiddleware("admin")(next)
-}
-
-// UserOrAdminMiddleware, user veya admin kullanıcılar için
-func UserOrAdminMiddleware(next HandlerFunc) HandlerFunc {
-	return RoleMiddleware("user", "admin")(next)
+// AccessControl, principal'ı UserService üzerinden güncel kullanıcı kaydına çözümler
+// ve rol/yetki kontrollerini yapar
+type AccessControl struct {
+	Users       domain.UserService
+	Permissions *auth.Authorizer
 }
 
-// Basit token validation (gerçek implementasyonda JWT kullanılmalı)
-func validateToken(token string) (int64, error) {
-	// Token formatı: "token_username_userID"
-	// Örnek: "token_testuser_2"
-
-	// Token'ı parçalara ayır
-	parts := strings.Split(token, "_")
-	if len(parts) != 3 {
-		return 0, fmt.Errorf("geçersiz token formatı")
+// Principal'ın rolünü veritabanındaki kullanıcı kaydından çözer; token'daki rol bayat olabilir.
+// Kullanıcının kayıtlı dil tercihi varsa dönen istek o dille devam eder.
+func (ac *AccessControl) resolve(w http.ResponseWriter, r *http.Request) (*auth.Principal, *http.Request, bool) {
+	// Principal'ı context'ten al
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthorized)
+		return nil, r, false
 	}
 
-	// Son parçayı user ID olarak al
-	userIDStr := parts[2]
-	var userID int64
-	_, err := fmt.Sscanf(userIDStr, "%d", &userID)
+	user, err := ac.Users.GetByID(principal.UserID)
 	if err != nil {
-		return 0, fmt.Errorf("geçersiz user ID")
+		writeError(w, r, errUnauthorized.WithMessage("user_not_found", "kullanıcı bulunamadı"))
+		return nil, r, false
+	}
+	principal.Resolve(user.Role, ac.Permissions)
+	if locale, ok := i18n.DefaultCatalog().Match(user.Locale); ok {
+		r = withLocale(w, r, locale)
 	}
+	return principal, r, true
+}
 
-	return userID, nil
+// RoleMiddleware, belirli roller için erişim kontrolü yapar
+func (ac *AccessControl) RoleMiddleware(requiredRoles ...string) func(HandlerFunc) HandlerFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			principal, r, ok := ac.resolve(w, r)
+			if !ok {
+				return
+			}
+
+			// Role kontrolü
+			for _, requiredRole := range requiredRoles {
+				if principal.Role == requiredRole {
+					next(w, r)
+					return
+				}
+			}
+			writeError(w, r, errForbidden)
+		}
+	}
 }
 
-// Basit user lookup (gerçek implementasyonda database'den alınmalı)
-func getUserByID(userID int64) (*domain.User, error) {
-	// Bu basit implementasyon - gerçek database lookup yapılmalı
-	return &domain.User{
-		ID:       userID,
-		Username: "user",
-		Role:     "user", // Varsayılan role
-	}, nil
+// Require, principal'ı çözümler ve verilen yetkilerin tümüne sahip olmasını şart koşar.
+// Handler'lar çözümlenmiş principal üzerinden ek yetki kontrolü yapabilir.
+func (ac *AccessControl) Require(perms ...auth.Permission) func(HandlerFunc) HandlerFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			principal, r, ok := ac.resolve(w, r)
+			if !ok {
+				return
+			}
+			for _, perm := range perms {
+				if !principal.Can(perm) {
+					writeError(w, r, errForbidden.WithDetails(map[string]interface{}{"permission": perm}))
+					return
+				}
+			}
+			next(w, r)
+		}
+	}
 }
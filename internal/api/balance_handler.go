@@ -2,65 +2,132 @@ package api
 
 import (
 	"encoding/json"
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/domain"
 	"net/http"
 	"strconv"
 	"time"
-	"gofinancialsystem/internal/domain"
 )
 
 // BalanceHandler, balance işlemleri için servisleri tutar
 type BalanceHandler struct {
 	BalanceService domain.BalanceService
+	Accounts       domain.AccountService
+	Guard          *OwnershipGuard
 }
 
-// Mevcut bakiye (GET /api/v1/balances/current)
-func (h *BalanceHandler) GetCurrentBalance(w http.ResponseWriter, r *http.Request) {
-	userIDStr := r.URL.Query().Get("user_id")
-	if userIDStr == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Kullanıcı ID gerekli"))
-		return
+// İsteğe bağlı currency sorgu parametresini doğrular; boş değer tüm cüzdanlar demektir
+func currencyFilter(r *http.Request) (string, error) {
+	currency := r.URL.Query().Get("currency")
+	if currency == "" {
+		return "", nil
 	}
+	return domain.ValidateCurrency(currency)
+}
+
+// account_id veya user_id (+ currency) sorgu parametrelerinden hesap referansı okur
+func accountRefFromQuery(r *http.Request) (accountRef, error) {
+	var ref accountRef
+	query := r.URL.Query()
 	
-	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if accountIDStr := query.Get("account_id"); accountIDStr != "" {
+		id, err := strconv.ParseInt(accountIDStr, 10, 64)
+		if err != nil {
+			return ref, errInvalidRequest.WithMessage("invalid_account_id", "geçersiz hesap ID")
+		}
+		ref.AccountID = id
+	}
+	if userIDStr := query.Get("user_id"); userIDStr != "" {
+		id, err := strconv.ParseInt(userIDStr, 10, 64)
+		if err != nil {
+			return ref, errInvalidRequest.WithMessage("invalid_user_id", "geçersiz kullanıcı ID")
+		}
+		ref.UserID = id
+	}
+	if ref.AccountID == 0 && ref.UserID == 0 {
+		return ref, errInvalidRequest.WithMessage("account_required", "hesap ID veya kullanıcı ID gerekli")
+	}
+	
+	currency, err := currencyFilter(r)
+	if err != nil {
+		return ref, err
+	}
+	ref.Currency = currency
+	return ref, nil
+}
+
+// Tek hesap gerektiren uçlar için hesabı çözer; kullanıcıya göre çözümde
+// currency verilmezse varsayılan para birimindeki hesap kullanılır.
+func (h *BalanceHandler) singleAccount(w http.ResponseWriter, r *http.Request, ref accountRef) (*domain.Account, bool) {
+	if ref.AccountID == 0 && ref.Currency == "" {
+		ref.Currency = domain.DefaultCurrency
+	}
+	return resolveAccount(w, r, h.Accounts, h.Guard, ref, false, domain.DelegationRead, auth.PermBalancesReadAny)
+}
+
+// Mevcut bakiyeler (GET /api/v1/balances/current)
+// account_id verilirse o hesabın bakiyesi, user_id verilirse kullanıcının tüm
+// hesaplarının bakiyeleri döner; currency verilirse sadece o para birimindekiler döner.
+func (h *BalanceHandler) GetCurrentBalance(w http.ResponseWriter, r *http.Request) {
+	ref, err := accountRefFromQuery(r)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Geçersiz kullanıcı ID"))
+		writeError(w, r, err)
 		return
 	}
 	
-	balance, err := h.BalanceService.GetBalance(userID)
+	var balances []*domain.Balance
+	if ref.AccountID != 0 {
+		account, ok := h.singleAccount(w, r, ref)
+		if !ok {
+			return
+		}
+		var balance *domain.Balance
+		balance, err = h.BalanceService.GetBalance(account.ID)
+		balances = []*domain.Balance{balance}
+	} else {
+		if !h.Guard.authorize(w, r, domain.DelegationRead, auth.PermBalancesReadAny, ref.UserID) {
+			return
+		}
+		balances, err = h.BalanceService.ListBalances(ref.UserID)
+		if err == nil && ref.Currency != "" {
+			balances = filterByCurrency(balances, ref.Currency)
+		}
+	}
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte("Bakiye bulunamadı"))
+		writeError(w, r, err)
 		return
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(balance)
+	json.NewEncoder(w).Encode(balances)
+}
+
+func filterByCurrency(balances []*domain.Balance, currency string) []*domain.Balance {
+	filtered := make([]*domain.Balance, 0, len(balances))
+	for _, balance := range balances {
+		if balance.Amount.Currency == currency {
+			filtered = append(filtered, balance)
+		}
+	}
+	return filtered
 }
 
 // Bakiye geçmişi (GET /api/v1/balances/historical)
 func (h *BalanceHandler) GetBalanceHistory(w http.ResponseWriter, r *http.Request) {
-	userIDStr := r.URL.Query().Get("user_id")
-	if userIDStr == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Kullanıcı ID gerekli"))
+	ref, err := accountRefFromQuery(r)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
-	
-	userID, err := strconv.ParseInt(userIDStr, 10, 64)
-	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Geçersiz kullanıcı ID"))
+	account, ok := h.singleAccount(w, r, ref)
+	if !ok {
 		return
 	}
 	
-	history, err := h.BalanceService.GetBalanceHistory(userID)
+	history, err := h.BalanceService.GetBalanceHistory(account.ID)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("Bakiye geçmişi alınamadı"))
+		writeError(w, r, err)
 		return
 	}
 	
@@ -70,35 +137,35 @@ func (h *BalanceHandler) GetBalanceHistory(w http.ResponseWriter, r *http.Reques
 }
 
 // Belirli zamandaki bakiye (GET /api/v1/balances/at-time)
+// Kullanıcıya göre sorgulanırsa ve currency verilmezse varsayılan para birimindeki hesap kullanılır.
+// Bakiyeye sadece valör tarihi timestamp günü veya öncesi olan işlemler dahildir.
 func (h *BalanceHandler) GetBalanceAtTime(w http.ResponseWriter, r *http.Request) {
-	userIDStr := r.URL.Query().Get("user_id")
 	timestampStr := r.URL.Query().Get("timestamp")
-	
-	if userIDStr == "" || timestampStr == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Kullanıcı ID ve timestamp gerekli"))
+	if timestampStr == "" {
+		writeError(w, r, errInvalidRequest.WithMessage("timestamp_required", "timestamp gerekli"))
 		return
 	}
 	
-	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	ref, err := accountRefFromQuery(r)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Geçersiz kullanıcı ID"))
+		writeError(w, r, err)
+		return
+	}
+	account, ok := h.singleAccount(w, r, ref)
+	if !ok {
 		return
 	}
 	
 	// Timestamp'i parse et (RFC3339 formatında)
 	targetTime, err := time.Parse(time.RFC3339, timestampStr)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Geçersiz timestamp formatı"))
+		writeError(w, r, errInvalidRequest.WithMessage("invalid_timestamp", "geçersiz timestamp formatı").WithDetails(map[string]interface{}{"expected_format": time.RFC3339}))
 		return
 	}
 	
-	balance, err := h.BalanceService.GetBalanceAtTime(userID, targetTime)
+	balance, err := h.BalanceService.GetBalanceAtTime(account.ID, targetTime)
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte("Belirtilen zamanda bakiye bulunamadı"))
+		writeError(w, r, err)
 		return
 	}
 	
@@ -108,34 +175,90 @@ func (h *BalanceHandler) GetBalanceAtTime(w http.ResponseWriter, r *http.Request
 }
 
 // Bakiye hesaplama (GET /api/v1/balances/calculate)
+// Kullanıcıya göre sorgulanır ve currency verilmezse kullanıcının tüm hesapları için ayrı ayrı hesaplanır.
 func (h *BalanceHandler) CalculateBalance(w http.ResponseWriter, r *http.Request) {
-	userIDStr := r.URL.Query().Get("user_id")
-	if userIDStr == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Kullanıcı ID gerekli"))
+	ref, err := accountRefFromQuery(r)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 	
-	userID, err := strconv.ParseInt(userIDStr, 10, 64)
-	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Geçersiz kullanıcı ID"))
-		return
+	var response interface{}
+	if ref.AccountID != 0 || ref.Currency != "" {
+		account, ok := h.singleAccount(w, r, ref)
+		if !ok {
+			return
+		}
+		amount, err := h.BalanceService.CalculateBalance(account.ID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response = map[string]interface{}{
+			"user_id":    account.OwnerID,
+			"account_id": account.ID,
+			"amount":     amount,
+		}
+	} else {
+		if !h.Guard.authorize(w, r, domain.DelegationRead, auth.PermBalancesReadAny, ref.UserID) {
+			return
+		}
+		accounts, err := h.Accounts.ListByOwner(ref.UserID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		results := make([]map[string]interface{}, 0, len(accounts))
+		for _, account := range accounts {
+			amount, err := h.BalanceService.CalculateBalance(account.ID)
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+			results = append(results, map[string]interface{}{
+				"account_id": account.ID,
+				"amount":     amount,
+			})
+		}
+		response = map[string]interface{}{
+			"user_id":  ref.UserID,
+			"accounts": results,
+		}
 	}
 	
-	amount, err := h.BalanceService.CalculateBalance(userID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Hesabın kredi limitini değiştirir (PUT /api/v1/admin/accounts/{id}/overdraft, admin)
+// Gövde {"limit": "500.00"} veya {"limit": {"amount": "500.00", "currency": "USD"}} biçimindedir; limit hesabın
+// para biriminde olmalıdır ve 0 kredili kullanımı kapatır. Yanıt, kullanılabilir krediyle birlikte güncel bakiyedir.
+func (h *BalanceHandler) SetOverdraftLimit(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("Bakiye hesaplanamadı"))
+		writeError(w, r, errInvalidRequest.WithMessage("invalid_account_id", "geçersiz hesap ID"))
 		return
 	}
-	
-	response := map[string]interface{}{
-		"user_id": userID,
-		"amount":  amount,
+	var req struct {
+		Limit *domain.Money `json:"limit"`
 	}
-	
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, decodeError(err))
+		return
+	}
+	if req.Limit == nil {
+		writeError(w, r, errInvalidRequest.WithMessage("overdraft_limit_required", "kredi limiti gerekli"))
+		return
+	}
+
+	balance, err := h.BalanceService.SetOverdraftLimit(id, *req.Limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
-} 
\ No newline at end of file
+	json.NewEncoder(w).Encode(balance)
+}
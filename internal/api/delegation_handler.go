@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/domain"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DelegationHandler, hesap sahiplerinin başka kullanıcılara verdiği yetkileri yönetir
+type DelegationHandler struct {
+	Delegations domain.DelegationRepository
+	UserService domain.UserService
+}
+
+// Hesap sahibi adına yetki devri oluşturur (POST /api/v1/delegations)
+func (h *DelegationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthorized)
+		return
+	}
+
+	var req struct {
+		DelegateID int64                  `json:"delegate_id"`
+		Scope      domain.DelegationScope `json:"scope"`
+		ExpiresAt  *time.Time             `json:"expires_at"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, decodeError(err))
+		return
+	}
+
+	delegation := &domain.Delegation{
+		OwnerID:    principal.UserID,
+		DelegateID: req.DelegateID,
+		Scope:      req.Scope,
+		CreatedAt:  time.Now(),
+		ExpiresAt:  req.ExpiresAt,
+	}
+	if err := delegation.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if delegation.ExpiresAt != nil && !delegation.ExpiresAt.After(delegation.CreatedAt) {
+		writeError(w, r, domain.NewValidationError("expiry_in_past", "bitiş zamanı geçmişte"))
+		return
+	}
+	if _, err := h.UserService.GetByID(req.DelegateID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.Delegations.Create(delegation); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(delegation)
+}
+
+// Principal'ın verdiği ve aldığı yetkileri listeler (GET /api/v1/delegations)
+func (h *DelegationHandler) List(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthorized)
+		return
+	}
+
+	delegations, err := h.Delegations.ListByUser(principal.UserID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if delegations == nil {
+		delegations = []*domain.Delegation{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(delegations)
+}
+
+// Yetki devrini geri alır (DELETE /api/v1/delegations/{id})
+// Sadece yetkiyi veren hesap sahibi veya users:write:any yetkisine sahip principal silebilir.
+func (h *DelegationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, r, errInvalidRequest.WithMessage("invalid_delegation_id", "geçersiz yetki devri ID"))
+		return
+	}
+
+	delegation, err := h.Delegations.FindByID(id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if delegation.OwnerID != principal.UserID && !principal.Can(auth.PermUsersWriteAny) {
+		writeError(w, r, errForbidden.WithMessage("delegation_delete", "bu yetki devrini silme yetkiniz yok"))
+		return
+	}
+	if err := h.Delegations.Delete(id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
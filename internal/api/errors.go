@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/i18n"
+	"log"
+	"net/http"
+)
+
+// ErrorResponse, tüm hata cevaplarının ortak JSON zarfıdır.
+// İstemciler hata türünü Message yerine Code alanına göre ayırt etmelidir.
+type ErrorResponse struct {
+	Code      domain.ErrorCode       `json:"code"`
+	Message   string                 `json:"message"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+}
+
+// Hata kodlarının HTTP durum kodlarına eşlemesi; listede olmayan kodlar 500 döner
+var errorStatuses = map[domain.ErrorCode]int{
+	domain.CodeInvalidRequest:          http.StatusBadRequest,
+	domain.CodeValidationFailed:        http.StatusBadRequest,
+	domain.CodeInvalidAmount:           http.StatusBadRequest,
+	domain.CodeAmountOverflow:          http.StatusBadRequest,
+	domain.CodeCurrencyMismatch:        http.StatusBadRequest,
+	domain.CodeUnsupportedCurrency:     http.StatusBadRequest,
+	domain.CodeInsufficientFunds:       http.StatusUnprocessableEntity,
+	domain.CodeAccountNotFound:         http.StatusNotFound,
+	domain.CodeAccountInactive:         http.StatusConflict,
+	domain.CodeUserNotFound:            http.StatusNotFound,
+	domain.CodeTransactionNotFound:     http.StatusNotFound,
+	domain.CodeDelegationNotFound:      http.StatusNotFound,
+	domain.CodeNotFound:                http.StatusNotFound,
+	domain.CodeRateNotFound:            http.StatusUnprocessableEntity,
+	domain.CodeQuoteNotFound:           http.StatusNotFound,
+	domain.CodeQuoteExpired:            http.StatusGone,
+	domain.CodeQuoteAlreadyUsed:        http.StatusConflict,
+	domain.CodeInvalidTransactionState: http.StatusConflict,
+	domain.CodeHoldNotFound:            http.StatusNotFound,
+	domain.CodeHoldExpired:             http.StatusGone,
+	domain.CodeInvalidHoldState:        http.StatusConflict,
+	domain.CodeLimitExceeded:           http.StatusUnprocessableEntity,
+	domain.CodeInvalidCredentials:      http.StatusUnauthorized,
+	domain.CodeUnauthorized:            http.StatusUnauthorized,
+	domain.CodeInvalidToken:            http.StatusUnauthorized,
+	domain.CodeTokenExpired:            http.StatusUnauthorized,
+	domain.CodeInvalidRefreshToken:     http.StatusUnauthorized,
+	domain.CodeRefreshTokenReused:      http.StatusUnauthorized,
+	domain.CodeSessionRevoked:          http.StatusUnauthorized,
+	domain.CodeForbidden:               http.StatusForbidden,
+	domain.CodeConflict:                http.StatusConflict,
+	domain.CodeIdempotencyKeyReused:    http.StatusUnprocessableEntity,
+	domain.CodeIdempotencyInProgress:   http.StatusConflict,
+	domain.CodeRouteNotFound:           http.StatusNotFound,
+	domain.CodeMethodNotAllowed:        http.StatusMethodNotAllowed,
+	domain.CodeRateLimited:             http.StatusTooManyRequests,
+	domain.CodePayloadTooLarge:         http.StatusRequestEntityTooLarge,
+	domain.CodeTimeout:                 http.StatusRequestTimeout,
+	domain.CodeInternal:                http.StatusInternalServerError,
+}
+
+// API katmanında üretilen sık kullanılan hatalar
+var (
+	errInvalidRequest = domain.NewError(domain.CodeInvalidRequest, "geçersiz istek")
+	errUnauthorized   = domain.NewError(domain.CodeUnauthorized, "kimlik doğrulaması gerekli")
+	errForbidden      = domain.NewError(domain.CodeForbidden, "bu işlem için yetkiniz yok")
+)
+
+// Hata için HTTP durum kodunu döndürür
+func errorStatus(code domain.ErrorCode) int {
+	if status, ok := errorStatuses[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Hatayı isteğin dilinde JSON zarfı olarak yazar. Domain hatası olmayan hatalar iç detay
+// sızdırmamak için loglanır ve istemciye genel bir internal_error olarak döner.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	de, ok := domain.AsError(err)
+	if !ok {
+		log.Printf("[%s] %s %s: %v", RequestIDFromContext(r.Context()), r.Method, r.URL.Path, err)
+		de = domain.ErrInternal
+	}
+	message, ok := i18n.DefaultCatalog().Translate(i18n.LocaleFromContext(r.Context()), de.MessageKey(), de.Params)
+	if !ok {
+		message = de.Message
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(errorStatus(de.Code))
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Code:      de.Code,
+		Message:   message,
+		Details:   de.Details,
+		RequestID: RequestIDFromContext(r.Context()),
+	})
+}
+
+// İstek gövdesi çözümlenemediğinde kullanılır; tutar gibi domain hataları olduğu gibi korunur
+func decodeError(err error) error {
+	if _, ok := domain.AsError(err); ok {
+		return err
+	}
+	return errInvalidRequest.WithDetails(map[string]interface{}{"reason": err.Error()})
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/domain"
+	"net/http"
+)
+
+// FeeHandler, ücret tarifesini ve ücret ön izlemelerini yönetir
+type FeeHandler struct {
+	Fees     domain.FeeService
+	Accounts domain.AccountService
+	Guard    *OwnershipGuard
+}
+
+// Para hareketi yapılmadan önce alınacak ücreti hesaplar (POST /api/v1/fees/preview)
+// operation withdraw, transfer veya fx olmalıdır; hesap account_id ile ya da user_id ve tutarın
+// para birimiyle (varsayılan hesap) belirtilir. fx için amount gönderilecek tutardır.
+func (h *FeeHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Operation domain.FeeOperation `json:"operation"`
+		AccountID int64               `json:"account_id"`
+		UserID    int64               `json:"user_id"`
+		Amount    domain.Money        `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, decodeError(err))
+		return
+	}
+
+	account, ok := resolveAccount(w, r, h.Accounts, h.Guard, accountRef{AccountID: req.AccountID, UserID: req.UserID, Currency: req.Amount.Currency},
+		false, domain.DelegationRead, auth.PermFeesReadAny)
+	if !ok {
+		return
+	}
+	if err := account.EnsureCurrency(req.Amount); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	quote, err := h.Fees.Preview(domain.FeeMovement{UserID: account.OwnerID, Operation: req.Operation, Amount: req.Amount})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(quote)
+}
+
+// Geçerli ücret tarifesini döndürür (GET /api/v1/fees)
+func (h *FeeHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.Fees.Schedule())
+}
+
+// Ücret tarifesini tamamen değiştirir (PUT /api/v1/admin/fees, admin)
+// Gövde FEES_FILE ile aynı biçimdedir: {"rules": [...]}. Boş liste tüm ücretleri kaldırır.
+func (h *FeeHandler) SetRules(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Rules []domain.FeeRule `json:"rules"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, decodeError(err))
+		return
+	}
+
+	schedule, err := h.Fees.SetRules(req.Rules)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(schedule)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/fx"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// FXHandler, kur tablolarını ve kur tekliflerini yönetir
+type FXHandler struct {
+	FX *fx.Service
+}
+
+// Principal için kilitli kur teklifi oluşturur (POST /api/v1/fx/quote)
+func (h *FXHandler) Quote(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthorized)
+		return
+	}
+
+	var req struct {
+		Sell        domain.Money `json:"sell"`
+		BuyCurrency string       `json:"buy_currency"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, decodeError(err))
+		return
+	}
+
+	quote, err := h.FX.Quote(principal.UserID, req.Sell, req.BuyCurrency)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(quote)
+}
+
+// Güncel kur tablosunu döndürür (GET /api/v1/fx/rates)
+// at parametresi (RFC3339) verilirse o zamanda geçerli olan sürüm döner.
+func (h *FXHandler) GetRates(w http.ResponseWriter, r *http.Request) {
+	var (
+		table *fx.RateTable
+		err   error
+	)
+	if at := r.URL.Query().Get("at"); at != "" {
+		t, parseErr := time.Parse(time.RFC3339, at)
+		if parseErr != nil {
+			writeError(w, r, errInvalidRequest.WithMessage("invalid_timestamp", "geçersiz timestamp formatı").WithDetails(map[string]interface{}{"expected_format": time.RFC3339}))
+			return
+		}
+		table, err = h.FX.Rates().At(t)
+	} else {
+		table, err = h.FX.Rates().Current()
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(table)
+}
+
+// Yayınlanmış kur tablosu sürümlerini listeler (GET /api/v1/fx/rates/versions)
+func (h *FXHandler) ListRateVersions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.FX.Rates().Versions())
+}
+
+// Yeni kur tablosu sürümü yayınlar (POST /api/v1/fx/rates, admin)
+// Gövde kur dosyasıyla aynı JSON biçimindedir (bkz. fx.RateFile).
+func (h *FXHandler) PublishRates(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthorized)
+		return
+	}
+
+	var req fx.RateFile
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, decodeError(err))
+		return
+	}
+
+	table, err := req.Table("admin:"+strconv.FormatInt(principal.UserID, 10), time.Now())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.FX.Rates().Publish(table); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(table)
+}
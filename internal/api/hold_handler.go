@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/domain"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HoldHandler, provizyon (bakiye bloke) işlemlerini yönetir
+type HoldHandler struct {
+	Holds    domain.HoldService
+	Accounts domain.AccountService
+	Guard    *OwnershipGuard
+}
+
+// Hesaba provizyon koyar (POST /api/v1/holds)
+// Hesap account_id ile ya da user_id ve tutarın para birimiyle (varsayılan hesap) belirtilir.
+// to_account_id veya to_user_id verilirse provizyon tahsil edildiğinde o hesaba transfer edilir.
+// ttl_seconds verilmezse sunucunun varsayılan provizyon süresi uygulanır.
+func (h *HoldHandler) Place(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		AccountID   int64        `json:"account_id"`
+		UserID      int64        `json:"user_id"`
+		ToAccountID int64        `json:"to_account_id"`
+		ToUserID    int64        `json:"to_user_id"`
+		Amount      domain.Money `json:"amount"`
+		TTLSeconds  int64        `json:"ttl_seconds"`
+		domain.TransactionDetails
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, decodeError(err))
+		return
+	}
+
+	account, ok := resolveAccount(w, r, h.Accounts, h.Guard, accountRef{AccountID: req.AccountID, UserID: req.UserID, Currency: req.Amount.Currency},
+		false, domain.DelegationWrite, auth.PermHoldsWriteAny)
+	if !ok {
+		return
+	}
+	hold := &domain.Hold{AccountID: account.ID, Amount: req.Amount, TransactionDetails: req.TransactionDetails}
+	if req.ToAccountID != 0 || req.ToUserID != 0 {
+		to, err := lookupAccount(h.Accounts, accountRef{AccountID: req.ToAccountID, UserID: req.ToUserID, Currency: req.Amount.Currency}, true)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		hold.ToAccountID = &to.ID
+	}
+
+	if err := h.Holds.Place(hold, time.Duration(req.TTLSeconds)*time.Second); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hold)
+}
+
+// Hesabın provizyonlarını listeler (GET /api/v1/holds?account_id= veya ?user_id=&currency=)
+func (h *HoldHandler) List(w http.ResponseWriter, r *http.Request) {
+	ref, err := accountRefFromQuery(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if ref.AccountID == 0 && ref.Currency == "" {
+		ref.Currency = domain.DefaultCurrency
+	}
+	account, ok := resolveAccount(w, r, h.Accounts, h.Guard, ref, false, domain.DelegationRead, auth.PermHoldsReadAny)
+	if !ok {
+		return
+	}
+
+	holds, err := h.Holds.ListByAccount(account.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if holds == nil {
+		holds = []*domain.Hold{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(holds)
+}
+
+// Belirli bir provizyonu getirir (GET /api/v1/holds/{id})
+func (h *HoldHandler) Get(w http.ResponseWriter, r *http.Request) {
+	hold, ok := h.holdFromPath(w, r, domain.DelegationRead, auth.PermHoldsReadAny)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(hold)
+}
+
+// Provizyonu tahsil eder (POST /api/v1/holds/{id}/capture)
+// Gövde boşsa veya amount verilmezse provizyonun tamamı tahsil edilir; kalan tutar serbest bırakılır.
+func (h *HoldHandler) Capture(w http.ResponseWriter, r *http.Request) {
+	hold, ok := h.holdFromPath(w, r, domain.DelegationWrite, auth.PermHoldsWriteAny)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Amount *domain.Money `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, r, decodeError(err))
+		return
+	}
+
+	captured, err := h.Holds.Capture(hold.ID, req.Amount)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(captured)
+}
+
+// Provizyonu iptal eder ve tutarın tamamını serbest bırakır (POST /api/v1/holds/{id}/void)
+func (h *HoldHandler) Void(w http.ResponseWriter, r *http.Request) {
+	hold, ok := h.holdFromPath(w, r, domain.DelegationWrite, auth.PermHoldsWriteAny)
+	if !ok {
+		return
+	}
+
+	voided, err := h.Holds.Void(hold.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(voided)
+}
+
+// Yoldaki provizyonu getirir ve principal'ın provizyonun hesap sahibine scope kapsamında erişimini kontrol eder
+func (h *HoldHandler) holdFromPath(w http.ResponseWriter, r *http.Request, scope domain.DelegationScope, anyPerm auth.Permission) (*domain.Hold, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, r, errInvalidRequest.WithMessage("invalid_hold_id", "geçersiz provizyon ID"))
+		return nil, false
+	}
+	hold, err := h.Holds.GetByID(id)
+	if err != nil {
+		writeError(w, r, err)
+		return nil, false
+	}
+	if !h.Guard.authorize(w, r, scope, anyPerm, hold.OwnerID) {
+		return nil, false
+	}
+	return hold, true
+}
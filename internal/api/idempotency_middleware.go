@@ -0,0 +1,150 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/domain"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// IdempotencyKeyHeader, istemcinin tekrar denemelerde gönderdiği başlıktır
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyMiddleware, aynı Idempotency-Key ile tekrar gelen isteklere ilk yanıtı döner.
+// Aynı anahtar farklı bir istek gövdesiyle kullanılırsa 422 döner. Başlık yoksa istek normal işlenir.
+// Anahtarlar kullanıcı bazında tutulduğu için AuthMiddleware'in içinde kullanılmalıdır.
+func IdempotencyMiddleware(store domain.IdempotencyRepository, ttl time.Duration) func(HandlerFunc) HandlerFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				writeError(w, r, errInvalidRequest.WithMessage("idempotency_key_too_long", "Idempotency-Key çok uzun").
+					WithDetails(map[string]interface{}{"max_length": maxIdempotencyKeyLength}))
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeError(w, r, errInvalidRequest.WithMessage("unreadable_body", "request body okunamadı"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			now := time.Now()
+			record := &domain.IdempotencyRecord{
+				Key:         scopedIdempotencyKey(r, key),
+				Fingerprint: requestFingerprint(r, body),
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(ttl),
+			}
+			existing, err := store.Reserve(record)
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+			if existing != nil {
+				replayIdempotentResponse(w, r, existing, record.Fingerprint)
+				return
+			}
+
+			rec := &recordingWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			handled := false
+			defer func() {
+				// Panic veya sunucu hatasında anahtar serbest bırakılır, istemci tekrar deneyebilir
+				if !handled {
+					store.Release(record.Key)
+				}
+			}()
+
+			next(rec, r)
+
+			if rec.statusCode >= http.StatusInternalServerError {
+				return
+			}
+			// İstek işlendi; para hareketi olmuş olabileceği için anahtar bu noktadan sonra asla serbest bırakılmaz.
+			// Yanıt kaydedilemezse anahtar işleniyor durumunda kalır ve süresi dolana kadar tekrarlar 409 alır.
+			handled = true
+			completeIdempotentRequest(store, r, record.Key, rec)
+		}
+	}
+}
+
+const idempotencyCompleteAttempts = 3
+
+// İlk isteğin yanıtını kaydeder; geçici depo hatalarında birkaç kez tekrar dener
+func completeIdempotentRequest(store domain.IdempotencyRepository, r *http.Request, key string, rec *recordingWriter) {
+	var err error
+	for attempt := 1; attempt <= idempotencyCompleteAttempts; attempt++ {
+		if err = store.Complete(key, rec.statusCode, rec.Header().Get("Content-Type"), rec.body.Bytes()); err == nil {
+			return
+		}
+		time.Sleep(time.Duration(attempt) * 50 * time.Millisecond)
+	}
+	log.Printf("[%s] %s %s: idempotency yanıtı kaydedilemedi, anahtar işleniyor durumunda bırakıldı: %v",
+		RequestIDFromContext(r.Context()), r.Method, r.URL.Path, err)
+}
+
+func replayIdempotentResponse(w http.ResponseWriter, r *http.Request, existing *domain.IdempotencyRecord, fingerprint string) {
+	if existing.Fingerprint != fingerprint {
+		writeError(w, r, domain.NewError(domain.CodeIdempotencyKeyReused, "Idempotency-Key farklı bir istek için kullanılmış"))
+		return
+	}
+	if !existing.Completed() {
+		writeError(w, r, domain.NewError(domain.CodeIdempotencyInProgress, "aynı Idempotency-Key ile bir istek hâlâ işleniyor"))
+		return
+	}
+	if existing.ContentType != "" {
+		w.Header().Set("Content-Type", existing.ContentType)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(existing.StatusCode)
+	w.Write(existing.Body)
+}
+
+// Anahtarı kullanıcıya göre ayırır; farklı kullanıcıların aynı anahtarı çakışmaz
+func scopedIdempotencyKey(r *http.Request, key string) string {
+	var userID int64
+	if principal, ok := auth.PrincipalFromContext(r.Context()); ok {
+		userID = principal.UserID
+	}
+	return fmt.Sprintf("%d:%s", userID, key)
+}
+
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter, yanıtı istemciye yazarken kaydedilmek üzere bir kopyasını tutar
+type recordingWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failingCompleteStore, yanıt kaydını her zaman reddeden idempotency deposudur
+type failingCompleteStore struct {
+	*repository.IdempotencyRepositoryImpl
+	released int
+}
+
+func (s *failingCompleteStore) Complete(string, int, string, []byte) error {
+	return errors.New("depo kullanılamıyor")
+}
+
+func (s *failingCompleteStore) Release(key string) error {
+	s.released++
+	return s.IdempotencyRepositoryImpl.Release(key)
+}
+
+func idempotentRequest(handler HandlerFunc) *httptest.ResponseRecorder {
+	return idempotentRequestAs(handler, 7, http.MethodPost, "/api/v1/transactions/debit", `{"amount":"10.00"}`)
+}
+
+// k1 anahtarıyla verilen kullanıcı adına istek gönderir
+func idempotentRequestAs(handler HandlerFunc, userID int64, method, path, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	r = r.WithContext(auth.WithPrincipal(r.Context(), &auth.Principal{UserID: userID}))
+	r.Header.Set(IdempotencyKeyHeader, "k1")
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w
+}
+
+// countingHandler, her çağrıda sayacı artırıp sayacı içeren bir JSON yanıtı döner
+func countingHandler(calls *int32) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"call":%d}`, n)
+	}
+}
+
+func TestIdempotencyReplaysCompletedRequest(t *testing.T) {
+	var calls int32
+	handler := IdempotencyMiddleware(repository.NewIdempotencyRepository(), time.Hour)(countingHandler(&calls))
+
+	first := idempotentRequest(handler)
+	if first.Code != http.StatusCreated || first.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("ilk istek: durum %d, replayed %q", first.Code, first.Header().Get("Idempotent-Replayed"))
+	}
+	for i := 0; i < 2; i++ {
+		w := idempotentRequest(handler)
+		if w.Code != http.StatusCreated || w.Body.String() != `{"call":1}` || w.Header().Get("Content-Type") != "application/json" ||
+			w.Header().Get("Idempotent-Replayed") != "true" {
+			t.Fatalf("tekrar %d: durum %d, gövde %s, başlıklar %v", i+1, w.Code, w.Body.String(), w.Header())
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("handler %d kez çalıştı, beklenen 1", calls)
+	}
+}
+
+// Parmak izi method, path ve gövdeyi kapsar; herhangi biri değişirse anahtar yeniden kullanılamaz
+func TestIdempotencyRejectsKeyReuseForDifferentRequest(t *testing.T) {
+	cases := []struct {
+		name, method, path, body string
+	}{
+		{"farklı gövde", http.MethodPost, "/api/v1/transactions/debit", `{"amount":"11.00"}`},
+		{"farklı path", http.MethodPost, "/api/v1/transactions/credit", `{"amount":"10.00"}`},
+		{"farklı method", http.MethodPut, "/api/v1/transactions/debit", `{"amount":"10.00"}`},
+	}
+	for _, c := range cases {
+		var calls int32
+		handler := IdempotencyMiddleware(repository.NewIdempotencyRepository(), time.Hour)(countingHandler(&calls))
+		idempotentRequest(handler)
+
+		w := idempotentRequestAs(handler, 7, c.method, c.path, c.body)
+		if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), string(domain.CodeIdempotencyKeyReused)) {
+			t.Errorf("%s: durum %d gövde %s, beklenen 422 idempotency_key_reused", c.name, w.Code, w.Body.String())
+		}
+		if calls != 1 {
+			t.Errorf("%s: handler %d kez çalıştı, beklenen 1", c.name, calls)
+		}
+	}
+}
+
+func TestIdempotencyConcurrentRequestInProgress(t *testing.T) {
+	var calls int32
+	entered, release := make(chan struct{}), make(chan struct{})
+	handler := IdempotencyMiddleware(repository.NewIdempotencyRepository(), time.Hour)(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		countingHandler(&calls)(w, r)
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- idempotentRequest(handler) }()
+	<-entered
+
+	w := idempotentRequest(handler)
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), string(domain.CodeIdempotencyInProgress)) {
+		t.Fatalf("eşzamanlı istek: durum %d gövde %s, beklenen 409 idempotency_in_progress", w.Code, w.Body.String())
+	}
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Fatalf("ilk istek: durum %d, beklenen 201", first.Code)
+	}
+	if w := idempotentRequest(handler); w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("tamamlandıktan sonra: durum %d, kayıtlı yanıt dönmeli", w.Code)
+	}
+	if calls != 1 {
+		t.Fatalf("handler %d kez çalıştı, beklenen 1", calls)
+	}
+}
+
+func TestIdempotencyKeysAreScopedPerUser(t *testing.T) {
+	var calls int32
+	handler := IdempotencyMiddleware(repository.NewIdempotencyRepository(), time.Hour)(countingHandler(&calls))
+
+	// Aynı anahtar başka kullanıcı için ne kayıtlı yanıtı döndürür ne de farklı gövde yüzünden reddedilir
+	requests := []struct {
+		userID int64
+		body   string
+		want   string
+	}{
+		{7, `{"amount":"10.00"}`, `{"call":1}`},
+		{8, `{"amount":"10.00"}`, `{"call":2}`},
+		{9, `{"amount":"99.00"}`, `{"call":3}`},
+		{7, `{"amount":"10.00"}`, `{"call":1}`},
+		{8, `{"amount":"10.00"}`, `{"call":2}`},
+	}
+	for i, req := range requests {
+		w := idempotentRequestAs(handler, req.userID, http.MethodPost, "/api/v1/transactions/debit", req.body)
+		if w.Code != http.StatusCreated || w.Body.String() != req.want {
+			t.Fatalf("istek %d (kullanıcı %d): durum %d gövde %s, beklenen %s", i+1, req.userID, w.Code, w.Body.String(), req.want)
+		}
+	}
+	if calls != 3 {
+		t.Fatalf("handler %d kez çalıştı, beklenen 3", calls)
+	}
+}
+
+func TestIdempotencyKeepsKeyWhenCompleteFails(t *testing.T) {
+	store := &failingCompleteStore{IdempotencyRepositoryImpl: repository.NewIdempotencyRepository()}
+	calls := 0
+	handler := IdempotencyMiddleware(store, time.Hour)(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	})
+
+	if w := idempotentRequest(handler); w.Code != http.StatusOK {
+		t.Fatalf("ilk istek: durum %d, beklenen 200", w.Code)
+	}
+	w := idempotentRequest(handler)
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "idempotency_in_progress") {
+		t.Fatalf("tekrar: durum %d gövde %s, beklenen 409 idempotency_in_progress", w.Code, w.Body.String())
+	}
+	if calls != 1 {
+		t.Fatalf("handler %d kez çalıştı, beklenen 1", calls)
+	}
+	if store.released != 0 {
+		t.Fatalf("anahtar %d kez serbest bırakıldı, beklenen 0", store.released)
+	}
+}
+
+func TestIdempotencyReleasesKeyOnServerError(t *testing.T) {
+	store := repository.NewIdempotencyRepository()
+	calls := 0
+	handler := IdempotencyMiddleware(store, time.Hour)(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	if w := idempotentRequest(handler); w.Code != http.StatusInternalServerError {
+		t.Fatalf("ilk istek: durum %d, beklenen 500", w.Code)
+	}
+	if w := idempotentRequest(handler); w.Code != http.StatusCreated {
+		t.Fatalf("tekrar: durum %d, beklenen 201", w.Code)
+	}
+	if w := idempotentRequest(handler); w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("üçüncü istek kayıtlı yanıtı dönmeli: durum %d", w.Code)
+	}
+	if calls != 2 {
+		t.Fatalf("handler %d kez çalıştı, beklenen 2", calls)
+	}
+}
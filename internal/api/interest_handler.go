@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/domain"
+	"net/http"
+	"time"
+)
+
+// InterestHandler, faiz tahakkuklarını ve faiz oranlarını yönetir
+type InterestHandler struct {
+	Interest domain.InterestService
+	Accounts domain.AccountService
+	Guard    *OwnershipGuard
+}
+
+// Hesabın günlük faiz tahakkuklarını listeler (GET /api/v1/interest/accruals?account_id= veya ?user_id=&currency=)
+// from ve to YYYY-MM-DD biçiminde UTC günleridir; to hariçtir. Verilmezlerse içinde bulunulan ay listelenir.
+func (h *InterestHandler) ListAccruals(w http.ResponseWriter, r *http.Request) {
+	ref, err := accountRefFromQuery(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if ref.AccountID == 0 && ref.Currency == "" {
+		ref.Currency = domain.DefaultCurrency
+	}
+	account, ok := resolveAccount(w, r, h.Accounts, h.Guard, ref, false, domain.DelegationRead, auth.PermInterestReadAny)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	for param, target := range map[string]*time.Time{"from": &from, "to": &to} {
+		if value := r.URL.Query().Get(param); value != "" {
+			day, err := time.Parse(time.DateOnly, value)
+			if err != nil {
+				writeError(w, r, errInvalidRequest.WithMessage("invalid_date", "geçersiz tarih formatı").
+					WithDetails(map[string]interface{}{"parameter": param, "expected_format": time.DateOnly}))
+				return
+			}
+			*target = day
+		}
+	}
+
+	accruals, err := h.Interest.ListAccruals(account.ID, from, to)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if accruals == nil {
+		accruals = []*domain.InterestAccrual{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(accruals)
+}
+
+// Tüm faiz oranlarını listeler (GET /api/v1/admin/interest/rates, admin)
+func (h *InterestHandler) ListRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.Interest.Rates()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"rates": rates})
+}
+
+// Yeni bir faiz oranı ekler (POST /api/v1/admin/interest/rates, admin)
+// Gövde INTEREST_FILE'daki bir oranla aynı biçimdedir. Oran bugünden veya ileri bir günden itibaren
+// geçerli olabilir; önceki günlerin tahakkukları eski oranla kalır.
+func (h *InterestHandler) SetRate(w http.ResponseWriter, r *http.Request) {
+	var rate domain.InterestRate
+	if err := json.NewDecoder(r.Body).Decode(&rate); err != nil {
+		writeError(w, r, decodeError(err))
+		return
+	}
+
+	if err := h.Interest.SetRate(rate); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	rates, err := h.Interest.Rates()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"rates": rates})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/domain"
+	"net/http"
+	"strconv"
+)
+
+// LimitHandler, işlem limitlerini ve admin limit override'larını yönetir
+type LimitHandler struct {
+	Limits domain.LimitService
+	Guard  *OwnershipGuard
+}
+
+// Kullanıcının limitlerini ve kalan kullanımını döndürür (GET /api/v1/limits?user_id=&currency=&counterparty_id=)
+// user_id verilmezse principal'ın limitleri döner. counterparty_id verilirse o alıcıya özel limitler de hesaplanır.
+func (h *LimitHandler) GetLimits(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	userID := principal.UserID
+	if userIDStr := query.Get("user_id"); userIDStr != "" {
+		id, err := strconv.ParseInt(userIDStr, 10, 64)
+		if err != nil {
+			writeError(w, r, errInvalidRequest.WithMessage("invalid_user_id", "geçersiz kullanıcı ID"))
+			return
+		}
+		userID = id
+	}
+	var counterpartyID *int64
+	if counterpartyStr := query.Get("counterparty_id"); counterpartyStr != "" {
+		id, err := strconv.ParseInt(counterpartyStr, 10, 64)
+		if err != nil {
+			writeError(w, r, errInvalidRequest.WithMessage("invalid_counterparty_id", "geçersiz karşı taraf ID"))
+			return
+		}
+		counterpartyID = &id
+	}
+	if !h.Guard.authorize(w, r, domain.DelegationRead, auth.PermLimitsReadAny, userID) {
+		return
+	}
+
+	headroom, err := h.Limits.Headroom(userID, query.Get("currency"), counterpartyID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user_id": userID,
+		"limits":  headroom,
+	})
+}
+
+// Tüm rol ve kullanıcı limit override'larını listeler (GET /api/v1/admin/limits/overrides, admin)
+func (h *LimitHandler) ListOverrides(w http.ResponseWriter, r *http.Request) {
+	overrides, err := h.Limits.ListOverrides()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if overrides == nil {
+		overrides = []*domain.LimitOverride{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(overrides)
+}
+
+// Rol veya kullanıcı için limit override'ı tanımlar (PUT /api/v1/admin/limits/overrides/{scope}/{subject}, admin)
+// Gövde {"rules": [...]} biçimindedir; kuralda boş bırakılan limitler alttaki katmandan gelir.
+func (h *LimitHandler) SetOverride(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Rules []domain.LimitRule `json:"rules"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, decodeError(err))
+		return
+	}
+
+	override := &domain.LimitOverride{
+		Scope:   domain.LimitScope(r.PathValue("scope")),
+		Subject: r.PathValue("subject"),
+		Rules:   req.Rules,
+	}
+	if override.Rules == nil {
+		override.Rules = []domain.LimitRule{}
+	}
+	if err := h.Limits.SetOverride(override); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(override)
+}
+
+// Limit override'ını siler (DELETE /api/v1/admin/limits/overrides/{scope}/{subject}, admin)
+func (h *LimitHandler) DeleteOverride(w http.ResponseWriter, r *http.Request) {
+	if err := h.Limits.DeleteOverride(domain.LimitScope(r.PathValue("scope")), r.PathValue("subject")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
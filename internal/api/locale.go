@@ -0,0 +1,43 @@
+package api
+
+import (
+	"gofinancialsystem/internal/i18n"
+	"net/http"
+)
+
+// LocaleMiddleware, cevap dilini Accept-Language başlığından seçer ve context'e ekler.
+// Kimliği doğrulanmış isteklerde kullanıcının kayıtlı dil tercihi başlığın önüne geçer (bkz. AccessControl).
+func LocaleMiddleware(next HandlerFunc) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		locale := i18n.DefaultCatalog().Negotiate(r.Header.Get("Accept-Language"))
+		w.Header().Add("Vary", "Accept-Language")
+		next(w, withLocale(w, r, locale))
+	}
+}
+
+func withLocale(w http.ResponseWriter, r *http.Request, locale i18n.Locale) *http.Request {
+	w.Header().Set("Content-Language", string(locale))
+	return r.WithContext(i18n.WithLocale(r.Context(), locale))
+}
+
+// Kullanıcı tercihi olarak gönderilen dili doğrular ve normalize eder; boş değer tercih yok demektir
+func supportedLocale(tag string) (string, error) {
+	if tag == "" {
+		return "", nil
+	}
+	locale, ok := i18n.DefaultCatalog().Match(tag)
+	if !ok {
+		return "", errInvalidRequest.WithMessage("unsupported_locale", "desteklenmeyen dil: "+tag).
+			WithParams(map[string]interface{}{"locale": tag})
+	}
+	return string(locale), nil
+}
+
+// Mesaj kataloğundaki anahtarı isteğin dilinde döndürür; anahtar yoksa anahtarın kendisi döner
+func localize(r *http.Request, key string, params map[string]interface{}) string {
+	msg, ok := i18n.DefaultCatalog().Translate(i18n.LocaleFromContext(r.Context()), key, params)
+	if !ok {
+		return key
+	}
+	return msg
+}
@@ -1,17 +1,54 @@
 package api
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"gofinancialsystem/internal/domain"
 	"net/http"
 	"sync"
 	"time"
 )
 
+// RequestIDHeader, isteği loglarda ve hata cevaplarında izlemek için kullanılan başlıktır
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestIDMiddleware, her isteğe bir ID atar. İstemci X-Request-ID gönderirse o kullanılır.
+// Hata cevaplarındaki request_id alanı bu değerdir, bu yüzden zincirin en başında olmalıdır.
+func RequestIDMiddleware(next HandlerFunc) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	}
+}
+
+// İsteğin ID'sini döndürür; RequestIDMiddleware kullanılmamışsa boş döner
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
 // CORS için varsayılan başlıklar
 var defaultCORSHeaders = map[string]string{
 	"Access-Control-Allow-Origin":      "*",
 	"Access-Control-Allow-Methods":     "GET, POST, PUT, DELETE, OPTIONS",
-	"Access-Control-Allow-Headers":     "Content-Type, Authorization",
+	"Access-Control-Allow-Headers":     "Content-Type, Authorization, Idempotency-Key, X-Request-ID",
+	"Access-Control-Expose-Headers":    "X-Request-ID, Idempotent-Replayed",
 	"Access-Control-Allow-Credentials": "true",
 }
 
@@ -55,8 +92,7 @@ func RateLimitMiddleware(next HandlerFunc) HandlerFunc {
 		now := time.Now()
 		if exists && now.Sub(last) < 500*time.Millisecond {
 			limiter.mu.Unlock()
-			w.WriteHeader(http.StatusTooManyRequests)
-			fmt.Fprint(w, "Çok fazla istek! Lütfen bekleyin.")
+			writeError(w, r, domain.NewError(domain.CodeRateLimited, "çok fazla istek, lütfen bekleyin"))
 			return
 		}
 		limiter.clients[ip] = now
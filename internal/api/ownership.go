@@ -0,0 +1,53 @@
+package api
+
+import (
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/domain"
+	"net/http"
+	"time"
+)
+
+// OwnershipGuard, kullanıcıların sadece kendi hesapları üzerinde işlem yapmasını sağlar.
+// "*:any" yetkisine sahip principal'lar veya hesap sahibinden yetki devri almış kullanıcılar muaftır.
+type OwnershipGuard struct {
+	Delegations domain.DelegationRepository
+}
+
+// Principal'ın ownerID'ye ait kaynağa scope kapsamında erişip erişemeyeceğini döndürür
+func (g *OwnershipGuard) Allowed(principal *auth.Principal, ownerID int64, scope domain.DelegationScope, anyPerm auth.Permission) bool {
+	if principal.UserID == ownerID || principal.Can(anyPerm) {
+		return true
+	}
+	delegations, err := g.Delegations.ListBetween(ownerID, principal.UserID)
+	if err != nil {
+		return false
+	}
+	now := time.Now()
+	for _, d := range delegations {
+		if d.Allows(scope, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Erişim yoksa hata cevabını yazar ve false döner; handler'lar bu durumda hemen dönmelidir
+// Birden fazla sahip verilirse (ör: transfer tarafları) herhangi birine erişim yeterlidir.
+// Sahibi olmayan kaynaklara (ör: sadece sistem hesaplarından alan bir işlem) yalnızca anyPerm ile erişilir.
+func (g *OwnershipGuard) authorize(w http.ResponseWriter, r *http.Request, scope domain.DelegationScope, anyPerm auth.Permission, ownerIDs ...int64) bool {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthorized)
+		return false
+	}
+	if len(ownerIDs) == 0 && principal.Can(anyPerm) {
+		return true
+	}
+	for _, ownerID := range ownerIDs {
+		if g.Allowed(principal, ownerID, scope, anyPerm) {
+			return true
+		}
+	}
+	writeError(w, r, errForbidden.WithMessage("account", "bu hesap üzerinde işlem yetkiniz yok"))
+	return false
+}
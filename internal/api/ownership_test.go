@@ -0,0 +1,299 @@
+package api
+
+import (
+	"fmt"
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/calendar"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/fees"
+	"gofinancialsystem/internal/fx"
+	"gofinancialsystem/internal/limits"
+	"gofinancialsystem/internal/repository/memtest"
+	"gofinancialsystem/internal/schedule"
+	"gofinancialsystem/internal/service"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Sahiplik testlerinde istek yapan kullanıcılar
+const (
+	callerOwner    = "owner"    // Kaynakların sahibi
+	callerDelegate = "delegate" // Sahipten write yetki devri almış kullanıcı
+	callerReader   = "reader"   // Sahipten read yetki devri almış kullanıcı
+	callerOther    = "other"    // Sahiple ilişkisi olmayan kullanıcı
+	callerAdmin    = "admin"
+	callerSupport  = "support" // Sadece transactions:reverse yetkisi olan operasyon kullanıcısı; sahiplik testlerinde kullanılmaz
+)
+
+var ownershipCallers = []string{callerOwner, callerDelegate, callerReader, callerOther, callerAdmin}
+
+// ownershipFixture, sahibin hesabı, işlemi, provizyonu ve talimatı hazırlanmış bir API sunucusudur
+type ownershipFixture struct {
+	router   *Router
+	tokens   map[string]string
+	owner    *domain.User
+	account  *domain.Account // Bakiyesi olan TRY hesabı
+	spare    *domain.Account // Bakiyesi olmayan, varsayılan olmadığı için kapatılabilir TRY hesabı
+	payee    *domain.User    // İşlemin ve talimatın karşı tarafı; isteklerde kullanılmaz
+	incoming *domain.Transaction
+	hold     *domain.Hold
+	order    *domain.ScheduledTransfer
+}
+
+func newOwnershipFixture(t *testing.T) *ownershipFixture {
+	t.Helper()
+	repos := memtest.New()
+	businessCalendar := calendar.Default()
+
+	userService := service.NewUserService(repos.Users)
+	accountService := service.NewAccountService(repos.Accounts, repos.Balances)
+	balanceService := service.NewBalanceService(repos.Balances, repos.Accounts, repos.Ledger, repos.UnitOfWork, businessCalendar)
+	limitEngine := limits.NewEngine(limits.DefaultRules(), repos.LimitOverrides, repos.Users, repos.Transactions)
+	feeEngine := fees.NewEngine(nil, repos.Transactions)
+	transactionService := service.NewTransactionService(repos.Transactions, repos.Accounts, repos.UnitOfWork, limitEngine, feeEngine, businessCalendar)
+	holdService := service.NewHoldService(repos.Holds, repos.Accounts, repos.UnitOfWork, limitEngine, time.Hour, businessCalendar)
+	interestService := service.NewInterestService(nil, repos.InterestRates, repos.Accounts, balanceService, repos.Interest, repos.UnitOfWork, businessCalendar)
+	scheduleService := schedule.NewService(repos.Schedules, repos.Accounts, transactionService,
+		domain.ScheduleRetryPolicy{MaxAttempts: 1, Interval: time.Hour}, time.Now)
+	fxService := fx.NewService(fx.NewRateStore(), 0, time.Minute)
+
+	keyRing, err := auth.NewEphemeralKeyRing()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := auth.NewSessionService(repos.Sessions, userService, auth.NewTokenService(keyRing, time.Hour, "test"), time.Hour)
+	authorizer, err := auth.ParseRolePermissions(auth.DefaultRolePermissions + ";support=transactions:reverse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &ownershipFixture{router: NewRouter(), tokens: map[string]string{}}
+	people := map[string]*domain.User{}
+	for _, name := range append(ownershipCallers, "payee", callerSupport) {
+		role := "user"
+		if name == callerAdmin || name == callerSupport {
+			role = name
+		}
+		user := &domain.User{Username: name, Email: name + "@example.com", Password: "hash", Role: role}
+		if err := repos.Users.Create(user); err != nil {
+			t.Fatal(err)
+		}
+		people[name] = user
+		if name == "payee" {
+			continue
+		}
+		pair, err := sessions.Login(user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.tokens[name] = pair.AccessToken
+	}
+	f.owner, f.payee = people[callerOwner], people["payee"]
+	for delegate, scope := range map[string]domain.DelegationScope{callerDelegate: domain.DelegationWrite, callerReader: domain.DelegationRead} {
+		if err := repos.Delegations.Create(&domain.Delegation{OwnerID: f.owner.ID, DelegateID: people[delegate].ID, Scope: scope}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if f.account, err = accountService.DefaultAccount(f.owner.ID, "TRY", true); err != nil {
+		t.Fatal(err)
+	}
+	payeeAccount, err := accountService.DefaultAccount(f.payee.ID, "TRY", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.spare = &domain.Account{OwnerID: f.owner.ID, Type: domain.AccountSavings, Currency: "TRY"}
+	if err := accountService.Open(f.spare); err != nil {
+		t.Fatal(err)
+	}
+	for _, accountID := range []int64{f.account.ID, payeeAccount.ID} {
+		if err := transactionService.Credit(accountID, domain.NewMoney(100000, "TRY"), domain.TransactionDetails{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Sahibe gelen transfer: taraflar sahip ve payee, iadeyi sahip (alıcı) yapar
+	if err := transactionService.Transfer(payeeAccount.ID, f.account.ID, domain.NewMoney(10000, "TRY"), domain.TransactionDetails{}); err != nil {
+		t.Fatal(err)
+	}
+	history, err := repos.Transactions.ListByUser(f.payee.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tx := range history {
+		if tx.Type == domain.TransactionTransfer {
+			f.incoming = tx
+		}
+	}
+	if f.incoming == nil {
+		t.Fatal("sahibe gelen transfer bulunamadı")
+	}
+
+	f.hold = &domain.Hold{AccountID: f.account.ID, Amount: domain.NewMoney(1000, "TRY")}
+	if err := holdService.Place(f.hold, 0); err != nil {
+		t.Fatal(err)
+	}
+	f.order = &domain.ScheduledTransfer{CreatedBy: f.owner.ID, FromAccountID: f.account.ID, ToAccountID: payeeAccount.ID,
+		Amount: domain.NewMoney(1000, "TRY"), Frequency: domain.ScheduleOnce}
+	if err := scheduleService.Create(f.order, time.Now().Add(24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	guard := &OwnershipGuard{Delegations: repos.Delegations}
+	RegisterRoutes(f.router, &Handlers{
+		Auth: &AuthHandler{UserService: userService, Sessions: sessions},
+		User: &UserHandler{UserService: userService, Guard: guard},
+		Transaction: &TransactionHandler{TransactionService: transactionService, BalanceService: balanceService,
+			Accounts: accountService, Guard: guard, FX: fxService},
+		Balance:        &BalanceHandler{BalanceService: balanceService, Accounts: accountService, Guard: guard},
+		Account:        &AccountHandler{Accounts: accountService, Guard: guard},
+		Hold:           &HoldHandler{Holds: holdService, Accounts: accountService, Guard: guard},
+		Delegation:     &DelegationHandler{Delegations: repos.Delegations, UserService: userService},
+		FX:             &FXHandler{FX: fxService},
+		Limit:          &LimitHandler{Limits: limitEngine, Guard: guard},
+		Fee:            &FeeHandler{Fees: feeEngine, Accounts: accountService, Guard: guard},
+		Interest:       &InterestHandler{Interest: interestService, Accounts: accountService, Guard: guard},
+		Schedule:       &ScheduleHandler{Schedules: scheduleService, Accounts: accountService, Guard: guard},
+		Authenticated:  AuthMiddleware(sessions),
+		Access:         &AccessControl{Users: userService, Permissions: authorizer},
+		Idempotency:    repos.Idempotency,
+		IdempotencyTTL: time.Hour,
+	})
+	return f
+}
+
+// Yol ve gövdedeki {owner}, {account}, {spare}, {payee}, {transaction}, {hold} ve {schedule} yer tutucularını doldurur
+func (f *ownershipFixture) expand(s string) string {
+	return strings.NewReplacer(
+		"{owner}", fmt.Sprint(f.owner.ID),
+		"{account}", fmt.Sprint(f.account.ID),
+		"{spare}", fmt.Sprint(f.spare.ID),
+		"{payee}", fmt.Sprint(f.payee.ID),
+		"{transaction}", fmt.Sprint(f.incoming.ID),
+		"{hold}", fmt.Sprint(f.hold.ID),
+		"{schedule}", fmt.Sprint(f.order.ID),
+	).Replace(s)
+}
+
+// İsteği caller'ın tokenıyla gönderir; yol ve gövdedeki yer tutucular doldurulur
+func (f *ownershipFixture) do(caller, method, path, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/api/v1"+f.expand(path), strings.NewReader(f.expand(body)))
+	r.Header.Set("Authorization", "Bearer "+f.tokens[caller])
+	if body != "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, r)
+	return w
+}
+
+// Okuma uçlarında read yetki devri yeterlidir, yazma uçlarında write gerekir
+var (
+	readAccess  = map[string]int{callerOwner: 200, callerDelegate: 200, callerReader: 200, callerOther: 403, callerAdmin: 200}
+	writeAccess = map[string]int{callerOwner: 200, callerDelegate: 200, callerReader: 403, callerOther: 403, callerAdmin: 200}
+	created     = map[string]int{callerOwner: 201, callerDelegate: 201, callerReader: 403, callerOther: 403, callerAdmin: 201}
+	adminOnly   = map[string]int{callerOwner: 403, callerDelegate: 403, callerReader: 403, callerOther: 403, callerAdmin: 200}
+	notFound    = map[string]int{callerOwner: 404, callerDelegate: 404, callerReader: 404, callerOther: 404, callerAdmin: 404}
+)
+
+// Sahiplik kontrolü yapan her uç, sahip, yetki devri alanlar, ilgisiz kullanıcı ve admin için denenir
+func TestOwnershipGuardedRoutes(t *testing.T) {
+	routes := []struct {
+		method, path, body string
+		want               map[string]int
+	}{
+		{"GET", "/users/{owner}", "", readAccess},
+		{"PUT", "/users/{owner}", `{}`, writeAccess},
+		{"DELETE", "/users/{owner}", "", adminOnly},
+
+		{"POST", "/accounts", `{"owner_id":{owner},"type":"savings","currency":"EUR"}`, created},
+		{"GET", "/accounts?user_id={owner}", "", readAccess},
+		{"GET", "/accounts/{account}", "", readAccess},
+		{"PUT", "/accounts/{account}", `{"nickname":"maaş"}`, writeAccess},
+		{"DELETE", "/accounts/{spare}", "", writeAccess},
+		{"GET", "/accounts/999999", "", notFound},
+
+		{"POST", "/transactions/credit", `{"account_id":{account},"amount":"10.00"}`, writeAccess},
+		{"POST", "/transactions/debit", `{"account_id":{account},"amount":"10.00"}`, writeAccess},
+		{"POST", "/transactions/debit", `{"user_id":{owner},"amount":"10.00"}`, writeAccess},
+		{"POST", "/transactions/transfer", `{"from_account_id":{account},"to_user_id":{payee},"amount":"10.00"}`, writeAccess},
+		{"POST", "/transactions/{transaction}/refund", `{"amount":"10.00"}`, created},
+		{"GET", "/transactions/history?user_id={owner}", "", readAccess},
+		{"GET", "/transactions/{transaction}", "", readAccess},
+		{"GET", "/transactions/999999", "", notFound},
+
+		{"POST", "/holds", `{"account_id":{account},"amount":"10.00"}`, created},
+		{"POST", "/holds/{hold}/capture", "", writeAccess},
+		{"POST", "/holds/{hold}/void", "", writeAccess},
+		{"GET", "/holds?account_id={account}", "", readAccess},
+		{"GET", "/holds?user_id={owner}", "", readAccess},
+		{"GET", "/holds/{hold}", "", readAccess},
+		{"GET", "/holds/999999", "", notFound},
+
+		{"GET", "/limits?user_id={owner}", "", readAccess},
+		{"POST", "/fees/preview", `{"operation":"withdraw","account_id":{account},"amount":"10.00"}`, readAccess},
+		{"GET", "/interest/accruals?account_id={account}", "", readAccess},
+
+		{"POST", "/schedules", `{"from_account_id":{account},"to_user_id":{payee},"amount":"10.00","frequency":"once","start_at":"2099-01-01T00:00:00Z"}`, created},
+		{"GET", "/schedules?user_id={owner}", "", readAccess},
+		{"GET", "/schedules/{schedule}", "", readAccess},
+		{"PUT", "/schedules/{schedule}", `{"description":"kira"}`, writeAccess},
+		{"DELETE", "/schedules/{schedule}", "", writeAccess},
+		{"GET", "/schedules/{schedule}/runs", "", readAccess},
+		{"GET", "/schedules/999999", "", notFound},
+
+		{"GET", "/balances/current?account_id={account}", "", readAccess},
+		{"GET", "/balances/current?user_id={owner}", "", readAccess},
+		{"GET", "/balances/historical?account_id={account}", "", readAccess},
+		{"GET", "/balances/at-time?account_id={account}&timestamp=2099-01-01T00:00:00Z", "", readAccess},
+		{"GET", "/balances/calculate?user_id={owner}", "", readAccess},
+		{"GET", "/balances/calculate?account_id={account}", "", readAccess},
+	}
+	for _, route := range routes {
+		for _, caller := range ownershipCallers {
+			t.Run(route.method+" "+route.path+"/"+caller, func(t *testing.T) {
+				w := newOwnershipFixture(t).do(caller, route.method, route.path, route.body)
+				if want := route.want[caller]; w.Code != want {
+					t.Fatalf("durum %d, beklenen %d: %s", w.Code, want, w.Body.String())
+				}
+			})
+		}
+	}
+}
+
+// Geri alma sadece transactions:reverse yetkisi ister; işlem sahibi olmak veya transactions:write yetkisi yetmez
+func TestReverseRequiresOnlyReversePermission(t *testing.T) {
+	cases := []struct {
+		caller, method, path, body string
+		want                       int
+	}{
+		{callerSupport, "POST", "/transactions/{transaction}/reverse", "", 201},
+		{callerAdmin, "POST", "/transactions/{transaction}/reverse", "", 201},
+		{callerOwner, "POST", "/transactions/{transaction}/reverse", "", 403},
+		{callerSupport, "POST", "/transactions/credit", `{"account_id":{account},"amount":"10.00"}`, 403},
+	}
+	for _, c := range cases {
+		if w := newOwnershipFixture(t).do(c.caller, c.method, c.path, c.body); w.Code != c.want {
+			t.Errorf("%s %s %s: durum %d, beklenen %d: %s", c.caller, c.method, c.path, w.Code, c.want, w.Body.String())
+		}
+	}
+
+	// Geri alma uç noktası hâlâ Idempotency-Key ile korunur: tekrar gelen istek ilk yanıtı alır
+	f := newOwnershipFixture(t)
+	var bodies []string
+	for i := 0; i < 2; i++ {
+		r := httptest.NewRequest("POST", "/api/v1"+f.expand("/transactions/{transaction}/reverse"), nil)
+		r.Header.Set("Authorization", "Bearer "+f.tokens[callerSupport])
+		r.Header.Set(IdempotencyKeyHeader, "reverse-1")
+		w := httptest.NewRecorder()
+		f.router.ServeHTTP(w, r)
+		if w.Code != 201 {
+			t.Fatalf("%d. geri alma: durum %d: %s", i+1, w.Code, w.Body.String())
+		}
+		bodies = append(bodies, w.Body.String())
+	}
+	if bodies[0] != bodies[1] {
+		t.Fatalf("tekrar gelen geri alma farklı yanıt aldı: %s, %s", bodies[0], bodies[1])
+	}
+}
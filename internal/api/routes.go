@@ -0,0 +1,142 @@
+package api
+
+import (
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/domain"
+	"net/http"
+	"time"
+)
+
+// Handlers, /api/v1 route'larına bağlanan handler'ları ve route'ların ortak bağımlılıklarını tutar
+type Handlers struct {
+	Auth        *AuthHandler
+	User        *UserHandler
+	Transaction *TransactionHandler
+	Balance     *BalanceHandler
+	Account     *AccountHandler
+	Hold        *HoldHandler
+	Delegation  *DelegationHandler
+	FX          *FXHandler
+	Limit       *LimitHandler
+	Fee         *FeeHandler
+	Interest    *InterestHandler
+	Schedule    *ScheduleHandler
+
+	Authenticated  Middleware     // Geçerli access token ister (AuthMiddleware)
+	Access         *AccessControl // Rol/yetki kontrolleri
+	Idempotency    domain.IdempotencyRepository
+	IdempotencyTTL time.Duration
+}
+
+// RegisterRoutes, API endpointlerini router'a /api/v1 altında ekler.
+// Global middleware'ler (loglama, rate limit vb.) çağıran tarafından router'a eklenir.
+func RegisterRoutes(router *Router, h *Handlers) {
+	can := h.Access.Require
+	idempotent := IdempotencyMiddleware(h.Idempotency, h.IdempotencyTTL)
+
+	v1 := router.Group("/api/v1")
+
+	// Health endpoint
+	v1.Handle("GET", "/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+
+	// Auth endpointleri (auth middleware yok)
+	v1.Handle("POST", "/auth/register", h.Auth.Register)
+	v1.Handle("POST", "/auth/login", h.Auth.Login)
+	v1.Handle("POST", "/auth/refresh", h.Auth.Refresh)
+	v1.Handle("POST", "/auth/logout", h.Auth.Logout)
+
+	// Bu gruptaki tüm endpointler geçerli bir access token gerektirir
+	secured := v1.Group("", h.Authenticated)
+
+	// Admin: kullanıcının tüm oturumlarını kapat
+	secured.Handle("POST", "/admin/sessions/revoke", can(auth.PermSessionsRevoke)(h.Auth.RevokeUserSessions))
+
+	// User Management endpointleri (yetki gerekli)
+	users := secured.Group("/users")
+	users.Handle("GET", "", can(auth.PermUsersReadAny)(h.User.ListUsers))
+	users.Handle("GET", "/{id}", can(auth.PermUsersRead)(h.User.GetUser))
+	users.Handle("PUT", "/{id}", can(auth.PermUsersWrite)(h.User.UpdateUser))
+	users.Handle("DELETE", "/{id}", can(auth.PermUsersDelete)(h.User.DeleteUser))
+
+	// Yetki devri endpointleri (hesap sahibi principal'dır)
+	secured.Handle("POST", "/delegations", can()(h.Delegation.Create))
+	secured.Handle("GET", "/delegations", can()(h.Delegation.List))
+	secured.Handle("DELETE", "/delegations/{id}", can()(h.Delegation.Delete))
+
+	// Hesap endpointleri: kullanıcı başına birden fazla hesap ve alt hesap
+	accounts := secured.Group("/accounts")
+	accounts.Handle("POST", "", can(auth.PermAccountsWrite)(h.Account.Create))
+	accounts.Handle("GET", "", can(auth.PermAccountsRead)(h.Account.List))
+	accounts.Handle("GET", "/{id}", can(auth.PermAccountsRead)(h.Account.Get))
+	accounts.Handle("PUT", "/{id}", can(auth.PermAccountsWrite)(h.Account.Update))
+	accounts.Handle("DELETE", "/{id}", can(auth.PermAccountsWrite)(h.Account.Close))
+
+	// Transaction endpointleri (yetki gerekli)
+	// Para hareketi yapan endpointler Idempotency-Key ile tekrar denemeye karşı korunur
+	transactions := secured.Group("/transactions")
+	moneyMovement := transactions.Group("", can(auth.PermTransactionsWrite), idempotent)
+	moneyMovement.Handle("POST", "/credit", h.Transaction.Credit)
+	moneyMovement.Handle("POST", "/debit", h.Transaction.Debit)
+	moneyMovement.Handle("POST", "/transfer", h.Transaction.Transfer)
+	moneyMovement.Handle("POST", "/{id}/refund", h.Transaction.Refund)
+	// Geri alma operasyon yetkisidir; transactions:write gerektirmez
+	reversal := transactions.Group("", can(auth.PermTransactionsReverse), idempotent)
+	reversal.Handle("POST", "/{id}/reverse", h.Transaction.Reverse)
+	transactions.Handle("GET", "/history", can(auth.PermTransactionsRead)(h.Transaction.GetHistory))
+	transactions.Handle("GET", "/{id}", can(auth.PermTransactionsRead)(h.Transaction.GetTransaction))
+
+	// Provizyon endpointleri: kullanılabilir bakiyeden tutar ayırma, tahsil ve iptal
+	holds := secured.Group("/holds")
+	holdMovement := holds.Group("", can(auth.PermHoldsWrite), idempotent)
+	holdMovement.Handle("POST", "", h.Hold.Place)
+	holdMovement.Handle("POST", "/{id}/capture", h.Hold.Capture)
+	holdMovement.Handle("POST", "/{id}/void", h.Hold.Void)
+	holds.Handle("GET", "", can(auth.PermHoldsRead)(h.Hold.List))
+	holds.Handle("GET", "/{id}", can(auth.PermHoldsRead)(h.Hold.Get))
+
+	// Limit endpointleri: kullanıcının kalan limitleri ve admin override'ları
+	secured.Handle("GET", "/limits", can(auth.PermLimitsRead)(h.Limit.GetLimits))
+	secured.Handle("GET", "/admin/limits/overrides", can(auth.PermLimitsWrite)(h.Limit.ListOverrides))
+	secured.Handle("PUT", "/admin/limits/overrides/{scope}/{subject}", can(auth.PermLimitsWrite)(h.Limit.SetOverride))
+	secured.Handle("DELETE", "/admin/limits/overrides/{scope}/{subject}", can(auth.PermLimitsWrite)(h.Limit.DeleteOverride))
+
+	// Ücret endpointleri: tarife, para hareketi öncesi ücret ön izlemesi ve admin tarife yönetimi
+	secured.Handle("GET", "/fees", can(auth.PermFeesRead)(h.Fee.GetSchedule))
+	secured.Handle("POST", "/fees/preview", can(auth.PermFeesRead)(h.Fee.Preview))
+	secured.Handle("PUT", "/admin/fees", can(auth.PermFeesWrite)(h.Fee.SetRules))
+
+	// Faiz endpointleri: hesabın günlük tahakkukları ve admin oran yönetimi
+	secured.Handle("GET", "/interest/accruals", can(auth.PermInterestRead)(h.Interest.ListAccruals))
+	secured.Handle("GET", "/admin/interest/rates", can(auth.PermInterestWrite)(h.Interest.ListRates))
+	secured.Handle("POST", "/admin/interest/rates", can(auth.PermInterestWrite)(h.Interest.SetRate))
+
+	// Zamanlanmış transfer endpointleri: ileri tarihli ve düzenli transfer talimatları, çalıştırma geçmişi
+	schedules := secured.Group("/schedules")
+	schedules.Group("", can(auth.PermSchedulesWrite), idempotent).
+		Handle("POST", "", h.Schedule.Create)
+	schedules.Handle("GET", "", can(auth.PermSchedulesRead)(h.Schedule.List))
+	schedules.Handle("GET", "/{id}", can(auth.PermSchedulesRead)(h.Schedule.Get))
+	schedules.Handle("PUT", "/{id}", can(auth.PermSchedulesWrite)(h.Schedule.Update))
+	schedules.Handle("DELETE", "/{id}", can(auth.PermSchedulesWrite)(h.Schedule.Cancel))
+	schedules.Handle("GET", "/{id}/runs", can(auth.PermSchedulesRead)(h.Schedule.Runs))
+
+	// Balance endpointleri (yetki gerekli)
+	balances := secured.Group("/balances", can(auth.PermBalancesRead))
+	balances.Handle("GET", "/current", h.Balance.GetCurrentBalance)
+	balances.Handle("GET", "/historical", h.Balance.GetBalanceHistory)
+	balances.Handle("GET", "/at-time", h.Balance.GetBalanceAtTime)
+	balances.Handle("GET", "/calculate", h.Balance.CalculateBalance)
+
+	// Admin: hesabın kredi limiti (overdraft); kullanılabilir kredi bakiye yanıtlarında döner
+	secured.Handle("PUT", "/admin/accounts/{id}/overdraft", can(auth.PermOverdraftWrite)(h.Balance.SetOverdraftLimit))
+
+	// Döviz endpointleri: kur tabloları ve kilitli kur teklifleri
+	fxRoutes := secured.Group("/fx")
+	fxRoutes.Handle("GET", "/rates", can(auth.PermFXRead)(h.FX.GetRates))
+	fxRoutes.Handle("GET", "/rates/versions", can(auth.PermFXRead)(h.FX.ListRateVersions))
+	fxRoutes.Handle("POST", "/rates", can(auth.PermFXRatesWrite)(h.FX.PublishRates))
+	fxRoutes.Handle("POST", "/quote", can(auth.PermFXQuote)(h.FX.Quote))
+}
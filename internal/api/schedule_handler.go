@@ -0,0 +1,204 @@
+package api
+
+import (
+	"encoding/json"
+	"gofinancialsystem/internal/auth"
+	"gofinancialsystem/internal/domain"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ScheduleHandler, zamanlanmış ve düzenli transfer talimatlarını yönetir
+type ScheduleHandler struct {
+	Schedules domain.ScheduleService
+	Accounts  domain.AccountService
+	Guard     *OwnershipGuard
+}
+
+// Yeni bir transfer talimatı oluşturur (POST /api/v1/schedules)
+// Taraflar transferdeki gibi hesap ID'siyle ya da kullanıcı ID'siyle (varsayılan hesap) belirtilir.
+// frequency once, weekly, monthly veya month_end olabilir; monthly için day_of_month (1-31) zorunludur,
+// ay daha kısaysa ayın son günü kullanılır. start_at verilmezse ilk çalıştırma hemen yapılır.
+func (h *ScheduleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthorized)
+		return
+	}
+
+	var req struct {
+		FromAccountID int64                    `json:"from_account_id"`
+		FromUserID    int64                    `json:"from_user_id"`
+		ToAccountID   int64                    `json:"to_account_id"`
+		ToUserID      int64                    `json:"to_user_id"`
+		Amount        domain.Money             `json:"amount"`
+		Frequency     domain.ScheduleFrequency `json:"frequency"`
+		DayOfMonth    int                      `json:"day_of_month"`
+		StartAt       time.Time                `json:"start_at"`
+		EndDate       *time.Time               `json:"end_date"`
+		domain.TransactionDetails
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, decodeError(err))
+		return
+	}
+
+	from, ok := resolveAccount(w, r, h.Accounts, h.Guard, accountRef{AccountID: req.FromAccountID, UserID: req.FromUserID, Currency: req.Amount.Currency},
+		false, domain.DelegationWrite, auth.PermSchedulesWriteAny)
+	if !ok {
+		return
+	}
+	to, err := lookupAccount(h.Accounts, accountRef{AccountID: req.ToAccountID, UserID: req.ToUserID, Currency: req.Amount.Currency}, true)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	order := &domain.ScheduledTransfer{
+		CreatedBy:          principal.UserID,
+		FromAccountID:      from.ID,
+		ToAccountID:        to.ID,
+		Amount:             req.Amount,
+		Frequency:          req.Frequency,
+		DayOfMonth:         req.DayOfMonth,
+		EndDate:            req.EndDate,
+		TransactionDetails: req.TransactionDetails,
+	}
+	if err := h.Schedules.Create(order, req.StartAt); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(order)
+}
+
+// Kullanıcının gönderen olduğu talimatları listeler (GET /api/v1/schedules?user_id=)
+// user_id verilmezse principal'ın talimatları döner.
+func (h *ScheduleHandler) List(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthorized)
+		return
+	}
+
+	userID := principal.UserID
+	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
+		id, err := strconv.ParseInt(userIDStr, 10, 64)
+		if err != nil {
+			writeError(w, r, errInvalidRequest.WithMessage("invalid_user_id", "geçersiz kullanıcı ID"))
+			return
+		}
+		userID = id
+	}
+	if !h.Guard.authorize(w, r, domain.DelegationRead, auth.PermSchedulesReadAny, userID) {
+		return
+	}
+
+	schedules, err := h.Schedules.ListByOwner(userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if schedules == nil {
+		schedules = []*domain.ScheduledTransfer{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(schedules)
+}
+
+// Belirli bir talimatı getirir (GET /api/v1/schedules/{id})
+func (h *ScheduleHandler) Get(w http.ResponseWriter, r *http.Request) {
+	order, ok := h.scheduleFromPath(w, r, domain.DelegationRead, auth.PermSchedulesReadAny)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(order)
+}
+
+// Talimatı günceller (PUT /api/v1/schedules/{id})
+// Gövdede verilen alanlar değişir: amount, description, status (active veya paused), start_at ve end_date.
+// Durdurulmuş talimat tekrar etkinleştirildiğinde arada kaçırılan çalıştırmalar yapılmaz.
+func (h *ScheduleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	order, ok := h.scheduleFromPath(w, r, domain.DelegationWrite, auth.PermSchedulesWriteAny)
+	if !ok {
+		return
+	}
+
+	var update domain.ScheduleUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, r, decodeError(err))
+		return
+	}
+
+	updated, err := h.Schedules.Update(order.ID, update)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(updated)
+}
+
+// Talimatı iptal eder (DELETE /api/v1/schedules/{id}); çalıştırma geçmişi korunur
+func (h *ScheduleHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	order, ok := h.scheduleFromPath(w, r, domain.DelegationWrite, auth.PermSchedulesWriteAny)
+	if !ok {
+		return
+	}
+
+	cancelled, err := h.Schedules.Cancel(order.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(cancelled)
+}
+
+// Talimatın çalıştırma geçmişini listeler (GET /api/v1/schedules/{id}/runs)
+func (h *ScheduleHandler) Runs(w http.ResponseWriter, r *http.Request) {
+	order, ok := h.scheduleFromPath(w, r, domain.DelegationRead, auth.PermSchedulesReadAny)
+	if !ok {
+		return
+	}
+
+	runs, err := h.Schedules.Runs(order.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(runs)
+}
+
+// Yoldaki talimatı getirir ve principal'ın talimat sahibine scope kapsamında erişimini kontrol eder
+func (h *ScheduleHandler) scheduleFromPath(w http.ResponseWriter, r *http.Request, scope domain.DelegationScope, anyPerm auth.Permission) (*domain.ScheduledTransfer, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, r, errInvalidRequest.WithMessage("invalid_schedule_id", "geçersiz talimat ID"))
+		return nil, false
+	}
+	order, err := h.Schedules.GetByID(id)
+	if err != nil {
+		writeError(w, r, err)
+		return nil, false
+	}
+	if !h.Guard.authorize(w, r, scope, anyPerm, order.OwnerID) {
+		return nil, false
+	}
+	return order, true
+}
@@ -2,25 +2,47 @@ package api
 
 import (
 	"fmt"
+	"gofinancialsystem/internal/domain"
 	"log"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
 )
 
 // HandlerFunc, custom router için handler fonksiyon tipidir
 type HandlerFunc func(w http.ResponseWriter, r *http.Request)
 
+// Middleware, bir handler'ı saran fonksiyon tipidir
+type Middleware func(HandlerFunc) HandlerFunc
+
 // Route, bir endpoint ve ona karşılık gelen handler'ı tutar
 type Route struct {
 	Method  string
 	Path    string
 	Handler HandlerFunc
+	group   *Group
 }
 
-// Router, custom router yapısıdır
-// Route'ları ve middleware'leri tutar
+// Router, trie tabanlı custom router yapısıdır.
+// "/api/v1/users/{id}" gibi path parametrelerini destekler; parametre değerleri
+// handler içinde r.PathValue("id") ile okunur. Statik segmentler parametrelere göre önceliklidir.
 type Router struct {
 	routes     []Route
-	middleware []func(HandlerFunc) HandlerFunc
+	middleware []Middleware
+	root       *node
+	notFound   HandlerFunc
+	once       sync.Once
+}
+
+// node, trie'nin bir path segmentini temsil eder
+type node struct {
+	static    map[string]*node
+	param     *node
+	paramName string
+	handlers  map[string]HandlerFunc // Method -> middleware zinciri uygulanmış handler
+	// Path eşleşip method eşleşmediğinde Allow başlığıyla 405 döner
+	methodNotAllowed HandlerFunc
 }
 
 // Yeni bir Router oluşturur
@@ -30,29 +52,204 @@ func NewRouter() *Router {
 
 // Route ekler
 func (r *Router) Handle(method, path string, handler HandlerFunc) {
-	r.routes = append(r.routes, Route{Method: method, Path: path, Handler: handler})
+	r.handle(method, path, handler, nil)
 }
 
-// Middleware ekler
-func (r *Router) Use(mw func(HandlerFunc) HandlerFunc) {
+// Tüm route'lara uygulanacak middleware ekler
+func (r *Router) Use(mw Middleware) {
+	r.mustNotBeCompiled()
 	r.middleware = append(r.middleware, mw)
 }
 
+// Ortak prefix ve middleware'leri paylaşan bir route grubu oluşturur
+func (r *Router) Group(prefix string, mws ...Middleware) *Group {
+	return newGroup(r, nil, prefix, mws)
+}
+
+func (r *Router) handle(method, path string, handler HandlerFunc, group *Group) {
+	r.mustNotBeCompiled()
+	r.routes = append(r.routes, Route{Method: strings.ToUpper(method), Path: path, Handler: handler, group: group})
+}
+
+func (r *Router) mustNotBeCompiled() {
+	if r.root != nil {
+		panic("router: sunucu başladıktan sonra route veya middleware eklenemez")
+	}
+}
+
+// Route ağacını kurar ve middleware zincirlerini her route için bir kez hesaplar
+func (r *Router) compile() {
+	root := &node{}
+	for _, route := range r.routes {
+		h := route.Handler
+		for g := route.group; g != nil; g = g.parent {
+			h = chain(h, g.middleware)
+		}
+		root.insert(route.Method, route.Path, chain(h, r.middleware))
+	}
+	root.finalize(r.middleware)
+	r.notFound = chain(func(w http.ResponseWriter, req *http.Request) {
+		writeError(w, req, domain.NewError(domain.CodeRouteNotFound, "endpoint bulunamadı"))
+	}, r.middleware)
+	r.root = root
+}
+
 // HTTP isteklerini karşılar
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	for _, route := range r.routes {
-		if req.Method == route.Method && req.URL.Path == route.Path {
-			h := route.Handler
-			// Middleware zincirini uygula
-			for i := len(r.middleware) - 1; i >= 0; i-- {
-				h = r.middleware[i](h)
+	r.once.Do(r.compile)
+
+	var params []string
+	n := r.root.lookup(splitPath(req.URL.Path), &params)
+	if n == nil {
+		r.notFound(w, req)
+		return
+	}
+	for i := 0; i < len(params); i += 2 {
+		req.SetPathValue(params[i], params[i+1])
+	}
+	if h, ok := n.handlers[req.Method]; ok {
+		h(w, req)
+		return
+	}
+	if h, ok := n.handlers[http.MethodGet]; ok && req.Method == http.MethodHead {
+		h(w, req)
+		return
+	}
+	n.methodNotAllowed(w, req)
+}
+
+// Middleware'leri kayıt sırasına göre uygular (ilk eklenen en dışta çalışır)
+func chain(h HandlerFunc, mws []Middleware) HandlerFunc {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+func splitPath(path string) []string {
+	var segments []string
+	for _, s := range strings.Split(path, "/") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+func (n *node) insert(method, path string, h HandlerFunc) {
+	cur := n
+	for _, seg := range splitPath(path) {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			name := seg[1 : len(seg)-1]
+			if name == "" {
+				panic(fmt.Sprintf("router: %s içinde isimsiz parametre", path))
+			}
+			if cur.param == nil {
+				cur.param = &node{paramName: name}
+			} else if cur.param.paramName != name {
+				panic(fmt.Sprintf("router: %s içindeki {%s} parametresi mevcut {%s} ile çakışıyor", path, name, cur.param.paramName))
 			}
-			h(w, req)
-			return
+			cur = cur.param
+			continue
 		}
+		if cur.static == nil {
+			cur.static = make(map[string]*node)
+		}
+		child, ok := cur.static[seg]
+		if !ok {
+			child = &node{}
+			cur.static[seg] = child
+		}
+		cur = child
+	}
+	if cur.handlers == nil {
+		cur.handlers = make(map[string]HandlerFunc)
+	}
+	if _, exists := cur.handlers[method]; exists {
+		panic(fmt.Sprintf("router: %s %s birden fazla kez tanımlandı", method, path))
+	}
+	cur.handlers[method] = h
+}
+
+// Handler'ı olan her düğüm için Allow başlığını ve 405 handler'ını hazırlar
+func (n *node) finalize(global []Middleware) {
+	if len(n.handlers) > 0 {
+		methods := make([]string, 0, len(n.handlers)+1)
+		for m := range n.handlers {
+			methods = append(methods, m)
+		}
+		if _, ok := n.handlers[http.MethodGet]; ok {
+			if _, ok := n.handlers[http.MethodHead]; !ok {
+				methods = append(methods, http.MethodHead)
+			}
+		}
+		sort.Strings(methods)
+		allow := strings.Join(methods, ", ")
+		// 405 cevabı da global middleware'lerden geçer (ör: CORS preflight istekleri)
+		n.methodNotAllowed = chain(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Allow", allow)
+			writeError(w, r, domain.NewError(domain.CodeMethodNotAllowed, "bu endpoint için HTTP metodu desteklenmiyor").
+				WithDetails(map[string]interface{}{"allowed_methods": methods}))
+		}, global)
+	}
+	for _, child := range n.static {
+		child.finalize(global)
+	}
+	if n.param != nil {
+		n.param.finalize(global)
+	}
+}
+
+// Segmentlere karşılık gelen düğümü bulur; statik eşleşme başarısız olursa parametreli dal denenir
+func (n *node) lookup(segments []string, params *[]string) *node {
+	if len(segments) == 0 {
+		if len(n.handlers) == 0 {
+			return nil
+		}
+		return n
+	}
+	if child, ok := n.static[segments[0]]; ok {
+		if found := child.lookup(segments[1:], params); found != nil {
+			return found
+		}
+	}
+	if n.param != nil {
+		mark := len(*params)
+		*params = append(*params, n.param.paramName, segments[0])
+		if found := n.param.lookup(segments[1:], params); found != nil {
+			return found
+		}
+		*params = (*params)[:mark]
 	}
-	w.WriteHeader(http.StatusNotFound)
-	fmt.Fprint(w, "404 Not Found")
+	return nil
+}
+
+// Group, ortak path prefix'i ve middleware'leri olan route kümesidir
+type Group struct {
+	router     *Router
+	parent     *Group
+	prefix     string
+	middleware []Middleware
+}
+
+func newGroup(r *Router, parent *Group, prefix string, mws []Middleware) *Group {
+	return &Group{router: r, parent: parent, prefix: strings.TrimSuffix(prefix, "/"), middleware: mws}
+}
+
+// Gruba route ekler; path grubun prefix'ine eklenir
+func (g *Group) Handle(method, path string, handler HandlerFunc) {
+	g.router.handle(method, g.prefix+path, handler, g)
+}
+
+// Sadece bu gruptaki (ve alt gruplardaki) route'lara uygulanacak middleware ekler
+func (g *Group) Use(mw Middleware) {
+	g.router.mustNotBeCompiled()
+	g.middleware = append(g.middleware, mw)
+}
+
+// Alt grup oluşturur; alt grubun middleware'leri üst grubunkilerden sonra çalışır
+func (g *Group) Group(prefix string, mws ...Middleware) *Group {
+	return newGroup(g.router, g, g.prefix+prefix, mws)
 }
 
 // Server, HTTP sunucusunu başlatır
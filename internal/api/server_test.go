@@ -0,0 +1,210 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// named, yanıta handler adını ve path parametresini yazan handler'dır
+func named(name string) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(name + ":" + r.PathValue("id")))
+	}
+}
+
+func serve(router *Router, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestRouterMethodNotAllowed(t *testing.T) {
+	router := NewRouter()
+	router.Handle("GET", "/items/{id}", named("get"))
+	router.Handle("post", "/items/{id}", named("post"))
+	router.Handle("GET", "/feed", named("feed"))
+	router.Handle("HEAD", "/feed", named("head"))
+	router.Handle("DELETE", "/jobs", named("delete"))
+
+	cases := []struct {
+		method, path, allow string
+	}{
+		// GET tanımlıysa HEAD de izinlidir; metotlar sıralı ve tekrarsız listelenir
+		{"DELETE", "/items/1", "GET, HEAD, POST"},
+		{"PUT", "/feed", "GET, HEAD"},
+		{"GET", "/jobs", "DELETE"},
+		{"HEAD", "/jobs", "DELETE"},
+	}
+	for _, c := range cases {
+		w := serve(router, c.method, c.path)
+		if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != c.allow {
+			t.Errorf("%s %s: %d, Allow %q; beklenen 405, %q", c.method, c.path, w.Code, w.Header().Get("Allow"), c.allow)
+			continue
+		}
+		var resp struct {
+			Code    string
+			Details struct {
+				AllowedMethods []string `json:"allowed_methods"`
+			}
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Code != "method_not_allowed" ||
+			strings.Join(resp.Details.AllowedMethods, ", ") != c.allow {
+			t.Errorf("%s %s: gövde %s, %v", c.method, c.path, w.Body.String(), err)
+		}
+	}
+
+	if w := serve(router, "GET", "/unknown"); w.Code != http.StatusNotFound || w.Header().Get("Allow") != "" {
+		t.Errorf("olmayan yol: %d, Allow %q; beklenen 404", w.Code, w.Header().Get("Allow"))
+	}
+	// Ara düğümün handler'ı yoksa yol bulunamaz, 405 dönmez
+	if w := serve(router, "GET", "/items"); w.Code != http.StatusNotFound {
+		t.Errorf("handler'ı olmayan ara yol: %d, beklenen 404", w.Code)
+	}
+}
+
+func TestRouterStaticSegmentsWin(t *testing.T) {
+	router := NewRouter()
+	router.Handle("GET", "/users/{id}", named("user"))
+	router.Handle("GET", "/users/me", named("me"))
+	router.Handle("GET", "/files/{id}/raw", named("raw"))
+	router.Handle("GET", "/files/latest/meta", named("meta"))
+
+	cases := []struct {
+		path, want string
+	}{
+		{"/users/me", "me:"},
+		{"/users/42", "user:42"},
+		{"/users/me/", "me:"},
+		{"/files/latest/meta", "meta:"},
+		// Statik dal sonuna kadar eşleşmezse parametreli dala dönülür
+		{"/files/latest/raw", "raw:latest"},
+		{"/files/7/raw", "raw:7"},
+	}
+	for _, c := range cases {
+		if w := serve(router, "GET", c.path); w.Code != http.StatusOK || w.Body.String() != c.want {
+			t.Errorf("%s: %d %q, beklenen %q", c.path, w.Code, w.Body.String(), c.want)
+		}
+	}
+	if w := serve(router, "GET", "/files/7/meta"); w.Code != http.StatusNotFound {
+		t.Errorf("/files/7/meta: %d, beklenen 404", w.Code)
+	}
+}
+
+func TestRouterHeadFallsBackToGet(t *testing.T) {
+	router := NewRouter()
+	router.Handle("GET", "/items/{id}", named("get"))
+	router.Handle("GET", "/feed", named("get"))
+	router.Handle("HEAD", "/feed", named("head"))
+
+	if w := serve(router, "HEAD", "/items/5"); w.Code != http.StatusOK || w.Body.String() != "get:5" {
+		t.Errorf("HEAD /items/5: %d %q, beklenen GET handler'ı", w.Code, w.Body.String())
+	}
+	// Açıkça tanımlanmış HEAD handler'ı GET'e tercih edilir
+	if w := serve(router, "HEAD", "/feed"); w.Body.String() != "head:" {
+		t.Errorf("HEAD /feed: %q, beklenen HEAD handler'ı", w.Body.String())
+	}
+}
+
+func TestRouterMiddlewareOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name)
+				next(w, r)
+			}
+		}
+	}
+	router := NewRouter()
+	router.Use(record("global1"))
+	api := router.Group("/api", record("group"))
+	admin := api.Group("/admin/", record("subgroup"))
+	router.Use(record("global2"))
+	api.Use(record("group-use"))
+	admin.Handle("GET", "/stats", func(w http.ResponseWriter, r *http.Request) { calls = append(calls, "handler") })
+	api.Handle("GET", "/ping", func(w http.ResponseWriter, r *http.Request) { calls = append(calls, "handler") })
+	router.Handle("GET", "/health", func(w http.ResponseWriter, r *http.Request) { calls = append(calls, "handler") })
+
+	cases := []struct {
+		path string
+		want []string
+	}{
+		// Global middleware'ler en dışta, sonra üst grup, sonra alt grup; her seviyede kayıt sırası korunur
+		{"/api/admin/stats", []string{"global1", "global2", "group", "group-use", "subgroup", "handler"}},
+		{"/api/ping", []string{"global1", "global2", "group", "group-use", "handler"}},
+		{"/health", []string{"global1", "global2", "handler"}},
+		// 404 ve 405 cevapları da global middleware'lerden geçer
+		{"/missing", []string{"global1", "global2"}},
+	}
+	for _, c := range cases {
+		calls = nil
+		serve(router, "GET", c.path)
+		if !reflect.DeepEqual(calls, c.want) {
+			t.Errorf("%s: %v, beklenen %v", c.path, calls, c.want)
+		}
+	}
+	calls = nil
+	if w := serve(router, "POST", "/api/ping"); w.Code != http.StatusMethodNotAllowed || !reflect.DeepEqual(calls, []string{"global1", "global2"}) {
+		t.Errorf("POST /api/ping: %d, %v", w.Code, calls)
+	}
+}
+
+func TestRouterPanicsAfterCompile(t *testing.T) {
+	router := NewRouter()
+	group := router.Group("/api")
+	group.Handle("GET", "/ping", named("ping"))
+	serve(router, "GET", "/api/ping")
+
+	cases := []struct {
+		name string
+		add  func()
+	}{
+		{"Router.Handle", func() { router.Handle("GET", "/late", named("late")) }},
+		{"Router.Use", func() { router.Use(func(h HandlerFunc) HandlerFunc { return h }) }},
+		{"Group.Handle", func() { group.Handle("GET", "/late", named("late")) }},
+		{"Group.Use", func() { group.Use(func(h HandlerFunc) HandlerFunc { return h }) }},
+		{"alt grup", func() { group.Group("/v2").Handle("GET", "/late", named("late")) }},
+	}
+	for _, c := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: derlemeden sonra ekleme panic vermeli", c.name)
+				}
+			}()
+			c.add()
+		}()
+	}
+	if w := serve(router, "GET", "/late"); w.Code != http.StatusNotFound {
+		t.Errorf("geç eklenen route: %d, beklenen 404", w.Code)
+	}
+}
+
+func TestRouterRejectsConflictingRoutes(t *testing.T) {
+	cases := []struct {
+		name   string
+		routes [][2]string
+	}{
+		{"aynı route iki kez", [][2]string{{"GET", "/items/{id}"}, {"GET", "/items/{id}"}}},
+		{"farklı parametre adı", [][2]string{{"GET", "/items/{id}"}, {"POST", "/items/{key}"}}},
+		{"isimsiz parametre", [][2]string{{"GET", "/items/{}"}}},
+	}
+	for _, c := range cases {
+		router := NewRouter()
+		for _, route := range c.routes {
+			router.Handle(route[0], route[1], named("x"))
+		}
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: derleme panic vermeli", c.name)
+				}
+			}()
+			serve(router, "GET", "/items/1")
+		}()
+	}
+}
@@ -2,136 +2,410 @@ package api
 
 import (
 	"encoding/json"
-	"fmt"
+	"gofinancialsystem/internal/auth"
 	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/fx"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // TransactionHandler, transaction işlemleri için servisleri tutar
 type TransactionHandler struct {
 	TransactionService domain.TransactionService
 	BalanceService     domain.BalanceService
+	Accounts           domain.AccountService
+	Guard              *OwnershipGuard
+	FX                 *fx.Service // quote_id ile yapılan döviz çevirili transferler için
 }
 
 // Para yatırma işlemi (POST /api/v1/transactions/credit)
+// account_id yerine user_id verilirse tutarın para birimindeki varsayılan hesap kullanılır, yoksa açılır.
 func (h *TransactionHandler) Credit(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		UserID int64   `json:"user_id"`
-		Amount float64 `json:"amount"`
+		AccountID int64        `json:"account_id"`
+		UserID    int64        `json:"user_id"`
+		Amount    domain.Money `json:"amount"`
+		domain.TransactionDetails
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Geçersiz istek"))
+		writeError(w, r, decodeError(err))
 		return
 	}
 
-	if err := h.TransactionService.Credit(req.UserID, req.Amount); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Para yatırma başarısız: " + err.Error()))
+	account, ok := h.account(w, r, accountRef{AccountID: req.AccountID, UserID: req.UserID, Currency: req.Amount.Currency}, true)
+	if !ok {
+		return
+	}
+
+	if err := h.TransactionService.Credit(account.ID, req.Amount, req.TransactionDetails); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(fmt.Sprintf("Para yatırma başarılı: %.2f", req.Amount)))
+	w.Write([]byte(localize(r, "transaction.credit.succeeded", map[string]interface{}{"amount": req.Amount})))
 }
 
 // Para çekme işlemi (POST /api/v1/transactions/debit)
+// account_id yerine user_id verilirse tutarın para birimindeki varsayılan hesap kullanılır.
 func (h *TransactionHandler) Debit(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		UserID int64   `json:"user_id"`
-		Amount float64 `json:"amount"`
+		AccountID int64        `json:"account_id"`
+		UserID    int64        `json:"user_id"`
+		Amount    domain.Money `json:"amount"`
+		domain.TransactionDetails
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Geçersiz istek"))
+		writeError(w, r, decodeError(err))
+		return
+	}
+
+	account, ok := h.account(w, r, accountRef{AccountID: req.AccountID, UserID: req.UserID, Currency: req.Amount.Currency}, false)
+	if !ok {
 		return
 	}
 
-	if err := h.TransactionService.Debit(req.UserID, req.Amount); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Para çekme başarısız: " + err.Error()))
+	if err := h.TransactionService.Debit(account.ID, req.Amount, req.TransactionDetails); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(fmt.Sprintf("Para çekme başarılı: %.2f", req.Amount)))
+	w.Write([]byte(localize(r, "transaction.debit.succeeded", map[string]interface{}{"amount": req.Amount})))
+}
+
+type transferRequest struct {
+	FromAccountID int64        `json:"from_account_id"`
+	FromUserID    int64        `json:"from_user_id"`
+	ToAccountID   int64        `json:"to_account_id"`
+	ToUserID      int64        `json:"to_user_id"`
+	Amount        domain.Money `json:"amount"`
+	QuoteID       string       `json:"quote_id"`
+	domain.TransactionDetails
 }
 
 // Transfer işlemi (POST /api/v1/transactions/transfer)
+// Taraflar hesap ID'siyle ya da kullanıcı ID'siyle (varsayılan hesap) belirtilebilir.
+// quote_id verilirse transfer, principal'ın aldığı kilitli kur teklifiyle para birimi çevrilerek yapılır.
 func (h *TransactionHandler) Transfer(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		FromUserID int64   `json:"from_user_id"`
-		ToUserID   int64   `json:"to_user_id"`
-		Amount     float64 `json:"amount"`
+	var req transferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, decodeError(err))
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Geçersiz istek"))
+	if req.QuoteID != "" {
+		h.transferWithQuote(w, r, req)
+		return
+	}
+
+	from, to, ok := h.transferAccounts(w, r, req, req.Amount.Currency, req.Amount.Currency)
+	if !ok {
 		return
 	}
 
-	if err := h.TransactionService.Transfer(req.FromUserID, req.ToUserID, req.Amount); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Transfer başarısız: " + err.Error()))
+	if err := h.TransactionService.Transfer(from.ID, to.ID, req.Amount, req.TransactionDetails); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(fmt.Sprintf("Transfer başarılı: %.2f", req.Amount)))
+	w.Write([]byte(localize(r, "transaction.transfer.succeeded", map[string]interface{}{"amount": req.Amount})))
+}
+
+// Kur teklifini kullanarak çevirili transfer yapar; işlem başarısız olursa teklif tekrar kullanılabilir.
+// Gövdede tutar da gönderildiyse teklifteki satış tutarıyla aynı olmalıdır.
+func (h *TransactionHandler) transferWithQuote(w http.ResponseWriter, r *http.Request, req transferRequest) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthorized)
+		return
+	}
+
+	quote, err := h.FX.Redeem(req.QuoteID, principal.UserID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !req.Amount.IsZero() && req.Amount != quote.Sell {
+		h.FX.Release(req.QuoteID)
+		writeError(w, r, domain.NewValidationError("amount_differs_from_quote", "tutar kur teklifindeki tutarla aynı olmalı"))
+		return
+	}
+
+	from, to, ok := h.transferAccounts(w, r, req, quote.Sell.Currency, quote.Buy.Currency)
+	if !ok {
+		h.FX.Release(req.QuoteID)
+		return
+	}
+
+	if err := h.TransactionService.TransferWithConversion(from.ID, to.ID, quote.Sell, quote.Conversion(), req.TransactionDetails); err != nil {
+		h.FX.Release(req.QuoteID)
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(localize(r, "transaction.transfer.converted", map[string]interface{}{
+		"amount":    quote.Sell,
+		"converted": quote.Buy,
+		"rate":      quote.Rate,
+	})))
+}
+
+// Transferin kaynak ve hedef hesaplarını çözer. Kaynak hesapta yazma yetkisi aranır;
+// alıcı kullanıcı ID'siyle verildiyse ve hedef para biriminde hesabı yoksa varsayılan hesabı açılır.
+func (h *TransactionHandler) transferAccounts(w http.ResponseWriter, r *http.Request, req transferRequest, sellCurrency, buyCurrency string) (*domain.Account, *domain.Account, bool) {
+	from, ok := h.account(w, r, accountRef{AccountID: req.FromAccountID, UserID: req.FromUserID, Currency: sellCurrency}, false)
+	if !ok {
+		return nil, nil, false
+	}
+	to, err := lookupAccount(h.Accounts, accountRef{AccountID: req.ToAccountID, UserID: req.ToUserID, Currency: buyCurrency}, true)
+	if err != nil {
+		writeError(w, r, err)
+		return nil, nil, false
+	}
+	return from, to, true
+}
+
+// İşlem yapılacak hesabı çözer ve principal'ın hesap sahibi adına yazma yetkisini kontrol eder
+func (h *TransactionHandler) account(w http.ResponseWriter, r *http.Request, ref accountRef, create bool) (*domain.Account, bool) {
+	return resolveAccount(w, r, h.Accounts, h.Guard, ref, create, domain.DelegationWrite, auth.PermTransactionsWriteAny)
+}
+
+// İşlemin tamamını geri alır (POST /api/v1/transactions/{id}/reverse)
+// Orijinale bağlı yeni bir reversal işlemi oluşturur; aynı işlem ikinci kez geri alınamaz.
+func (h *TransactionHandler) Reverse(w http.ResponseWriter, r *http.Request) {
+	id, ok := transactionIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	reversal, err := h.TransactionService.Reverse(id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reversal)
+}
+
+// İşlemin bir kısmını veya tamamını iade eder (POST /api/v1/transactions/{id}/refund)
+// İadeyi orijinal işlemin alıcısı (veya onun adına yazma yetkisi olan) yapabilir; iadelerin toplamı orijinal tutarı aşamaz.
+func (h *TransactionHandler) Refund(w http.ResponseWriter, r *http.Request) {
+	id, ok := transactionIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Amount domain.Money `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, decodeError(err))
+		return
+	}
+
+	original, err := h.TransactionService.GetByID(id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	// İadeyi parayı geri ödeyecek taraf yapar; alıcısı olmayan işlemler (para çekme) sadece yöneticiler tarafından iade edilebilir
+	var payers []int64
+	if original.ToUserID != nil {
+		payers = append(payers, *original.ToUserID)
+	}
+	if !h.Guard.authorize(w, r, domain.DelegationWrite, auth.PermTransactionsWriteAny, payers...) {
+		return
+	}
+
+	refund, err := h.TransactionService.Refund(id, req.Amount)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(refund)
 }
 
 // Transaction geçmişi (GET /api/v1/transactions/history)
+// Filtreler: from, to (RFC3339), direction (in|out), type ve status (virgülle ayrılmış), currency, min_amount, max_amount,
+// counterparty_id, q (açıklamada geçen metin), external_reference, tag (virgülle ayrılmış, hepsi bulunmalı),
+// metadata=anahtar:değer (tekrarlanabilir); sort=asc|desc (varsayılan desc), limit ve önceki sayfanın next_cursor değeri cursor.
 func (h *TransactionHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 	userIDStr := r.URL.Query().Get("user_id")
 	if userIDStr == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Kullanıcı ID gerekli"))
+		writeError(w, r, errInvalidRequest.WithMessage("user_id_required", "kullanıcı ID gerekli"))
 		return
 	}
 
 	userID, err := strconv.ParseInt(userIDStr, 10, 64)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Geçersiz kullanıcı ID"))
+		writeError(w, r, errInvalidRequest.WithMessage("invalid_user_id", "geçersiz kullanıcı ID"))
+		return
+	}
+
+	if !h.Guard.authorize(w, r, domain.DelegationRead, auth.PermTransactionsReadAny, userID) {
+		return
+	}
+
+	filter, err := historyFilter(r)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
+	filter.UserID = userID
 
-	transactions, err := h.TransactionService.ListByUser(userID)
+	page, err := h.TransactionService.History(filter)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("Transaction geçmişi alınamadı"))
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(transactions)
+	json.NewEncoder(w).Encode(page)
+}
+
+// Geçmiş sorgusunun parametrelerini filtreye çevirir; sınır ve aralık kontrolleri servis tarafında yapılır
+func historyFilter(r *http.Request) (domain.TransactionFilter, error) {
+	query := r.URL.Query()
+	filter := domain.TransactionFilter{Descending: true}
+
+	for param, target := range map[string]**time.Time{"from": &filter.CreatedFrom, "to": &filter.CreatedTo} {
+		if value := query.Get(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, errInvalidRequest.WithMessage("invalid_timestamp", "geçersiz timestamp formatı").
+					WithDetails(map[string]interface{}{"parameter": param, "expected_format": time.RFC3339})
+			}
+			*target = &t
+		}
+	}
+
+	filter.Direction = domain.TransactionDirection(query.Get("direction"))
+
+	for _, value := range splitList(query.Get("type")) {
+		t := domain.TransactionType(value)
+		if !t.IsValid() {
+			return filter, errInvalidRequest.WithMessage("invalid_transaction_type", "geçersiz işlem türü: {type}").
+				WithParams(map[string]interface{}{"type": value})
+		}
+		filter.Types = append(filter.Types, t)
+	}
+	for _, value := range splitList(query.Get("status")) {
+		s := domain.TransactionStatus(value)
+		if !s.IsValid() {
+			return filter, errInvalidRequest.WithMessage("invalid_transaction_status", "geçersiz işlem durumu: {status}").
+				WithParams(map[string]interface{}{"status": value})
+		}
+		filter.Statuses = append(filter.Statuses, s)
+	}
+
+	filter.Currency = query.Get("currency")
+	for param, target := range map[string]**int64{"min_amount": &filter.MinAmount, "max_amount": &filter.MaxAmount} {
+		if value := query.Get(param); value != "" {
+			if filter.Currency == "" {
+				return filter, domain.ErrAmountRangeRequiresCurrency
+			}
+			amount, err := domain.ParseMoney(value, filter.Currency)
+			if err != nil {
+				return filter, err
+			}
+			*target = &amount.Amount
+		}
+	}
+
+	if value := query.Get("counterparty_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return filter, errInvalidRequest.WithMessage("invalid_user_id", "geçersiz kullanıcı ID")
+		}
+		filter.CounterpartyID = &id
+	}
+
+	filter.Text = strings.TrimSpace(query.Get("q"))
+	filter.Reference = query.Get("external_reference")
+	filter.Tags = splitList(query.Get("tag"))
+	for _, pair := range query["metadata"] {
+		key, value, ok := strings.Cut(pair, ":")
+		if !ok || key == "" {
+			return filter, errInvalidRequest.WithMessage("invalid_metadata_filter", "metadata filtresi anahtar:değer biçiminde olmalı")
+		}
+		if filter.Metadata == nil {
+			filter.Metadata = make(map[string]string)
+		}
+		filter.Metadata[key] = value
+	}
+
+	switch query.Get("sort") {
+	case "", "desc":
+	case "asc":
+		filter.Descending = false
+	default:
+		return filter, errInvalidRequest.WithMessage("invalid_sort", "sıralama asc veya desc olmalı")
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, errInvalidRequest.WithMessage("invalid_limit", "geçersiz sayfa boyutu")
+		}
+		filter.Limit = limit
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		cursor, err := domain.DecodeTransactionCursor(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Cursor = cursor
+	}
+	return filter, nil
+}
+
+// Virgülle ayrılmış sorgu parametresini boş öğeleri atarak böler
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
 }
 
 // Belirli bir transaction'ı getir (GET /api/v1/transactions/{id})
 func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Request) {
-	idStr := r.URL.Query().Get("id")
-	if idStr == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Transaction ID gerekli"))
+	id, ok := transactionIDFromPath(w, r)
+	if !ok {
 		return
 	}
 
-	id, err := strconv.ParseInt(idStr, 10, 64)
+	transaction, err := h.TransactionService.GetByID(id)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Geçersiz transaction ID"))
+		writeError(w, r, err)
 		return
 	}
 
-	transaction, err := h.TransactionService.GetByID(id)
-	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte("Transaction bulunamadı"))
+	// Transaction'ın taraflarından birine erişimi olan kullanıcı görüntüleyebilir
+	var parties []int64
+	if transaction.FromUserID != nil {
+		parties = append(parties, *transaction.FromUserID)
+	}
+	if transaction.ToUserID != nil {
+		parties = append(parties, *transaction.ToUserID)
+	}
+	if !h.Guard.authorize(w, r, domain.DelegationRead, auth.PermTransactionsReadAny, parties...) {
 		return
 	}
 
@@ -139,3 +413,18 @@ func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Reque
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(transaction)
 }
+
+func transactionIDFromPath(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, r, errInvalidRequest.WithMessage("transaction_id_required", "transaction ID gerekli"))
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, r, errInvalidRequest.WithMessage("invalid_transaction_id", "geçersiz transaction ID"))
+		return 0, false
+	}
+	return id, true
+}
@@ -2,7 +2,7 @@ package api
 
 import (
 	"encoding/json"
-	"fmt"
+	"gofinancialsystem/internal/auth"
 	"gofinancialsystem/internal/domain"
 	"net/http"
 	"strconv"
@@ -11,37 +11,37 @@ import (
 // UserHandler, kullanıcı yönetimi işlemleri için servisleri tutar
 type UserHandler struct {
 	UserService domain.UserService
+	Guard       *OwnershipGuard
 }
 
 // Tüm kullanıcıları listeler (GET /api/v1/users)
 func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	// Basit bir örnek - gerçek uygulamada repository'den tüm kullanıcıları çekersiniz
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Kullanıcı listesi (henüz implement edilmedi)"))
+	w.Write([]byte(localize(r, "user.list_not_implemented", nil)))
 }
 
 // Belirli bir kullanıcıyı getirir (GET /api/v1/users/{id})
 func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
-	// URL'den ID'yi çıkar (basit implementasyon)
-	// Gerçek uygulamada path parameter parsing yaparsınız
-	idStr := r.URL.Query().Get("id")
+	idStr := r.PathValue("id")
 	if idStr == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Kullanıcı ID gerekli"))
+		writeError(w, r, errInvalidRequest.WithMessage("user_id_required", "kullanıcı ID gerekli"))
 		return
 	}
 
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Geçersiz kullanıcı ID"))
+		writeError(w, r, errInvalidRequest.WithMessage("invalid_user_id", "geçersiz kullanıcı ID"))
+		return
+	}
+
+	if !h.Guard.authorize(w, r, domain.DelegationRead, auth.PermUsersReadAny, id) {
 		return
 	}
 
 	user, err := h.UserService.GetByID(id)
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte("Kullanıcı bulunamadı"))
+		writeError(w, r, err)
 		return
 	}
 
@@ -53,33 +53,74 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 
 // Kullanıcı bilgilerini günceller (PUT /api/v1/users/{id})
 func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, r, errInvalidRequest.WithMessage("user_id_required", "kullanıcı ID gerekli"))
+		return
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, r, errInvalidRequest.WithMessage("invalid_user_id", "geçersiz kullanıcı ID"))
+		return
+	}
+
+	if !h.Guard.authorize(w, r, domain.DelegationWrite, auth.PermUsersWriteAny, id) {
+		return
+	}
+
 	var req struct {
-		Username string `json:"username"`
-		Email    string `json:"email"`
-		Role     string `json:"role"`
+		Username string  `json:"username"`
+		Email    string  `json:"email"`
+		Role     *string `json:"role"`   // Rol bu uçtan değiştirilemez; verilirse istek reddedilir
+		Locale   *string `json:"locale"` // null/eksik: değiştirme, "": tercihi kaldır
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Geçersiz istek"))
+		writeError(w, r, decodeError(err))
+		return
+	}
+	if req.Role != nil {
+		writeError(w, r, domain.NewValidationError("role_not_updatable", "rol bu istekle değiştirilemez"))
 		return
 	}
 
-	// Basit bir örnek - gerçek uygulamada kullanıcıyı güncelleme işlemi yaparsınız
+	if req.Locale != nil {
+		locale, err := supportedLocale(*req.Locale)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if err := h.UserService.SetLocale(id, locale); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	// Diğer alanlar için basit bir örnek - gerçek uygulamada kullanıcıyı güncelleme işlemi yaparsınız
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Kullanıcı güncellendi"))
+	w.Write([]byte(localize(r, "user.updated", nil)))
 }
 
 // Kullanıcıyı siler (DELETE /api/v1/users/{id})
 func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
-	idStr := r.URL.Query().Get("id")
+	idStr := r.PathValue("id")
 	if idStr == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Kullanıcı ID gerekli"))
+		writeError(w, r, errInvalidRequest.WithMessage("user_id_required", "kullanıcı ID gerekli"))
+		return
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, r, errInvalidRequest.WithMessage("invalid_user_id", "geçersiz kullanıcı ID"))
+		return
+	}
+
+	if err := h.UserService.Delete(id); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	// Basit bir örnek - gerçek uygulamada kullanıcı silme işlemi yaparsınız
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(fmt.Sprintf("Kullanıcı %s silindi", idStr)))
+	w.Write([]byte(localize(r, "user.deleted", map[string]interface{}{"id": id})))
 }
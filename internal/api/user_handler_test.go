@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"gofinancialsystem/internal/domain"
+	"testing"
+)
+
+func errorCode(t *testing.T, body []byte) domain.ErrorCode {
+	t.Helper()
+	var resp ErrorResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("hata gövdesi çözülemedi: %v: %s", err, body)
+	}
+	return resp.Code
+}
+
+func TestDeleteUser(t *testing.T) {
+	f := newOwnershipFixture(t)
+
+	if w := f.do(callerAdmin, "DELETE", "/users/999999", ""); w.Code != 404 || errorCode(t, w.Body.Bytes()) != domain.CodeUserNotFound {
+		t.Fatalf("olmayan kullanıcı: %d %s, beklenen 404", w.Code, w.Body.String())
+	}
+	if w := f.do(callerAdmin, "DELETE", "/users/abc", ""); w.Code != 400 {
+		t.Fatalf("geçersiz ID: %d %s, beklenen 400", w.Code, w.Body.String())
+	}
+
+	if w := f.do(callerAdmin, "DELETE", "/users/{payee}", ""); w.Code != 200 {
+		t.Fatalf("silme: %d %s", w.Code, w.Body.String())
+	}
+	if w := f.do(callerAdmin, "GET", "/users/{payee}", ""); w.Code != 404 {
+		t.Fatalf("silinen kullanıcı: %d %s, beklenen 404", w.Code, w.Body.String())
+	}
+	if w := f.do(callerAdmin, "DELETE", "/users/{payee}", ""); w.Code != 404 {
+		t.Fatalf("ikinci silme: %d %s, beklenen 404", w.Code, w.Body.String())
+	}
+}
+
+// Rol sadece yetki yönetimiyle değişir; güncelleme isteğinde verilmesi sessizce yok sayılmaz
+func TestUpdateUserRejectsRole(t *testing.T) {
+	f := newOwnershipFixture(t)
+	for _, caller := range []string{callerOwner, callerAdmin} {
+		w := f.do(caller, "PUT", "/users/{owner}", `{"role":"admin"}`)
+		if w.Code != 400 || errorCode(t, w.Body.Bytes()) != domain.CodeValidationFailed {
+			t.Fatalf("%s: %d %s, beklenen 400 validation_failed", caller, w.Code, w.Body.String())
+		}
+	}
+	if w := f.do(callerOwner, "PUT", "/users/{owner}", `{"locale":"en"}`); w.Code != 200 {
+		t.Fatalf("rolsüz güncelleme: %d %s", w.Code, w.Body.String())
+	}
+	var user domain.User
+	if err := json.Unmarshal(f.do(callerOwner, "GET", "/users/{owner}", "").Body.Bytes(), &user); err != nil || user.Role != "user" {
+		t.Fatalf("rol %q, %v; beklenen user", user.Role, err)
+	}
+}
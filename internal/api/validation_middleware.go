@@ -2,8 +2,8 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"gofinancialsystem/internal/domain"
 	"net/http"
 	"time"
 )
@@ -15,15 +15,13 @@ func ValidationMiddleware(next HandlerFunc) HandlerFunc {
 		if r.Method == "POST" || r.Method == "PUT" {
 			contentType := r.Header.Get("Content-Type")
 			if contentType != "application/json" {
-				w.WriteHeader(http.StatusBadRequest)
-				w.Write([]byte("Content-Type application/json olmalı"))
+				writeError(w, r, errInvalidRequest.WithMessage("content_type", "Content-Type application/json olmalı"))
 				return
 			}
 
 			// Request body'sini kontrol et
 			if r.Body == nil {
-				w.WriteHeader(http.StatusBadRequest)
-				w.Write([]byte("Request body gerekli"))
+				writeError(w, r, errInvalidRequest.WithMessage("body_required", "request body gerekli"))
 				return
 			}
 		}
@@ -38,17 +36,9 @@ func ErrorHandlingMiddleware(next HandlerFunc) HandlerFunc {
 		defer func() {
 			if err := recover(); err != nil {
 				// Log the error (gerçek implementasyonda logger kullanılmalı)
-				fmt.Printf("Panic yakalandı: %v\n", err)
-				
-				// JSON error response
-				errorResponse := map[string]interface{}{
-					"error":   "Internal Server Error",
-					"message": "Beklenmeyen bir hata oluştu",
-				}
-				
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusInternalServerError)
-				json.NewEncoder(w).Encode(errorResponse)
+				fmt.Printf("Panic yakalandı [%s]: %v\n", RequestIDFromContext(r.Context()), err)
+
+				writeError(w, r, domain.ErrInternal)
 			}
 		}()
 
@@ -89,8 +79,7 @@ func RequestSizeMiddleware(maxSize int64) func(HandlerFunc) HandlerFunc {
 	return func(next HandlerFunc) HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			if r.ContentLength > maxSize {
-				w.WriteHeader(http.StatusRequestEntityTooLarge)
-				w.Write([]byte("Request boyutu çok büyük"))
+				writeError(w, r, domain.NewError(domain.CodePayloadTooLarge, "request boyutu çok büyük").WithDetails(map[string]interface{}{"max_bytes": maxSize}))
 				return
 			}
 			next(w, r)
@@ -117,8 +106,7 @@ func TimeoutMiddleware(timeout time.Duration) func(HandlerFunc) HandlerFunc {
 			case <-done:
 				// Request tamamlandı
 			case <-ctx.Done():
-				w.WriteHeader(http.StatusRequestTimeout)
-				w.Write([]byte("Request timeout"))
+				writeError(w, r, domain.NewError(domain.CodeTimeout, "istek zaman aşımına uğradı"))
 			}
 		}
 	}
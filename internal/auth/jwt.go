@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = domain.NewError(domain.CodeInvalidToken, "geçersiz token")
+	ErrTokenExpired = domain.NewError(domain.CodeTokenExpired, "token süresi dolmuş")
+)
+
+// Saat farklarına karşı tanınan tolerans
+const clockSkew = 30 * time.Second
+
+// Claims, access token içinde taşınan standart ve uygulamaya özel alanlardır
+type Claims struct {
+	Subject   string `json:"sub"`
+	Username  string `json:"username,omitempty"`
+	Role      string `json:"role,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	ID        string `json:"jti"`
+	SessionID string `json:"sid,omitempty"`
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid"`
+}
+
+// TokenService, KeyRing'deki anahtarlarla JWT access token üretir ve doğrular
+type TokenService struct {
+	ring   *KeyRing
+	ttl    time.Duration
+	issuer string
+	now    func() time.Time
+}
+
+// Yeni bir TokenService oluşturur
+func NewTokenService(ring *KeyRing, ttl time.Duration, issuer string) *TokenService {
+	return &TokenService{ring: ring, ttl: ttl, issuer: issuer, now: time.Now}
+}
+
+// Kullanıcı için aktif anahtarla imzalanmış bir access token üretir. Her token bir oturuma
+// bağlanır; oturum iptal edildiğinde token da geçersiz olur (bkz. SessionService.Verify).
+func (s *TokenService) Issue(user *domain.User, sessionID string) (string, *Claims, error) {
+	if sessionID == "" {
+		return "", nil, errors.New("access token bir oturuma bağlanmalı")
+	}
+	now := s.now()
+	jti, err := newTokenID()
+	if err != nil {
+		return "", nil, err
+	}
+	claims := &Claims{
+		Subject:   strconv.FormatInt(user.ID, 10),
+		Username:  user.Username,
+		Role:      user.Role,
+		Issuer:    s.issuer,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.ttl).Unix(),
+		ID:        jti,
+		SessionID: sessionID,
+	}
+	token, err := s.sign(claims)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, claims, nil
+}
+
+// Token'ın imzasını, algoritmasını ve süresini doğrular, içindeki kimliği döndürür
+func (s *TokenService) Verify(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, ErrInvalidToken
+	}
+	key, err := s.ring.lookup(h.KeyID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	// Algoritma token'dan değil anahtardan gelir; "none" ve algoritma karıştırma saldırıları engellenir
+	if h.Algorithm != key.Algorithm {
+		return nil, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !key.verify([]byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	now := s.now()
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)) {
+		return nil, ErrTokenExpired
+	}
+	if time.Unix(claims.IssuedAt, 0).After(now.Add(clockSkew)) {
+		return nil, ErrInvalidToken
+	}
+	if claims.ID == "" || (s.issuer != "" && claims.Issuer != s.issuer) {
+		return nil, ErrInvalidToken
+	}
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return &Principal{
+		UserID:    userID,
+		Username:  claims.Username,
+		Role:      claims.Role,
+		TokenID:   claims.ID,
+		SessionID: claims.SessionID,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+func (s *TokenService) sign(claims *Claims) (string, error) {
+	key, err := s.ring.activeKey()
+	if err != nil {
+		return "", err
+	}
+	h, err := encodeSegment(header{Algorithm: key.Algorithm, Type: "JWT", KeyID: key.ID})
+	if err != nil {
+		return "", err
+	}
+	c, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := h + "." + c
+	signature, err := key.sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func encodeSegment(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/repository"
+	"strings"
+	"testing"
+	"time"
+)
+
+var issuedAt = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func hmacKey(t *testing.T, id string) *Key {
+	t.Helper()
+	key, err := NewHMACKey(id, []byte(strings.Repeat(id, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func ed25519Key(t *testing.T, id string) *Key {
+	t.Helper()
+	key, err := NewEd25519Key(id, []byte(strings.Repeat("s", 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// Verilen anahtarlarla bir KeyRing ve saati issuedAt'e sabitlenmiş TokenService oluşturur; ilk anahtar aktiftir
+func newTokenService(t *testing.T, keys ...*Key) (*TokenService, *KeyRing) {
+	t.Helper()
+	ring := NewKeyRing()
+	for _, key := range keys {
+		if err := ring.Add(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := NewTokenService(ring, 15*time.Minute, "test")
+	s.now = func() time.Time { return issuedAt }
+	return s, ring
+}
+
+func issue(t *testing.T, s *TokenService) string {
+	t.Helper()
+	token, _, err := s.Issue(&domain.User{ID: 7, Username: "alice", Role: "user"}, "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// forge, başlığı ve imzası saldırgan tarafından seçilmiş bir token üretir
+func forge(t *testing.T, h header, claims *Claims, sign func(signingInput []byte) []byte) string {
+	t.Helper()
+	hs, err := encodeSegment(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, err := encodeSegment(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingInput := hs + "." + cs
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(signingInput)))
+}
+
+func validClaims() *Claims {
+	return &Claims{Subject: "7", Role: "admin", Issuer: "test", IssuedAt: issuedAt.Unix(),
+		ExpiresAt: issuedAt.Add(15 * time.Minute).Unix(), ID: "jti-1", SessionID: "session-1"}
+}
+
+func TestVerifyRejectsForgedTokens(t *testing.T) {
+	hs := hmacKey(t, "hs")
+	ed := ed25519Key(t, "ed")
+	s, _ := newTokenService(t, hs, ed)
+	token := issue(t, s)
+	parts := strings.Split(token, ".")
+
+	// Ed25519 açık anahtarını HMAC gizli anahtarı gibi kullanan klasik algoritma karıştırma saldırısı
+	hmacWith := func(secret []byte) func([]byte) []byte {
+		return func(input []byte) []byte {
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(input)
+			return mac.Sum(nil)
+		}
+	}
+	payload, err := encodeSegment(validClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name  string
+		token string
+	}{
+		{"bozuk imza", parts[0] + "." + parts[1] + "." + strings.Repeat("A", len(parts[2]))},
+		{"değiştirilmiş gövde", parts[0] + "." + payload + "." + parts[2]},
+		{"imzasız", parts[0] + "." + parts[1] + "."},
+		{"eksik bölüm", parts[0] + "." + parts[1]},
+		{"alg none", forge(t, header{Algorithm: "none", Type: "JWT", KeyID: "hs"}, validClaims(), func([]byte) []byte { return nil })},
+		{"HS256 başlıklı EdDSA anahtarı", forge(t, header{Algorithm: AlgHS256, Type: "JWT", KeyID: "ed"}, validClaims(), hmacWith(ed.publicKey))},
+		{"EdDSA başlıklı HS256 anahtarı", forge(t, header{Algorithm: AlgEdDSA, Type: "JWT", KeyID: "hs"}, validClaims(), hmacWith(hs.secret))},
+		{"bilinmeyen kid", forge(t, header{Algorithm: AlgHS256, Type: "JWT", KeyID: "yok"}, validClaims(), hmacWith(hs.secret))},
+		{"kid yok", forge(t, header{Algorithm: AlgHS256, Type: "JWT"}, validClaims(), hmacWith(hs.secret))},
+		{"başka issuer", forge(t, header{Algorithm: AlgHS256, Type: "JWT", KeyID: "hs"},
+			&Claims{Subject: "7", Issuer: "other", IssuedAt: issuedAt.Unix(), ExpiresAt: issuedAt.Add(time.Minute).Unix(), ID: "jti-1"}, hmacWith(hs.secret))},
+		{"jti yok", forge(t, header{Algorithm: AlgHS256, Type: "JWT", KeyID: "hs"},
+			&Claims{Subject: "7", Issuer: "test", IssuedAt: issuedAt.Unix(), ExpiresAt: issuedAt.Add(time.Minute).Unix()}, hmacWith(hs.secret))},
+	}
+	for _, c := range cases {
+		if _, err := s.Verify(c.token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: hata %v, beklenen ErrInvalidToken", c.name, err)
+		}
+	}
+
+	// Aynı anahtarla doğru imzalanan token kabul edilir; yukarıdaki retler imzadan kaynaklanır
+	principal, err := s.Verify(forge(t, header{Algorithm: AlgHS256, Type: "JWT", KeyID: "hs"}, validClaims(), hmacWith(hs.secret)))
+	if err != nil || principal.UserID != 7 || principal.Role != "admin" || principal.SessionID != "session-1" {
+		t.Fatalf("Verify = %+v, %v", principal, err)
+	}
+}
+
+func TestVerifyExpiryAndClockSkew(t *testing.T) {
+	s, _ := newTokenService(t, ed25519Key(t, "ed"))
+	token := issue(t, s)
+	expiresAt := issuedAt.Add(15 * time.Minute)
+
+	cases := []struct {
+		name string
+		now  time.Time
+		err  error
+	}{
+		{"süresi içinde", issuedAt.Add(time.Minute), nil},
+		{"süre doldu, tolerans içinde", expiresAt.Add(clockSkew), nil},
+		{"tolerans da geçti", expiresAt.Add(clockSkew + time.Second), ErrTokenExpired},
+		// Doğrulayan sunucunun saati geride olabilir; iat tolerans kadar ileride olabilir
+		{"iat tolerans kadar ileride", issuedAt.Add(-clockSkew), nil},
+		{"iat toleranstan ileride", issuedAt.Add(-clockSkew - time.Second), ErrInvalidToken},
+	}
+	for _, c := range cases {
+		s.now = func() time.Time { return c.now }
+		_, err := s.Verify(token)
+		if (c.err == nil && err != nil) || (c.err != nil && !errors.Is(err, c.err)) {
+			t.Errorf("%s: hata %v, beklenen %v", c.name, err, c.err)
+		}
+	}
+}
+
+func TestVerifyAfterKeyRotation(t *testing.T) {
+	s, ring := newTokenService(t, hmacKey(t, "k1"))
+	old := issue(t, s)
+
+	if err := ring.Add(ed25519Key(t, "k2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ring.SetActive("k2"); err != nil {
+		t.Fatal(err)
+	}
+	fresh := issue(t, s)
+	var h header
+	if err := decodeSegment(strings.Split(fresh, ".")[0], &h); err != nil || h.KeyID != "k2" || h.Algorithm != AlgEdDSA {
+		t.Fatalf("rotasyon sonrası başlık = %+v, %v; beklenen k2/EdDSA", h, err)
+	}
+	// Eski anahtar halkada kaldıkça eski tokenlar doğrulanır
+	for name, token := range map[string]string{"eski": old, "yeni": fresh} {
+		if _, err := s.Verify(token); err != nil {
+			t.Fatalf("%s token: %v", name, err)
+		}
+	}
+
+	if err := ring.Remove("k2"); err == nil {
+		t.Fatal("aktif anahtar çıkarılamamalı")
+	}
+	if err := ring.Remove("k1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Verify(old); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("çıkarılan anahtarın tokenı: %v, beklenen ErrInvalidToken", err)
+	}
+	if _, err := s.Verify(fresh); err != nil {
+		t.Fatalf("yeni token: %v", err)
+	}
+}
+
+func TestTokensRequireSession(t *testing.T) {
+	s, _ := newTokenService(t, hmacKey(t, "hs"))
+	if _, _, err := s.Issue(&domain.User{ID: 7}, ""); err == nil {
+		t.Fatal("oturumsuz token üretilmemeli")
+	}
+
+	// Oturumsuz bir token imzalansa bile (ör. eski sürümden kalma) iptal edilemeyeceği için reddedilir
+	claims := validClaims()
+	claims.SessionID = ""
+	token, err := s.sign(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := NewSessionService(repository.NewSessionRepository(), nil, s, time.Hour)
+	sessions.now = s.now
+	if _, err := sessions.Verify(token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("oturumsuz token: %v, beklenen ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Desteklenen imza algoritmaları (JWT "alg" başlığı)
+const (
+	AlgHS256 = "HS256"
+	AlgEdDSA = "EdDSA"
+)
+
+var ErrUnknownKey = errors.New("bilinmeyen imza anahtarı")
+
+// Key, kid ile tanımlanan tek bir imza anahtarıdır
+type Key struct {
+	ID         string
+	Algorithm  string
+	secret     []byte             // HS256
+	privateKey ed25519.PrivateKey // EdDSA (sadece doğrulama yapan anahtarlarda nil)
+	publicKey  ed25519.PublicKey  // EdDSA
+}
+
+// HS256 için paylaşılan gizli anahtar oluşturur
+func NewHMACKey(id string, secret []byte) (*Key, error) {
+	if len(secret) < 32 {
+		return nil, errors.New("HS256 anahtarı en az 32 byte olmalı")
+	}
+	return &Key{ID: id, Algorithm: AlgHS256, secret: append([]byte(nil), secret...)}, nil
+}
+
+// 32 byte'lık seed'den Ed25519 imza anahtarı oluşturur
+func NewEd25519Key(id string, seed []byte) (*Key, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("Ed25519 seed %d byte olmalı", ed25519.SeedSize)
+	}
+	priv := ed25519.NewKeyFromSeed(seed)
+	return &Key{ID: id, Algorithm: AlgEdDSA, privateKey: priv, publicKey: priv.Public().(ed25519.PublicKey)}, nil
+}
+
+// Sadece doğrulama yapabilen Ed25519 açık anahtarı oluşturur
+func NewEd25519VerifyKey(id string, pub ed25519.PublicKey) (*Key, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("Ed25519 açık anahtarı %d byte olmalı", ed25519.PublicKeySize)
+	}
+	return &Key{ID: id, Algorithm: AlgEdDSA, publicKey: pub}, nil
+}
+
+func (k *Key) canSign() bool {
+	return k.secret != nil || k.privateKey != nil
+}
+
+func (k *Key) sign(signingInput []byte) ([]byte, error) {
+	switch k.Algorithm {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(signingInput)
+		return mac.Sum(nil), nil
+	case AlgEdDSA:
+		if k.privateKey == nil {
+			return nil, errors.New("anahtar sadece doğrulama için kullanılabilir")
+		}
+		return ed25519.Sign(k.privateKey, signingInput), nil
+	}
+	return nil, errors.New("desteklenmeyen algoritma")
+}
+
+func (k *Key) verify(signingInput, signature []byte) bool {
+	switch k.Algorithm {
+	case AlgHS256:
+		expected, _ := k.sign(signingInput)
+		return hmac.Equal(expected, signature)
+	case AlgEdDSA:
+		return ed25519.Verify(k.publicKey, signingInput, signature)
+	}
+	return false
+}
+
+// KeyRing, imza anahtarlarını kid ile tutar. Yeni tokenlar aktif anahtarla imzalanır,
+// eski anahtarlar halkadan çıkarılana kadar mevcut tokenları doğrulamaya devam eder.
+type KeyRing struct {
+	mu     sync.RWMutex
+	keys   map[string]*Key
+	active string
+}
+
+// Yeni bir boş KeyRing oluşturur
+func NewKeyRing() *KeyRing {
+	return &KeyRing{keys: make(map[string]*Key)}
+}
+
+// Anahtarı halkaya ekler; halkadaki ilk imzalayabilen anahtar aktif olur
+func (kr *KeyRing) Add(key *Key) error {
+	if key.ID == "" {
+		return errors.New("anahtar kid boş olamaz")
+	}
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if _, exists := kr.keys[key.ID]; exists {
+		return fmt.Errorf("%s kid'li anahtar zaten var", key.ID)
+	}
+	kr.keys[key.ID] = key
+	if kr.active == "" && key.canSign() {
+		kr.active = key.ID
+	}
+	return nil
+}
+
+// Yeni tokenları imzalayacak anahtarı değiştirir (rotasyon)
+func (kr *KeyRing) SetActive(id string) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	key, exists := kr.keys[id]
+	if !exists {
+		return ErrUnknownKey
+	}
+	if !key.canSign() {
+		return errors.New("sadece doğrulama yapan anahtar aktif yapılamaz")
+	}
+	kr.active = id
+	return nil
+}
+
+// Anahtarı halkadan çıkarır; bu anahtarla imzalanmış tokenlar artık doğrulanmaz
+func (kr *KeyRing) Remove(id string) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if id == kr.active {
+		return errors.New("aktif anahtar çıkarılamaz")
+	}
+	delete(kr.keys, id)
+	return nil
+}
+
+func (kr *KeyRing) activeKey() (*Key, error) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	key, exists := kr.keys[kr.active]
+	if !exists {
+		return nil, errors.New("aktif imza anahtarı yok")
+	}
+	return key, nil
+}
+
+func (kr *KeyRing) lookup(id string) (*Key, error) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	key, exists := kr.keys[id]
+	if !exists {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// ParseKeyRing, "kid:alg:base64anahtar" girdilerinin virgülle ayrıldığı tanımdan KeyRing oluşturur.
+// HS256 için base64 gizli anahtar, EdDSA için base64 32 byte seed beklenir.
+func ParseKeyRing(spec, activeID string) (*KeyRing, error) {
+	ring := NewKeyRing()
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("geçersiz anahtar tanımı: %q", parts[0])
+		}
+		material, err := base64.StdEncoding.DecodeString(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("%s anahtarı base64 değil", parts[0])
+		}
+		var key *Key
+		switch parts[1] {
+		case AlgHS256:
+			key, err = NewHMACKey(parts[0], material)
+		case AlgEdDSA:
+			key, err = NewEd25519Key(parts[0], material)
+		default:
+			err = fmt.Errorf("%s anahtarı için desteklenmeyen algoritma: %s", parts[0], parts[1])
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := ring.Add(key); err != nil {
+			return nil, err
+		}
+	}
+	if activeID != "" {
+		if err := ring.SetActive(activeID); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := ring.activeKey(); err != nil {
+		return nil, err
+	}
+	return ring, nil
+}
+
+// Geliştirme ortamı için rastgele HS256 anahtarlı bir KeyRing oluşturur
+// (sunucu yeniden başladığında eski tokenlar geçersiz olur)
+func NewEphemeralKeyRing() (*KeyRing, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	key, err := NewHMACKey("ephemeral", secret)
+	if err != nil {
+		return nil, err
+	}
+	ring := NewKeyRing()
+	return ring, ring.Add(key)
+}
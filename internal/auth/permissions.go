@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Permission, "kaynak:işlem[:kapsam]" biçiminde ince taneli bir yetkidir
+type Permission string
+
+const (
+	PermTransactionsRead     Permission = "transactions:read"
+	PermTransactionsWrite    Permission = "transactions:write"
+	PermTransactionsReadAny  Permission = "transactions:read:any"
+	PermTransactionsWriteAny Permission = "transactions:write:any"
+	PermTransactionsReverse  Permission = "transactions:reverse"
+	PermBalancesRead         Permission = "balances:read"
+	PermBalancesReadAny      Permission = "balances:read:any"
+	PermAccountsRead         Permission = "accounts:read"
+	PermAccountsWrite        Permission = "accounts:write"
+	PermAccountsReadAny      Permission = "accounts:read:any"
+	PermAccountsWriteAny     Permission = "accounts:write:any"
+	PermHoldsRead            Permission = "holds:read"
+	PermHoldsWrite           Permission = "holds:write"
+	PermHoldsReadAny         Permission = "holds:read:any"
+	PermHoldsWriteAny        Permission = "holds:write:any"
+	PermLimitsRead           Permission = "limits:read"
+	PermLimitsReadAny        Permission = "limits:read:any"
+	PermLimitsWrite          Permission = "limits:write"
+	PermFeesRead             Permission = "fees:read"
+	PermFeesReadAny          Permission = "fees:read:any"
+	PermFeesWrite            Permission = "fees:write"
+	PermInterestRead         Permission = "interest:read"
+	PermInterestReadAny      Permission = "interest:read:any"
+	PermInterestWrite        Permission = "interest:write"
+	PermOverdraftWrite       Permission = "overdraft:write"
+	PermSchedulesRead        Permission = "schedules:read"
+	PermSchedulesWrite       Permission = "schedules:write"
+	PermSchedulesReadAny     Permission = "schedules:read:any"
+	PermSchedulesWriteAny    Permission = "schedules:write:any"
+	PermUsersRead            Permission = "users:read"
+	PermUsersReadAny         Permission = "users:read:any"
+	PermUsersWrite           Permission = "users:write"
+	PermUsersWriteAny        Permission = "users:write:any"
+	PermUsersDelete          Permission = "users:delete"
+	PermSessionsRevoke       Permission = "sessions:revoke"
+	PermFXRead               Permission = "fx:read"
+	PermFXQuote              Permission = "fx:quote"
+	PermFXRatesWrite         Permission = "fx:rates:write"
+)
+
+// Tüm yetkileri kapsayan joker karakter
+const wildcard = "*"
+
+// Config'te ROLE_PERMISSIONS verilmediğinde kullanılan rol eşlemesi
+const DefaultRolePermissions = "admin=*;" +
+	"user=transactions:read,transactions:write,balances:read,accounts:read,accounts:write,holds:read,holds:write,limits:read,fees:read,interest:read,schedules:read,schedules:write,users:read,users:write,fx:read,fx:quote"
+
+// Authorizer, rolleri yetki kümelerine eşler
+type Authorizer struct {
+	roles map[string]map[Permission]bool
+}
+
+// ParseRolePermissions, "rol=yetki1,yetki2;rol2=*" biçimindeki tanımdan Authorizer oluşturur
+func ParseRolePermissions(spec string) (*Authorizer, error) {
+	a := &Authorizer{roles: make(map[string]map[Permission]bool)}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		role := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || role == "" {
+			return nil, fmt.Errorf("geçersiz rol tanımı: %q", entry)
+		}
+		perms := make(map[Permission]bool)
+		for _, p := range strings.Split(parts[1], ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				perms[Permission(p)] = true
+			}
+		}
+		a.roles[role] = perms
+	}
+	return a, nil
+}
+
+// Rolün verilen yetkiye sahip olup olmadığını döndürür
+func (a *Authorizer) Can(role string, perm Permission) bool {
+	perms := a.roles[role]
+	return perms[wildcard] || perms[perm]
+}
+
+// Rolün sahip olduğu yetkileri döndürür
+func (a *Authorizer) Permissions(role string) []Permission {
+	var result []Permission
+	for p := range a.roles[role] {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// Principal, doğrulanmış bir token'ın temsil ettiği kullanıcıdır
+type Principal struct {
+	UserID    int64
+	Username  string
+	Role      string
+	TokenID   string
+	SessionID string
+	ExpiresAt time.Time
+
+	authorizer *Authorizer // Rol güncel kullanıcı kaydından çözümlendikten sonra atanır
+}
+
+// Principal'ın rolünü güncel kullanıcı kaydından gelen rolle değiştirir ve yetkileri bağlar
+func (p *Principal) Resolve(role string, authorizer *Authorizer) {
+	p.Role = role
+	p.authorizer = authorizer
+}
+
+// Principal'ın verilen yetkiye sahip olup olmadığını döndürür
+func (p *Principal) Can(perm Permission) bool {
+	return p.authorizer != nil && p.authorizer.Can(p.Role, perm)
+}
+
+// Context anahtarı olarak dışarıdan çakışmayacak özel bir tip kullanılır
+type principalKey struct{}
+
+// Principal'ı context'e ekler
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// Context'teki Principal'ı döndürür
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
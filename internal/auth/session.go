@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"gofinancialsystem/internal/domain"
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken = domain.NewError(domain.CodeInvalidRefreshToken, "geçersiz refresh token")
+	ErrRefreshTokenReused  = domain.NewError(domain.CodeRefreshTokenReused, "refresh token tekrar kullanıldı, oturum kapatıldı")
+	ErrSessionRevoked      = domain.NewError(domain.CodeSessionRevoked, "oturum sonlandırılmış")
+)
+
+// TokenPair, giriş ve yenileme sonrasında istemciye dönen token çiftidir
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	AccessExpiresAt  time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// SessionService, oturumları, refresh token rotasyonunu ve sunucu tarafı iptali yönetir
+type SessionService struct {
+	repo       domain.SessionRepository
+	users      domain.UserService
+	tokens     *TokenService
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+// Yeni bir SessionService oluşturur
+func NewSessionService(repo domain.SessionRepository, users domain.UserService, tokens *TokenService, refreshTTL time.Duration) *SessionService {
+	return &SessionService{repo: repo, users: users, tokens: tokens, refreshTTL: refreshTTL, now: time.Now}
+}
+
+// Kimliği doğrulanmış kullanıcı için yeni bir oturum açar
+func (s *SessionService) Login(user *domain.User) (*TokenPair, error) {
+	now := s.now()
+	sessionID, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	session := &domain.Session{
+		ID:        sessionID,
+		UserID:    user.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.refreshTTL),
+	}
+	raw, token, err := s.newRefreshToken(session, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateSession(session, token); err != nil {
+		return nil, err
+	}
+	return s.pair(user, session, raw, token)
+}
+
+// Refresh token'ı tek kullanımlık olarak yenisiyle değiştirir. Daha önce kullanılmış bir
+// token gelirse çalındığı varsayılır ve tüm token ailesi (oturum) iptal edilir.
+func (s *SessionService) Refresh(rawRefreshToken string) (*TokenPair, error) {
+	now := s.now()
+	current, err := s.repo.FindRefreshToken(hashToken(rawRefreshToken))
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	session, err := s.repo.FindSession(current.SessionID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if !session.Active(now) {
+		return nil, ErrSessionRevoked
+	}
+	if current.UsedAt != nil {
+		return nil, s.revokeReused(session, now)
+	}
+	if !now.Before(current.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	raw, next, err := s.newRefreshToken(session, now)
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.repo.RotateRefreshToken(current.Hash, next, now)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Aynı token eşzamanlı olarak başka bir istekte kullanıldı
+		return nil, s.revokeReused(session, now)
+	}
+	user, err := s.users.GetByID(session.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	return s.pair(user, session, raw, next)
+}
+
+// Tekrar kullanılan refresh token'ın oturumunu kapatır. Oturum kapatılamazsa repository hatası döner;
+// token kullanılmış işaretli kaldığı için sonraki denemeler de reddedilir ve oturumu kapatmayı yeniden dener.
+func (s *SessionService) revokeReused(session *domain.Session, now time.Time) error {
+	if err := s.repo.RevokeSession(session.ID, now); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// Refresh token'ın ait olduğu oturumu kapatır
+func (s *SessionService) Logout(rawRefreshToken string) error {
+	current, err := s.repo.FindRefreshToken(hashToken(rawRefreshToken))
+	if err != nil {
+		return ErrInvalidRefreshToken
+	}
+	return s.repo.RevokeSession(current.SessionID, s.now())
+}
+
+// Kullanıcının tüm oturumlarını kapatır (ör: cihaz çalındığında)
+func (s *SessionService) RevokeAll(userID int64) error {
+	return s.repo.RevokeUserSessions(userID, s.now())
+}
+
+// Access token'ı doğrular ve bağlı olduğu oturumun hâlâ açık olduğunu kontrol eder.
+// Oturumu olmayan token iptal edilemeyeceği için reddedilir.
+func (s *SessionService) Verify(token string) (*Principal, error) {
+	principal, err := s.tokens.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if principal.SessionID == "" {
+		return nil, ErrInvalidToken
+	}
+	session, err := s.repo.FindSession(principal.SessionID)
+	if err != nil || !session.Active(s.now()) {
+		return nil, ErrSessionRevoked
+	}
+	return principal, nil
+}
+
+func (s *SessionService) newRefreshToken(session *domain.Session, now time.Time) (string, *domain.RefreshToken, error) {
+	raw, err := randomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+	return raw, &domain.RefreshToken{
+		Hash:      hashToken(raw),
+		SessionID: session.ID,
+		CreatedAt: now,
+		ExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+func (s *SessionService) pair(user *domain.User, session *domain.Session, raw string, refresh *domain.RefreshToken) (*TokenPair, error) {
+	access, claims, err := s.tokens.Issue(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:      access,
+		TokenType:        "Bearer",
+		AccessExpiresAt:  time.Unix(claims.ExpiresAt, 0).UTC(),
+		RefreshToken:     raw,
+		RefreshExpiresAt: refresh.ExpiresAt.UTC(),
+	}, nil
+}
+
+// Refresh token'lar veritabanında sadece SHA-256 hash olarak tutulur
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/repository"
+	"testing"
+	"time"
+)
+
+// fixedUsers, her ID için aynı kullanıcıyı döndüren UserService'tir
+type fixedUsers struct{ domain.UserService }
+
+func (fixedUsers) GetByID(id int64) (*domain.User, error) {
+	return &domain.User{ID: id, Username: "alice", Role: "user"}, nil
+}
+
+// failingRevokes, oturum kapatmayı her zaman reddeden session deposudur
+type failingRevokes struct {
+	*repository.SessionRepositoryImpl
+}
+
+func (failingRevokes) RevokeSession(string, time.Time) error {
+	return errors.New("depo kullanılamıyor")
+}
+
+func newSessionService(t *testing.T, repo domain.SessionRepository) *SessionService {
+	t.Helper()
+	tokens, _ := newTokenService(t, hmacKey(t, "hs"))
+	s := NewSessionService(repo, fixedUsers{}, tokens, 24*time.Hour)
+	s.now = tokens.now
+	return s
+}
+
+func TestRefreshRotatesTokens(t *testing.T) {
+	s := newSessionService(t, repository.NewSessionRepository())
+	first, err := s.Login(&domain.User{ID: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := s.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == first.AccessToken {
+		t.Fatal("yenileme yeni bir token çifti döndürmeli")
+	}
+	third, err := s.Refresh(second.RefreshToken)
+	if err != nil {
+		t.Fatalf("yeni refresh token: %v", err)
+	}
+	if _, err := s.Verify(third.AccessToken); err != nil {
+		t.Fatalf("yenilenen access token: %v", err)
+	}
+	if _, err := s.Refresh("bilinmeyen"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("bilinmeyen refresh token: %v, beklenen ErrInvalidRefreshToken", err)
+	}
+}
+
+// Kullanılmış bir refresh token tekrar gelirse çalındığı varsayılır ve bütün oturum kapanır
+func TestRefreshReuseRevokesSession(t *testing.T) {
+	s := newSessionService(t, repository.NewSessionRepository())
+	first, err := s.Login(&domain.User{ID: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Refresh(first.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("kullanılmış refresh token: %v, beklenen ErrRefreshTokenReused", err)
+	}
+	// Oturumdaki diğer tokenlar da geçersizdir: meşru kullanıcının en son aldığı çift dahil
+	if _, err := s.Refresh(second.RefreshToken); !errors.Is(err, ErrSessionRevoked) {
+		t.Fatalf("aynı oturumun yeni refresh token'ı: %v, beklenen ErrSessionRevoked", err)
+	}
+	for name, token := range map[string]string{"ilk": first.AccessToken, "son": second.AccessToken} {
+		if _, err := s.Verify(token); !errors.Is(err, ErrSessionRevoked) {
+			t.Fatalf("%s access token: %v, beklenen ErrSessionRevoked", name, err)
+		}
+	}
+
+	// Başka oturumlar etkilenmez
+	other, err := s.Login(&domain.User{ID: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Verify(other.AccessToken); err != nil {
+		t.Fatalf("diğer oturum: %v", err)
+	}
+}
+
+// Oturum kapatılamıyorsa tekrar kullanım hatası yerine depo hatası döner ve token kabul edilmez
+func TestRefreshReuseFailsClosedWhenRevokeFails(t *testing.T) {
+	s := newSessionService(t, failingRevokes{repository.NewSessionRepository()})
+	first, err := s.Login(&domain.User{ID: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Refresh(first.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		pair, err := s.Refresh(first.RefreshToken)
+		if err == nil || pair != nil || errors.Is(err, ErrRefreshTokenReused) {
+			t.Fatalf("deneme %d: %+v, %v; beklenen depo hatası", i+1, pair, err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package calendar
+
+import (
+	"gofinancialsystem/internal/domain"
+	"time"
+
+	// Saat dilimi veritabanı olmayan ortamlarda da takvimin saat dilimi yüklenebilsin
+	_ "time/tzdata"
+)
+
+// Calendar, domain.BusinessCalendar arayüzünün hafta sonu günleri, tatiller ve işlem türü başına
+// kesim saatleriyle çalışan implementasyonudur. Günler takvimin saat diliminde belirlenir.
+type Calendar struct {
+	name     string
+	location *time.Location
+	weekend  map[time.Weekday]bool
+	holidays map[time.Time]Holiday                    // Gün -> tatil
+	cutoffs  map[domain.TransactionType]time.Duration // İşlem türü -> gün başından itibaren kesim saati
+}
+
+// Holiday, takvimdeki bir tatil günüdür. Cutoff verilmişse gün yarım iş günüdür (ör: arife): iş günü sayılır
+// ama kesim saati olan işlem türleri için kesim saati en geç Cutoff olur.
+type Holiday struct {
+	Date   time.Time
+	Name   string
+	Cutoff *time.Duration
+}
+
+// Her günün iş günü olduğu, tatil ve kesim saati olmayan UTC takvimini döndürür;
+// bu takvimde işlemlerin valör tarihi her zaman kayıt günüdür
+func Default() *Calendar {
+	return &Calendar{
+		name:     "default",
+		location: time.UTC,
+		weekend:  map[time.Weekday]bool{},
+		holidays: map[time.Time]Holiday{},
+		cutoffs:  map[domain.TransactionType]time.Duration{},
+	}
+}
+
+// Takvimin adını döndürür
+func (c *Calendar) Name() string {
+	return c.name
+}
+
+// t anının takvimin saat dilimindeki tarihini UTC gece yarısı olarak döndürür
+func (c *Calendar) Date(t time.Time) time.Time {
+	local := t.In(c.location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Günün hafta sonu veya tam gün tatil olmayan bir iş günü olup olmadığını döndürür
+func (c *Calendar) IsBusinessDay(day time.Time) bool {
+	day = domain.StartOfDay(day)
+	if c.weekend[day.Weekday()] {
+		return false
+	}
+	holiday, ok := c.holidays[day]
+	return !ok || holiday.Cutoff != nil
+}
+
+// day'den sonraki ilk iş gününü döndürür
+func (c *Calendar) NextBusinessDay(day time.Time) time.Time {
+	day = domain.StartOfDay(day).AddDate(0, 0, 1)
+	for !c.IsBusinessDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// Günün tatilini döndürür; gün tatil değilse false döner
+func (c *Calendar) Holiday(day time.Time) (Holiday, bool) {
+	holiday, ok := c.holidays[domain.StartOfDay(day)]
+	return holiday, ok
+}
+
+// at anında kaydedilen txType türündeki işlemin muhasebe ve valör tarihlerini döndürür.
+// Kayıt günü iş günü değilse veya işlem türünün o günkü kesim saatinde ya da sonrasında kaydedildiyse
+// valör tarihi sonraki iş günüdür.
+func (c *Calendar) Dates(txType domain.TransactionType, at time.Time) (booking, value time.Time) {
+	local := at.In(c.location)
+	booking = c.Date(at)
+	if !c.IsBusinessDay(booking) {
+		return booking, c.NextBusinessDay(booking)
+	}
+	if cutoff, ok := c.cutoff(txType, booking); ok {
+		midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, c.location)
+		if local.Sub(midnight) >= cutoff {
+			return booking, c.NextBusinessDay(booking)
+		}
+	}
+	return booking, booking
+}
+
+// İşlem türünün day günündeki kesim saatini döndürür; yarım iş günlerinde günün kesim saati daha erkense o kullanılır
+func (c *Calendar) cutoff(txType domain.TransactionType, day time.Time) (time.Duration, bool) {
+	cutoff, ok := c.cutoffs[txType]
+	if !ok {
+		return 0, false
+	}
+	if holiday, isHoliday := c.holidays[day]; isHoliday && holiday.Cutoff != nil && *holiday.Cutoff < cutoff {
+		cutoff = *holiday.Cutoff
+	}
+	return cutoff, true
+}
@@ -0,0 +1,94 @@
+package calendar
+
+import (
+	"gofinancialsystem/internal/domain"
+	"testing"
+	"time"
+)
+
+func loadTR(t *testing.T) *Calendar {
+	t.Helper()
+	c, err := LoadFile("../../calendars/tr.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Kesim saatinden sonra, hafta sonu ve tatilde kaydedilen işlemler sonraki iş gününe valörlenir;
+// günler ve kesim saatleri at'in saat diliminden bağımsız olarak takvimin saat diliminde (Europe/Istanbul) belirlenir
+func TestDates(t *testing.T) {
+	tr := loadTR(t)
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name           string
+		txType         domain.TransactionType
+		at             time.Time
+		booking, value time.Time
+	}{
+		{"kesim saatinden önce", domain.TransactionTransfer, time.Date(2026, 10, 27, 16, 59, 0, 0, istanbul), day(2026, 10, 27), day(2026, 10, 27)},
+		{"kesim saatinde", domain.TransactionTransfer, time.Date(2026, 10, 27, 17, 0, 0, 0, istanbul), day(2026, 10, 27), day(2026, 10, 28)},
+		{"para çekme kesim saati", domain.TransactionWithdraw, time.Date(2026, 10, 27, 17, 30, 0, 0, istanbul), day(2026, 10, 27), day(2026, 10, 28)},
+		{"kesim saati olmayan işlem", domain.TransactionDeposit, time.Date(2026, 10, 27, 20, 0, 0, 0, istanbul), day(2026, 10, 27), day(2026, 10, 27)},
+		{"arife, yarım gün kesiminden önce", domain.TransactionTransfer, time.Date(2026, 10, 28, 12, 29, 0, 0, istanbul), day(2026, 10, 28), day(2026, 10, 28)},
+		{"arife, yarım gün kesiminde", domain.TransactionTransfer, time.Date(2026, 10, 28, 12, 30, 0, 0, istanbul), day(2026, 10, 28), day(2026, 10, 30)},
+		{"arife, kesim saati olmayan işlem", domain.TransactionDeposit, time.Date(2026, 10, 28, 15, 0, 0, 0, istanbul), day(2026, 10, 28), day(2026, 10, 28)},
+		{"tatil", domain.TransactionTransfer, time.Date(2026, 10, 29, 10, 0, 0, 0, istanbul), day(2026, 10, 29), day(2026, 10, 30)},
+		{"tatilde kesim saati olmayan işlem", domain.TransactionDeposit, time.Date(2026, 10, 29, 10, 0, 0, 0, istanbul), day(2026, 10, 29), day(2026, 10, 30)},
+		{"cuma akşamı", domain.TransactionTransfer, time.Date(2026, 10, 30, 18, 0, 0, 0, istanbul), day(2026, 10, 30), day(2026, 11, 2)},
+		{"cumartesi", domain.TransactionTransfer, time.Date(2026, 10, 31, 10, 0, 0, 0, istanbul), day(2026, 10, 31), day(2026, 11, 2)},
+		{"pazar", domain.TransactionDeposit, time.Date(2026, 11, 1, 10, 0, 0, 0, istanbul), day(2026, 11, 1), day(2026, 11, 2)},
+		{"yılbaşı ve hafta sonu", domain.TransactionTransfer, time.Date(2026, 12, 31, 18, 0, 0, 0, istanbul), day(2026, 12, 31), day(2027, 1, 4)},
+
+		// İstanbul UTC+3'tür: 14:00 UTC kesim saatidir, 22:30 UTC ertesi gündür
+		{"UTC, kesim saatinden önce", domain.TransactionTransfer, time.Date(2026, 10, 27, 13, 59, 0, 0, time.UTC), day(2026, 10, 27), day(2026, 10, 27)},
+		{"UTC, kesim saatinde", domain.TransactionTransfer, time.Date(2026, 10, 27, 14, 0, 0, 0, time.UTC), day(2026, 10, 27), day(2026, 10, 28)},
+		{"UTC, İstanbul'da ertesi gün", domain.TransactionTransfer, time.Date(2026, 10, 27, 22, 30, 0, 0, time.UTC), day(2026, 10, 28), day(2026, 10, 28)},
+		{"UTC, arife kesimi", domain.TransactionTransfer, time.Date(2026, 10, 28, 9, 30, 0, 0, time.UTC), day(2026, 10, 28), day(2026, 10, 30)},
+		{"UTC cuma, İstanbul'da cumartesi", domain.TransactionDeposit, time.Date(2026, 10, 30, 21, 30, 0, 0, time.UTC), day(2026, 10, 31), day(2026, 11, 2)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			booking, value := tr.Dates(c.txType, c.at)
+			if !booking.Equal(c.booking) || !value.Equal(c.value) {
+				t.Fatalf("muhasebe %s, valör %s; beklenen %s, %s", booking.Format(time.DateOnly), value.Format(time.DateOnly),
+					c.booking.Format(time.DateOnly), c.value.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+// Arifeler yarım iş günüdür; tam gün tatiller ve hafta sonu iş günü değildir
+func TestBusinessDays(t *testing.T) {
+	tr := loadTR(t)
+	cases := []struct {
+		day      time.Time
+		business bool
+		holiday  string
+	}{
+		{day(2026, 10, 27), true, ""},
+		{day(2026, 10, 28), true, "Cumhuriyet Bayramı arifesi"},
+		{day(2026, 10, 29), false, "Cumhuriyet Bayramı"},
+		{day(2026, 10, 31), false, ""},
+		{day(2026, 11, 1), false, ""},
+	}
+	for _, c := range cases {
+		if got := tr.IsBusinessDay(c.day); got != c.business {
+			t.Errorf("%s iş günü: %v, beklenen %v", c.day.Format(time.DateOnly), got, c.business)
+		}
+		holiday, ok := tr.Holiday(c.day)
+		if ok != (c.holiday != "") || holiday.Name != c.holiday {
+			t.Errorf("%s tatili: %q, beklenen %q", c.day.Format(time.DateOnly), holiday.Name, c.holiday)
+		}
+	}
+	if next := tr.NextBusinessDay(day(2026, 10, 28)); !next.Equal(day(2026, 10, 30)) {
+		t.Errorf("arifeden sonraki iş günü %s, beklenen 2026-10-30", next.Format(time.DateOnly))
+	}
+}
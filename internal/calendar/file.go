@@ -0,0 +1,145 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"gofinancialsystem/internal/domain"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File, CALENDAR_FILE ile verilen iş günü takviminin YAML biçimidir:
+//
+//	name: TR
+//	timezone: Europe/Istanbul
+//	weekend: [saturday, sunday]
+//	cutoffs:
+//	  transfer: "17:00"
+//	holidays:
+//	  - date: 2026-01-01
+//	    name: Yılbaşı
+//	  - date: 2026-03-19
+//	    name: Ramazan Bayramı arifesi
+//	    cutoff: "12:30"
+//
+// Kesim saatleri işlem türü başınadır ve takvimin saat diliminde HH:MM biçimindedir; kesim saati verilmeyen
+// türler sadece hafta sonu ve tatillerde sonraki iş gününe valörlenir. cutoff verilen tatiller yarım iş günüdür.
+type File struct {
+	Name     string            `yaml:"name"`
+	Timezone string            `yaml:"timezone"`
+	Weekend  []string          `yaml:"weekend"`
+	Cutoffs  map[string]string `yaml:"cutoffs"`
+	Holidays []HolidayFile     `yaml:"holidays"`
+}
+
+// HolidayFile, takvim dosyasındaki bir tatil girdisidir
+type HolidayFile struct {
+	Date   string `yaml:"date"`
+	Name   string `yaml:"name"`
+	Cutoff string `yaml:"cutoff"`
+}
+
+// Takvim dosyasını okur
+func LoadFile(path string) (*Calendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseYAML(f)
+}
+
+// YAML biçimindeki takvimi okur ve doğrular (bkz. File)
+func ParseYAML(r io.Reader) (*Calendar, error) {
+	var file File
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("calendar: takvim dosyası okunamadı: %w", err)
+	}
+	calendar, err := New(file)
+	if err != nil {
+		return nil, fmt.Errorf("calendar: takvim dosyası geçersiz: %w", err)
+	}
+	return calendar, nil
+}
+
+// Takvim tanımını doğrular ve Calendar oluşturur; saat dilimi verilmezse UTC kullanılır
+func New(file File) (*Calendar, error) {
+	c := Default()
+	if file.Name != "" {
+		c.name = file.Name
+	}
+	if file.Timezone != "" {
+		location, err := time.LoadLocation(file.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("geçersiz saat dilimi %q: %w", file.Timezone, err)
+		}
+		c.location = location
+	}
+
+	for _, name := range file.Weekend {
+		day, ok := weekdays[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("geçersiz hafta sonu günü: %q", name)
+		}
+		c.weekend[day] = true
+	}
+	if len(c.weekend) == 7 {
+		return nil, errors.New("takvimde en az bir iş günü olmalı")
+	}
+
+	for txType, value := range file.Cutoffs {
+		if !domain.TransactionType(txType).IsValid() {
+			return nil, fmt.Errorf("geçersiz işlem türü: %q", txType)
+		}
+		cutoff, err := parseClock(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s kesim saati geçersiz: %w", txType, err)
+		}
+		c.cutoffs[domain.TransactionType(txType)] = cutoff
+	}
+
+	for _, entry := range file.Holidays {
+		day, err := time.Parse(time.DateOnly, entry.Date)
+		if err != nil {
+			return nil, fmt.Errorf("geçersiz tatil tarihi %q: YYYY-MM-DD biçiminde olmalı", entry.Date)
+		}
+		if _, exists := c.holidays[day]; exists {
+			return nil, fmt.Errorf("tatil tarihi birden fazla kez tanımlanmış: %s", entry.Date)
+		}
+		holiday := Holiday{Date: day, Name: entry.Name}
+		if entry.Cutoff != "" {
+			cutoff, err := parseClock(entry.Cutoff)
+			if err != nil {
+				return nil, fmt.Errorf("%s tatilinin kesim saati geçersiz: %w", entry.Date, err)
+			}
+			holiday.Cutoff = &cutoff
+		}
+		c.holidays[day] = holiday
+	}
+	return c, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// HH:MM biçimindeki saati gün başından itibaren süreye çevirir
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q HH:MM biçiminde olmalı", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,24 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// Open, verilen URL ile PostgreSQL bağlantı havuzunu açar ve bağlantıyı doğrular
+func Open(dbURL string) (*sql.DB, error) {
+	conn, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetMaxOpenConns(25)
+	conn.SetMaxIdleConns(5)
+	conn.SetConnMaxLifetime(30 * time.Minute)
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
@@ -0,0 +1,168 @@
+// Package dbtest, PostgreSQL'e karşı çalışan testler için geçici veritabanları sağlar.
+//
+// TEST_DATABASE_URL verilmişse o sunucu kullanılır (ör: CI'da açılan geçici bir PostgreSQL; URL biçiminde
+// olmalı ve kullanıcının veritabanı oluşturma yetkisi olmalıdır). Verilmemişse PATH'te initdb ve pg_ctl varsa
+// geçici bir dizinde yeni bir sunucu başlatılır. İkisi de yoksa PostgreSQL testleri atlanır; CI ortam değişkeni
+// verilmişse (CI sunucuları bunu tanımlar) atlanmak yerine başarısız olur, böylece CI'da veritabanı eksikliği fark edilir.
+// Her test kendi veritabanını alır; veritabanı test bitince silinir.
+package dbtest
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/lib/pq"
+)
+
+// EnvURL, kullanılacak PostgreSQL sunucusunun bağlantı URL'sini veren ortam değişkenidir
+const EnvURL = "TEST_DATABASE_URL"
+
+var errNoServer = errors.New("PostgreSQL testleri atlandı: " + EnvURL + " verilmedi ve PATH'te initdb/pg_ctl bulunamadı")
+
+var (
+	once      sync.Once
+	serverURL string
+	serverErr error
+	tempDir   string // Geçici sunucu başlatıldıysa veri ve soket dizini
+	databases atomic.Int64
+)
+
+// Main, paketin testlerini çalıştırır ve geçici sunucu başlatıldıysa durdurur; TestMain'den çağrılır:
+//
+//	func TestMain(m *testing.M) { os.Exit(dbtest.Main(m)) }
+func Main(m *testing.M) int {
+	code := m.Run()
+	if tempDir != "" {
+		exec.Command("pg_ctl", "-D", filepath.Join(tempDir, "data"), "-m", "immediate", "stop").Run()
+		os.RemoveAll(tempDir)
+	}
+	return code
+}
+
+// New, migration'ları uygulanmış boş bir veritabanı oluşturur ve bağlantı havuzunu döndürür
+func New(t testing.TB) *sql.DB {
+	t.Helper()
+	dbURL := Empty(t)
+	if err := Migrator(t, dbURL).Up(); err != nil {
+		t.Fatalf("migration uygulanamadı: %v", err)
+	}
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatalf("veritabanı açılamadı: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// Empty, migration uygulanmamış boş bir veritabanı oluşturur ve bağlantı URL'sini döndürür
+func Empty(t testing.TB) string {
+	t.Helper()
+	once.Do(func() { serverURL, serverErr = server() })
+	if errors.Is(serverErr, errNoServer) {
+		if os.Getenv("CI") != "" {
+			t.Fatal(serverErr)
+		}
+		t.Skip(serverErr)
+	}
+	if serverErr != nil {
+		t.Fatalf("PostgreSQL başlatılamadı: %v", serverErr)
+	}
+
+	admin, err := sql.Open("postgres", serverURL)
+	if err != nil {
+		t.Fatalf("PostgreSQL'e bağlanılamadı: %v", err)
+	}
+	defer admin.Close()
+	name := fmt.Sprintf("gofinancial_test_%d_%d", os.Getpid(), databases.Add(1))
+	if _, err := admin.Exec(`CREATE DATABASE ` + name); err != nil {
+		t.Fatalf("test veritabanı oluşturulamadı: %v", err)
+	}
+	t.Cleanup(func() {
+		admin, err := sql.Open("postgres", serverURL)
+		if err != nil {
+			return
+		}
+		defer admin.Close()
+		admin.Exec(`DROP DATABASE IF EXISTS ` + name)
+	})
+
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		t.Fatalf("%s URL biçiminde olmalı: %v", EnvURL, err)
+	}
+	u.Path = "/" + name
+	return u.String()
+}
+
+// Migrator, projenin migrations dizinini dbURL'deki veritabanına uygulayan migrate örneğini döndürür
+func Migrator(t testing.TB, dbURL string) *migrate.Migrate {
+	t.Helper()
+	_, file, _, _ := runtime.Caller(0)
+	dir := filepath.Join(filepath.Dir(file), "..", "..", "..", "migrations")
+	m, err := migrate.New("file://"+filepath.ToSlash(dir), dbURL)
+	if err != nil {
+		t.Fatalf("migration başlatılamadı: %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+	return m
+}
+
+// Kullanılacak sunucunun yönetici bağlantı URL'sini döndürür; gerekirse geçici bir sunucu başlatır
+func server() (string, error) {
+	if dbURL := os.Getenv(EnvURL); dbURL != "" {
+		return dbURL, nil
+	}
+	initdb, err := exec.LookPath("initdb")
+	if err != nil {
+		return "", errNoServer
+	}
+	pgCtl, err := exec.LookPath("pg_ctl")
+	if err != nil {
+		return "", errNoServer
+	}
+
+	dir, err := os.MkdirTemp("", "gofinancial-pg-")
+	if err != nil {
+		return "", err
+	}
+	data := filepath.Join(dir, "data")
+	if out, err := exec.Command(initdb, "-D", data, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync").CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("initdb: %v: %s", err, out)
+	}
+	port, err := freePort()
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	options := fmt.Sprintf("-p %d -k %s -c listen_addresses=127.0.0.1 -c fsync=off", port, dir)
+	if out, err := exec.Command(pgCtl, "-D", data, "-l", filepath.Join(dir, "server.log"), "-o", options, "-w", "start").CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("pg_ctl start: %v: %s", err, out)
+	}
+	tempDir = dir
+	return fmt.Sprintf("postgres://postgres@127.0.0.1:%d/postgres?sslmode=disable", port), nil
+}
+
+// İşletim sisteminden boş bir TCP portu alır
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"time"
+)
+
+// AccountType, hesabın türünü belirler
+type AccountType string
+
+const (
+	AccountChecking AccountType = "checking" // Vadesiz hesap
+	AccountSavings  AccountType = "savings"  // Birikim hesabı
+	AccountSub      AccountType = "sub"      // Bir ana hesaba bağlı alt hesap (ör: kumbara)
+)
+
+// AccountStatus, hesabın para hareketine açık olup olmadığını belirler
+type AccountStatus string
+
+const (
+	AccountActive AccountStatus = "active"
+	AccountFrozen AccountStatus = "frozen" // Geçici olarak para hareketine kapalı; tekrar açılabilir
+	AccountClosed AccountStatus = "closed" // Kalıcı olarak kapatılmış
+)
+
+// Nickname için izin verilen en fazla karakter sayısı
+const maxNicknameLength = 100
+
+var (
+	ErrAccountFrozen = NewError(CodeAccountInactive, "hesap dondurulmuş").WithMessage("frozen", "hesap dondurulmuş")
+	ErrAccountClosed = NewError(CodeAccountInactive, "hesap kapatılmış").WithMessage("closed", "hesap kapatılmış")
+)
+
+// Account, bir kullanıcının tek para birimindeki hesabıdır. Bir kullanıcının birden fazla hesabı olabilir;
+// Default işaretli hesap, hesap belirtilmeden (kullanıcı ID'si ile) yapılan işlemlerde kullanılır.
+type Account struct {
+	ID        int64         `json:"id"`
+	OwnerID   int64         `json:"owner_id"`
+	ParentID  *int64        `json:"parent_id,omitempty"` // Sadece alt hesaplarda dolu
+	Type      AccountType   `json:"type"`
+	Currency  string        `json:"currency"`
+	Status    AccountStatus `json:"status"`
+	Nickname  string        `json:"nickname,omitempty"`
+	Default   bool          `json:"default"` // Kullanıcının bu para birimindeki varsayılan hesabı
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// Hesap verisinin geçerli olup olmadığını kontrol eder
+func (a *Account) Validate() error {
+	switch a.Type {
+	case AccountChecking, AccountSavings:
+		if a.ParentID != nil {
+			return NewValidationError("parent_requires_sub", "sadece alt hesapların ana hesabı olabilir")
+		}
+	case AccountSub:
+		if a.ParentID == nil {
+			return NewValidationError("sub_requires_parent", "alt hesap için ana hesap gerekli")
+		}
+		if a.Default {
+			return NewValidationError("sub_cannot_be_default", "alt hesap varsayılan hesap olamaz")
+		}
+	default:
+		return NewValidationError("invalid_account_type", "geçersiz hesap türü")
+	}
+	if _, err := ValidateCurrency(a.Currency); err != nil {
+		return err
+	}
+	if len([]rune(a.Nickname)) > maxNicknameLength {
+		return NewValidationError("nickname_too_long", "hesap adı çok uzun")
+	}
+	return nil
+}
+
+// Hesabın para hareketine açık olduğunu kontrol eder
+func (a *Account) EnsureActive() error {
+	switch a.Status {
+	case AccountActive:
+		return nil
+	case AccountFrozen:
+		return ErrAccountFrozen.WithDetails(map[string]interface{}{"account_id": a.ID})
+	default:
+		return ErrAccountClosed.WithDetails(map[string]interface{}{"account_id": a.ID})
+	}
+}
+
+// Tutarın hesabın para biriminde olduğunu kontrol eder; farklı para birimleri çevrilmeden karıştırılamaz
+func (a *Account) EnsureCurrency(amount Money) error {
+	if NormalizeCurrency(amount.Currency) != a.Currency {
+		return ErrCurrencyMismatch.WithMessage("account_currency", "tutarın para birimi hesabın para birimiyle aynı olmalı").
+			WithParams(map[string]interface{}{"currency": a.Currency}).
+			WithDetails(map[string]interface{}{"account_id": a.ID, "account_currency": a.Currency})
+	}
+	return nil
+}
+
+// AccountRepository, kullanıcı hesaplarını saklar
+type AccountRepository interface {
+	Create(account *Account) error
+	FindByID(id int64) (*Account, error)
+	// Kullanıcının kapalı olmayan varsayılan hesabını getirir
+	FindDefault(ownerID int64, currency string) (*Account, error)
+	// Kullanıcının tüm hesaplarını ID sırasıyla döndürür
+	ListByOwner(ownerID int64) ([]*Account, error)
+	// Verilen türdeki kapatılmamış hesapları ID sırasıyla döndürür (faiz gibi toplu işler için)
+	ListOpenByType(accountType AccountType) ([]*Account, error)
+	// Nickname ve Status alanlarını günceller
+	Update(account *Account) error
+}
+
+// AccountService, hesap açma, güncelleme ve kapatma kurallarını uygular
+type AccountService interface {
+	Open(account *Account) error
+	GetByID(id int64) (*Account, error)
+	ListByOwner(ownerID int64) ([]*Account, error)
+	Update(id int64, nickname *string, status *AccountStatus) (*Account, error)
+	Close(id int64) (*Account, error)
+	// Kullanıcının para birimindeki varsayılan hesabını döndürür; create true ise yoksa açar
+	DefaultAccount(ownerID int64, currency string, create bool) (*Account, error)
+}
@@ -5,27 +5,79 @@ import (
 	"time"
 )
 
+// Balance, bir hesabın bakiye projeksiyonudur; UserID hesabın sahibidir.
+// Ledger bakiyesi (Amount) kayıtlı tutardır; kullanılabilir bakiye bundan aktif provizyonlar düşülerek bulunur.
 type Balance struct {
-	UserID        int64     `json:"user_id"`
-	Amount        float64   `json:"amount"`
-	LastUpdatedAt time.Time `json:"last_updated_at"`
-	mu            sync.Mutex
+	AccountID       int64     `json:"account_id"`
+	UserID          int64     `json:"user_id"`
+	Amount          Money     `json:"ledger"`
+	Held            Money     `json:"held"`             // Aktif provizyonların toplamı
+	Available       Money     `json:"available"`        // Amount - Held; kredili hesapta kredi limitine kadar negatif olabilir
+	OverdraftLimit  Money     `json:"overdraft_limit"`  // Kullanılabilir bakiyenin inebileceği en düşük değerin mutlak değeri
+	AvailableCredit Money     `json:"available_credit"` // Kullanılmamış kredi limiti; para çıkışları Available + AvailableCredit ile sınırlıdır
+	LastUpdatedAt   time.Time `json:"last_updated_at"`
+	mu              sync.Mutex
 }
 
-func (b *Balance) Add(amount float64) {
+// Ledger bakiyesi, provizyon tutarı ve kredi limitinden kullanılabilir bakiyeyi ve krediyi hesaplayarak bakiye değerlerini atar
+func (b *Balance) SetAmounts(amount, held, overdraft Money) {
+	b.Amount = amount
+	b.Held = NewMoney(held.Amount, amount.Currency)
+	b.Available = NewMoney(amount.Amount-held.Amount, amount.Currency)
+	b.OverdraftLimit = NewMoney(overdraft.Amount, amount.Currency)
+	credit := overdraft.Amount
+	if b.Available.IsNegative() {
+		credit += b.Available.Amount
+	}
+	if credit < 0 {
+		// Limit, kullanılan kredinin altına indirilmiş
+		credit = 0
+	}
+	b.AvailableCredit = NewMoney(credit, amount.Currency)
+}
+
+// Bakiye ve provizyon değişikliğini uygular. Kullanılabilir bakiyeyi azaltan bir değişiklik onu kredi limitinin
+// (overdraft) altına düşürecekse ErrInsufficientFunds döner; limit kullanılan kredinin altına indirilmiş olsa bile
+// kullanılabilir bakiyeyi artıran değişiklikler uygulanır. Tüm BalanceRepository implementasyonları aynı kuralı
+// bu fonksiyonla uygular.
+func ApplyBalanceChange(amount, held, overdraft, amountDelta, heldDelta Money) (Money, Money, error) {
+	newAmount, err := amount.Add(amountDelta)
+	if err != nil {
+		return Money{}, Money{}, err
+	}
+	if !heldDelta.IsZero() && NormalizeCurrency(heldDelta.Currency) != newAmount.Currency {
+		return Money{}, Money{}, ErrCurrencyMismatch
+	}
+	newHeld := NewMoney(held.Amount+heldDelta.Amount, newAmount.Currency)
+	if newHeld.IsNegative() {
+		return Money{}, Money{}, ErrInvalidHoldState.WithMessage("release_exceeds_held", "serbest bırakılan provizyon tutarı ayrılan tutarı aşıyor")
+	}
+	if newAmount.Amount-newHeld.Amount < -overdraft.Amount && amountDelta.Amount-heldDelta.Amount < 0 {
+		return Money{}, Money{}, ErrInsufficientFunds
+	}
+	return newAmount, newHeld, nil
+}
+
+func (b *Balance) Add(amount Money) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	b.Amount += amount
+	sum, err := b.Amount.Add(amount)
+	if err != nil {
+		return err
+	}
+	b.Amount = sum
 	b.LastUpdatedAt = time.Now()
+	return nil
 }
 
-func (b *Balance) Subtract(amount float64) bool {
+func (b *Balance) Subtract(amount Money) bool {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	if b.Amount < amount {
+	diff, err := b.Amount.Sub(amount)
+	if err != nil || diff.IsNegative() {
 		return false
 	}
-	b.Amount -= amount
+	b.Amount = diff
 	b.LastUpdatedAt = time.Now()
 	return true
 }
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// BusinessCalendar, iş günlerini ve işlemlerin muhasebe (booking) ve valör (value) tarihlerini belirler.
+// Günler, takvimin saat dilimindeki tarihin UTC gece yarısı olarak gösterilir (bkz. StartOfDay).
+type BusinessCalendar interface {
+	// t anının takvimin saat dilimindeki tarihini döndürür
+	Date(t time.Time) time.Time
+	// Günün hafta sonu veya tatil olmayan bir iş günü olup olmadığını döndürür
+	IsBusinessDay(day time.Time) bool
+	// day'den sonraki ilk iş gününü döndürür
+	NextBusinessDay(day time.Time) time.Time
+	// at anında kaydedilen txType türündeki işlemin muhasebe ve valör tarihlerini döndürür. Muhasebe tarihi
+	// kayıt günüdür; valör tarihi kayıt günü iş günü değilse veya işlem türünün kesim saatinden sonra
+	// kaydedildiyse sonraki iş günüdür.
+	Dates(txType TransactionType, at time.Time) (booking, value time.Time)
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+)
+
+// DelegationScope, yetki devrinin kapsamını belirler
+type DelegationScope string
+
+const (
+	DelegationRead  DelegationScope = "read"  // Bakiye ve işlem geçmişini görüntüleme
+	DelegationWrite DelegationScope = "write" // Hesap adına para hareketi yapma (read'i de kapsar)
+)
+
+// Delegation, hesap sahibinin başka bir kullanıcıya hesabı üzerinde verdiği açık yetkidir
+type Delegation struct {
+	ID         int64           `json:"id"`
+	OwnerID    int64           `json:"owner_id"`    // Yetkiyi veren hesap sahibi
+	DelegateID int64           `json:"delegate_id"` // Yetkiyi alan kullanıcı
+	Scope      DelegationScope `json:"scope"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ExpiresAt  *time.Time      `json:"expires_at,omitempty"`
+}
+
+func (d *Delegation) Validate() error {
+	if d.OwnerID == d.DelegateID {
+		return NewValidationError("self_delegation", "kullanıcı kendine yetki devredemez")
+	}
+	if d.Scope != DelegationRead && d.Scope != DelegationWrite {
+		return NewValidationError("invalid_scope", "geçersiz yetki kapsamı")
+	}
+	return nil
+}
+
+// Yetki devrinin verilen zamanda istenen kapsamı sağlayıp sağlamadığını döndürür
+func (d *Delegation) Allows(scope DelegationScope, now time.Time) bool {
+	if d.ExpiresAt != nil && !now.Before(*d.ExpiresAt) {
+		return false
+	}
+	return d.Scope == DelegationWrite || d.Scope == scope
+}
+
+var ErrDelegationNotFound = NewError(CodeDelegationNotFound, "yetki devri bulunamadı")
+
+type DelegationRepository interface {
+	Create(d *Delegation) error
+	FindByID(id int64) (*Delegation, error)
+	// Sahibin temsilciye verdiği yetkileri listeler
+	ListBetween(ownerID, delegateID int64) ([]*Delegation, error)
+	ListByUser(userID int64) ([]*Delegation, error)
+	Delete(id int64) error
+}
@@ -0,0 +1,136 @@
+package domain
+
+import "errors"
+
+// ErrorCode, istemcilerin hata türünü mesaj metnine bakmadan ayırt edebilmesi için
+// sabit, makine tarafından okunabilir hata kodudur. Kodlar yayınlandıktan sonra değiştirilmemelidir.
+type ErrorCode string
+
+const (
+	CodeInvalidRequest          ErrorCode = "invalid_request"
+	CodeValidationFailed        ErrorCode = "validation_failed"
+	CodeInvalidAmount           ErrorCode = "invalid_amount"
+	CodeAmountOverflow          ErrorCode = "amount_overflow"
+	CodeCurrencyMismatch        ErrorCode = "currency_mismatch"
+	CodeUnsupportedCurrency     ErrorCode = "unsupported_currency"
+	CodeInsufficientFunds       ErrorCode = "insufficient_funds"
+	CodeAccountNotFound         ErrorCode = "account_not_found"
+	CodeAccountInactive         ErrorCode = "account_inactive"
+	CodeUserNotFound            ErrorCode = "user_not_found"
+	CodeTransactionNotFound     ErrorCode = "transaction_not_found"
+	CodeDelegationNotFound      ErrorCode = "delegation_not_found"
+	CodeNotFound                ErrorCode = "not_found"
+	CodeRateNotFound            ErrorCode = "rate_not_found"
+	CodeQuoteNotFound           ErrorCode = "quote_not_found"
+	CodeQuoteExpired            ErrorCode = "quote_expired"
+	CodeQuoteAlreadyUsed        ErrorCode = "quote_already_used"
+	CodeInvalidTransactionState ErrorCode = "invalid_transaction_state"
+	CodeHoldNotFound            ErrorCode = "hold_not_found"
+	CodeHoldExpired             ErrorCode = "hold_expired"
+	CodeInvalidHoldState        ErrorCode = "invalid_hold_state"
+	CodeLimitExceeded           ErrorCode = "limit_exceeded"
+	CodeInvalidCredentials      ErrorCode = "invalid_credentials"
+	CodeUnauthorized            ErrorCode = "unauthorized"
+	CodeInvalidToken            ErrorCode = "invalid_token"
+	CodeTokenExpired            ErrorCode = "token_expired"
+	CodeInvalidRefreshToken     ErrorCode = "invalid_refresh_token"
+	CodeRefreshTokenReused      ErrorCode = "refresh_token_reused"
+	CodeSessionRevoked          ErrorCode = "session_revoked"
+	CodeForbidden               ErrorCode = "forbidden"
+	CodeConflict                ErrorCode = "conflict"
+	CodeIdempotencyKeyReused    ErrorCode = "idempotency_key_reused"
+	CodeIdempotencyInProgress   ErrorCode = "idempotency_in_progress"
+	CodeRouteNotFound           ErrorCode = "route_not_found"
+	CodeMethodNotAllowed        ErrorCode = "method_not_allowed"
+	CodeRateLimited             ErrorCode = "rate_limited"
+	CodePayloadTooLarge         ErrorCode = "payload_too_large"
+	CodeTimeout                 ErrorCode = "timeout"
+	CodeInternal                ErrorCode = "internal_error"
+)
+
+// Error, koduyla birlikte taşınan domain hatasıdır.
+// errors.Is karşılaştırması koda göre yapılır; mesajı veya detayları farklı kopyalar da eşleşir.
+// Message varsayılan (Türkçe) metindir; API katmanı Key ile mesaj kataloğundan çeviriyi alır.
+type Error struct {
+	Code    ErrorCode
+	Key     string                 // Mesaj kataloğu anahtarı; boşsa Code kullanılır
+	Message string                 // Katalogda çeviri yoksa kullanılan varsayılan mesaj
+	Params  map[string]interface{} // Mesajdaki {isim} yer tutucularının değerleri
+	Details map[string]interface{}
+}
+
+var (
+	ErrInsufficientFunds       = NewError(CodeInsufficientFunds, "yetersiz bakiye")
+	ErrAccountNotFound         = NewError(CodeAccountNotFound, "hesap bulunamadı")
+	ErrInvalidAmount           = NewError(CodeInvalidAmount, "tutar sıfırdan büyük olmalı")
+	ErrUserNotFound            = NewError(CodeUserNotFound, "kullanıcı bulunamadı")
+	ErrTransactionNotFound     = NewError(CodeTransactionNotFound, "işlem bulunamadı")
+	ErrInvalidTransactionState = NewError(CodeInvalidTransactionState, "işlem bu durumda değiştirilemez")
+	ErrInvalidCredentials      = NewError(CodeInvalidCredentials, "kullanıcı adı veya şifre hatalı")
+	ErrConflict                = NewError(CodeConflict, "istek mevcut durumla çakışıyor")
+	ErrInternal                = NewError(CodeInternal, "beklenmeyen bir hata oluştu")
+)
+
+// Yeni bir domain hatası oluşturur
+func NewError(code ErrorCode, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Doğrulama hatası oluşturur (ör: boş alan, geçersiz format); key katalogda "validation_failed.<key>" olur
+func NewValidationError(key, message string) *Error {
+	return NewError(CodeValidationFailed, message).WithMessage(key, message)
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Mesaj kataloğundaki anahtarı döndürür
+func (e *Error) MessageKey() string {
+	if e.Key != "" {
+		return e.Key
+	}
+	return string(e.Code)
+}
+
+// Aynı koda sahip, mesajı değiştirilmiş bir kopya döndürür; katalog anahtarı "<kod>.<variant>" olur
+func (e *Error) WithMessage(variant, message string) *Error {
+	c := *e
+	c.Key = string(e.Code) + "." + variant
+	c.Message = message
+	return &c
+}
+
+// Mesajdaki yer tutucular için değerler içeren bir kopya döndürür
+func (e *Error) WithParams(params map[string]interface{}) *Error {
+	c := *e
+	c.Params = params
+	return &c
+}
+
+// Aynı koda sahip, verilen detayları içeren bir kopya döndürür
+func (e *Error) WithDetails(details map[string]interface{}) *Error {
+	c := *e
+	c.Details = make(map[string]interface{}, len(e.Details)+len(details))
+	for k, v := range e.Details {
+		c.Details[k] = v
+	}
+	for k, v := range details {
+		c.Details[k] = v
+	}
+	return &c
+}
+
+// Hata zincirinde bir domain hatası varsa onu döndürür
+func AsError(err error) (*Error, bool) {
+	var de *Error
+	if errors.As(err, &de) {
+		return de, true
+	}
+	return nil, false
+}
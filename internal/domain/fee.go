@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"time"
+)
+
+// Ücret hesaplarında yüzdeler baz puan (1/10000) olarak verilir
+const bpsDenominator = 10000
+
+// FeeOperation, ücret kuralının uygulandığı para hareketi türüdür
+type FeeOperation string
+
+const (
+	FeeWithdraw FeeOperation = "withdraw" // Para çekme
+	FeeTransfer FeeOperation = "transfer" // Aynı para biriminde transfer
+	FeeFX       FeeOperation = "fx"       // Döviz çevirili transfer
+)
+
+// Ücretin uygulandığı işlemin ücret türünü döndürür; ücrete tabi olmayan işlemlerde false döner
+func FeeOperationOf(tx *Transaction) (FeeOperation, bool) {
+	switch {
+	case tx.Type == TransactionWithdraw:
+		return FeeWithdraw, true
+	case tx.Type == TransactionTransfer && tx.Conversion != nil:
+		return FeeFX, true
+	case tx.Type == TransactionTransfer:
+		return FeeTransfer, true
+	}
+	return "", false
+}
+
+// İşlem türünü ve döviz çevirisi olup olmadığını döndürür; ücretsiz kullanım hakkı bu işlemler üzerinden sayılır
+func (o FeeOperation) filter() (TransactionType, bool) {
+	if o == FeeWithdraw {
+		return TransactionWithdraw, false
+	}
+	return TransactionTransfer, o == FeeFX
+}
+
+func (o FeeOperation) IsValid() bool {
+	return o == FeeWithdraw || o == FeeTransfer || o == FeeFX
+}
+
+// FeeRule, bir ücret türü ve para birimi için ücret tarifesidir. Ücret sabit tutar ile tutarın
+// yüzdesinin toplamıdır; varsa en az ve en fazla ücrete sıkıştırılır. Takvim ayı içinde ilk
+// FreePerMonth işlem ücretsizdir.
+type FeeRule struct {
+	Operation    FeeOperation `json:"operation"`
+	Currency     string       `json:"currency"` // Döviz çevirisinde gönderilen tutarın para birimi
+	Flat         *Money       `json:"flat,omitempty"`
+	PercentBps   int64        `json:"percent_bps,omitempty"` // Tutarın baz puan cinsinden yüzdesi (25 = %0,25)
+	Min          *Money       `json:"min,omitempty"`
+	Max          *Money       `json:"max,omitempty"`
+	FreePerMonth int          `json:"free_per_month,omitempty"`
+}
+
+// Kuralı doğrular; para birimi boşsa varsayılan para birimi kullanılır ve tutarlar bu para biriminde olmalıdır
+func (r *FeeRule) Normalize() error {
+	if !r.Operation.IsValid() {
+		return NewValidationError("invalid_fee_operation", "ücret türü withdraw, transfer veya fx olmalı")
+	}
+	currency, err := ValidateCurrency(NormalizeCurrency(r.Currency))
+	if err != nil {
+		return err
+	}
+	r.Currency = currency
+	for _, m := range []*Money{r.Flat, r.Min, r.Max} {
+		if m == nil {
+			continue
+		}
+		if NormalizeCurrency(m.Currency) != currency {
+			return ErrCurrencyMismatch.WithMessage("fee_currency", "ücret tutarları kuralın para biriminde olmalı: {currency}").
+				WithParams(map[string]interface{}{"currency": currency})
+		}
+		if m.IsNegative() {
+			return NewValidationError("negative_fee", "ücret tutarları negatif olamaz")
+		}
+	}
+	if r.PercentBps < 0 || r.PercentBps > bpsDenominator {
+		return NewValidationError("invalid_fee_percent", "ücret yüzdesi 0 ile {max} baz puan arasında olmalı").
+			WithParams(map[string]interface{}{"max": bpsDenominator})
+	}
+	if r.Min != nil && r.Max != nil && r.Min.Amount > r.Max.Amount {
+		return NewValidationError("invalid_fee_range", "en düşük ücret en yüksek ücretten büyük olamaz")
+	}
+	if r.FreePerMonth < 0 {
+		return NewValidationError("invalid_free_tier", "ücretsiz işlem sayısı negatif olamaz")
+	}
+	return nil
+}
+
+// Tutarın ücretini ücretsiz kullanım hakkını hesaba katmadan hesaplar; yüzde kısmı yukarı yuvarlanır
+func (r *FeeRule) Fee(amount Money) (Money, error) {
+	fee, err := amount.MulRat(r.PercentBps, bpsDenominator, RoundUp)
+	if err != nil {
+		return Money{}, err
+	}
+	fee.Currency = r.Currency
+	if r.Flat != nil {
+		fee.Amount += r.Flat.Amount
+	}
+	if r.Min != nil && fee.Amount < r.Min.Amount {
+		fee.Amount = r.Min.Amount
+	}
+	if r.Max != nil && fee.Amount > r.Max.Amount {
+		fee.Amount = r.Max.Amount
+	}
+	return fee, nil
+}
+
+// Kuralları doğrular; aynı ücret türü ve para birimi için birden fazla kural olamaz
+func NormalizeFeeRules(rules []FeeRule) error {
+	seen := make(map[string]bool, len(rules))
+	for i := range rules {
+		if err := rules[i].Normalize(); err != nil {
+			return err
+		}
+		key := string(rules[i].Operation) + ":" + rules[i].Currency
+		if seen[key] {
+			return NewValidationError("duplicate_fee_rule", "aynı ücret türü ve para birimi için birden fazla kural var")
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// FeeSchedule, geçerli ücret kurallarıdır
+type FeeSchedule struct {
+	Rules     []FeeRule `json:"rules"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FeeMovement, ücreti hesaplanacak para hareketidir
+type FeeMovement struct {
+	UserID    int64 // Ücreti ödeyen (gönderen) kullanıcı
+	Operation FeeOperation
+	Amount    Money // Döviz çevirisinde gönderilen tutar
+}
+
+// FeeQuote, bir para hareketinin ücretidir
+type FeeQuote struct {
+	Operation     FeeOperation `json:"operation"`
+	Amount        Money        `json:"amount"`
+	Fee           Money        `json:"fee"`                      // Kural yoksa veya işlem ücretsizse sıfır
+	Total         Money        `json:"total"`                    // Hesaptan düşecek toplam tutar
+	FreeRemaining *int         `json:"free_remaining,omitempty"` // Bu işlemden sonra ay içinde kalan ücretsiz işlem sayısı
+	Rule          *FeeRule     `json:"rule,omitempty"`
+}
+
+// Ücretsiz kullanım hakkının sayıldığı, hareketle aynı türdeki işlemlerin filtresi (from dahil, ay başından itibaren)
+func (m FeeMovement) UsageFilter(from time.Time) TransactionFilter {
+	txType, converted := m.Operation.filter()
+	return TransactionFilter{
+		UserID:      m.UserID,
+		Direction:   DirectionOut,
+		Types:       []TransactionType{txType},
+		Statuses:    []TransactionStatus{TransactionCompleted},
+		Currency:    NormalizeCurrency(m.Amount.Currency),
+		CreatedFrom: &from,
+		Converted:   &converted,
+	}
+}
+
+// FeeService, para hareketlerinin ücretlerini ücret tarifesine göre hesaplar
+type FeeService interface {
+	// Hareketin ücretini hesaplar. Ücretsiz kullanım hakkı txs üzerinden okunur; böylece ücret,
+	// para hareketiyle aynı unit of work içinde hesaplanabilir.
+	Calculate(txs TransactionRepository, movement FeeMovement) (*FeeQuote, error)
+	// Hareket yapılmadan önce kullanıcıya gösterilecek ücreti hesaplar
+	Preview(movement FeeMovement) (*FeeQuote, error)
+	Schedule() *FeeSchedule
+	// Ücret tarifesini doğrular ve tamamen değiştirir
+	SetRules(rules []FeeRule) (*FeeSchedule, error)
+}
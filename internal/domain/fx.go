@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRateNotFound     = NewError(CodeRateNotFound, "bu para birimi çifti için kur bulunamadı")
+	ErrQuoteNotFound    = NewError(CodeQuoteNotFound, "kur teklifi bulunamadı")
+	ErrQuoteExpired     = NewError(CodeQuoteExpired, "kur teklifinin süresi doldu")
+	ErrQuoteAlreadyUsed = NewError(CodeQuoteAlreadyUsed, "kur teklifi zaten kullanıldı")
+)
+
+// FXConversion, farklı para birimleri arasındaki bir işlemde kullanılan kuru denetim için kaydeder.
+// İşlemin Amount alanı gönderenden düşen tutar, Target ise alıcıya geçen tutardır.
+type FXConversion struct {
+	QuoteID     string    `json:"quote_id,omitempty"` // Çevirili işlemin geri alma ve iadelerinde boştur
+	Rate        string    `json:"rate"`               // Spread uygulanmış, müşteriye verilen kur (1 kaynak = Rate hedef)
+	MidRate     string    `json:"mid_rate"`           // Kur tablosundaki orta kur
+	SpreadBps   int64     `json:"spread_bps"`
+	RateVersion time.Time `json:"rate_version"` // Kurun alındığı kur tablosu sürümü
+	Target      Money     `json:"target"`
+}
+
+// Çevirinin ters yöndeki karşılığını döndürür: aynı kur sürümünün ters kurlarıyla hedef para biriminden
+// kaynak para birimine. Çevirili işlemin geri alma ve iadelerinde kullanılır; target gönderene geri dönen tutardır.
+// Ters çevirinin kur teklifi olmadığı için QuoteID boştur.
+func (c FXConversion) Reverse(target Money) (FXConversion, error) {
+	rate, err := inverseRate(c.Rate)
+	if err != nil {
+		return FXConversion{}, err
+	}
+	mid, err := inverseRate(c.MidRate)
+	if err != nil {
+		return FXConversion{}, err
+	}
+	return FXConversion{Rate: rate, MidRate: mid, SpreadBps: c.SpreadBps, RateVersion: c.RateVersion, Target: target}, nil
+}
+
+// Ondalık kurun tersini 10 basamağa yuvarlanmış, sondaki sıfırları atılmış metin olarak döndürür
+func inverseRate(s string) (string, error) {
+	rate, ok := new(big.Rat).SetString(s)
+	if !ok || rate.Sign() <= 0 {
+		return "", fmt.Errorf("geçersiz kur: %q", s)
+	}
+	inverse := strings.TrimRight(rate.Inv(rate).FloatString(10), "0")
+	return strings.TrimSuffix(inverse, "."), nil
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"time"
+)
+
+// HoldStatus, provizyonun yaşam döngüsündeki durumudur
+type HoldStatus string
+
+const (
+	HoldAuthorized HoldStatus = "authorized" // Tutar kullanılabilir bakiyeden ayrılmış, henüz tahsil edilmemiş
+	HoldCaptured   HoldStatus = "captured"   // Tamamı veya bir kısmı tahsil edilmiş; kalan serbest bırakılmış
+	HoldVoided     HoldStatus = "voided"     // İptal edilmiş; tutarın tamamı serbest bırakılmış
+	HoldExpired    HoldStatus = "expired"    // Süresi dolduğu için otomatik olarak serbest bırakılmış
+)
+
+// Bir provizyonun açık kalabileceği en uzun süre
+const MaxHoldTTL = 30 * 24 * time.Hour
+
+var (
+	ErrHoldNotFound     = NewError(CodeHoldNotFound, "provizyon bulunamadı")
+	ErrHoldExpired      = NewError(CodeHoldExpired, "provizyonun süresi doldu")
+	ErrInvalidHoldState = NewError(CodeInvalidHoldState, "provizyon bu durumda değiştirilemez")
+)
+
+// Hold, bir hesabın kullanılabilir bakiyesinden ayrılmış ama henüz ledger'a yazılmamış tutardır (kart provizyonu gibi).
+// Provizyon ledger bakiyesini değiştirmez; tahsil edildiğinde tahsil edilen tutar için normal bir işlem oluşturulur.
+type Hold struct {
+	ID             int64      `json:"id"`
+	AccountID      int64      `json:"account_id"`
+	OwnerID        int64      `json:"owner_id"`
+	ToAccountID    *int64     `json:"to_account_id,omitempty"` // Tahsilatta paranın gideceği hesap; boşsa para çekme olarak tahsil edilir
+	Amount         Money      `json:"amount"`
+	CapturedAmount Money      `json:"captured_amount"`
+	Status         HoldStatus `json:"status"`
+	TransactionID  *int64     `json:"transaction_id,omitempty"` // Tahsilatta oluşturulan işlem
+	ExpiresAt      time.Time  `json:"expires_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	TransactionDetails
+}
+
+// Provizyonun hâlâ tahsil veya iptal edilebilir durumda olduğunu kontrol eder
+func (h *Hold) EnsureAuthorized() error {
+	if h.Status != HoldAuthorized {
+		return ErrInvalidHoldState.WithMessage("not_authorized", "sadece onaylanmış provizyonlar tahsil veya iptal edilebilir").
+			WithDetails(map[string]interface{}{"status": h.Status})
+	}
+	return nil
+}
+
+// Provizyonun süresinin now itibarıyla dolup dolmadığını döndürür
+func (h *Hold) Expired(now time.Time) bool {
+	return !now.Before(h.ExpiresAt)
+}
+
+// HoldRepository, provizyon kayıtlarını saklar
+type HoldRepository interface {
+	Create(hold *Hold) error
+	FindByID(id int64) (*Hold, error)
+	// FindByID gibidir; unit of work içinde kaydı transaction sonuna kadar kilitler
+	FindByIDForUpdate(id int64) (*Hold, error)
+	// Status, CapturedAmount, TransactionID ve UpdatedAt alanlarını günceller
+	Update(hold *Hold) error
+	// Hesabın provizyonlarını ID sırasıyla listeler
+	ListByAccount(accountID int64) ([]*Hold, error)
+	// Süresi now itibarıyla dolmuş, hâlâ onaylı provizyonları listeler
+	ListExpired(now time.Time) ([]*Hold, error)
+}
+
+// HoldService, provizyon koyma, tahsil, iptal ve süre aşımı kurallarını uygular
+type HoldService interface {
+	// Hesabın kullanılabilir bakiyesinden tutarı ayırır; ttl sıfırsa varsayılan süre kullanılır
+	Place(hold *Hold, ttl time.Duration) error
+	GetByID(id int64) (*Hold, error)
+	ListByAccount(accountID int64) ([]*Hold, error)
+	// Provizyonun amount kadarını tahsil eder (nil ise tamamını); kalan tutar serbest bırakılır
+	Capture(id int64, amount *Money) (*Hold, error)
+	// Provizyonu iptal eder ve tutarın tamamını serbest bırakır
+	Void(id int64) (*Hold, error)
+	// Süresi dolmuş provizyonları serbest bırakır ve kaç provizyonun sona erdiğini döndürür
+	ExpireDue(now time.Time) (int, error)
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord, Idempotency-Key ile gelen bir isteğin parmak izini ve ilk yanıtını tutar
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string // İstek gövdesi, metot ve yolun SHA-256 özeti
+	StatusCode  int    // 0 ise istek hâlâ işleniyor demektir
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// İlk isteğin yanıtı kaydedildiyse true döner
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// IdempotencyRepository, idempotency anahtarlarını saklayan değiştirilebilir depodur
+type IdempotencyRepository interface {
+	// Anahtar yoksa veya süresi dolmuşsa kaydı oluşturur ve nil döner; aksi halde mevcut kaydı döner
+	Reserve(rec *IdempotencyRecord) (*IdempotencyRecord, error)
+	// İlk isteğin yanıtını kaydeder
+	Complete(key string, statusCode int, contentType string, body []byte) error
+	// Yanıt kaydedilmeden anahtarı serbest bırakır (ör: sunucu hatası)
+	Release(key string) error
+	// Süresi dolmuş anahtarları siler
+	DeleteExpired(now time.Time) error
+}
@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"math/big"
+	"time"
+)
+
+// Tahakkuklar kuruş altı hassasiyetle, alt birimin milyonda biri cinsinden tutulur
+const AccrualScale = 1_000_000
+
+var ErrAccrualExists = ErrConflict.WithMessage("accrual_exists", "bu tarih için faiz tahakkuku zaten yapılmış")
+
+// DayCount, yıllık faizin günlere nasıl bölüneceğini belirleyen gün sayım kuralıdır
+type DayCount string
+
+const (
+	DayCountACT365 DayCount = "ACT/365" // Her gün yılın 1/365'i; artık yıllarda yıl 366/365 faiz işler
+	DayCount30360  DayCount = "30/360"  // Her ay 30, yıl 360 gün sayılır (ABD 30/360 kuralı)
+)
+
+func (c DayCount) IsValid() bool {
+	return c == DayCountACT365 || c == DayCount30360
+}
+
+// Günün (day, day+1 aralığının) yıl kesrini num/den olarak döndürür
+func (c DayCount) DayFraction(day time.Time) (num, den int64) {
+	if c == DayCount30360 {
+		return days30360(day, day.AddDate(0, 0, 1)), 360
+	}
+	return 1, 365
+}
+
+// İki tarih arasındaki gün sayısını 30/360 kuralıyla hesaplar: ayın 31'i 30 sayılır,
+// bitiş günü 31 ise ve başlangıç günü 30 veya 31 ise o da 30 sayılır
+func days30360(from, to time.Time) int64 {
+	y1, m1, d1 := from.Date()
+	y2, m2, d2 := to.Date()
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 && d1 == 30 {
+		d2 = 30
+	}
+	return int64(360*(y2-y1) + 30*(int(m2)-int(m1)) + (d2 - d1))
+}
+
+// InterestMode, tahakkuk eden ama henüz bakiyeye eklenmemiş faizin faiz getirip getirmediğini belirler
+type InterestMode string
+
+const (
+	InterestSimple   InterestMode = "simple"   // Faiz sadece bakiyeye işler
+	InterestCompound InterestMode = "compound" // Faiz bakiyeye ve dönem içinde tahakkuk etmiş faize günlük işler
+)
+
+func (m InterestMode) IsValid() bool {
+	return m == InterestSimple || m == InterestCompound
+}
+
+// InterestRate, bir hesap türü ve para birimi için EffectiveFrom gününden itibaren geçerli yıllık faiz oranıdır.
+// Pozitif bakiyelere AnnualRateBps ile faiz ödenir, kredi limiti kullanılan negatif bakiyelerden OverdraftRateBps
+// ile faiz alınır. Oran değişiklikleri yeni bir kayıt olarak eklenir; her gün o gün geçerli olan oranla tahakkuk eder.
+type InterestRate struct {
+	AccountType      AccountType  `json:"account_type"`
+	Currency         string       `json:"currency"`
+	AnnualRateBps    int64        `json:"annual_rate_bps"`              // Yıllık oran, baz puan (350 = %3,5)
+	OverdraftRateBps int64        `json:"overdraft_rate_bps,omitempty"` // Negatif bakiyenin yıllık faiz oranı, baz puan
+	Mode             InterestMode `json:"mode"`
+	DayCount         DayCount     `json:"day_count"`
+	EffectiveFrom    time.Time    `json:"effective_from"` // UTC gün başına yuvarlanır
+}
+
+// Oranı doğrular; boş alanlara varsayılanlar (varsayılan para birimi, basit faiz, ACT/365) uygulanır
+func (r *InterestRate) Normalize() error {
+	if r.AccountType != AccountChecking && r.AccountType != AccountSavings && r.AccountType != AccountSub {
+		return NewValidationError("invalid_account_type", "geçersiz hesap türü")
+	}
+	currency, err := ValidateCurrency(NormalizeCurrency(r.Currency))
+	if err != nil {
+		return err
+	}
+	r.Currency = currency
+	for _, bps := range []int64{r.AnnualRateBps, r.OverdraftRateBps} {
+		if bps < 0 || bps > bpsDenominator {
+			return NewValidationError("invalid_interest_rate", "yıllık faiz oranı 0 ile {max} baz puan arasında olmalı").
+				WithParams(map[string]interface{}{"max": bpsDenominator})
+		}
+	}
+	if r.Mode == "" {
+		r.Mode = InterestSimple
+	}
+	if !r.Mode.IsValid() {
+		return NewValidationError("invalid_interest_mode", "faiz türü simple veya compound olmalı")
+	}
+	if r.DayCount == "" {
+		r.DayCount = DayCountACT365
+	}
+	if !r.DayCount.IsValid() {
+		return NewValidationError("invalid_day_count", "gün sayım kuralı ACT/365 veya 30/360 olmalı")
+	}
+	if r.EffectiveFrom.IsZero() {
+		return NewValidationError("effective_from_required", "oranın geçerlilik başlangıcı gerekli")
+	}
+	r.EffectiveFrom = StartOfDay(r.EffectiveFrom)
+	return nil
+}
+
+// Oranları doğrular; aynı hesap türü, para birimi ve geçerlilik günü için birden fazla oran olamaz
+func NormalizeInterestRates(rates []InterestRate) error {
+	seen := make(map[string]bool, len(rates))
+	for i := range rates {
+		if err := rates[i].Normalize(); err != nil {
+			return err
+		}
+		key := rates[i].key() + ":" + rates[i].EffectiveFrom.Format("2006-01-02")
+		if seen[key] {
+			return NewValidationError("duplicate_interest_rate", "aynı hesap türü, para birimi ve gün için birden fazla oran var")
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+func (r *InterestRate) key() string {
+	return string(r.AccountType) + ":" + r.Currency
+}
+
+// Oranları hesap türü, para birimi ve geçerlilik gününe göre sıralamak için karşılaştırır
+func InterestRateLess(a, b InterestRate) bool {
+	if a.AccountType != b.AccountType {
+		return a.AccountType < b.AccountType
+	}
+	if a.Currency != b.Currency {
+		return a.Currency < b.Currency
+	}
+	return a.EffectiveFrom.Before(b.EffectiveFrom)
+}
+
+// Hesap türü ve para birimi için day gününde geçerli olan oranı döndürür; oran yoksa nil
+func InterestRateOn(rates []InterestRate, accountType AccountType, currency string, day time.Time) *InterestRate {
+	var current *InterestRate
+	for i := range rates {
+		r := &rates[i]
+		if r.AccountType != accountType || r.Currency != currency || r.EffectiveFrom.After(day) {
+			continue
+		}
+		if current == nil || r.EffectiveFrom.After(current.EffectiveFrom) {
+			current = r
+		}
+	}
+	return current
+}
+
+// Bakiyeye işleyen yıllık oranı döndürür: negatif bakiyeye kredili hesap oranı, diğerlerine mevduat oranı
+func (r *InterestRate) RateBps(baseMicros int64) int64 {
+	if baseMicros < 0 {
+		return r.OverdraftRateBps
+	}
+	return r.AnnualRateBps
+}
+
+// Verilen bakiyenin bir günlük faizini alt birimin milyonda biri cinsinden hesaplar (yarım değerler çifte yuvarlanır).
+// Negatif bakiyenin faizi negatiftir, yani hesaptan alınır.
+func (r *InterestRate) DailyAccrual(baseMicros int64, day time.Time) int64 {
+	num, den := r.DayCount.DayFraction(day)
+	q := new(big.Rat).SetFrac(big.NewInt(baseMicros), big.NewInt(1))
+	q.Mul(q, big.NewRat(r.RateBps(baseMicros)*num, bpsDenominator*den))
+	amount, _ := roundRat(q, RoundHalfEven)
+	return amount
+}
+
+// Zamanı içinde bulunduğu UTC gününün başına yuvarlar
+func StartOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// InterestAccrual, bir hesabın bir günlük faiz tahakkukudur. Her hesap ve gün için en fazla bir tahakkuk olur;
+// tahakkuklar ay sonunda toplanıp tek bir interest işlemiyle bakiyeye eklenir.
+type InterestAccrual struct {
+	ID                  int64        `json:"id"`
+	AccountID           int64        `json:"account_id"`
+	Date                time.Time    `json:"date"`            // Tahakkukun ait olduğu UTC günü
+	Balance             Money        `json:"balance"`         // Gün sonu ledger bakiyesi
+	AnnualRateBps       int64        `json:"annual_rate_bps"` // Tahakkukta kullanılan oran; negatif bakiyede kredili hesap oranı
+	Mode                InterestMode `json:"mode,omitempty"`
+	DayCount            DayCount     `json:"day_count,omitempty"`
+	AccruedMicros       int64        `json:"accrued_micros"`                  // Alt birimin milyonda biri cinsinden; negatifse hesaptan alınır
+	PostedTransactionID *int64       `json:"posted_transaction_id,omitempty"` // Bakiyeye eklendiği interest işlemi
+	CreatedAt           time.Time    `json:"created_at"`
+}
+
+// InterestRepository, günlük faiz tahakkuklarını saklar
+type InterestRepository interface {
+	// Aynı hesap ve gün için tahakkuk varsa ErrAccrualExists döner
+	CreateAccrual(accrual *InterestAccrual) error
+	// Hesabın son tahakkuk gününü döndürür; hiç tahakkuk yoksa nil
+	LastAccrualDate(accountID int64) (*time.Time, error)
+	// Hesabın before gününden önceki, henüz bakiyeye eklenmemiş tahakkuklarını gün sırasıyla döndürür
+	ListUnposted(accountID int64, before time.Time) ([]*InterestAccrual, error)
+	// Tahakkukları bakiyeye eklendikleri interest işlemine bağlar
+	MarkPosted(ids []int64, txID int64) error
+	// Hesabın [from, to) aralığındaki tahakkuklarını gün sırasıyla döndürür
+	ListByAccount(accountID int64, from, to time.Time) ([]*InterestAccrual, error)
+}
+
+// InterestRateRepository, yönetim API'siyle eklenen faiz oranlarını saklar
+type InterestRateRepository interface {
+	// Aynı hesap türü, para birimi ve geçerlilik günü için kayıtlı oran varsa onun yerine geçer
+	Save(rate InterestRate) error
+	// Oranları hesap türü, para birimi ve geçerlilik gününe göre sıralı döndürür
+	List() ([]InterestRate, error)
+}
+
+// InterestService, faiz oranlarını yönetir, günlük faiz tahakkuk ettirir ve aylık olarak bakiyeye ekler
+type InterestService interface {
+	// now gününden önceki, henüz tahakkuk etmemiş tüm günler için faiz tahakkuk ettirir; eklenen tahakkuk sayısını döner
+	AccrueDue(now time.Time) (int, error)
+	// now ayından önceki ayların tahakkuklarını interest işlemi olarak bakiyelere ekler; oluşan işlem sayısını döner
+	PostDue(now time.Time) (int, error)
+	ListAccruals(accountID int64, from, to time.Time) ([]*InterestAccrual, error)
+	// INTEREST_FILE'daki ve sonradan eklenen oranları birlikte döndürür
+	Rates() ([]InterestRate, error)
+	// Yeni bir oran ekler; aynı hesap türü, para birimi ve gün için oran varsa onun yerine geçer
+	SetRate(rate InterestRate) error
+}
@@ -7,23 +7,42 @@ type UserService interface {
 	Register(user *User) error
 	Authenticate(username, password string) (*User, error)
 	GetByID(id int64) (*User, error)
+	SetLocale(userID int64, locale string) error
+	Delete(id int64) error
 }
 
 type TransactionService interface {
 	Create(tx *Transaction) error
 	GetByID(id int64) (*Transaction, error)
 	ListByUser(userID int64) ([]*Transaction, error)
-	Credit(userID int64, amount float64) error
-	Debit(userID int64, amount float64) error
-	Transfer(fromUserID, toUserID int64, amount float64) error
+	// Kullanıcının işlem geçmişini filtreleyerek sayfa sayfa döndürür
+	History(filter TransactionFilter) (*TransactionPage, error)
+	// Para hareketleri hesaplar arasında yapılır; hesabın para birimi tutarınkiyle aynı olmalıdır
+	// details isteğe bağlıdır; verilen alanlar kaydedilmeden önce doğrulanır
+	Credit(accountID int64, amount Money, details TransactionDetails) error
+	Debit(accountID int64, amount Money, details TransactionDetails) error
+	Transfer(fromAccountID, toAccountID int64, amount Money, details TransactionDetails) error
+	// Kilitli bir kur teklifiyle amount'u gönderen hesaptan düşer, conversion.Target'ı alıcı hesaba geçirir
+	TransferWithConversion(fromAccountID, toAccountID int64, amount Money, conversion FXConversion, details TransactionDetails) error
+	// İşlemin tamamını ters çeviren, orijinale bağlı yeni bir reversal işlemi oluşturur
+	Reverse(txID int64) (*Transaction, error)
+	// İşlemin amount kadarını iade eden, orijinale bağlı yeni bir refund işlemi oluşturur
+	Refund(txID int64, amount Money) (*Transaction, error)
 }
 
 type BalanceService interface {
-	GetBalance(userID int64) (*Balance, error)
-	UpdateBalance(userID int64, amount float64) error
-	GetBalanceHistory(userID int64) ([]*Balance, error)
-	GetBalanceAtTime(userID int64, targetTime time.Time) (*Balance, error)
-	CalculateBalance(userID int64) (float64, error)
+	GetBalance(accountID int64) (*Balance, error)
+	// Kullanıcının tüm hesaplarının bakiyelerini hesap ID sırasıyla döndürür
+	ListBalances(userID int64) ([]*Balance, error)
+	UpdateBalance(accountID int64, amount Money) error
+	// Hesabın kredi limitini (overdraft) değiştirir ve güncel bakiyeyi döndürür
+	SetOverdraftLimit(accountID int64, limit Money) (*Balance, error)
+	GetBalanceHistory(accountID int64) ([]*Balance, error)
+	// Hesabın targetTime anındaki valörlü bakiyesini döndürür (bkz. ValueDatedBalance)
+	GetBalanceAtTime(accountID int64, targetTime time.Time) (*Balance, error)
+	CalculateBalance(accountID int64) (Money, error)
+	// Hesabın at anından önce kaydedilmiş ve valör tarihi day veya öncesi olan ledger kayıtlarıyla bakiyesini hesaplar
+	ValueDatedBalance(accountID int64, at, day time.Time) (Money, error)
 }
 
 // Repository arayüzleri
@@ -31,15 +50,58 @@ type UserRepository interface {
 	Create(user *User) error
 	FindByID(id int64) (*User, error)
 	FindByUsername(username string) (*User, error)
+	UpdateLocale(id int64, locale string) error
+	// Kullanıcıyı oturumları ve yetki devirleriyle birlikte siler; finansal kaydı varsa ErrUserHasRecords döner
+	Delete(id int64) error
 }
 
 type TransactionRepository interface {
 	Create(tx *Transaction) error
 	FindByID(id int64) (*Transaction, error)
+	// Kullanıcının işlemlerini (created_at, id) sırasıyla listeler
 	ListByUser(userID int64) ([]*Transaction, error)
+	// Normalize edilmiş filtreye uyan işlemlerden bir sayfa döndürür
+	Search(filter TransactionFilter) (*TransactionPage, error)
+	// Filtreye uyan işlemlerin tutarlarını sayfalama uygulamadan toplar; filtrede para birimi olmalıdır
+	Sum(filter TransactionFilter) (Money, error)
+	// Filtreye uyan işlemleri sayfalama uygulamadan sayar
+	Count(filter TransactionFilter) (int64, error)
+	// FindByID gibidir; unit of work içinde kaydı transaction sonuna kadar kilitler
+	FindByIDForUpdate(id int64) (*Transaction, error)
+	// Unit of work içinde kullanıcıyı transaction sonuna kadar kilitler; aynı kullanıcının eşzamanlı para
+	// hareketleri limit ve ücretsiz kullanım sayımlarını sırayla okur. Unit of work dışında etkisizdir.
+	LockUser(userID int64) error
+	// Orijinal işleme bağlı geri alma, iade ve ücret işlemlerini listeler
+	ListByOriginal(originalID int64) ([]*Transaction, error)
+	UpdateStatus(id int64, status TransactionStatus) error
 }
 
+// BalanceRepository, hesap bakiyelerinin projeksiyonunu tutar (ledger bakiyesini sadece ledger.Post günceller)
 type BalanceRepository interface {
-	GetByUserID(userID int64) (*Balance, error)
-	Update(userID int64, amount float64) error
+	// Hesabın bakiyesini getirir; hesapta henüz hareket yoksa ErrAccountNotFound döner
+	Get(accountID int64) (*Balance, error)
+	// Hesabın bakiyesini tutar kadar değiştirir; bakiye satırı yoksa tutarın para biriminde açılır
+	Update(accountID int64, amount Money) error
+	// Hesabın provizyon tutarını değiştirir: pozitif tutar kullanılabilir bakiyeden ayrılır
+	// (yetmiyorsa ErrInsufficientFunds), negatif tutar serbest bırakılır
+	Hold(accountID int64, amount Money) error
+	// Hesabın kredi limitini (overdraft) değiştirir; limit hesabın para biriminde olmalıdır.
+	// Bakiye satırı yoksa açılır. Limit kullanılan kredinin altına indirilebilir; bu durumda
+	// bakiye kapanana kadar yeni para çıkışı yapılamaz.
+	SetOverdraftLimit(accountID int64, limit Money) error
+}
+
+// Repositories, bir unit of work içinde birlikte kullanılan repository'leri taşır
+type Repositories struct {
+	Balances     BalanceRepository
+	Transactions TransactionRepository
+	Ledger       LedgerRepository
+	Holds        HoldRepository
+	Interest     InterestRepository
+}
+
+// UnitOfWork, fn içindeki tüm repository değişikliklerini tek bir atomik işlem olarak uygular.
+// fn hata dönerse hiçbir değişiklik kalıcı olmaz.
+type UnitOfWork interface {
+	Do(fn func(repos Repositories) error) error
 }
@@ -29,18 +29,24 @@ func (u *User) UnmarshalJSON(data []byte) error {
 func (t *Transaction) MarshalJSON() ([]byte, error) {
 	type Alias Transaction
 	return json.Marshal(&struct {
-		CreatedAt string `json:"created_at"`
+		CreatedAt   string `json:"created_at"`
+		BookingDate string `json:"booking_date,omitempty"`
+		ValueDate   string `json:"value_date,omitempty"`
 		*Alias
 	}{
-		CreatedAt: t.CreatedAt.Format(time.RFC3339),
-		Alias:     (*Alias)(t),
+		CreatedAt:   t.CreatedAt.Format(time.RFC3339),
+		BookingDate: formatDate(t.BookingDate),
+		ValueDate:   formatDate(t.ValueDate),
+		Alias:       (*Alias)(t),
 	})
 }
 
 func (t *Transaction) UnmarshalJSON(data []byte) error {
 	type Alias Transaction
 	aux := &struct {
-		CreatedAt string `json:"created_at"`
+		CreatedAt   string `json:"created_at"`
+		BookingDate string `json:"booking_date"`
+		ValueDate   string `json:"value_date"`
 		*Alias
 	}{
 		Alias: (*Alias)(t),
@@ -53,7 +59,26 @@ func (t *Transaction) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	t.CreatedAt = parsed
-	return nil
+	if t.BookingDate, err = parseDate(aux.BookingDate); err != nil {
+		return err
+	}
+	t.ValueDate, err = parseDate(aux.ValueDate)
+	return err
+}
+
+// Gün alanları JSON'da YYYY-MM-DD biçimindedir; sıfır gün boş bırakılır
+func formatDate(day time.Time) string {
+	if day.IsZero() {
+		return ""
+	}
+	return day.Format(time.DateOnly)
+}
+
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.DateOnly, value)
 }
 
 // Balance JSON
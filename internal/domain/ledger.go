@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Posting, bir yevmiye kaydının tek bir hesaba yazılan bacağıdır.
+// Pozitif tutar hesabın bakiyesini artırır, negatif tutar azaltır.
+type Posting struct {
+	AccountID string `json:"account_id"`
+	Amount    Money  `json:"amount"`
+}
+
+// JournalEntry, toplamı sıfır olan postinglerden oluşan değiştirilemez yevmiye kaydıdır
+type JournalEntry struct {
+	ID            int64     `json:"id"`
+	TransactionID *int64    `json:"transaction_id,omitempty"`
+	Description   string    `json:"description"`
+	Postings      []Posting `json:"postings"`
+	CreatedAt     time.Time `json:"created_at"`
+	ValueDate     time.Time `json:"value_date"` // Kaydın valörlü bakiyede sayılmaya başladığı gün; işlemin valör tarihi
+}
+
+// Kaydın çift taraflı muhasebe kurallarına uyup uymadığını kontrol eder:
+// en az iki posting olmalı, hiçbiri sıfır olmamalı ve her para biriminde toplam sıfır olmalı
+func (e *JournalEntry) Validate() error {
+	if len(e.Postings) < 2 {
+		return errors.New("yevmiye kaydı en az iki posting içermeli")
+	}
+	totals := make(map[string]Money)
+	for _, p := range e.Postings {
+		if p.AccountID == "" {
+			return errors.New("posting hesabı boş olamaz")
+		}
+		if p.Amount.IsZero() {
+			return errors.New("posting tutarı sıfır olamaz")
+		}
+		currency := NormalizeCurrency(p.Amount.Currency)
+		sum, err := NewMoney(totals[currency].Amount, currency).Add(p.Amount)
+		if err != nil {
+			return err
+		}
+		totals[currency] = sum
+	}
+	for _, total := range totals {
+		if !total.IsZero() {
+			return errors.New("yevmiye kaydı dengede değil: postinglerin toplamı sıfır olmalı")
+		}
+	}
+	return nil
+}
+
+// LedgerRepository, yevmiye kayıtlarını saklar; hesap bakiyeleri postinglerden türetilir
+type LedgerRepository interface {
+	Append(entry *JournalEntry) error
+	ListByTransaction(txID int64) ([]*JournalEntry, error)
+	ListByAccount(accountID string) ([]*JournalEntry, error)
+	AccountBalance(accountID, currency string) (Money, error)
+	// Hesabın at anından önce oluşturulmuş ve valör tarihi valueDay veya öncesi olan kayıtlarla bakiyesini hesaplar
+	AccountBalanceAt(accountID, currency string, at, valueDay time.Time) (Money, error)
+}
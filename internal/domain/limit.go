@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"time"
+)
+
+// Kayan limit pencereleri: günlük limit son 24 saati, aylık limit son 30 günü kapsar
+const (
+	DailyLimitWindow   = 24 * time.Hour
+	MonthlyLimitWindow = 30 * 24 * time.Hour
+)
+
+var (
+	ErrLimitExceeded         = NewError(CodeLimitExceeded, "işlem limiti aşılıyor")
+	ErrLimitOverrideNotFound = NewError(CodeNotFound, "limit override'ı bulunamadı").WithMessage("limit_override", "limit override'ı bulunamadı")
+)
+
+// LimitRule, bir işlem türü ve para birimi için tanımlı limitlerdir. Boş bırakılan limit uygulanmaz;
+// geçersiz kılma (override) kurallarında boş alan alttaki katmanın (rol veya varsayılan) değerini korur.
+type LimitRule struct {
+	Type                TransactionType `json:"type"`
+	Currency            string          `json:"currency"`
+	PerTransaction      *Money          `json:"per_transaction,omitempty"`      // Tek işlemde en fazla
+	Daily               *Money          `json:"daily,omitempty"`                // Son 24 saatte toplam en fazla
+	Monthly             *Money          `json:"monthly,omitempty"`              // Son 30 günde toplam en fazla
+	CounterpartyDaily   *Money          `json:"counterparty_daily,omitempty"`   // Aynı alıcıya son 24 saatte en fazla
+	CounterpartyMonthly *Money          `json:"counterparty_monthly,omitempty"` // Aynı alıcıya son 30 günde en fazla
+}
+
+// Kuralı doğrular; para birimi boşsa varsayılan para birimi kullanılır ve limitler bu para biriminde olmalıdır
+func (r *LimitRule) Normalize() error {
+	if !r.Type.IsLimited() {
+		return NewValidationError("invalid_limit_type", "limit sadece deposit, withdraw ve transfer işlemleri için tanımlanabilir")
+	}
+	currency, err := ValidateCurrency(NormalizeCurrency(r.Currency))
+	if err != nil {
+		return err
+	}
+	r.Currency = currency
+	for _, limit := range r.limits() {
+		if limit == nil {
+			continue
+		}
+		if NormalizeCurrency(limit.Currency) != currency {
+			return ErrCurrencyMismatch.WithMessage("limit_currency", "limit tutarları kuralın para biriminde olmalı: {currency}").
+				WithParams(map[string]interface{}{"currency": currency})
+		}
+		if limit.IsNegative() {
+			return NewValidationError("negative_limit", "limit tutarı negatif olamaz")
+		}
+	}
+	if r.Type != TransactionTransfer && (r.CounterpartyDaily != nil || r.CounterpartyMonthly != nil) {
+		return NewValidationError("counterparty_limit_requires_transfer", "karşı taraf limitleri sadece transferler için tanımlanabilir")
+	}
+	return nil
+}
+
+func (r *LimitRule) limits() []*Money {
+	return []*Money{r.PerTransaction, r.Daily, r.Monthly, r.CounterpartyDaily, r.CounterpartyMonthly}
+}
+
+// Kuralın üzerine override'ın dolu alanlarını yazar
+func (r *LimitRule) Merge(override LimitRule) {
+	if override.PerTransaction != nil {
+		r.PerTransaction = override.PerTransaction
+	}
+	if override.Daily != nil {
+		r.Daily = override.Daily
+	}
+	if override.Monthly != nil {
+		r.Monthly = override.Monthly
+	}
+	if override.CounterpartyDaily != nil {
+		r.CounterpartyDaily = override.CounterpartyDaily
+	}
+	if override.CounterpartyMonthly != nil {
+		r.CounterpartyMonthly = override.CounterpartyMonthly
+	}
+}
+
+// Türün limitlere tabi bir para hareketi olup olmadığını döndürür; geri alma ve iadeler limitlere tabi değildir
+func (t TransactionType) IsLimited() bool {
+	return t == TransactionDeposit || t == TransactionWithdraw || t == TransactionTransfer
+}
+
+// LimitScope, limit override'ının kime uygulandığını belirler
+type LimitScope string
+
+const (
+	LimitScopeRole LimitScope = "role" // Roldeki tüm kullanıcılara; varsayılanların üzerine uygulanır
+	LimitScopeUser LimitScope = "user" // Tek bir kullanıcıya; rol override'ının üzerine uygulanır
+)
+
+// LimitOverride, bir rol veya kullanıcı için varsayılan limitleri değiştiren kurallardır
+type LimitOverride struct {
+	Scope     LimitScope  `json:"scope"`
+	Subject   string      `json:"subject"` // Rol adı veya kullanıcı ID'si
+	Rules     []LimitRule `json:"rules"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// Override'ı ve kurallarını doğrular
+func (o *LimitOverride) Normalize() error {
+	if o.Scope != LimitScopeRole && o.Scope != LimitScopeUser {
+		return NewValidationError("invalid_limit_scope", "limit kapsamı role veya user olmalı")
+	}
+	if o.Subject == "" {
+		return NewValidationError("limit_subject_required", "rol adı veya kullanıcı ID gerekli")
+	}
+	return NormalizeLimitRules(o.Rules)
+}
+
+// Kuralları doğrular; aynı tür ve para birimi için birden fazla kural olamaz
+func NormalizeLimitRules(rules []LimitRule) error {
+	seen := make(map[string]bool, len(rules))
+	for i := range rules {
+		if err := rules[i].Normalize(); err != nil {
+			return err
+		}
+		key := string(rules[i].Type) + ":" + rules[i].Currency
+		if seen[key] {
+			return NewValidationError("duplicate_limit_rule", "aynı işlem türü ve para birimi için birden fazla kural var")
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// LimitMovement, limit kontrolünden geçecek para hareketidir
+type LimitMovement struct {
+	UserID         int64 // Para çıkışlarında gönderen, para yatırmada alıcı
+	Type           TransactionType
+	Amount         Money
+	CounterpartyID *int64 // Sadece transferlerde alıcı kullanıcı
+}
+
+// LimitUsage, bir kayan penceredeki limitin kullanımıdır
+type LimitUsage struct {
+	Limit     Money `json:"limit"`
+	Used      Money `json:"used"`
+	Remaining Money `json:"remaining"`
+}
+
+// LimitHeadroom, kullanıcının bir işlem türü ve para biriminde kalan limitleridir
+type LimitHeadroom struct {
+	Type                TransactionType `json:"type"`
+	Currency            string          `json:"currency"`
+	PerTransaction      *Money          `json:"per_transaction,omitempty"`
+	Daily               *LimitUsage     `json:"daily,omitempty"`
+	Monthly             *LimitUsage     `json:"monthly,omitempty"`
+	CounterpartyDaily   *LimitUsage     `json:"counterparty_daily,omitempty"`   // Sadece karşı taraf belirtildiyse
+	CounterpartyMonthly *LimitUsage     `json:"counterparty_monthly,omitempty"` // Sadece karşı taraf belirtildiyse
+}
+
+// LimitOverrideRepository, rol ve kullanıcı limit override'larını saklar
+type LimitOverrideRepository interface {
+	// Override yoksa ErrLimitOverrideNotFound döner
+	Find(scope LimitScope, subject string) (*LimitOverride, error)
+	// Aynı kapsam ve konu için var olan override'ın yerine geçer
+	Save(override *LimitOverride) error
+	// Override yoksa ErrLimitOverrideNotFound döner
+	Delete(scope LimitScope, subject string) error
+	List() ([]*LimitOverride, error)
+}
+
+// LimitService, para hareketlerini varsayılan ve override edilmiş limitlere göre denetler
+type LimitService interface {
+	// Hareket bir limiti aşacaksa ErrLimitExceeded döner. Kullanım txs üzerinden okunur; böylece
+	// kontrol, para hareketiyle aynı unit of work içinde yapılabilir.
+	Check(txs TransactionRepository, movement LimitMovement) error
+	// Kullanıcının limitlerini ve kalan kullanımını döndürür; currency boşsa tüm para birimleri
+	Headroom(userID int64, currency string, counterpartyID *int64) ([]LimitHeadroom, error)
+	ListOverrides() ([]*LimitOverride, error)
+	SetOverride(override *LimitOverride) error
+	DeleteOverride(scope LimitScope, subject string) error
+}
@@ -0,0 +1,346 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultCurrency, para birimi belirtilmeyen tutarlar için kullanılan varsayılan koddur
+const DefaultCurrency = "TRY"
+
+var (
+	ErrCurrencyMismatch    = NewError(CodeCurrencyMismatch, "para birimleri uyuşmuyor")
+	ErrUnsupportedCurrency = NewError(CodeUnsupportedCurrency, "desteklenmeyen para birimi")
+	ErrAmountOverflow      = NewError(CodeAmountOverflow, "tutar taşması")
+	ErrInvalidMoney        = ErrInvalidAmount.WithMessage("malformed", "geçersiz tutar")
+)
+
+// RoundingMode, alt birime sığmayan tutarların nasıl yuvarlanacağını belirler
+type RoundingMode int
+
+const (
+	RoundUnnecessary RoundingMode = iota // Yuvarlama gerekirse hata döner
+	RoundHalfUp                          // 0.5 sıfırdan uzağa yuvarlanır
+	RoundHalfEven                        // 0.5 en yakın çift sayıya yuvarlanır (banker yuvarlaması)
+	RoundDown                            // Sıfıra doğru kesilir
+	RoundUp                              // Sıfırdan uzağa yuvarlanır
+	RoundFloor                           // Negatif sonsuza doğru yuvarlanır
+	RoundCeiling                         // Pozitif sonsuza doğru yuvarlanır
+)
+
+// Money, tutarı para biriminin alt birimi (ör: kuruş) cinsinden tam sayı olarak tutar.
+// float64 kullanılmadığı için toplama/çıkarma işlemlerinde kayıp oluşmaz.
+type Money struct {
+	Amount   int64  // Alt birim cinsinden tutar (ör: 1050 = 10.50 TRY)
+	Currency string // ISO 4217 para birimi kodu
+}
+
+// Alt birim cinsinden tutar ile yeni bir Money oluşturur
+func NewMoney(minor int64, currency string) Money {
+	return Money{Amount: minor, Currency: NormalizeCurrency(currency)}
+}
+
+// Varsayılan para biriminde Money oluşturur
+func MinorUnits(minor int64) Money {
+	return NewMoney(minor, DefaultCurrency)
+}
+
+// ISO 4217 para birimlerinin alt birim basamak sayıları (ör: 1 USD = 100 cent, 1 JPY = 1 yen)
+var currencyExponents = map[string]int{
+	"TRY": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"CHF": 2,
+	"JPY": 0,
+	"KWD": 3,
+	"BHD": 3,
+}
+
+// Para biriminin desteklenip desteklenmediğini döndürür
+func IsSupportedCurrency(currency string) bool {
+	_, ok := currencyExponents[NormalizeCurrency(currency)]
+	return ok
+}
+
+// Desteklenen para birimlerini alfabetik sırayla döndürür
+func SupportedCurrencies() []string {
+	codes := make([]string, 0, len(currencyExponents))
+	for code := range currencyExponents {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Para biriminin desteklendiğini doğrular ve normalize edilmiş kodunu döndürür
+func ValidateCurrency(currency string) (string, error) {
+	code := NormalizeCurrency(currency)
+	if _, ok := currencyExponents[code]; !ok {
+		return "", ErrUnsupportedCurrency.WithParams(map[string]interface{}{"currency": code})
+	}
+	return code, nil
+}
+
+// Para biriminin ondalık basamak sayısını döndürür; bilinmeyen birimler için 2 varsayılır
+func CurrencyExponent(currency string) int {
+	if exp, ok := currencyExponents[NormalizeCurrency(currency)]; ok {
+		return exp
+	}
+	return 2
+}
+
+// "10.50" gibi ondalık bir metni Money'e çevirir; fazla basamak varsa hata döner
+func ParseMoney(s, currency string) (Money, error) {
+	return ParseMoneyRounded(s, currency, RoundUnnecessary)
+}
+
+// Kabul edilen tutar biçimi: isteğe bağlı eksi işareti, rakamlar ve isteğe bağlı ondalık kısım.
+// big.Rat.SetString'in kabul ettiği üs, kesir, 0x/0b/0o önekleri ve "_" ayraçları böylece reddedilir.
+var decimalPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// Ondalık metni verilen yuvarlama moduna göre Money'e çevirir
+func ParseMoneyRounded(s, currency string, mode RoundingMode) (Money, error) {
+	s = strings.TrimSpace(s)
+	if !decimalPattern.MatchString(s) {
+		return Money{}, ErrInvalidMoney
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Money{}, ErrInvalidMoney
+	}
+	currency, err := ValidateCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	r.Mul(r, big.NewRat(pow10(CurrencyExponent(currency)), 1))
+	minor, err := roundRat(r, mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+// Tutarı num/den oranıyla çarpar ve sonucu verilen moda göre yuvarlar (ör: yüzde hesapları)
+func (m Money) MulRat(num, den int64, mode RoundingMode) (Money, error) {
+	if den == 0 {
+		return Money{}, ErrInvalidMoney
+	}
+	r := new(big.Rat).SetFrac(big.NewInt(m.Amount), big.NewInt(1))
+	r.Mul(r, big.NewRat(num, den))
+	minor, err := roundRat(r, mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: minor, Currency: m.Currency}, nil
+}
+
+// Tutarı verilen kurla (1 birim kaynak = rate birim hedef) hedef para birimine çevirir.
+// Alt birim basamak farkı (ör: USD 2, JPY 0) hesaba katılır; sonuç verilen moda göre yuvarlanır.
+func (m Money) Convert(currency string, rate *big.Rat, mode RoundingMode) (Money, error) {
+	currency, err := ValidateCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	if rate == nil || rate.Sign() <= 0 {
+		return Money{}, ErrInvalidMoney
+	}
+	r := new(big.Rat).SetFrac(big.NewInt(m.Amount), big.NewInt(pow10(CurrencyExponent(m.currency()))))
+	r.Mul(r, rate)
+	r.Mul(r, big.NewRat(pow10(CurrencyExponent(currency)), 1))
+	minor, err := roundRat(r, mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+// İki tutarı taşma ve para birimi kontrolü yaparak toplar
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	if (o.Amount > 0 && m.Amount > math.MaxInt64-o.Amount) ||
+		(o.Amount < 0 && m.Amount < math.MinInt64-o.Amount) {
+		return Money{}, ErrAmountOverflow
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: m.currency()}, nil
+}
+
+// İki tutarı taşma ve para birimi kontrolü yaparak çıkarır
+func (m Money) Sub(o Money) (Money, error) {
+	if o.Amount == math.MinInt64 {
+		return Money{}, ErrAmountOverflow
+	}
+	return m.Add(o.Neg())
+}
+
+// Tutarın ters işaretlisini döndürür
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// Tutarın mutlak değerini döndürür
+func (m Money) Abs() Money {
+	if m.Amount < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+// İki tutarı karşılaştırır (-1, 0, 1)
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (m Money) IsZero() bool     { return m.Amount == 0 }
+func (m Money) IsPositive() bool { return m.Amount > 0 }
+func (m Money) IsNegative() bool { return m.Amount < 0 }
+
+// Tutarı "10.50" formatında döndürür
+func (m Money) Decimal() string {
+	exp := CurrencyExponent(m.currency())
+	sign := ""
+	v := new(big.Int).SetInt64(m.Amount)
+	if v.Sign() < 0 {
+		sign = "-"
+		v.Neg(v)
+	}
+	digits := v.String()
+	if exp == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+// Tutarı "10.50 TRY" formatında döndürür
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Decimal(), m.currency())
+}
+
+// Money JSON'da {"amount":"10.50","currency":"TRY"} olarak yazılır
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{
+		Amount:   m.Decimal(),
+		Currency: m.currency(),
+	})
+}
+
+// Money hem {"amount":"10.50","currency":"TRY"} hem de "10.50" biçiminde okunabilir.
+// Kayan noktalı sayılar bilinçli olarak reddedilir.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		parsed, err := ParseMoney(s, DefaultCurrency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+	var aux struct {
+		Amount   *string `json:"amount"`
+		Currency string  `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil || aux.Amount == nil {
+		return ErrInvalidMoney.WithMessage("must_be_string", "tutar metin olarak gönderilmeli (ör: \"10.50\")")
+	}
+	parsed, err := ParseMoney(*aux.Amount, aux.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func (m Money) currency() string {
+	return NormalizeCurrency(m.Currency)
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.currency() != o.currency() {
+		return ErrCurrencyMismatch
+	}
+	return nil
+}
+
+// Para birimi kodunu büyük harfe çevirir, boşsa varsayılan para birimini döndürür
+func NormalizeCurrency(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return DefaultCurrency
+	}
+	return currency
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
+
+// Rasyonel sayıyı verilen moda göre tam sayıya yuvarlar
+func roundRat(r *big.Rat, mode RoundingMode) (int64, error) {
+	num, den := r.Num(), r.Denom()
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() != 0 {
+		// Kalanın paydanın yarısıyla karşılaştırması: 2*|rem| ? den
+		twice := new(big.Int).Abs(rem)
+		twice.Lsh(twice, 1)
+		half := twice.Cmp(den)
+		negative := num.Sign() < 0
+		awayFromZero := false
+		switch mode {
+		case RoundUnnecessary:
+			return 0, ErrInvalidMoney.WithMessage("too_precise", "tutar para biriminin hassasiyetinden fazla ondalık içeriyor")
+		case RoundHalfUp:
+			awayFromZero = half >= 0
+		case RoundHalfEven:
+			awayFromZero = half > 0 || (half == 0 && q.Bit(0) == 1)
+		case RoundDown:
+			awayFromZero = false
+		case RoundUp:
+			awayFromZero = true
+		case RoundFloor:
+			awayFromZero = negative
+		case RoundCeiling:
+			awayFromZero = !negative
+		default:
+			return 0, errors.New("bilinmeyen yuvarlama modu")
+		}
+		if awayFromZero {
+			if negative {
+				q.Sub(q, big.NewInt(1))
+			} else {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+	if !q.IsInt64() {
+		return 0, ErrAmountOverflow
+	}
+	return q.Int64(), nil
+}
@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+// errorKey, hatanın mesaj kataloğu anahtarını döndürür (ör: "invalid_amount.too_precise")
+func errorKey(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.MessageKey()
+	}
+	return ""
+}
+
+func TestParseMoneyRounded(t *testing.T) {
+	modes := []struct {
+		name string
+		mode RoundingMode
+	}{
+		{"HalfUp", RoundHalfUp}, {"HalfEven", RoundHalfEven}, {"Down", RoundDown}, {"Up", RoundUp},
+		{"Floor", RoundFloor}, {"Ceiling", RoundCeiling},
+	}
+	cases := []struct {
+		input string
+		want  [6]int64 // modes sırasıyla TRY kuruş karşılıkları
+	}{
+		{"1.005", [6]int64{101, 100, 100, 101, 100, 101}},
+		{"1.015", [6]int64{102, 102, 101, 102, 101, 102}},
+		{"-1.005", [6]int64{-101, -100, -100, -101, -101, -100}},
+		{"1.001", [6]int64{100, 100, 100, 101, 100, 101}},
+		{"-1.009", [6]int64{-101, -101, -100, -101, -101, -100}},
+		{"1.00", [6]int64{100, 100, 100, 100, 100, 100}},
+	}
+	for _, c := range cases {
+		for i, m := range modes {
+			got, err := ParseMoneyRounded(c.input, "TRY", m.mode)
+			if err != nil || got != NewMoney(c.want[i], "TRY") {
+				t.Errorf("%s %s: %v, %v; beklenen %d kuruş", c.input, m.name, got, err, c.want[i])
+			}
+		}
+	}
+
+	// RoundUnnecessary sadece tam bölünen tutarları kabul eder
+	if got, err := ParseMoneyRounded("1.00", "TRY", RoundUnnecessary); err != nil || got.Amount != 100 {
+		t.Errorf("1.00 Unnecessary: %v, %v", got, err)
+	}
+	if _, err := ParseMoneyRounded("1.005", "TRY", RoundUnnecessary); errorKey(err) != "invalid_amount.too_precise" {
+		t.Errorf("1.005 Unnecessary: %v, beklenen too_precise", err)
+	}
+	if _, err := ParseMoneyRounded("1.005", "TRY", RoundingMode(99)); err == nil {
+		t.Error("bilinmeyen yuvarlama modu reddedilmeli")
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	cases := []struct {
+		input, currency string
+		want            int64
+		err             error
+	}{
+		{"10.50", "TRY", 1050, nil},
+		{" 10.5 ", "try", 1050, nil},
+		{"-0.50", "USD", -50, nil},
+		{"10", "JPY", 10, nil},
+		{"1.234", "KWD", 1234, nil},
+		{"0.001", "BHD", 1, nil},
+		{"92233720368547758.07", "TRY", math.MaxInt64, nil},
+
+		// Para biriminin alt birim basamağından fazlası yuvarlanmadan kabul edilmez
+		{"10.5", "JPY", 0, ErrInvalidMoney},
+		{"1.2345", "KWD", 0, ErrInvalidMoney},
+		{"92233720368547758.08", "TRY", 0, ErrAmountOverflow},
+		{"10", "XXX", 0, ErrUnsupportedCurrency},
+
+		// big.Rat.SetString'in kabul ettiği ama tutar olmayan biçimler
+		{"0x10", "TRY", 0, ErrInvalidMoney},
+		{"0b101", "TRY", 0, ErrInvalidMoney},
+		{"0o17", "TRY", 0, ErrInvalidMoney},
+		{"1_000", "TRY", 0, ErrInvalidMoney},
+		{"1e3", "TRY", 0, ErrInvalidMoney},
+		{"1/2", "TRY", 0, ErrInvalidMoney},
+		{"+1", "TRY", 0, ErrInvalidMoney},
+		{".5", "TRY", 0, ErrInvalidMoney},
+		{"5.", "TRY", 0, ErrInvalidMoney},
+		{"1.2.3", "TRY", 0, ErrInvalidMoney},
+		{"10,50", "TRY", 0, ErrInvalidMoney},
+		{"١٠", "TRY", 0, ErrInvalidMoney},
+		{"", "TRY", 0, ErrInvalidMoney},
+	}
+	for _, c := range cases {
+		got, err := ParseMoney(c.input, c.currency)
+		if c.err != nil {
+			if !errors.Is(err, c.err) {
+				t.Errorf("%q %s: %v, %v; beklenen %v", c.input, c.currency, got, err, c.err)
+			}
+			continue
+		}
+		if err != nil || got != NewMoney(c.want, c.currency) {
+			t.Errorf("%q %s: %v, %v; beklenen %d", c.input, c.currency, got, err, c.want)
+		}
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	cases := []struct {
+		money Money
+		want  string
+	}{
+		{NewMoney(1050, "TRY"), "10.50"},
+		{NewMoney(5, "TRY"), "0.05"},
+		{NewMoney(-5, "USD"), "-0.05"},
+		{NewMoney(1234, "JPY"), "1234"},
+		{NewMoney(-1234, "JPY"), "-1234"},
+		{NewMoney(1234, "KWD"), "1.234"},
+		{NewMoney(-1, "BHD"), "-0.001"},
+		{NewMoney(math.MinInt64, "TRY"), "-92233720368547758.08"},
+	}
+	for _, c := range cases {
+		if got := c.money.Decimal(); got != c.want {
+			t.Errorf("%d %s: %s, beklenen %s", c.money.Amount, c.money.Currency, got, c.want)
+		}
+	}
+}
+
+func TestMoneyAddSub(t *testing.T) {
+	try := func(minor int64) Money { return NewMoney(minor, "TRY") }
+	cases := []struct {
+		name string
+		op   func() (Money, error)
+		want Money
+		err  error
+	}{
+		{"toplama", func() (Money, error) { return try(150).Add(try(-50)) }, try(100), nil},
+		{"çıkarma", func() (Money, error) { return try(150).Sub(try(200)) }, try(-50), nil},
+		{"üst sınıra kadar", func() (Money, error) { return try(math.MaxInt64 - 1).Add(try(1)) }, try(math.MaxInt64), nil},
+		{"alt sınıra kadar", func() (Money, error) { return try(math.MinInt64 + 1).Sub(try(1)) }, try(math.MinInt64), nil},
+		{"toplamada üst taşma", func() (Money, error) { return try(math.MaxInt64).Add(try(1)) }, Money{}, ErrAmountOverflow},
+		{"toplamada alt taşma", func() (Money, error) { return try(math.MinInt64).Add(try(-1)) }, Money{}, ErrAmountOverflow},
+		{"çıkarmada üst taşma", func() (Money, error) { return try(math.MaxInt64).Sub(try(-1)) }, Money{}, ErrAmountOverflow},
+		{"çıkarmada alt taşma", func() (Money, error) { return try(math.MinInt64).Sub(try(1)) }, Money{}, ErrAmountOverflow},
+		// -MinInt64 int64'e sığmaz; Neg ile toplamaya çevrilmeden reddedilir
+		{"MinInt64 çıkarma", func() (Money, error) { return try(0).Sub(try(math.MinInt64)) }, Money{}, ErrAmountOverflow},
+		{"farklı para birimi", func() (Money, error) { return try(100).Add(NewMoney(100, "USD")) }, Money{}, ErrCurrencyMismatch},
+		{"boş para birimi varsayılandır", func() (Money, error) { return Money{Amount: 100}.Add(try(1)) }, try(101), nil},
+	}
+	for _, c := range cases {
+		got, err := c.op()
+		if (c.err == nil && err != nil) || (c.err != nil && !errors.Is(err, c.err)) || got != c.want {
+			t.Errorf("%s: %v, %v; beklenen %v, %v", c.name, got, err, c.want, c.err)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	for _, m := range []Money{NewMoney(1050, "TRY"), NewMoney(-1, "USD"), NewMoney(1234, "JPY"), NewMoney(1234, "KWD")} {
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var back Money
+		if err := json.Unmarshal(data, &back); err != nil || back != m {
+			t.Errorf("%s: %s geri okunduğunda %v, %v", m, data, back, err)
+		}
+	}
+	if data, _ := json.Marshal(NewMoney(1234, "KWD")); string(data) != `{"amount":"1.234","currency":"KWD"}` {
+		t.Errorf("KWD JSON = %s", data)
+	}
+
+	cases := []struct {
+		input string
+		want  Money
+		key   string // Hata bekleniyorsa mesaj anahtarı
+	}{
+		{`"10.50"`, NewMoney(1050, "TRY"), ""},
+		{`{"amount":"10","currency":"jpy"}`, NewMoney(10, "JPY"), ""},
+		{`10.5`, Money{}, "invalid_amount.must_be_string"},
+		{`1050`, Money{}, "invalid_amount.must_be_string"},
+		{`{"amount":10.5,"currency":"TRY"}`, Money{}, "invalid_amount.must_be_string"},
+		{`{"currency":"TRY"}`, Money{}, "invalid_amount.must_be_string"},
+		{`{"amount":"10.5","currency":"JPY"}`, Money{}, "invalid_amount.too_precise"},
+		{`{"amount":"0x10","currency":"TRY"}`, Money{}, "invalid_amount.malformed"},
+		{`"1e3"`, Money{}, "invalid_amount.malformed"},
+	}
+	for _, c := range cases {
+		var got Money
+		err := json.Unmarshal([]byte(c.input), &got)
+		if c.key != "" {
+			if errorKey(err) != c.key {
+				t.Errorf("%s: %v, beklenen %s", c.input, err, c.key)
+			}
+			continue
+		}
+		if err != nil || got != c.want {
+			t.Errorf("%s: %v, %v; beklenen %v", c.input, got, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+// ScheduleFrequency, talimatın ne sıklıkla çalıştığını belirler
+type ScheduleFrequency string
+
+const (
+	ScheduleOnce     ScheduleFrequency = "once"      // Tek seferlik, ileri tarihli transfer
+	ScheduleWeekly   ScheduleFrequency = "weekly"    // Her hafta başlangıç günüyle aynı gün
+	ScheduleMonthly  ScheduleFrequency = "monthly"   // Her ay DayOfMonth gününde; ay daha kısaysa ayın son günü
+	ScheduleMonthEnd ScheduleFrequency = "month_end" // Her ayın son günü
+)
+
+func (f ScheduleFrequency) IsValid() bool {
+	return f == ScheduleOnce || f == ScheduleWeekly || f == ScheduleMonthly || f == ScheduleMonthEnd
+}
+
+// ScheduleStatus, talimatın yaşam döngüsündeki durumudur
+type ScheduleStatus string
+
+const (
+	ScheduleActive    ScheduleStatus = "active"    // Zamanı geldiğinde çalışır
+	SchedulePaused    ScheduleStatus = "paused"    // Kullanıcı tarafından durdurulmuş; tekrar etkinleştirilebilir
+	ScheduleCompleted ScheduleStatus = "completed" // Son çalıştırması yapılmış
+	ScheduleFailed    ScheduleStatus = "failed"    // Tek seferlik talimat denemeleri tükendiği için başarısız
+	ScheduleCancelled ScheduleStatus = "cancelled" // Kullanıcı tarafından iptal edilmiş
+)
+
+// Talimatın bir daha çalışmayacağı durumda olup olmadığını döndürür
+func (s ScheduleStatus) IsFinal() bool {
+	return s == ScheduleCompleted || s == ScheduleFailed || s == ScheduleCancelled
+}
+
+// Zamanlanmış transferlerin oluşturduğu işlemlere eklenen metadata alanları; çalıştırmanın işlemini bulmak
+// ve aynı çalıştırmanın iki kez yapılmasını önlemek için kullanılır
+const (
+	ScheduleMetadataID  = "scheduled_transfer_id"
+	ScheduleMetadataFor = "scheduled_for"
+)
+
+var (
+	ErrScheduleNotFound     = NewError(CodeNotFound, "talimat bulunamadı").WithMessage("scheduled_transfer", "talimat bulunamadı")
+	ErrInvalidScheduleState = ErrConflict.WithMessage("schedule_state", "talimat bu durumda değiştirilemez")
+)
+
+// ScheduledTransfer, ileri tarihli veya düzenli tekrarlanan bir transfer talimatıdır.
+// NextRunAt sıradaki çalıştırmanın planlandığı zamandır; başarısız bir deneme tekrar denenecekse DueAt
+// yeniden deneme zamanına ertelenir, NextRunAt değişmez.
+type ScheduledTransfer struct {
+	ID            int64             `json:"id"`
+	OwnerID       int64             `json:"owner_id"` // Gönderen hesabın sahibi
+	CreatedBy     int64             `json:"created_by"`
+	FromAccountID int64             `json:"from_account_id"`
+	ToAccountID   int64             `json:"to_account_id"`
+	Amount        Money             `json:"amount"`
+	Frequency     ScheduleFrequency `json:"frequency"`
+	DayOfMonth    int               `json:"day_of_month,omitempty"` // Sadece monthly için, 1-31
+	NextRunAt     time.Time         `json:"next_run_at"`
+	DueAt         time.Time         `json:"due_at"`             // Sıradaki denemenin zamanı
+	EndDate       *time.Time        `json:"end_date,omitempty"` // Bu zamandan sonraki çalıştırmalar yapılmaz
+	Attempts      int               `json:"attempts"`           // Sıradaki çalıştırmanın başarısız deneme sayısı
+	Status        ScheduleStatus    `json:"status"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	TransactionDetails
+}
+
+// Talimatı doğrular ve start zamanından itibaren ilk çalıştırma zamanını hesaplar
+func (s *ScheduledTransfer) Schedule(start time.Time) error {
+	if !s.Frequency.IsValid() {
+		return NewValidationError("invalid_frequency", "sıklık once, weekly, monthly veya month_end olmalı")
+	}
+	if s.Frequency == ScheduleMonthly {
+		if s.DayOfMonth < 1 || s.DayOfMonth > 31 {
+			return NewValidationError("invalid_day_of_month", "aylık talimatlar için ayın günü 1 ile 31 arasında olmalı")
+		}
+	} else {
+		s.DayOfMonth = 0
+	}
+	if s.FromAccountID == s.ToAccountID {
+		return NewValidationError("same_account_transfer", "gönderen ve alıcı hesap aynı olamaz")
+	}
+	start = start.UTC().Truncate(time.Second)
+	switch s.Frequency {
+	case ScheduleMonthly:
+		if s.NextRunAt = monthDay(start, 0, s.DayOfMonth); s.NextRunAt.Before(start) {
+			s.NextRunAt = monthDay(start, 1, s.DayOfMonth)
+		}
+	case ScheduleMonthEnd:
+		s.NextRunAt = monthDay(start, 0, 31)
+	default:
+		s.NextRunAt = start
+	}
+	if s.EndDate != nil && s.EndDate.Before(s.NextRunAt) {
+		return NewValidationError("end_before_start", "bitiş tarihi ilk çalıştırmadan önce olamaz")
+	}
+	s.DueAt = s.NextRunAt
+	s.Attempts = 0
+	return nil
+}
+
+// Sıradaki çalıştırmadan sonraki çalıştırma zamanını döndürür; talimat tek seferlikse veya
+// sonraki çalıştırma bitiş tarihinden sonraysa false döner
+func (s *ScheduledTransfer) NextOccurrence() (time.Time, bool) {
+	var next time.Time
+	switch s.Frequency {
+	case ScheduleWeekly:
+		next = s.NextRunAt.AddDate(0, 0, 7)
+	case ScheduleMonthly:
+		next = monthDay(s.NextRunAt, 1, s.DayOfMonth)
+	case ScheduleMonthEnd:
+		next = monthDay(s.NextRunAt, 1, 31)
+	default:
+		return time.Time{}, false
+	}
+	if s.EndDate != nil && next.After(*s.EndDate) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// Sıradaki çalıştırmaya geçer; başka çalıştırma yoksa talimatı final durumuna alır
+func (s *ScheduledTransfer) Advance(final ScheduleStatus) {
+	s.Attempts = 0
+	next, ok := s.NextOccurrence()
+	if !ok {
+		s.Status = final
+		return
+	}
+	s.NextRunAt, s.DueAt = next, next
+}
+
+// t'nin ayından months ay sonraki ayın day gününü t'nin saatiyle döndürür; ay daha kısaysa ayın son günü
+func monthDay(t time.Time, months, day int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// Çalıştırmada oluşturulacak transferin açıklayıcı bilgilerini döndürür; talimatın metadata'sına
+// talimat ID'si ve planlanan zaman eklenir
+func (s *ScheduledTransfer) RunDetails() TransactionDetails {
+	details := s.TransactionDetails
+	details.Metadata = make(map[string]string, len(s.Metadata)+2)
+	for k, v := range s.Metadata {
+		details.Metadata[k] = v
+	}
+	details.Metadata[ScheduleMetadataID] = strconv.FormatInt(s.ID, 10)
+	details.Metadata[ScheduleMetadataFor] = s.NextRunAt.Format(time.RFC3339)
+	return details
+}
+
+// ScheduleUpdate, talimatta değiştirilebilecek alanlardır; nil alanlar değişmez
+type ScheduleUpdate struct {
+	Amount      *Money          `json:"amount,omitempty"`
+	Description *string         `json:"description,omitempty"`
+	Status      *ScheduleStatus `json:"status,omitempty"`   // Sadece active veya paused
+	StartAt     *time.Time      `json:"start_at,omitempty"` // Sıradaki çalıştırmayı bu zamandan itibaren yeniden planlar
+	EndDate     *time.Time      `json:"end_date,omitempty"`
+}
+
+// ScheduleRunStatus, bir çalıştırma denemesinin sonucudur
+type ScheduleRunStatus string
+
+const (
+	ScheduleRunSucceeded ScheduleRunStatus = "succeeded"
+	ScheduleRunRetrying  ScheduleRunStatus = "retrying" // Başarısız; yeniden denenecek
+	ScheduleRunFailed    ScheduleRunStatus = "failed"   // Başarısız; bu çalıştırma için tekrar denenmeyecek
+)
+
+// ScheduleRun, talimatın bir çalıştırma denemesinin kaydıdır
+type ScheduleRun struct {
+	ID            int64             `json:"id"`
+	ScheduleID    int64             `json:"schedule_id"`
+	ScheduledFor  time.Time         `json:"scheduled_for"`
+	Attempt       int               `json:"attempt"`
+	Status        ScheduleRunStatus `json:"status"`
+	TransactionID *int64            `json:"transaction_id,omitempty"`
+	ErrorCode     string            `json:"error_code,omitempty"`
+	Error         string            `json:"error,omitempty"`
+	RanAt         time.Time         `json:"ran_at"`
+}
+
+// ScheduleRetryPolicy, yetersiz bakiye nedeniyle başarısız olan çalıştırmaların nasıl tekrar deneneceğidir
+type ScheduleRetryPolicy struct {
+	MaxAttempts int           // İlk deneme dahil en fazla deneme sayısı
+	Interval    time.Duration // Denemeler arasındaki süre
+}
+
+// ScheduleRepository, talimatları ve çalıştırma geçmişlerini saklar
+type ScheduleRepository interface {
+	Create(s *ScheduledTransfer) error
+	FindByID(id int64) (*ScheduledTransfer, error)
+	// Kullanıcının gönderen olduğu talimatları ID sırasıyla listeler
+	ListByOwner(ownerID int64) ([]*ScheduledTransfer, error)
+	Update(s *ScheduledTransfer) error
+	// Etkin olup deneme zamanı now itibarıyla gelmiş talimatları deneme zamanı sırasıyla listeler
+	ListDue(now time.Time) ([]*ScheduledTransfer, error)
+	AddRun(run *ScheduleRun) error
+	// Talimatın çalıştırmalarını eskiden yeniye listeler
+	ListRuns(scheduleID int64) ([]*ScheduleRun, error)
+}
+
+// ScheduleService, zamanlanmış transfer talimatlarını yönetir ve zamanı gelenleri çalıştırır
+type ScheduleService interface {
+	// Talimatı doğrular ve start zamanından itibaren planlar; start sıfırsa ilk çalıştırma hemen yapılır
+	Create(s *ScheduledTransfer, start time.Time) error
+	GetByID(id int64) (*ScheduledTransfer, error)
+	ListByOwner(ownerID int64) ([]*ScheduledTransfer, error)
+	Update(id int64, update ScheduleUpdate) (*ScheduledTransfer, error)
+	Cancel(id int64) (*ScheduledTransfer, error)
+	Runs(id int64) ([]*ScheduleRun, error)
+	// Zamanı now itibarıyla gelmiş talimatları çalıştırır; kaydedilen deneme sayısını ve kaydedilemeyen denemelerin hatalarını döner
+	RunDue(now time.Time) (int, error)
+}
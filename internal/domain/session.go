@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// Session, bir girişle başlayan ve aynı refresh token ailesini paylaşan oturumdur
+type Session struct {
+	ID        string
+	UserID    int64
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+// Oturum iptal edilmemiş ve süresi dolmamışsa true döner
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// RefreshToken, oturuma ait tek kullanımlık yenileme token'ıdır; sadece hash'i saklanır
+type RefreshToken struct {
+	Hash      string
+	SessionID string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time // Rotasyonda kullanıldıysa dolu
+}
+
+// SessionRepository, oturumları ve refresh token hash'lerini saklar
+type SessionRepository interface {
+	CreateSession(session *Session, token *RefreshToken) error
+	FindSession(id string) (*Session, error)
+	FindRefreshToken(hash string) (*RefreshToken, error)
+	// Eski token'ı kullanılmış işaretleyip yenisini ekler; eski token zaten kullanılmışsa false döner
+	RotateRefreshToken(oldHash string, next *RefreshToken, now time.Time) (bool, error)
+	RevokeSession(id string, now time.Time) error
+	RevokeUserSessions(userID int64, now time.Time) error
+}
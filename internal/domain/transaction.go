@@ -1,7 +1,6 @@
 package domain
 
 import (
-	"errors"
 	"time"
 )
 
@@ -17,21 +16,52 @@ const (
 	TransactionDeposit  TransactionType = "deposit"
 	TransactionWithdraw TransactionType = "withdraw"
 	TransactionTransfer TransactionType = "transfer"
+	TransactionReversal TransactionType = "reversal" // Orijinal işlemin tamamını ters çevirir
+	TransactionRefund   TransactionType = "refund"   // Orijinal işlemin bir kısmını veya tamamını iade eder
+	TransactionFee      TransactionType = "fee"      // Orijinal işlem için tahsil edilen ücret (ücretler hesabına)
+	TransactionInterest TransactionType = "interest" // Bir dönemde tahakkuk eden faizin bakiyeye eklenmesi
 )
 
+// Türün bilinen işlem türlerinden biri olup olmadığını döndürür
+func (t TransactionType) IsValid() bool {
+	switch t {
+	case TransactionDeposit, TransactionWithdraw, TransactionTransfer, TransactionReversal, TransactionRefund, TransactionFee, TransactionInterest:
+		return true
+	}
+	return false
+}
+
+// Durumun bilinen işlem durumlarından biri olup olmadığını döndürür
+func (s TransactionStatus) IsValid() bool {
+	switch s {
+	case TransactionPending, TransactionCompleted, TransactionFailed:
+		return true
+	}
+	return false
+}
+
 type Transaction struct {
-	ID         int64             `json:"id"`
-	FromUserID *int64            `json:"from_user_id,omitempty"`
-	ToUserID   *int64            `json:"to_user_id,omitempty"`
-	Amount     float64           `json:"amount"`
-	Type       TransactionType   `json:"type"`
-	Status     TransactionStatus `json:"status"`
-	CreatedAt  time.Time         `json:"created_at"`
+	ID            int64             `json:"id"`
+	FromUserID    *int64            `json:"from_user_id,omitempty"`
+	ToUserID      *int64            `json:"to_user_id,omitempty"`
+	FromAccountID *int64            `json:"from_account_id,omitempty"` // Paranın çıktığı hesap (sahibi FromUserID)
+	ToAccountID   *int64            `json:"to_account_id,omitempty"`   // Paranın girdiği hesap (sahibi ToUserID)
+	Amount        Money             `json:"amount"`
+	Type          TransactionType   `json:"type"`
+	Status        TransactionStatus `json:"status"`
+	OriginalID    *int64            `json:"original_transaction_id,omitempty"` // Geri alma ve iadelerde ters çevrilen, ücretlerde ücretin alındığı işlem
+	CreatedAt     time.Time         `json:"created_at"`
+	BookingDate   time.Time         `json:"booking_date"` // İşlemin hesaba kaydedildiği gün
+	ValueDate     time.Time         `json:"value_date"`   // Tutarın faiz ve valörlü bakiyede sayılmaya başladığı gün
+	// Farklı para birimleri arasındaki transferlerde kullanılan kur; aynı para biriminde nil
+	Conversion *FXConversion `json:"conversion,omitempty"`
+	// Açıklama, dış referans, etiketler ve metadata JSON'da işlemin kendi alanları olarak görünür
+	TransactionDetails
 }
 
 func (t *Transaction) Complete() error {
 	if t.Status != TransactionPending {
-		return errors.New("sadece bekleyen işlemler tamamlanabilir")
+		return ErrInvalidTransactionState.WithMessage("complete_requires_pending", "sadece bekleyen işlemler tamamlanabilir")
 	}
 	t.Status = TransactionCompleted
 	return nil
@@ -39,8 +69,19 @@ func (t *Transaction) Complete() error {
 
 func (t *Transaction) Fail() error {
 	if t.Status != TransactionPending {
-		return errors.New("sadece bekleyen işlemler başarısız yapılabilir")
+		return ErrInvalidTransactionState.WithMessage("fail_requires_pending", "sadece bekleyen işlemler başarısız yapılabilir")
 	}
 	t.Status = TransactionFailed
 	return nil
 }
+
+// Geri alma ve iadeler sadece tamamlanmış para hareketleri için yapılabilir; bunların kendisi tekrar ters çevrilemez
+func (t *Transaction) EnsureReversible() error {
+	if t.Type == TransactionReversal || t.Type == TransactionRefund {
+		return ErrInvalidTransactionState.WithMessage("not_reversible", "geri alma ve iade işlemleri ters çevrilemez")
+	}
+	if t.Status != TransactionCompleted {
+		return ErrInvalidTransactionState.WithMessage("rollback_requires_completed", "sadece tamamlanmış işlemler geri alınabilir")
+	}
+	return nil
+}
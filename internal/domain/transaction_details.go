@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// İşlem açıklaması ve etiket sınırları
+const (
+	MaxDescriptionLength       = 255
+	MaxExternalReferenceLength = 64
+	MaxTags                    = 10
+	MaxTagLength               = 32
+	MaxMetadataEntries         = 20
+	MaxMetadataKeyLength       = 40
+	MaxMetadataValueLength     = 256
+)
+
+var (
+	externalReferencePattern = regexp.MustCompile(`^[A-Za-z0-9._:/-]+$`)
+	tagPattern               = regexp.MustCompile(`^[a-z0-9_-]+$`)
+	metadataKeyPattern       = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+// TransactionDetails, kullanıcının işleme eklediği isteğe bağlı açıklayıcı bilgilerdir.
+// Para hareketini etkilemez; işlem geçmişinde arama ve mutabakat için kullanılır.
+type TransactionDetails struct {
+	Description       string            `json:"description,omitempty"`
+	ExternalReference string            `json:"external_reference,omitempty"` // Dış sistemdeki kayıt numarası (fatura, sipariş vb.)
+	Tags              []string          `json:"tags,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+}
+
+// Alanları doğrular; etiketler küçük harfe çevrilir ve tekrar edenler atılır
+func (d *TransactionDetails) Normalize() error {
+	d.Description = strings.TrimSpace(d.Description)
+	if utf8.RuneCountInString(d.Description) > MaxDescriptionLength {
+		return detailsError("description_too_long", "açıklama en fazla {max} karakter olabilir", MaxDescriptionLength)
+	}
+	if hasControlCharacters(d.Description) {
+		return NewValidationError("invalid_description", "açıklama kontrol karakteri içeremez")
+	}
+
+	d.ExternalReference = strings.TrimSpace(d.ExternalReference)
+	if len(d.ExternalReference) > MaxExternalReferenceLength {
+		return detailsError("external_reference_too_long", "dış referans en fazla {max} karakter olabilir", MaxExternalReferenceLength)
+	}
+	if d.ExternalReference != "" && !externalReferencePattern.MatchString(d.ExternalReference) {
+		return NewValidationError("invalid_external_reference", "dış referans sadece harf, rakam ve . _ : / - içerebilir")
+	}
+
+	tags, err := NormalizeTags(d.Tags)
+	if err != nil {
+		return err
+	}
+	d.Tags = tags
+
+	if len(d.Metadata) > MaxMetadataEntries {
+		return detailsError("too_many_metadata_entries", "en fazla {max} metadata alanı eklenebilir", MaxMetadataEntries)
+	}
+	for key, value := range d.Metadata {
+		if len(key) > MaxMetadataKeyLength || !metadataKeyPattern.MatchString(key) {
+			return NewValidationError("invalid_metadata_key", "metadata anahtarı en fazla 40 karakter olmalı ve sadece harf, rakam ve . _ - içerebilir: {key}").
+				WithParams(map[string]interface{}{"key": key})
+		}
+		if utf8.RuneCountInString(value) > MaxMetadataValueLength || hasControlCharacters(value) {
+			return NewValidationError("invalid_metadata_value", "metadata değeri en fazla 256 karakter olmalı ve kontrol karakteri içeremez: {key}").
+				WithParams(map[string]interface{}{"key": key})
+		}
+	}
+	if len(d.Metadata) == 0 {
+		d.Metadata = nil
+	}
+	return nil
+}
+
+// Etiketleri küçük harfe çevirir, tekrar edenleri atar ve biçimlerini doğrular
+func NormalizeTags(tags []string) ([]string, error) {
+	if len(tags) > MaxTags {
+		return nil, detailsError("too_many_tags", "en fazla {max} etiket eklenebilir", MaxTags)
+	}
+	var result []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || len(tag) > MaxTagLength || !tagPattern.MatchString(tag) {
+			return nil, NewValidationError("invalid_tag", "etiket 1-32 karakter olmalı ve sadece harf, rakam, _ ve - içerebilir: {tag}").
+				WithParams(map[string]interface{}{"tag": tag})
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
+
+func detailsError(variant, message string, max int) *Error {
+	return NewValidationError(variant, message).WithParams(map[string]interface{}{"max": max})
+}
+
+func hasControlCharacters(s string) bool {
+	return strings.IndexFunc(s, unicode.IsControl) >= 0
+}
@@ -0,0 +1,236 @@
+package domain
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sayfa boyutu sınırları
+const (
+	DefaultTransactionPageSize = 50
+	MaxTransactionPageSize     = 200
+)
+
+// Tutar aralığı filtresi para birimi olmadan yorumlanamaz (alt birim basamakları para birimine bağlıdır)
+var ErrAmountRangeRequiresCurrency = NewValidationError("amount_range_requires_currency", "tutar aralığı için para birimi gerekli")
+
+// TransactionDirection, işlemin filtredeki kullanıcıya göre yönüdür
+type TransactionDirection string
+
+const (
+	DirectionAny TransactionDirection = ""    // Gönderen veya alıcı
+	DirectionIn  TransactionDirection = "in"  // Kullanıcı alıcı (para girişi)
+	DirectionOut TransactionDirection = "out" // Kullanıcı gönderen (para çıkışı)
+)
+
+// TransactionFilter, bir kullanıcının işlem geçmişi sorgusudur.
+// Boş bırakılan alanlar filtre uygulanmaz demektir; sonuçlar (created_at, id) sırasıyla döner.
+type TransactionFilter struct {
+	UserID         int64                // Gönderen veya alıcı olarak yer aldığı işlemler
+	Direction      TransactionDirection // Boşsa her iki yön
+	CreatedFrom    *time.Time           // created_at >= CreatedFrom
+	CreatedTo      *time.Time           // created_at < CreatedTo
+	Types          []TransactionType    // Verilen türlerden herhangi biri
+	Statuses       []TransactionStatus  // Verilen durumlardan herhangi biri
+	Currency       string               // Tutar aralığı verilmişse zorunludur
+	MinAmount      *int64               // Currency'nin alt biriminde, dahil
+	MaxAmount      *int64               // Currency'nin alt biriminde, dahil
+	CounterpartyID *int64               // İşlemin diğer tarafı bu kullanıcı olmalı
+	ExcludeSelf    bool                 // Gönderen ve alıcısı aynı kullanıcı olan işlemler (kendi hesapları arası) hariç
+	Converted      *bool                // Doğruysa sadece döviz çevirili, yanlışsa sadece çevirisiz işlemler
+	Text           string               // Açıklamada büyük/küçük harf duyarsız geçen metin
+	Reference      string               // Dış referansla birebir eşleşme
+	Tags           []string             // Verilen etiketlerin hepsi bulunmalı
+	Metadata       map[string]string    // Verilen metadata alanlarının hepsi aynı değerle bulunmalı
+	Descending     bool                 // En yeniden eskiye
+	Cursor         *TransactionCursor   // Önceki sayfanın son kaydı; nil ise ilk sayfa
+	Limit          int
+}
+
+// TransactionCursor, sayfanın son kaydının sıralama anahtarıdır (keyset sayfalama)
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+// TransactionPage, işlem geçmişinin bir sayfasıdır
+type TransactionPage struct {
+	Transactions []*Transaction `json:"transactions"`
+	NextCursor   string         `json:"next_cursor,omitempty"` // Boşsa son sayfadır
+	Total        *int64         `json:"total,omitempty"`       // Filtreye uyan toplam kayıt; hesaplaması pahalıysa boş
+}
+
+// Filtreyi doğrular ve varsayılan sayfa boyutunu uygular
+func (f *TransactionFilter) Normalize() error {
+	switch {
+	case f.Limit == 0:
+		f.Limit = DefaultTransactionPageSize
+	case f.Limit < 0 || f.Limit > MaxTransactionPageSize:
+		return NewValidationError("invalid_page_size", "sayfa boyutu 1 ile {max} arasında olmalı").
+			WithParams(map[string]interface{}{"max": MaxTransactionPageSize})
+	}
+	if f.Direction != DirectionAny && f.Direction != DirectionIn && f.Direction != DirectionOut {
+		return NewValidationError("invalid_direction", "yön in veya out olmalı")
+	}
+	if f.CreatedFrom != nil && f.CreatedTo != nil && !f.CreatedFrom.Before(*f.CreatedTo) {
+		return NewValidationError("invalid_date_range", "başlangıç tarihi bitiş tarihinden önce olmalı")
+	}
+	if f.MinAmount != nil || f.MaxAmount != nil {
+		if f.Currency == "" {
+			return ErrAmountRangeRequiresCurrency
+		}
+		if f.MinAmount != nil && f.MaxAmount != nil && *f.MinAmount > *f.MaxAmount {
+			return NewValidationError("invalid_amount_range", "en düşük tutar en yüksek tutardan büyük olamaz")
+		}
+	}
+	tags, err := NormalizeTags(f.Tags)
+	if err != nil {
+		return err
+	}
+	f.Tags = tags
+	if f.Currency != "" {
+		currency, err := ValidateCurrency(f.Currency)
+		if err != nil {
+			return err
+		}
+		f.Currency = currency
+	}
+	return nil
+}
+
+// İşlemin filtredeki kullanıcı dışındaki tarafını döndürür (para yatırma/çekmede yoktur)
+func (f *TransactionFilter) counterparty(tx *Transaction) *int64 {
+	if tx.FromUserID != nil && *tx.FromUserID == f.UserID {
+		return tx.ToUserID
+	}
+	return tx.FromUserID
+}
+
+// İşlemin, sıralama ve sayfalama dışındaki tüm filtrelere uyup uymadığını döndürür.
+// Veritabanı kullanmayan repository'ler için ortak eşleştirme mantığıdır.
+func (f *TransactionFilter) Matches(tx *Transaction) bool {
+	from := tx.FromUserID != nil && *tx.FromUserID == f.UserID
+	to := tx.ToUserID != nil && *tx.ToUserID == f.UserID
+	if !from && !to || f.Direction == DirectionIn && !to || f.Direction == DirectionOut && !from {
+		return false
+	}
+	if f.ExcludeSelf && from && to {
+		return false
+	}
+	if f.CreatedFrom != nil && tx.CreatedAt.Before(*f.CreatedFrom) {
+		return false
+	}
+	if f.CreatedTo != nil && !tx.CreatedAt.Before(*f.CreatedTo) {
+		return false
+	}
+	if len(f.Types) > 0 && !containsType(f.Types, tx.Type) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !containsStatus(f.Statuses, tx.Status) {
+		return false
+	}
+	if f.Currency != "" && NormalizeCurrency(tx.Amount.Currency) != f.Currency {
+		return false
+	}
+	if f.MinAmount != nil && tx.Amount.Amount < *f.MinAmount {
+		return false
+	}
+	if f.MaxAmount != nil && tx.Amount.Amount > *f.MaxAmount {
+		return false
+	}
+	if f.CounterpartyID != nil {
+		other := f.counterparty(tx)
+		if other == nil || *other != *f.CounterpartyID {
+			return false
+		}
+	}
+	if f.Converted != nil && (tx.Conversion != nil) != *f.Converted {
+		return false
+	}
+	if f.Text != "" && !strings.Contains(strings.ToLower(tx.Description), strings.ToLower(f.Text)) {
+		return false
+	}
+	if f.Reference != "" && tx.ExternalReference != f.Reference {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !containsString(tx.Tags, tag) {
+			return false
+		}
+	}
+	for key, value := range f.Metadata {
+		if actual, ok := tx.Metadata[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, s string) bool {
+	for _, candidate := range values {
+		if candidate == s {
+			return true
+		}
+	}
+	return false
+}
+
+// İşlemin, sayfalama sırasına göre imlecin ötesinde olup olmadığını döndürür
+func (f *TransactionFilter) AfterCursor(tx *Transaction) bool {
+	if f.Cursor == nil {
+		return true
+	}
+	c := f.Cursor
+	if f.Descending {
+		return tx.CreatedAt.Before(c.CreatedAt) || (tx.CreatedAt.Equal(c.CreatedAt) && tx.ID < c.ID)
+	}
+	return tx.CreatedAt.After(c.CreatedAt) || (tx.CreatedAt.Equal(c.CreatedAt) && tx.ID > c.ID)
+}
+
+func containsType(types []TransactionType, t TransactionType) bool {
+	for _, candidate := range types {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
+
+func containsStatus(statuses []TransactionStatus, s TransactionStatus) bool {
+	for _, candidate := range statuses {
+		if candidate == s {
+			return true
+		}
+	}
+	return false
+}
+
+// İşlemin imlecini istemciye verilecek opak metne çevirir
+func EncodeTransactionCursor(tx *Transaction) string {
+	raw := strconv.FormatInt(tx.CreatedAt.UnixNano(), 10) + ":" + strconv.FormatInt(tx.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// EncodeTransactionCursor ile üretilmiş metni çözer
+func DecodeTransactionCursor(s string) (*TransactionCursor, error) {
+	invalid := NewValidationError("invalid_cursor", "geçersiz sayfa imleci")
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalid
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, invalid
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, invalid
+	}
+	txID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, invalid
+	}
+	return &TransactionCursor{CreatedAt: time.Unix(0, n).UTC(), ID: txID}, nil
+}
@@ -1,36 +1,39 @@
 package domain
 
 import (
-	"errors"
 	"regexp"
 )
 
+// Hesabı, işlemi veya talimatı olan kullanıcı silinemez; finansal kayıtlar kullanıcıya bağlı kalmalıdır
+var ErrUserHasRecords = ErrConflict.WithMessage("user_has_records", "finansal kaydı olan kullanıcı silinemez")
+
 // User, sistemdeki kullanıcıyı temsil eder
 // Kullanıcı adı, e-posta, şifre ve rol bilgilerini içerir
 type User struct {
-	ID       int64  `json:"id"`       // Kullanıcının benzersiz ID'si
-	Username string `json:"username"` // Kullanıcı adı
-	Email    string `json:"email"`    // E-posta adresi
-	Password string `json:"password"` // Şifre (hash'lenmiş olarak tutulmalı)
-	Role     string `json:"role"`     // Kullanıcı rolü (ör: admin, user)
+	ID       int64  `json:"id"`               // Kullanıcının benzersiz ID'si
+	Username string `json:"username"`         // Kullanıcı adı
+	Email    string `json:"email"`            // E-posta adresi
+	Password string `json:"password"`         // Şifre (hash'lenmiş olarak tutulmalı)
+	Role     string `json:"role"`             // Kullanıcı rolü (ör: admin, user)
+	Locale   string `json:"locale,omitempty"` // Tercih edilen dil (ör: tr, en); boşsa Accept-Language kullanılır
 }
 
 // Kullanıcı verisinin geçerli olup olmadığını kontrol eder
 func (u *User) Validate() error {
 	if u.Username == "" {
-		return errors.New("username boş olamaz")
+		return NewValidationError("username_required", "username boş olamaz")
 	}
 	if u.Email == "" {
-		return errors.New("email boş olamaz")
+		return NewValidationError("email_required", "email boş olamaz")
 	}
 	if !isValidEmail(u.Email) {
-		return errors.New("geçersiz email formatı")
+		return NewValidationError("invalid_email", "geçersiz email formatı")
 	}
 	if u.Password == "" {
-		return errors.New("şifre boş olamaz")
+		return NewValidationError("password_required", "şifre boş olamaz")
 	}
 	if u.Role == "" {
-		return errors.New("rol boş olamaz")
+		return NewValidationError("role_required", "rol boş olamaz")
 	}
 	return nil
 }
@@ -0,0 +1,126 @@
+package fees
+
+import (
+	"gofinancialsystem/internal/domain"
+	"sync"
+	"time"
+)
+
+// Engine, FeeService arayüzünün implementasyonudur. Ücret tarifesi bellekte tutulur; açılışta
+// dosyadan yüklenir ve admin endpoint'inden tamamen değiştirilebilir.
+type Engine struct {
+	mu           sync.RWMutex
+	schedule     *domain.FeeSchedule
+	transactions domain.TransactionRepository // Unit of work dışındaki ön izlemelerde ücretsiz kullanım sayımı için
+	now          func() time.Time
+}
+
+// Yeni bir Engine oluşturur; rules doğrulanmış olmalıdır (bkz. ParseJSON). Kural yoksa ücret alınmaz.
+func NewEngine(rules []domain.FeeRule, transactions domain.TransactionRepository) *Engine {
+	if rules == nil {
+		rules = []domain.FeeRule{}
+	}
+	e := &Engine{transactions: transactions, now: time.Now}
+	e.schedule = &domain.FeeSchedule{Rules: rules, UpdatedAt: e.now()}
+	return e
+}
+
+// Hareketin ücretini hesaplar; hareketin türü ve para birimi için kural yoksa ücret sıfırdır.
+// Ücretsiz kullanım txs üzerinden sayılır; unit of work içinde kullanıcı transaction sonuna kadar kilitli kalır.
+func (e *Engine) Calculate(txs domain.TransactionRepository, m domain.FeeMovement) (*domain.FeeQuote, error) {
+	if !m.Operation.IsValid() {
+		return nil, domain.NewValidationError("invalid_fee_operation", "ücret türü withdraw, transfer veya fx olmalı")
+	}
+	currency := domain.NormalizeCurrency(m.Amount.Currency)
+	quote := &domain.FeeQuote{
+		Operation: m.Operation,
+		Amount:    m.Amount,
+		Fee:       domain.NewMoney(0, currency),
+		Total:     m.Amount,
+	}
+	rule := e.rule(m.Operation, currency)
+	if rule == nil {
+		return quote, nil
+	}
+	quote.Rule = rule
+
+	if rule.FreePerMonth > 0 {
+		// Eşzamanlı hareketler aynı sayımı görüp birlikte ücretsiz geçmesin diye kullanıcı önce kilitlenir
+		if err := txs.LockUser(m.UserID); err != nil {
+			return nil, err
+		}
+		used, err := txs.Count(m.UsageFilter(monthStart(e.now())))
+		if err != nil {
+			return nil, err
+		}
+		if used < int64(rule.FreePerMonth) {
+			remaining := rule.FreePerMonth - int(used) - 1
+			quote.FreeRemaining = &remaining
+			return quote, nil
+		}
+		remaining := 0
+		quote.FreeRemaining = &remaining
+	}
+
+	fee, err := rule.Fee(m.Amount)
+	if err != nil {
+		return nil, err
+	}
+	total, err := m.Amount.Add(fee)
+	if err != nil {
+		return nil, err
+	}
+	quote.Fee = fee
+	quote.Total = total
+	return quote, nil
+}
+
+// Ücreti, unit of work dışında kayıtlı işlemlere göre hesaplar
+func (e *Engine) Preview(m domain.FeeMovement) (*domain.FeeQuote, error) {
+	if !m.Amount.IsPositive() {
+		return nil, domain.ErrInvalidAmount
+	}
+	if _, err := domain.ValidateCurrency(m.Amount.Currency); err != nil {
+		return nil, err
+	}
+	return e.Calculate(e.transactions, m)
+}
+
+// Geçerli ücret tarifesini döndürür
+func (e *Engine) Schedule() *domain.FeeSchedule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.schedule
+}
+
+// Ücret tarifesini doğrular ve tamamen değiştirir; sonraki hareketler yeni tarifeyle ücretlendirilir
+func (e *Engine) SetRules(rules []domain.FeeRule) (*domain.FeeSchedule, error) {
+	if rules == nil {
+		rules = []domain.FeeRule{}
+	}
+	if err := domain.NormalizeFeeRules(rules); err != nil {
+		return nil, err
+	}
+	schedule := &domain.FeeSchedule{Rules: rules, UpdatedAt: e.now()}
+	e.mu.Lock()
+	e.schedule = schedule
+	e.mu.Unlock()
+	return schedule, nil
+}
+
+func (e *Engine) rule(operation domain.FeeOperation, currency string) *domain.FeeRule {
+	schedule := e.Schedule()
+	for i := range schedule.Rules {
+		if schedule.Rules[i].Operation == operation && schedule.Rules[i].Currency == currency {
+			rule := schedule.Rules[i]
+			return &rule
+		}
+	}
+	return nil
+}
+
+// Ücretsiz kullanım hakları UTC takvim ayının başında yenilenir
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,216 @@
+package fees
+
+import (
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/repository/memtest"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+func money(minor int64, currency string) *domain.Money {
+	m := domain.NewMoney(minor, currency)
+	return &m
+}
+
+func ptr(id int64) *int64 { return &id }
+
+func errorKey(err error) string {
+	if e, ok := domain.AsError(err); ok {
+		return e.MessageKey()
+	}
+	return ""
+}
+
+func newTestEngine(t *testing.T, rules []domain.FeeRule, transactions domain.TransactionRepository) *Engine {
+	t.Helper()
+	if err := domain.NormalizeFeeRules(rules); err != nil {
+		t.Fatal(err)
+	}
+	e := NewEngine(rules, transactions)
+	e.now = func() time.Time { return testNow }
+	return e
+}
+
+func TestCalculateFee(t *testing.T) {
+	e := newTestEngine(t, []domain.FeeRule{
+		{Operation: domain.FeeTransfer, Currency: "TRY", Flat: money(100, "TRY"), PercentBps: 10, Min: money(200, "TRY"), Max: money(2500, "TRY")},
+		{Operation: domain.FeeWithdraw, Currency: "try", PercentBps: 25},
+		{Operation: domain.FeeFX, Currency: "USD", Flat: money(50, "USD"), PercentBps: 100, Max: money(1000, "USD")},
+		{Operation: domain.FeeWithdraw, Currency: "JPY", PercentBps: 33},
+		{Operation: domain.FeeWithdraw, Currency: "EUR", Flat: money(0, "EUR")},
+	}, memtest.New().Transactions)
+
+	cases := []struct {
+		name      string
+		operation domain.FeeOperation
+		amount    domain.Money
+		fee       int64
+		rule      bool // Bir kural eşleşmeli
+	}{
+		// Sabit ücret ile yüzde toplanır, sonra en az ve en fazla ücrete sıkıştırılır
+		{"en az ücrete yükseltilir", domain.FeeTransfer, domain.NewMoney(10000, "TRY"), 200, true},
+		{"sabit ve yüzde", domain.FeeTransfer, domain.NewMoney(500000, "TRY"), 600, true},
+		{"en fazla ücrete indirilir", domain.FeeTransfer, domain.NewMoney(3000000, "TRY"), 2500, true},
+		{"en fazla ücret sınırında", domain.FeeTransfer, domain.NewMoney(2400000, "TRY"), 2500, true},
+
+		// Yüzde kısmı alt birime yukarı yuvarlanır
+		{"tam bölünen yüzde", domain.FeeWithdraw, domain.NewMoney(40000, "TRY"), 100, true},
+		{"küsurat yukarı yuvarlanır", domain.FeeWithdraw, domain.NewMoney(10001, "TRY"), 26, true},
+		{"en küçük tutar", domain.FeeWithdraw, domain.NewMoney(1, "TRY"), 1, true},
+		{"küçük harf para birimi", domain.FeeWithdraw, domain.NewMoney(40000, "try"), 100, true},
+		{"alt birimi olmayan para birimi", domain.FeeWithdraw, domain.NewMoney(1001, "JPY"), 4, true},
+		{"sıfır ücretli kural", domain.FeeWithdraw, domain.NewMoney(10000, "EUR"), 0, true},
+		{"döviz çevirisi", domain.FeeFX, domain.NewMoney(20000, "USD"), 250, true},
+		{"döviz çevirisinde en fazla ücret", domain.FeeFX, domain.NewMoney(200000, "USD"), 1000, true},
+
+		// Kural, ücret türü ve para biriminin ikisiyle birden eşleşmelidir
+		{"para birimi için kural yok", domain.FeeTransfer, domain.NewMoney(10000, "EUR"), 0, false},
+		{"ücret türü için kural yok", domain.FeeFX, domain.NewMoney(10000, "TRY"), 0, false},
+		{"döviz çevirisi kuralı transfere uygulanmaz", domain.FeeTransfer, domain.NewMoney(20000, "USD"), 0, false},
+	}
+	for _, c := range cases {
+		quote, err := e.Preview(domain.FeeMovement{UserID: 1, Operation: c.operation, Amount: c.amount})
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		currency := domain.NormalizeCurrency(c.amount.Currency)
+		if quote.Fee != domain.NewMoney(c.fee, currency) || quote.Total.Amount != c.amount.Amount+c.fee || (quote.Rule != nil) != c.rule {
+			t.Errorf("%s: ücret %s, toplam %s, kural %+v; beklenen %d", c.name, quote.Fee, quote.Total, quote.Rule, c.fee)
+		}
+		if quote.Rule != nil && (quote.Rule.Operation != c.operation || quote.Rule.Currency != currency) {
+			t.Errorf("%s: eşleşen kural %+v", c.name, quote.Rule)
+		}
+		if quote.FreeRemaining != nil {
+			t.Errorf("%s: ücretsiz hakkı olmayan kuralda kalan hak %d", c.name, *quote.FreeRemaining)
+		}
+	}
+
+	invalid := []struct {
+		name     string
+		movement domain.FeeMovement
+		key      string
+	}{
+		{"sıfır tutar", domain.FeeMovement{Operation: domain.FeeWithdraw, Amount: domain.NewMoney(0, "TRY")}, "invalid_amount"},
+		{"desteklenmeyen para birimi", domain.FeeMovement{Operation: domain.FeeWithdraw, Amount: domain.NewMoney(100, "XXX")}, "unsupported_currency"},
+		{"geçersiz ücret türü", domain.FeeMovement{Operation: "deposit", Amount: domain.NewMoney(100, "TRY")}, "validation_failed.invalid_fee_operation"},
+	}
+	for _, c := range invalid {
+		if _, err := e.Preview(c.movement); errorKey(err) != c.key {
+			t.Errorf("%s: %v, beklenen %s", c.name, err, c.key)
+		}
+	}
+}
+
+func TestFreePerMonth(t *testing.T) {
+	const alice, bob = 1, 2
+	thisMonth := testNow.Add(-5 * 24 * time.Hour)
+	lastMonth := time.Date(2026, 2, 28, 23, 59, 59, 0, time.UTC)
+	transfer := func(from, to int64, amount domain.Money, status domain.TransactionStatus, at time.Time) *domain.Transaction {
+		return &domain.Transaction{Type: domain.TransactionTransfer, FromUserID: &from, ToUserID: &to, Amount: amount, Status: status, CreatedAt: at}
+	}
+	converted := func(tx *domain.Transaction) *domain.Transaction {
+		tx.Conversion = &domain.FXConversion{Rate: "32.5", Target: domain.NewMoney(tx.Amount.Amount*32, "TRY")}
+		return tx
+	}
+	try := domain.NewMoney(10000, "TRY")
+
+	// Ayda iki transfer ücretsizdir; sonrasında 2,00 TRY alınır
+	cases := []struct {
+		name      string
+		previous  []*domain.Transaction
+		fee       int64
+		remaining int
+	}{
+		{"ilk işlem", nil, 0, 1},
+		{"son ücretsiz işlem", []*domain.Transaction{transfer(alice, bob, try, domain.TransactionCompleted, thisMonth)}, 0, 0},
+		{"hak bitmiş", []*domain.Transaction{
+			transfer(alice, bob, try, domain.TransactionCompleted, thisMonth),
+			transfer(alice, bob, try, domain.TransactionCompleted, thisMonth),
+		}, 200, 0},
+		// Sadece bu ay gönderilmiş, tamamlanmış, aynı türde ve para birimindeki işlemler sayılır
+		{"sayılmayan işlemler", []*domain.Transaction{
+			transfer(alice, bob, try, domain.TransactionCompleted, lastMonth),
+			transfer(alice, bob, try, domain.TransactionPending, thisMonth),
+			transfer(alice, bob, try, domain.TransactionFailed, thisMonth),
+			transfer(bob, alice, try, domain.TransactionCompleted, thisMonth),
+			transfer(alice, bob, domain.NewMoney(10000, "USD"), domain.TransactionCompleted, thisMonth),
+			converted(transfer(alice, bob, try, domain.TransactionCompleted, thisMonth)),
+			{Type: domain.TransactionWithdraw, FromUserID: ptr(alice), Amount: try, Status: domain.TransactionCompleted, CreatedAt: thisMonth},
+		}, 0, 1},
+		{"ay başı sayılır", []*domain.Transaction{
+			transfer(alice, bob, try, domain.TransactionCompleted, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)),
+			transfer(alice, bob, try, domain.TransactionCompleted, testNow),
+		}, 200, 0},
+	}
+	for _, c := range cases {
+		repos := memtest.New()
+		for _, tx := range c.previous {
+			if err := repos.Transactions.Create(tx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		e := newTestEngine(t, []domain.FeeRule{
+			{Operation: domain.FeeTransfer, Currency: "TRY", Flat: money(200, "TRY"), FreePerMonth: 2},
+		}, repos.Transactions)
+		quote, err := e.Preview(domain.FeeMovement{UserID: alice, Operation: domain.FeeTransfer, Amount: try})
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if quote.Fee.Amount != c.fee || quote.FreeRemaining == nil || *quote.FreeRemaining != c.remaining {
+			t.Errorf("%s: ücret %s, kalan hak %v; beklenen %d, %d", c.name, quote.Fee, quote.FreeRemaining, c.fee, c.remaining)
+		}
+	}
+}
+
+func TestSetRules(t *testing.T) {
+	e := newTestEngine(t, []domain.FeeRule{{Operation: domain.FeeWithdraw, Currency: "TRY", Flat: money(100, "TRY")}}, memtest.New().Transactions)
+
+	invalid := []struct {
+		name  string
+		rules []domain.FeeRule
+		err   error
+		key   string
+	}{
+		{"en az ücret en fazladan büyük", []domain.FeeRule{{Operation: domain.FeeWithdraw, Currency: "TRY", Min: money(500, "TRY"), Max: money(100, "TRY")}}, nil, "validation_failed.invalid_fee_range"},
+		{"yüzde sınırı", []domain.FeeRule{{Operation: domain.FeeWithdraw, Currency: "TRY", PercentBps: 10001}}, nil, "validation_failed.invalid_fee_percent"},
+		{"negatif ücret", []domain.FeeRule{{Operation: domain.FeeWithdraw, Currency: "TRY", Flat: money(-1, "TRY")}}, nil, "validation_failed.negative_fee"},
+		{"kuralın para biriminde olmayan tutar", []domain.FeeRule{{Operation: domain.FeeWithdraw, Currency: "TRY", Max: money(100, "USD")}}, domain.ErrCurrencyMismatch, ""},
+		{"aynı kural iki kez", []domain.FeeRule{
+			{Operation: domain.FeeTransfer, Currency: "USD"},
+			{Operation: domain.FeeTransfer, Currency: "usd", PercentBps: 10},
+		}, nil, "validation_failed.duplicate_fee_rule"},
+		{"geçersiz ücret türü", []domain.FeeRule{{Operation: "deposit", Currency: "TRY"}}, nil, "validation_failed.invalid_fee_operation"},
+		{"negatif ücretsiz hak", []domain.FeeRule{{Operation: domain.FeeWithdraw, Currency: "TRY", FreePerMonth: -1}}, nil, "validation_failed.invalid_free_tier"},
+	}
+	for _, c := range invalid {
+		_, err := e.SetRules(c.rules)
+		if (c.err != nil && !errors.Is(err, c.err)) || (c.key != "" && errorKey(err) != c.key) {
+			t.Errorf("%s: %v, beklenen %v%s", c.name, err, c.err, c.key)
+		}
+	}
+	// Reddedilen tarifeler geçerli tarifeyi değiştirmez
+	withdraw := domain.FeeMovement{UserID: 1, Operation: domain.FeeWithdraw, Amount: domain.NewMoney(10000, "TRY")}
+	if quote, err := e.Preview(withdraw); err != nil || quote.Fee.Amount != 100 {
+		t.Fatalf("reddedilen tarifelerden sonra ücret %+v, %v; beklenen 1,00 TRY", quote, err)
+	}
+
+	schedule, err := e.SetRules([]domain.FeeRule{{Operation: domain.FeeWithdraw, Currency: "try", PercentBps: 50}})
+	if err != nil || schedule.Rules[0].Currency != "TRY" || !schedule.UpdatedAt.Equal(testNow) || e.Schedule() != schedule {
+		t.Fatalf("SetRules = %+v, %v", schedule, err)
+	}
+	if quote, err := e.Preview(withdraw); err != nil || quote.Fee.Amount != 50 {
+		t.Fatalf("yeni tarifeyle ücret %+v, %v; beklenen 0,50 TRY", quote, err)
+	}
+	// Boş tarife bütün ücretleri kaldırır
+	if _, err := e.SetRules(nil); err != nil {
+		t.Fatal(err)
+	}
+	if quote, err := e.Preview(withdraw); err != nil || !quote.Fee.IsZero() || quote.Rule != nil {
+		t.Fatalf("boş tarifeyle ücret %+v, %v", quote, err)
+	}
+}
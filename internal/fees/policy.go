@@ -0,0 +1,40 @@
+package fees
+
+import (
+	"encoding/json"
+	"fmt"
+	"gofinancialsystem/internal/domain"
+	"io"
+	"os"
+)
+
+// PolicyFile, FEES_FILE ile verilen ücret tarifesinin JSON biçimidir:
+//
+//	{"rules": [{"operation": "transfer", "currency": "TRY", "flat": "1.00", "percent_bps": 10, "max": "25.00", "free_per_month": 3}]}
+//
+// TRY dışındaki para birimlerinde tutarlar {"amount": "...", "currency": "USD"} biçiminde yazılmalıdır.
+type PolicyFile struct {
+	Rules []domain.FeeRule `json:"rules"`
+}
+
+// Ücret tarifesi dosyasını okur
+func LoadFile(path string) ([]domain.FeeRule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseJSON(f)
+}
+
+// JSON biçimindeki ücret tarifesini okur ve doğrular (bkz. PolicyFile)
+func ParseJSON(r io.Reader) ([]domain.FeeRule, error) {
+	var file PolicyFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("fees: ücret dosyası okunamadı: %w", err)
+	}
+	if err := domain.NormalizeFeeRules(file.Rules); err != nil {
+		return nil, fmt.Errorf("fees: ücret dosyası geçersiz: %w", err)
+	}
+	return file.Rules, nil
+}
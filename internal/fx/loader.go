@@ -0,0 +1,81 @@
+package fx
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RateFile, kur dosyasının ve admin endpoint'inin JSON biçimidir:
+//
+//	{"version": "2024-01-01T09:00:00Z", "rates": [{"base": "USD", "quote": "TRY", "rate": "32.45"}]}
+//
+// Version verilmezse yükleme zamanı kullanılır.
+type RateFile struct {
+	Version *time.Time `json:"version"`
+	Rates   []PairRate `json:"rates"`
+}
+
+// Dosya içeriğini kur tablosuna çevirir
+func (f RateFile) Table(source string, now time.Time) (*RateTable, error) {
+	version := now
+	if f.Version != nil {
+		version = *f.Version
+	}
+	return NewRateTable(version, source, f.Rates)
+}
+
+// Kur dosyasını uzantısına göre (.csv veya .json) okur
+func LoadFile(path string, now time.Time) (*RateTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ParseJSON(f, path, now)
+	case ".csv":
+		return ParseCSV(f, path, now)
+	default:
+		return nil, fmt.Errorf("fx: desteklenmeyen kur dosyası biçimi: %s", path)
+	}
+}
+
+// JSON biçimindeki kur tablosunu okur (bkz. RateFile)
+func ParseJSON(r io.Reader, source string, now time.Time) (*RateTable, error) {
+	var file RateFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("fx: kur dosyası okunamadı: %w", err)
+	}
+	return file.Table(source, now)
+}
+
+// "base,quote,rate" başlıklı CSV kur tablosunu okur. Dosyanın sürümü yükleme zamanıdır.
+func ParseCSV(r io.Reader, source string, now time.Time) (*RateTable, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("fx: kur dosyası okunamadı: %w", err)
+	}
+	if len(records) > 0 && strings.EqualFold(records[0][0], "base") {
+		records = records[1:]
+	}
+	rates := make([]PairRate, 0, len(records))
+	for i, rec := range records {
+		rate, err := ParseRate(rec[2])
+		if err != nil {
+			return nil, fmt.Errorf("fx: %d. satırdaki kur geçersiz: %w", i+1, err)
+		}
+		rates = append(rates, PairRate{Pair: Pair{Base: rec[0], Quote: rec[1]}, Rate: rate})
+	}
+	return NewRateTable(now, source, rates)
+}
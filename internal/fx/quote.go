@@ -0,0 +1,179 @@
+package fx
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"gofinancialsystem/internal/domain"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// Spread baz puan cinsindendir: 10000 bps = %100
+const bpsDenominator = 10000
+
+// Quote, bir kullanıcıya verilen ve ExpiresAt'e kadar kilitli kalan kur teklifidir.
+// Teklif sadece bir kez ve sadece teklifi alan kullanıcı tarafından kullanılabilir.
+type Quote struct {
+	ID          string       `json:"id"`
+	UserID      int64        `json:"user_id"`
+	Sell        domain.Money `json:"sell"` // Kullanıcının cüzdanından düşecek tutar
+	Buy         domain.Money `json:"buy"`  // Karşılığında alınacak tutar
+	Rate        Rate         `json:"rate"` // Spread uygulanmış kur
+	MidRate     Rate         `json:"mid_rate"`
+	SpreadBps   int64        `json:"spread_bps"`
+	RateVersion time.Time    `json:"rate_version"`
+	CreatedAt   time.Time    `json:"created_at"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+}
+
+// Teklifin işlem kaydına yazılacak denetim bilgisini döndürür
+func (q *Quote) Conversion() domain.FXConversion {
+	return domain.FXConversion{
+		QuoteID:     q.ID,
+		Rate:        q.Rate.String(),
+		MidRate:     q.MidRate.String(),
+		SpreadBps:   q.SpreadBps,
+		RateVersion: q.RateVersion,
+		Target:      q.Buy,
+	}
+}
+
+// Service, güncel kur tablosundan spread uygulanmış teklifler üretir ve kilitli teklifleri saklar
+type Service struct {
+	rates     *RateStore
+	spreadBps int64
+	ttl       time.Duration
+	now       func() time.Time
+
+	mu     sync.Mutex
+	quotes map[string]*Quote
+	used   map[string]bool
+}
+
+// Yeni bir fx Service oluşturur; spreadBps baz puan cinsinden, ttl teklifin kilitli kalma süresidir
+func NewService(rates *RateStore, spreadBps int64, ttl time.Duration) *Service {
+	return &Service{
+		rates:     rates,
+		spreadBps: spreadBps,
+		ttl:       ttl,
+		now:       time.Now,
+		quotes:    make(map[string]*Quote),
+		used:      make(map[string]bool),
+	}
+}
+
+// Kur tablolarını döndürür
+func (s *Service) Rates() *RateStore {
+	return s.rates
+}
+
+// sell tutarının buyCurrency karşılığı için kilitli bir teklif oluşturur.
+// Müşteriye verilen kur orta kurdan spread kadar düşüktür; alınacak tutar aşağı yuvarlanır.
+func (s *Service) Quote(userID int64, sell domain.Money, buyCurrency string) (*Quote, error) {
+	if !sell.IsPositive() {
+		return nil, domain.ErrInvalidAmount
+	}
+	sellCurrency, err := domain.ValidateCurrency(sell.Currency)
+	if err != nil {
+		return nil, err
+	}
+	buyCurrency, err = domain.ValidateCurrency(buyCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if sellCurrency == buyCurrency {
+		return nil, domain.NewValidationError("same_currency_quote", "teklif için farklı para birimleri gerekli")
+	}
+
+	table, err := s.rates.Current()
+	if err != nil {
+		return nil, err
+	}
+	mid, err := table.Lookup(sellCurrency, buyCurrency)
+	if err != nil {
+		return nil, err
+	}
+	// Kayda yazılan kurla tutarın yeniden hesaplanabilmesi için kur önce metin hassasiyetine yuvarlanır
+	spread := mid.Rat()
+	spread.Mul(spread, big.NewRat(bpsDenominator-s.spreadBps, bpsDenominator))
+	rate, err := ParseRate(Rate{value: spread}.String())
+	if err != nil {
+		return nil, err
+	}
+	buy, err := sell.Convert(buyCurrency, rate.Rat(), domain.RoundDown)
+	if err != nil {
+		return nil, err
+	}
+	if !buy.IsPositive() {
+		return nil, domain.ErrInvalidAmount.WithMessage("below_minimum_conversion", "tutar çevrilemeyecek kadar küçük")
+	}
+
+	id, err := newQuoteID()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	q := &Quote{
+		ID:          id,
+		UserID:      userID,
+		Sell:        sell,
+		Buy:         buy,
+		Rate:        rate,
+		MidRate:     mid,
+		SpreadBps:   s.spreadBps,
+		RateVersion: table.Version,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	}
+	s.mu.Lock()
+	s.quotes[id] = q
+	s.mu.Unlock()
+	return q, nil
+}
+
+// Teklifi kullanıldı olarak işaretler ve döndürür. Teklif başka bir kullanıcıya aitse
+// varlığı sızdırılmamak için bulunamadı hatası döner. İşlem başarısız olursa Release çağrılmalıdır.
+func (s *Service) Redeem(id string, userID int64) (*Quote, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q, ok := s.quotes[id]
+	if !ok || q.UserID != userID {
+		return nil, domain.ErrQuoteNotFound
+	}
+	if !s.now().Before(q.ExpiresAt) {
+		return nil, domain.ErrQuoteExpired
+	}
+	if s.used[id] {
+		return nil, domain.ErrQuoteAlreadyUsed
+	}
+	s.used[id] = true
+	return q, nil
+}
+
+// Başarısız bir işlemde kullanılan teklifi tekrar kullanılabilir hale getirir
+func (s *Service) Release(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.used, id)
+}
+
+// Süresi dolmuş teklifleri siler
+func (s *Service) DeleteExpired(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, q := range s.quotes {
+		if !now.Before(q.ExpiresAt) {
+			delete(s.quotes, id)
+			delete(s.used, id)
+		}
+	}
+}
+
+func newQuoteID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,230 @@
+package fx
+
+import (
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"testing"
+	"time"
+)
+
+var (
+	v1 = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	v2 = v1.Add(time.Hour)
+)
+
+func errorKey(err error) string {
+	if e, ok := domain.AsError(err); ok {
+		return e.MessageKey()
+	}
+	return ""
+}
+
+func rateTable(t *testing.T, version time.Time, rates map[string]string) *RateTable {
+	t.Helper()
+	var pairs []PairRate
+	for pair, value := range rates {
+		rate, err := ParseRate(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pairs = append(pairs, PairRate{Pair: Pair{Base: pair[:3], Quote: pair[4:]}, Rate: rate})
+	}
+	table, err := NewRateTable(version, "test", pairs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+// Saati v2'ye sabitlenmiş, USD/TRY 32.5 ve EUR/USD 1.08 kurlarıyla bir servis oluşturur
+func newQuoteService(t *testing.T, spreadBps int64) *Service {
+	t.Helper()
+	store := NewRateStore()
+	if err := store.Publish(rateTable(t, v1, map[string]string{"USD/TRY": "32.5", "EUR/USD": "1.08"})); err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(store, spreadBps, time.Minute)
+	s.now = func() time.Time { return v2 }
+	return s
+}
+
+func TestQuoteSpread(t *testing.T) {
+	cases := []struct {
+		name      string
+		spreadBps int64
+		sell      domain.Money
+		buy       string
+		rate      string
+		bought    int64
+		key       string // Hata bekleniyorsa mesaj anahtarı
+	}{
+		{"spreadsiz", 0, domain.NewMoney(10000, "USD"), "TRY", "32.5", 325000, ""},
+		{"15 bps spread", 15, domain.NewMoney(10000, "USD"), "TRY", "32.45125", 324512, ""},
+		{"100 bps spread", 100, domain.NewMoney(5000, "EUR"), "USD", "1.0692", 5346, ""},
+		// Tabloda sadece ters çift varsa ters kur kullanılır; kur 10 basamağa yuvarlanır, tutar aşağı yuvarlanır
+		{"ters çift", 0, domain.NewMoney(100000, "TRY"), "USD", "0.0307692308", 3076, ""},
+		{"küçük harf para birimi", 0, domain.NewMoney(10000, "usd"), "try", "32.5", 325000, ""},
+		{"çevrilemeyecek kadar küçük", 0, domain.NewMoney(1, "TRY"), "USD", "", 0, "invalid_amount.below_minimum_conversion"},
+		{"sıfır tutar", 0, domain.NewMoney(0, "USD"), "TRY", "", 0, "invalid_amount"},
+		{"negatif tutar", 0, domain.NewMoney(-100, "USD"), "TRY", "", 0, "invalid_amount"},
+		{"aynı para birimi", 0, domain.NewMoney(100, "USD"), "USD", "", 0, "validation_failed.same_currency_quote"},
+		{"tabloda olmayan çift", 0, domain.NewMoney(100, "USD"), "JPY", "", 0, "rate_not_found"},
+		{"desteklenmeyen para birimi", 0, domain.NewMoney(100, "USD"), "XXX", "", 0, "unsupported_currency"},
+	}
+	for _, c := range cases {
+		s := newQuoteService(t, c.spreadBps)
+		q, err := s.Quote(7, c.sell, c.buy)
+		if c.key != "" {
+			if errorKey(err) != c.key {
+				t.Errorf("%s: %+v, %v; beklenen %s", c.name, q, err, c.key)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if q.Rate.String() != c.rate || q.Buy.Amount != c.bought || q.Buy.Currency != domain.NormalizeCurrency(c.buy) || q.SpreadBps != c.spreadBps {
+			t.Errorf("%s: kur %s, alınan %s; beklenen %s, %d", c.name, q.Rate, q.Buy, c.rate, c.bought)
+		}
+		// Kayda yazılan kurla alınan tutar yeniden hesaplanabilmelidir
+		if recomputed, err := q.Sell.Convert(q.Buy.Currency, q.Rate.Rat(), domain.RoundDown); err != nil || recomputed != q.Buy {
+			t.Errorf("%s: yazılan kurla %v, %v; beklenen %s", c.name, recomputed, err, q.Buy)
+		}
+		if conversion := q.Conversion(); conversion.QuoteID != q.ID || conversion.Rate != c.rate || conversion.Target != q.Buy {
+			t.Errorf("%s: çeviri kaydı %+v", c.name, conversion)
+		}
+	}
+
+	if _, err := NewService(NewRateStore(), 0, time.Minute).Quote(7, domain.NewMoney(100, "USD"), "TRY"); !errors.Is(err, ErrNoRates) {
+		t.Errorf("kur tablosu yokken: %v, beklenen ErrNoRates", err)
+	}
+}
+
+func TestQuoteExpiry(t *testing.T) {
+	cases := []struct {
+		name    string
+		after   time.Duration // Teklif alındıktan sonra geçen süre
+		userID  int64
+		release bool // Kullanımdan sonra Release çağrılır
+		redeems int  // Arka arkaya kullanım denemesi
+		err     error
+	}{
+		{"süresi içinde", 59 * time.Second, 7, false, 1, nil},
+		{"süre dolduğu an", time.Minute, 7, false, 1, domain.ErrQuoteExpired},
+		{"başka kullanıcı", time.Second, 8, false, 1, domain.ErrQuoteNotFound},
+		{"ikinci kullanım", time.Second, 7, false, 2, domain.ErrQuoteAlreadyUsed},
+		{"başarısız işlemden sonra tekrar", time.Second, 7, true, 2, nil},
+	}
+	for _, c := range cases {
+		s := newQuoteService(t, 0)
+		q, err := s.Quote(7, domain.NewMoney(10000, "USD"), "TRY")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.CreatedAt != v2 || q.ExpiresAt != v2.Add(time.Minute) {
+			t.Fatalf("teklif zamanı %s - %s", q.CreatedAt, q.ExpiresAt)
+		}
+		s.now = func() time.Time { return v2.Add(c.after) }
+		for i := 0; i < c.redeems; i++ {
+			var redeemed *Quote
+			redeemed, err = s.Redeem(q.ID, c.userID)
+			if err == nil && redeemed != q {
+				t.Errorf("%s: kullanılan teklif %+v, beklenen %+v", c.name, redeemed, q)
+			}
+			if c.release {
+				s.Release(q.ID)
+			}
+		}
+		if (c.err == nil && err != nil) || (c.err != nil && !errors.Is(err, c.err)) {
+			t.Errorf("%s: %v, beklenen %v", c.name, err, c.err)
+		}
+	}
+
+	// Süresi dolan teklifler silinir; silinmeyenler kullanılabilir kalır
+	s := newQuoteService(t, 0)
+	old, _ := s.Quote(7, domain.NewMoney(100, "USD"), "TRY")
+	s.now = func() time.Time { return v2.Add(30 * time.Second) }
+	fresh, _ := s.Quote(7, domain.NewMoney(100, "USD"), "TRY")
+	s.now = func() time.Time { return v2.Add(time.Minute) }
+	s.DeleteExpired(s.now())
+	if _, err := s.Redeem(old.ID, 7); !errors.Is(err, domain.ErrQuoteNotFound) {
+		t.Errorf("silinen teklif: %v, beklenen ErrQuoteNotFound", err)
+	}
+	if _, err := s.Redeem(fresh.ID, 7); err != nil {
+		t.Errorf("süresi dolmamış teklif: %v", err)
+	}
+}
+
+func TestQuoteRateVersions(t *testing.T) {
+	s := newQuoteService(t, 0)
+	before, err := s.Quote(7, domain.NewMoney(10000, "USD"), "TRY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Rates().Publish(rateTable(t, v2, map[string]string{"USD/TRY": "33"})); err != nil {
+		t.Fatal(err)
+	}
+	after, err := s.Quote(7, domain.NewMoney(10000, "USD"), "TRY")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		quote   *Quote
+		version time.Time
+		rate    string
+		bought  int64
+	}{
+		{"önceki sürümle alınan teklif", before, v1, "32.5", 325000},
+		{"yeni sürümle alınan teklif", after, v2, "33", 330000},
+	}
+	for _, c := range cases {
+		if c.quote.RateVersion != c.version || c.quote.MidRate.String() != c.rate || c.quote.Buy.Amount != c.bought ||
+			c.quote.Conversion().RateVersion != c.version {
+			t.Errorf("%s: sürüm %s, kur %s, alınan %s", c.name, c.quote.RateVersion, c.quote.MidRate, c.quote.Buy)
+		}
+		// Kilitli teklif yeni kur yayınlandıktan sonra da kendi kuruyla kullanılır
+		if q, err := s.Redeem(c.quote.ID, 7); err != nil || q.Buy.Amount != c.bought {
+			t.Errorf("%s: kullanım %+v, %v", c.name, q, err)
+		}
+	}
+	// Yeni tabloda EUR/USD yok; eski tablodaki kur artık kullanılmaz
+	if _, err := s.Quote(7, domain.NewMoney(100, "EUR"), "USD"); errorKey(err) != "rate_not_found" {
+		t.Errorf("güncel tabloda olmayan çift: %v, beklenen rate_not_found", err)
+	}
+
+	for _, version := range []time.Time{v1, v2, v1.Add(time.Minute)} {
+		if err := s.Rates().Publish(rateTable(t, version, map[string]string{"USD/TRY": "1"})); !errors.Is(err, ErrStaleRateTable) {
+			t.Errorf("%s sürümü: %v, beklenen ErrStaleRateTable", version, err)
+		}
+	}
+	if versions := s.Rates().Versions(); len(versions) != 2 || versions[0] != v1 || versions[1] != v2 {
+		t.Errorf("sürümler %v, beklenen [v1 v2]", versions)
+	}
+
+	lookups := []struct {
+		name string
+		at   time.Time
+		want time.Time // Sıfırsa tablo bulunmamalı
+	}{
+		{"ilk sürümden önce", v1.Add(-time.Second), time.Time{}},
+		{"ilk sürüm anı", v1, v1},
+		{"sürümler arası", v2.Add(-time.Second), v1},
+		{"ikinci sürüm anı", v2, v2},
+		{"sonrası", v2.Add(24 * time.Hour), v2},
+	}
+	for _, l := range lookups {
+		table, err := s.Rates().At(l.at)
+		if l.want.IsZero() {
+			if !errors.Is(err, ErrNoRates) {
+				t.Errorf("%s: %v, beklenen ErrNoRates", l.name, err)
+			}
+			continue
+		}
+		if err != nil || table.Version != l.want {
+			t.Errorf("%s: %v, %v; beklenen %s", l.name, table, err, l.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Package fx, döviz kurlarını (sürümlü kur tabloları) ve süreli, kilitli kur tekliflerini yönetir.
+// Kurlar bir CSV/JSON dosyasından veya admin endpoint'inden yüklenir; her yükleme yeni bir sürümdür.
+package fx
+
+import (
+	"encoding/json"
+	"gofinancialsystem/internal/domain"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kurların metne çevrilirken tutulan ondalık basamak sayısı
+const rateScale = 10
+
+// Rate, 1 birim kaynak para biriminin kaç birim hedef para birimi ettiğini gösteren kesin ondalık kurdur
+type Rate struct {
+	value *big.Rat
+}
+
+var errInvalidRate = domain.NewValidationError("invalid_rate", "kur sıfırdan büyük bir ondalık sayı olmalı")
+
+// "32.4512" gibi ondalık bir metinden kur oluşturur
+func ParseRate(s string) (Rate, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.ContainsAny(s, "eE/") {
+		return Rate{}, errInvalidRate
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || r.Sign() <= 0 {
+		return Rate{}, errInvalidRate
+	}
+	return Rate{value: r}, nil
+}
+
+// Kurun kesin değerinin kopyasını döndürür
+func (r Rate) Rat() *big.Rat {
+	if r.value == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(r.value)
+}
+
+// Ters kuru döndürür (USD/TRY -> TRY/USD)
+func (r Rate) Inverse() Rate {
+	return Rate{value: new(big.Rat).Inv(r.value)}
+}
+
+// Kuru rateScale basamağa yuvarlanmış, sondaki sıfırları atılmış ondalık metin olarak döndürür
+func (r Rate) String() string {
+	s := r.Rat().FloatString(rateScale)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errInvalidRate
+	}
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Pair, kaynak/hedef para birimi çiftidir (ör: USD/TRY)
+type Pair struct {
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+}
+
+func (p Pair) String() string {
+	return p.Base + "/" + p.Quote
+}
+
+// PairRate, bir çiftin kur tablosundaki değeridir
+type PairRate struct {
+	Pair
+	Rate Rate `json:"rate"`
+}
+
+// RateTable, belirli bir anda geçerli olan kurların değiştirilemez anlık görüntüsüdür.
+// Version, tablonun geçerli olmaya başladığı zamandır ve sürüm kimliği olarak kullanılır.
+type RateTable struct {
+	Version time.Time `json:"version"`
+	Source  string    `json:"source"` // Tablonun nereden yüklendiği (dosya yolu veya "admin:<kullanıcı>")
+	rates   map[Pair]Rate
+}
+
+// Verilen kurlardan doğrulanmış bir kur tablosu oluşturur
+func NewRateTable(version time.Time, source string, rates []PairRate) (*RateTable, error) {
+	if len(rates) == 0 {
+		return nil, domain.NewValidationError("empty_rate_table", "kur tablosu en az bir kur içermeli")
+	}
+	t := &RateTable{Version: version.UTC(), Source: source, rates: make(map[Pair]Rate, len(rates))}
+	for _, pr := range rates {
+		base, err := domain.ValidateCurrency(pr.Base)
+		if err != nil {
+			return nil, err
+		}
+		quote, err := domain.ValidateCurrency(pr.Quote)
+		if err != nil {
+			return nil, err
+		}
+		if base == quote {
+			return nil, domain.NewValidationError("same_currency_pair", "kur çiftinin para birimleri farklı olmalı")
+		}
+		if pr.Rate.value == nil {
+			return nil, errInvalidRate
+		}
+		t.rates[Pair{Base: base, Quote: quote}] = pr.Rate
+	}
+	return t, nil
+}
+
+// Çiftin kurunu döndürür; tabloda sadece ters çift varsa ters kur kullanılır
+func (t *RateTable) Lookup(base, quote string) (Rate, error) {
+	pair := Pair{Base: domain.NormalizeCurrency(base), Quote: domain.NormalizeCurrency(quote)}
+	if rate, ok := t.rates[pair]; ok {
+		return rate, nil
+	}
+	if rate, ok := t.rates[Pair{Base: pair.Quote, Quote: pair.Base}]; ok {
+		return rate.Inverse(), nil
+	}
+	return Rate{}, domain.ErrRateNotFound.WithParams(map[string]interface{}{"pair": pair.String()})
+}
+
+// Tablodaki kurları çifte göre sıralı döndürür
+func (t *RateTable) Rates() []PairRate {
+	result := make([]PairRate, 0, len(t.rates))
+	for pair, rate := range t.rates {
+		result = append(result, PairRate{Pair: pair, Rate: rate})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Pair.String() < result[j].Pair.String() })
+	return result
+}
+
+func (t *RateTable) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Version time.Time  `json:"version"`
+		Source  string     `json:"source"`
+		Rates   []PairRate `json:"rates"`
+	}{t.Version, t.Source, t.Rates()})
+}
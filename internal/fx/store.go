@@ -0,0 +1,69 @@
+package fx
+
+import (
+	"gofinancialsystem/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoRates = domain.ErrRateNotFound.WithMessage("no_table", "henüz yüklenmiş bir kur tablosu yok")
+	// Var olan en yeni sürümden daha eski bir tablo yayınlanamaz; geçmiş değiştirilemez
+	ErrStaleRateTable = domain.ErrConflict.WithMessage("stale_rate_table", "kur tablosu sürümü en güncel sürümden eski olamaz")
+)
+
+// RateStore, kur tablolarının tüm sürümlerini saklar. Yeni bir tablo bir öncekinin yerine geçer;
+// eski sürümler denetim ve geçmişe dönük sorgular için korunur.
+type RateStore struct {
+	mu       sync.RWMutex
+	versions []*RateTable // Version'a göre artan sırada
+}
+
+// Boş bir RateStore oluşturur
+func NewRateStore() *RateStore {
+	return &RateStore{}
+}
+
+// Tabloyu yeni sürüm olarak yayınlar; sürümü mevcut en yeni sürümden sonra olmalıdır
+func (s *RateStore) Publish(table *RateTable) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := len(s.versions); n > 0 && !table.Version.After(s.versions[n-1].Version) {
+		return ErrStaleRateTable
+	}
+	s.versions = append(s.versions, table)
+	return nil
+}
+
+// Şu an geçerli olan (en yeni) kur tablosunu döndürür
+func (s *RateStore) Current() (*RateTable, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.versions) == 0 {
+		return nil, ErrNoRates
+	}
+	return s.versions[len(s.versions)-1], nil
+}
+
+// Verilen zamanda geçerli olan kur tablosunu döndürür
+func (s *RateStore) At(t time.Time) (*RateTable, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i := sort.Search(len(s.versions), func(i int) bool { return s.versions[i].Version.After(t) })
+	if i == 0 {
+		return nil, ErrNoRates
+	}
+	return s.versions[i-1], nil
+}
+
+// Yayınlanmış tüm sürümleri eskiden yeniye döndürür
+func (s *RateStore) Versions() []time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]time.Time, len(s.versions))
+	for i, t := range s.versions {
+		result[i] = t.Version
+	}
+	return result
+}
@@ -0,0 +1,153 @@
+// Package i18n, API mesajlarının dil paketlerini ve istek başına dil seçimini yönetir.
+// Mesajlar hata kodu (veya "kod.varyant") anahtarıyla locales/<dil>.json dosyalarında tutulur.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale, desteklenen bir dilin ISO 639-1 kodudur
+type Locale string
+
+const (
+	Turkish Locale = "tr"
+	English Locale = "en"
+
+	// Default, istek dili belirlenemediğinde ve bir anahtar seçilen dilde bulunamadığında kullanılır
+	Default = Turkish
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// Catalog, dillere göre mesaj paketlerini tutar
+type Catalog struct {
+	bundles map[Locale]map[string]string
+}
+
+var defaultCatalog = mustLoadEmbedded()
+
+// Gömülü dil paketlerinden oluşan varsayılan kataloğu döndürür
+func DefaultCatalog() *Catalog {
+	return defaultCatalog
+}
+
+// Verilen dil paketlerinden yeni bir Catalog oluşturur
+func NewCatalog(bundles map[Locale]map[string]string) *Catalog {
+	return &Catalog{bundles: bundles}
+}
+
+func mustLoadEmbedded() *Catalog {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	bundles := make(map[Locale]map[string]string, len(files))
+	for _, f := range files {
+		data, err := localeFiles.ReadFile("locales/" + f.Name())
+		if err != nil {
+			panic(err)
+		}
+		messages := make(map[string]string)
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: %s okunamadı: %v", f.Name(), err))
+		}
+		bundles[Locale(strings.TrimSuffix(f.Name(), path.Ext(f.Name())))] = messages
+	}
+	return NewCatalog(bundles)
+}
+
+// Katalogdaki dilleri sıralı olarak döndürür
+func (c *Catalog) Locales() []Locale {
+	locales := make([]Locale, 0, len(c.bundles))
+	for l := range c.bundles {
+		locales = append(locales, l)
+	}
+	sort.Slice(locales, func(i, j int) bool { return locales[i] < locales[j] })
+	return locales
+}
+
+// Dilin katalogda olup olmadığını döndürür
+func (c *Catalog) Supports(locale Locale) bool {
+	_, ok := c.bundles[locale]
+	return ok
+}
+
+// Anahtarın mesajını verilen dilde döndürür; dilde yoksa varsayılan dile düşer.
+// Mesajdaki {isim} yer tutucuları params ile doldurulur. Anahtar hiç yoksa ok false döner.
+func (c *Catalog) Translate(locale Locale, key string, params map[string]interface{}) (string, bool) {
+	msg, ok := c.bundles[locale][key]
+	if !ok {
+		msg, ok = c.bundles[Default][key]
+	}
+	if !ok {
+		return "", false
+	}
+	return interpolate(msg, params), true
+}
+
+func interpolate(msg string, params map[string]interface{}) string {
+	if len(params) == 0 {
+		return msg
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
+
+// "en-US", "EN" gibi bir dil etiketini katalogdaki dile çözer
+func (c *Catalog) Match(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	locale := Locale(tag)
+	return locale, tag != "" && c.Supports(locale)
+}
+
+// Accept-Language başlığından katalogdaki en uygun dili seçer (q değerlerine göre).
+// Desteklenen bir dil bulunamazsa Default döner.
+func (c *Catalog) Negotiate(acceptLanguage string) Locale {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, q := part, 1.0
+		if i := strings.Index(part, ";"); i >= 0 {
+			tag = part[:i]
+			if v, ok := strings.CutPrefix(strings.TrimSpace(part[i+1:]), "q="); ok {
+				parsed, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					continue
+				}
+				q = parsed
+			}
+		}
+		if locale, ok := c.Match(tag); ok && q > bestQ {
+			best, bestQ = locale, q
+		}
+	}
+	return best
+}
+
+type localeKey struct{}
+
+// Dili context'e ekler
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// Context'teki dili döndürür; yoksa Default döner
+func LocaleFromContext(ctx context.Context) Locale {
+	if locale, ok := ctx.Value(localeKey{}).(Locale); ok {
+		return locale
+	}
+	return Default
+}
@@ -0,0 +1,41 @@
+package interest
+
+import (
+	"encoding/json"
+	"fmt"
+	"gofinancialsystem/internal/domain"
+	"io"
+	"os"
+)
+
+// PolicyFile, INTEREST_FILE ile verilen faiz oranlarının JSON biçimidir:
+//
+//	{"rates": [{"account_type": "savings", "currency": "TRY", "annual_rate_bps": 3500, "mode": "compound",
+//	            "day_count": "ACT/365", "effective_from": "2026-01-01T00:00:00Z"}]}
+//
+// Aynı hesap türü ve para birimi için farklı effective_from günleriyle birden fazla oran verilebilir.
+type PolicyFile struct {
+	Rates []domain.InterestRate `json:"rates"`
+}
+
+// Faiz oranları dosyasını okur
+func LoadFile(path string) ([]domain.InterestRate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseJSON(f)
+}
+
+// JSON biçimindeki faiz oranlarını okur ve doğrular (bkz. PolicyFile)
+func ParseJSON(r io.Reader) ([]domain.InterestRate, error) {
+	var file PolicyFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("interest: faiz dosyası okunamadı: %w", err)
+	}
+	if err := domain.NormalizeInterestRates(file.Rates); err != nil {
+		return nil, fmt.Errorf("interest: faiz dosyası geçersiz: %w", err)
+	}
+	return file.Rates, nil
+}
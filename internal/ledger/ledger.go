@@ -0,0 +1,208 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"gofinancialsystem/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sistem hesapları: kullanıcı hesaplarının karşı bacaklarını taşır
+const (
+	CashIn   = "system:cash-in"  // Sisteme giren para (para yatırma)
+	CashOut  = "system:cash-out" // Sistemden çıkan para (para çekme)
+	Fees     = "system:fees"     // Tahsil edilen ücretler
+	Suspense = "system:suspense" // Karşılığı henüz belli olmayan düzeltmeler
+	FX       = "system:fx"       // Döviz çevirilerinde para birimleri arasındaki pozisyon (spread geliri burada birikir)
+	Interest = "system:interest" // Müşterilere ödenen ve kredili hesaplardan alınan faizler
+)
+
+// Müşteri hesaplarının ledger hesap ID'leri "account:<hesap ID>" biçimindedir
+const customerAccountPrefix = "account:"
+
+// Account, ledger'daki bir hesabı tanımlar
+type Account struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	System bool   `json:"system"`
+}
+
+// Sistem hesaplarının listesini döndürür
+func SystemAccounts() []Account {
+	return []Account{
+		{ID: CashIn, Name: "cash-in", System: true},
+		{ID: CashOut, Name: "cash-out", System: true},
+		{ID: Fees, Name: "fees", System: true},
+		{ID: Suspense, Name: "suspense", System: true},
+		{ID: FX, Name: "fx", System: true},
+		{ID: Interest, Name: "interest", System: true},
+	}
+}
+
+// Müşteri hesabının (domain.Account) ledger hesap ID'sini döndürür
+func CustomerAccount(accountID int64) string {
+	return customerAccountPrefix + strconv.FormatInt(accountID, 10)
+}
+
+// Ledger hesabı bir müşteri hesabıysa domain.Account ID'sini döndürür
+func CustomerAccountID(ledgerAccount string) (int64, bool) {
+	if !strings.HasPrefix(ledgerAccount, customerAccountPrefix) {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(ledgerAccount, customerAccountPrefix), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// Para yatırma: cash-in hesabından müşteri hesabına
+func CreditEntry(txID int64, accountID int64, amount domain.Money) *domain.JournalEntry {
+	return newEntry(txID, fmt.Sprintf("deposit #%d", txID), CashIn, CustomerAccount(accountID), amount)
+}
+
+// Para çekme: müşteri hesabından cash-out hesabına
+func DebitEntry(txID int64, accountID int64, amount domain.Money) *domain.JournalEntry {
+	return newEntry(txID, fmt.Sprintf("withdraw #%d", txID), CustomerAccount(accountID), CashOut, amount)
+}
+
+// Transfer: gönderen hesaptan alıcı hesaba
+func TransferEntry(txID int64, fromAccountID, toAccountID int64, amount domain.Money) *domain.JournalEntry {
+	return newEntry(txID, fmt.Sprintf("transfer #%d", txID), CustomerAccount(fromAccountID), CustomerAccount(toAccountID), amount)
+}
+
+// Ücret: ödeyen müşteri hesabından fees hesabına
+func FeeEntry(txID int64, accountID int64, amount domain.Money) *domain.JournalEntry {
+	return newEntry(txID, fmt.Sprintf("fee #%d", txID), CustomerAccount(accountID), Fees, amount)
+}
+
+// Faiz: interest hesabından müşteri hesabına
+func InterestEntry(txID int64, accountID int64, amount domain.Money) *domain.JournalEntry {
+	return newEntry(txID, fmt.Sprintf("interest #%d", txID), Interest, CustomerAccount(accountID), amount)
+}
+
+// Kredili hesap faizi: negatif bakiyeli müşteri hesabından interest hesabına
+func OverdraftInterestEntry(txID int64, accountID int64, amount domain.Money) *domain.JournalEntry {
+	return newEntry(txID, fmt.Sprintf("overdraft interest #%d", txID), CustomerAccount(accountID), Interest, amount)
+}
+
+// Döviz çevirili transfer: kaynak tutar gönderen hesaptan FX hesabına, hedef tutar FX hesabından alıcı hesaba.
+// Her para birimi kendi içinde dengede kalır.
+func ExchangeEntry(txID int64, fromAccountID, toAccountID int64, source, target domain.Money) *domain.JournalEntry {
+	return &domain.JournalEntry{
+		TransactionID: &txID,
+		Description:   fmt.Sprintf("exchange #%d", txID),
+		Postings: []domain.Posting{
+			{AccountID: CustomerAccount(fromAccountID), Amount: source.Neg()},
+			{AccountID: FX, Amount: source},
+			{AccountID: FX, Amount: target.Neg()},
+			{AccountID: CustomerAccount(toAccountID), Amount: target},
+		},
+		CreatedAt: time.Now(),
+	}
+}
+
+// Karşı bacağı suspense hesabı olan bakiye düzeltmesi
+func AdjustmentEntry(accountID int64, amount domain.Money) *domain.JournalEntry {
+	return &domain.JournalEntry{
+		Description: fmt.Sprintf("adjustment account %d", accountID),
+		Postings: []domain.Posting{
+			{AccountID: Suspense, Amount: amount.Neg()},
+			{AccountID: CustomerAccount(accountID), Amount: amount},
+		},
+		CreatedAt: time.Now(),
+	}
+}
+
+// Geri alma: orijinal işlemin kayıtlarındaki tüm postingleri ters çeviren, txID'ye ait yeni bir kayıt üretir
+func ReversalEntry(txID, originalID int64, originals []*domain.JournalEntry) (*domain.JournalEntry, error) {
+	return reverseEntry(txID, fmt.Sprintf("reversal #%d of #%d", txID, originalID), originals, 1, 1)
+}
+
+// İade: orijinal işlemin postinglerini refund/original oranında ters çevirir.
+// Her bacak sıfıra doğru kesildiği için iki bacaklı kayıtlar (ör: çevirili transferin her para birimi) dengede kalır.
+func RefundEntry(txID, originalID int64, originals []*domain.JournalEntry, refund, original domain.Money) (*domain.JournalEntry, error) {
+	return reverseEntry(txID, fmt.Sprintf("refund #%d of #%d", txID, originalID), originals, refund.Amount, original.Amount)
+}
+
+// Son iade: orijinal işlemin önceki iadelerden sonra kalan postinglerini tamamen ters çevirir; böylece kısmi
+// iadelerde sıfıra doğru kesilen tutarlar (ör: çevirili transferin hedef para birimi bacağı) son iadeyle kapanır.
+func FinalRefundEntry(txID, originalID int64, originals, refunds []*domain.JournalEntry) (*domain.JournalEntry, error) {
+	entries := append(append([]*domain.JournalEntry{}, originals...), refunds...)
+	return reverseEntry(txID, fmt.Sprintf("refund #%d of #%d", txID, originalID), entries, 1, 1)
+}
+
+func reverseEntry(txID int64, description string, originals []*domain.JournalEntry, num, den int64) (*domain.JournalEntry, error) {
+	if len(originals) == 0 {
+		return nil, errors.New("geri alınacak yevmiye kaydı bulunamadı")
+	}
+	entry := &domain.JournalEntry{
+		TransactionID: &txID,
+		Description:   description,
+		CreatedAt:     time.Now(),
+	}
+	// Aynı hesabın aynı para birimindeki postingleri birleştirilir; son iadede orijinal ve önceki iadelerin
+	// postingleri net tutar olarak ters çevrilir (bakiye kontrolü ara adımlarda başarısız olmaz)
+	type key struct{ account, currency string }
+	index := make(map[key]int)
+	for _, original := range originals {
+		for _, p := range original.Postings {
+			amount, err := p.Amount.Neg().MulRat(num, den, domain.RoundDown)
+			if err != nil {
+				return nil, err
+			}
+			k := key{p.AccountID, amount.Currency}
+			if i, ok := index[k]; ok {
+				entry.Postings[i].Amount.Amount += amount.Amount
+				continue
+			}
+			index[k] = len(entry.Postings)
+			entry.Postings = append(entry.Postings, domain.Posting{AccountID: p.AccountID, Amount: amount})
+		}
+	}
+	postings := entry.Postings[:0]
+	for _, p := range entry.Postings {
+		if !p.Amount.IsZero() {
+			postings = append(postings, p)
+		}
+	}
+	entry.Postings = postings
+	return entry, nil
+}
+
+// Post, kaydı doğrular, ledger'a ekler ve müşteri hesaplarının bakiye projeksiyonunu günceller.
+// repos bir unit of work'ten gelmelidir; böylece postingler ve bakiyeler birlikte commit edilir.
+// Valör tarihi verilmemiş kayıtlar oluşturuldukları gün (UTC) valörlenir.
+func Post(repos domain.Repositories, entry *domain.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if entry.ValueDate.IsZero() {
+		entry.ValueDate = domain.StartOfDay(entry.CreatedAt)
+	}
+	for _, p := range entry.Postings {
+		if accountID, ok := CustomerAccountID(p.AccountID); ok {
+			if err := repos.Balances.Update(accountID, p.Amount); err != nil {
+				return err
+			}
+		}
+	}
+	return repos.Ledger.Append(entry)
+}
+
+func newEntry(txID int64, description, from, to string, amount domain.Money) *domain.JournalEntry {
+	return &domain.JournalEntry{
+		TransactionID: &txID,
+		Description:   description,
+		Postings: []domain.Posting{
+			{AccountID: from, Amount: amount.Neg()},
+			{AccountID: to, Amount: amount},
+		},
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,148 @@
+package ledger
+
+import (
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/repository/memtest"
+	"testing"
+)
+
+// Kayıtların postinglerini hesap ve para birimine göre toplar
+func netPositions(entries ...*domain.JournalEntry) map[[2]string]int64 {
+	net := make(map[[2]string]int64)
+	for _, e := range entries {
+		for _, p := range e.Postings {
+			net[[2]string{p.AccountID, p.Amount.Currency}] += p.Amount.Amount
+		}
+	}
+	return net
+}
+
+// Kaydın geçerli olduğunu ve her para biriminde postinglerin toplamının sıfır olduğunu doğrular
+func assertBalanced(t *testing.T, name string, entry *domain.JournalEntry) {
+	t.Helper()
+	if err := entry.Validate(); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	totals := make(map[string]int64)
+	for _, p := range entry.Postings {
+		totals[p.Amount.Currency] += p.Amount.Amount
+	}
+	for currency, total := range totals {
+		if total != 0 {
+			t.Fatalf("%s: %s postinglerinin toplamı %d, beklenen 0", name, currency, total)
+		}
+	}
+}
+
+// Çevirili transfer: 100,00 EUR gönderenden çıkar, 3245,00 TRY alıcıya girer; ayrıca 5,00 EUR ücret alınır
+func exchangeWithFee() []*domain.JournalEntry {
+	return []*domain.JournalEntry{
+		ExchangeEntry(1, 10, 20, domain.NewMoney(10000, "EUR"), domain.NewMoney(324500, "TRY")),
+		FeeEntry(1, 10, domain.NewMoney(500, "EUR")),
+	}
+}
+
+func TestReverseEntriesBalancePerCurrency(t *testing.T) {
+	originals := map[string][]*domain.JournalEntry{
+		"transfer": {TransferEntry(1, 10, 20, domain.NewMoney(10001, "TRY")), FeeEntry(1, 10, domain.NewMoney(333, "TRY"))},
+		"exchange": exchangeWithFee(),
+	}
+	for name, entries := range originals {
+		reversal, err := ReversalEntry(2, 1, entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertBalanced(t, name+" geri alma", reversal)
+		// Geri alma orijinali tamamen kapatır
+		for k, amount := range netPositions(append(entries, reversal)...) {
+			if amount != 0 {
+				t.Fatalf("%s geri alma sonrası %v pozisyonu %d, beklenen 0", name, k, amount)
+			}
+		}
+
+		// Oranlar sıfıra doğru kesilir; küsuratlı oranlarda da her para birimi dengede kalır
+		original := entries[0].Postings[0].Amount.Neg()
+		for _, refund := range []int64{1, 3333, 6667, original.Amount - 1} {
+			entry, err := RefundEntry(3, 1, entries, domain.NewMoney(refund, original.Currency), original)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertBalanced(t, name+" kısmi iade", entry)
+		}
+	}
+
+	if _, err := ReversalEntry(2, 1, nil); err == nil {
+		t.Fatal("kaydı olmayan işlem geri alınamamalı")
+	}
+}
+
+func TestFinalRefundLeavesNetZero(t *testing.T) {
+	originals := exchangeWithFee()
+	source := domain.NewMoney(10000, "EUR")
+
+	var refunds []*domain.JournalEntry
+	for i, amount := range []int64{3333, 3333, 1} {
+		entry, err := RefundEntry(int64(10+i), 1, originals, domain.NewMoney(amount, "EUR"), source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertBalanced(t, "kısmi iade", entry)
+		refunds = append(refunds, entry)
+	}
+	// Kısmi iadeler sıfıra doğru kesilir: alıcıdan 1081,55 + 1081,55 + 0,32 TRY alınmıştır, alıcıda 1081,58 TRY kalır
+	if net := netPositions(append(originals, refunds...)...)[[2]string{CustomerAccount(20), "TRY"}]; net != 108158 {
+		t.Fatalf("kısmi iadeler sonrası alıcıda kalan %d, beklenen 108158", net)
+	}
+
+	final, err := FinalRefundEntry(20, 1, originals, refunds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertBalanced(t, "son iade", final)
+	for k, amount := range netPositions(append(append(originals, refunds...), final)...) {
+		if amount != 0 {
+			t.Fatalf("son iade sonrası %v pozisyonu %d, beklenen 0", k, amount)
+		}
+	}
+	if got := final.Postings[0]; got.AccountID != CustomerAccount(10) || got.Amount != domain.NewMoney(3333+168, "EUR") {
+		t.Fatalf("son iadenin ilk postingi %+v, beklenen kalan tutar ve ücret (35,01 EUR) gönderene", got)
+	}
+}
+
+func TestPostRejectsUnbalancedEntry(t *testing.T) {
+	repos := memtest.New()
+	post := func(entry *domain.JournalEntry) error {
+		return repos.UnitOfWork.Do(func(r domain.Repositories) error { return Post(r, entry) })
+	}
+
+	unbalanced := []*domain.JournalEntry{
+		{Postings: []domain.Posting{{AccountID: CustomerAccount(1), Amount: domain.NewMoney(100, "TRY")}, {AccountID: CashIn, Amount: domain.NewMoney(-99, "TRY")}}},
+		// Tutarlar eşit ama para birimleri farklı: her para birimi ayrı dengelenmeli
+		{Postings: []domain.Posting{{AccountID: CustomerAccount(1), Amount: domain.NewMoney(100, "TRY")}, {AccountID: CashIn, Amount: domain.NewMoney(-100, "EUR")}}},
+		{Postings: []domain.Posting{{AccountID: CustomerAccount(1), Amount: domain.NewMoney(100, "TRY")}}},
+		{Postings: []domain.Posting{{AccountID: CustomerAccount(1), Amount: domain.NewMoney(0, "TRY")}, {AccountID: CashIn, Amount: domain.NewMoney(0, "TRY")}}},
+		{Postings: []domain.Posting{{AccountID: "", Amount: domain.NewMoney(100, "TRY")}, {AccountID: CashIn, Amount: domain.NewMoney(-100, "TRY")}}},
+	}
+	for i, entry := range unbalanced {
+		if err := post(entry); err == nil {
+			t.Fatalf("kayıt %d dengesiz olduğu halde kabul edildi", i+1)
+		}
+	}
+	if entries, err := repos.Ledger.ListByAccount(CustomerAccount(1)); err != nil || len(entries) != 0 {
+		t.Fatalf("reddedilen kayıtlar yazılmış: %d kayıt, %v", len(entries), err)
+	}
+	if balance, err := repos.Balances.Get(1); err == nil && !balance.Amount.IsZero() {
+		t.Fatalf("reddedilen kayıt bakiyeyi değiştirmiş: %s", balance.Amount)
+	}
+
+	entry := CreditEntry(1, 1, domain.NewMoney(100, "TRY"))
+	if err := post(entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.ValueDate != domain.StartOfDay(entry.CreatedAt) {
+		t.Fatalf("valör %s, beklenen kaydın günü", entry.ValueDate)
+	}
+	if balance, err := repos.Balances.Get(1); err != nil || balance.Amount != domain.NewMoney(100, "TRY") {
+		t.Fatalf("bakiye %v, %v; beklenen 1,00 TRY", balance, err)
+	}
+}
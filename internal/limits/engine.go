@@ -0,0 +1,292 @@
+package limits
+
+import (
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"strconv"
+	"time"
+)
+
+// Engine, LimitService arayüzünün implementasyonudur. Bir kullanıcının geçerli limitleri
+// varsayılan kuralların üzerine önce rolünün, sonra kendisinin override'ı uygulanarak bulunur.
+type Engine struct {
+	defaults     []domain.LimitRule
+	overrides    domain.LimitOverrideRepository
+	users        domain.UserRepository        // Kullanıcının rolünü bulmak için
+	transactions domain.TransactionRepository // Unit of work dışındaki kullanım sorguları için
+	now          func() time.Time
+}
+
+// Yeni bir Engine oluşturur; defaults doğrulanmış olmalıdır (bkz. ParseJSON, DefaultRules)
+func NewEngine(defaults []domain.LimitRule, overrides domain.LimitOverrideRepository, users domain.UserRepository, transactions domain.TransactionRepository) *Engine {
+	return &Engine{defaults: defaults, overrides: overrides, users: users, transactions: transactions, now: time.Now}
+}
+
+// Para hareketinin kullanıcının limitlerinden herhangi birini aşıp aşmadığını kontrol eder.
+// Kayan pencerelerdeki kullanım tamamlanmış işlemlerden txs üzerinden hesaplanır; txs unit of work'ün
+// repository'si olmalıdır, kullanıcı transaction sonuna kadar kilitli kalır.
+func (e *Engine) Check(txs domain.TransactionRepository, m domain.LimitMovement) error {
+	// Kullanıcının kendi hesapları arasındaki transferler limitlere tabi değildir
+	if !m.Type.IsLimited() || m.CounterpartyID != nil && *m.CounterpartyID == m.UserID {
+		return nil
+	}
+	rule, err := e.rule(m.UserID, m.Type, domain.NormalizeCurrency(m.Amount.Currency))
+	if err != nil || rule == nil {
+		return err
+	}
+	if rule.PerTransaction != nil && m.Amount.Amount > rule.PerTransaction.Amount {
+		return limitError("per_transaction", "işlem tutarı işlem başına limiti aşıyor: {limit}", rule, *rule.PerTransaction, *rule.PerTransaction)
+	}
+	// Eşzamanlı hareketler aynı kullanımı okuyup birlikte limiti aşmasın diye kullanıcı önce kilitlenir
+	if err := txs.LockUser(m.UserID); err != nil {
+		return err
+	}
+	for _, w := range windows(rule, m.CounterpartyID) {
+		usage, err := e.usage(txs, m.UserID, rule, w)
+		if err != nil {
+			return err
+		}
+		if m.Amount.Amount > usage.Remaining.Amount {
+			return limitError(w.variant, w.message, rule, usage.Limit, usage.Remaining)
+		}
+	}
+	return nil
+}
+
+// Kullanıcının limitlerini ve kayan pencerelerde kalan kullanımını döndürür.
+// Karşı taraf limitleri sadece counterpartyID verilmişse hesaplanır.
+func (e *Engine) Headroom(userID int64, currency string, counterpartyID *int64) ([]domain.LimitHeadroom, error) {
+	if currency != "" {
+		var err error
+		if currency, err = domain.ValidateCurrency(domain.NormalizeCurrency(currency)); err != nil {
+			return nil, err
+		}
+	}
+	rules, err := e.effective(userID)
+	if err != nil {
+		return nil, err
+	}
+	result := []domain.LimitHeadroom{}
+	for i := range rules {
+		rule := &rules[i]
+		if currency != "" && rule.Currency != currency {
+			continue
+		}
+		h := domain.LimitHeadroom{Type: rule.Type, Currency: rule.Currency, PerTransaction: rule.PerTransaction}
+		for _, w := range windows(rule, counterpartyID) {
+			usage, err := e.usage(e.transactions, userID, rule, w)
+			if err != nil {
+				return nil, err
+			}
+			switch w.variant {
+			case "daily":
+				h.Daily = usage
+			case "monthly":
+				h.Monthly = usage
+			case "counterparty_daily":
+				h.CounterpartyDaily = usage
+			case "counterparty_monthly":
+				h.CounterpartyMonthly = usage
+			}
+		}
+		result = append(result, h)
+	}
+	return result, nil
+}
+
+// Tüm rol ve kullanıcı override'larını listeler
+func (e *Engine) ListOverrides() ([]*domain.LimitOverride, error) {
+	return e.overrides.List()
+}
+
+// Rol veya kullanıcı için override'ı doğrular ve kaydeder; varsa öncekinin yerine geçer
+func (e *Engine) SetOverride(o *domain.LimitOverride) error {
+	if err := o.Normalize(); err != nil {
+		return err
+	}
+	if o.Scope == domain.LimitScopeUser {
+		id, err := strconv.ParseInt(o.Subject, 10, 64)
+		if err != nil {
+			return domain.NewValidationError("invalid_limit_subject", "kullanıcı override'ının konusu kullanıcı ID olmalı")
+		}
+		if _, err := e.users.FindByID(id); err != nil {
+			return err
+		}
+	}
+	o.UpdatedAt = e.now()
+	return e.overrides.Save(o)
+}
+
+// Override'ı siler; kapsamdaki kullanıcılar tekrar alttaki katmanın limitlerine döner
+func (e *Engine) DeleteOverride(scope domain.LimitScope, subject string) error {
+	return e.overrides.Delete(scope, subject)
+}
+
+// Kullanıcının geçerli kurallarını döndürür: varsayılanlar, rol override'ı ve kullanıcı override'ı sırasıyla birleştirilir
+func (e *Engine) effective(userID int64) ([]domain.LimitRule, error) {
+	user, err := e.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	rules := append([]domain.LimitRule{}, e.defaults...)
+	layers := []struct {
+		scope   domain.LimitScope
+		subject string
+	}{
+		{domain.LimitScopeRole, user.Role},
+		{domain.LimitScopeUser, strconv.FormatInt(userID, 10)},
+	}
+	for _, layer := range layers {
+		override, err := e.overrides.Find(layer.scope, layer.subject)
+		if errors.Is(err, domain.ErrLimitOverrideNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range override.Rules {
+			rules = mergeRule(rules, o)
+		}
+	}
+	return rules, nil
+}
+
+func mergeRule(rules []domain.LimitRule, override domain.LimitRule) []domain.LimitRule {
+	for i := range rules {
+		if rules[i].Type == override.Type && rules[i].Currency == override.Currency {
+			rules[i].Merge(override)
+			return rules
+		}
+	}
+	return append(rules, override)
+}
+
+func (e *Engine) rule(userID int64, txType domain.TransactionType, currency string) (*domain.LimitRule, error) {
+	rules, err := e.effective(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rules {
+		if rules[i].Type == txType && rules[i].Currency == currency {
+			return &rules[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// window, kuralın kayan pencereli limitlerinden biridir
+type window struct {
+	variant        string
+	message        string
+	limit          domain.Money
+	length         time.Duration
+	counterpartyID *int64
+}
+
+// Kuralın tanımlı kayan pencerelerini döndürür; karşı taraf limitleri sadece karşı taraf biliniyorsa uygulanır
+func windows(rule *domain.LimitRule, counterpartyID *int64) []window {
+	var result []window
+	add := func(limit *domain.Money, w window) {
+		if limit != nil {
+			w.limit = *limit
+			result = append(result, w)
+		}
+	}
+	add(rule.Daily, window{variant: "daily", message: "günlük limit aşılıyor; kalan: {remaining}", length: domain.DailyLimitWindow})
+	add(rule.Monthly, window{variant: "monthly", message: "aylık limit aşılıyor; kalan: {remaining}", length: domain.MonthlyLimitWindow})
+	if counterpartyID != nil {
+		add(rule.CounterpartyDaily, window{variant: "counterparty_daily", message: "bu alıcıya günlük limit aşılıyor; kalan: {remaining}",
+			length: domain.DailyLimitWindow, counterpartyID: counterpartyID})
+		add(rule.CounterpartyMonthly, window{variant: "counterparty_monthly", message: "bu alıcıya aylık limit aşılıyor; kalan: {remaining}",
+			length: domain.MonthlyLimitWindow, counterpartyID: counterpartyID})
+	}
+	return result
+}
+
+// Penceredeki tamamlanmış işlemlerin toplamını ve kalan limiti hesaplar. Kendi hesapları arasındaki transferler
+// sayılmaz; penceredeki işlemlerin geri alınan veya iade edilen kısmı kullanımdan düşülür.
+func (e *Engine) usage(txs domain.TransactionRepository, userID int64, rule *domain.LimitRule, w window) (*domain.LimitUsage, error) {
+	from := e.now().Add(-w.length)
+	direction, returned := domain.DirectionOut, domain.DirectionIn
+	if rule.Type == domain.TransactionDeposit {
+		direction, returned = domain.DirectionIn, domain.DirectionOut
+	}
+	filter := domain.TransactionFilter{
+		UserID:         userID,
+		Direction:      direction,
+		Types:          []domain.TransactionType{rule.Type},
+		Statuses:       []domain.TransactionStatus{domain.TransactionCompleted},
+		Currency:       rule.Currency,
+		CreatedFrom:    &from,
+		CounterpartyID: w.counterpartyID,
+		ExcludeSelf:    true,
+	}
+	used, err := txs.Sum(filter)
+	if err != nil {
+		return nil, err
+	}
+	refunded, err := refundedInWindow(txs, filter, returned)
+	if err != nil {
+		return nil, err
+	}
+	used.Amount -= refunded
+	if used.IsNegative() {
+		used.Amount = 0
+	}
+	remaining := domain.NewMoney(w.limit.Amount-used.Amount, rule.Currency)
+	if remaining.IsNegative() {
+		remaining.Amount = 0
+	}
+	return &domain.LimitUsage{Limit: w.limit, Used: used, Remaining: remaining}, nil
+}
+
+// Orijinali filtreye uyan geri alma ve iadelerin toplamını orijinal işlemin para biriminde döndürür.
+// Geri alma ve iadeler orijinalden sonra oluşturulduğu için sadece filtrenin penceresindekilere bakılır.
+func refundedInWindow(txs domain.TransactionRepository, filter domain.TransactionFilter, direction domain.TransactionDirection) (int64, error) {
+	returns := domain.TransactionFilter{
+		UserID:      filter.UserID,
+		Direction:   direction,
+		Types:       []domain.TransactionType{domain.TransactionReversal, domain.TransactionRefund},
+		Statuses:    []domain.TransactionStatus{domain.TransactionCompleted},
+		CreatedFrom: filter.CreatedFrom,
+		Limit:       domain.MaxTransactionPageSize,
+	}
+	var total int64
+	for {
+		page, err := txs.Search(returns)
+		if err != nil {
+			return 0, err
+		}
+		for _, tx := range page.Transactions {
+			if tx.OriginalID == nil {
+				continue
+			}
+			original, err := txs.FindByID(*tx.OriginalID)
+			if err != nil {
+				return 0, err
+			}
+			if !filter.Matches(original) {
+				continue
+			}
+			// Çevirili transferlerin iadesi alıcının para birimindedir; çevrilen tutar orijinalin para birimindedir
+			amount := tx.Amount
+			if tx.Conversion != nil {
+				amount = tx.Conversion.Target
+			}
+			if domain.NormalizeCurrency(amount.Currency) == filter.Currency {
+				total += amount.Amount
+			}
+		}
+		if page.NextCursor == "" {
+			return total, nil
+		}
+		last := page.Transactions[len(page.Transactions)-1]
+		returns.Cursor = &domain.TransactionCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+func limitError(variant, message string, rule *domain.LimitRule, limit, remaining domain.Money) error {
+	return domain.ErrLimitExceeded.WithMessage(variant, message).
+		WithParams(map[string]interface{}{"limit": limit, "remaining": remaining}).
+		WithDetails(map[string]interface{}{"type": rule.Type, "currency": rule.Currency, "limit": limit, "remaining": remaining})
+}
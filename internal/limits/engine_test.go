@@ -0,0 +1,249 @@
+package limits
+
+import (
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/repository/memtest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+func try(amount int64) *domain.Money {
+	m := domain.NewMoney(amount, "TRY")
+	return &m
+}
+
+// engineFixture, saati testNow'a sabitlenmiş bir Engine ve alice (user), bob (user), carol (vip) kullanıcılarıdır
+type engineFixture struct {
+	engine            *Engine
+	repos             *memtest.Repositories
+	now               time.Time
+	alice, bob, carol int64
+}
+
+func newEngineFixture(t *testing.T, defaults ...domain.LimitRule) *engineFixture {
+	t.Helper()
+	if err := domain.NormalizeLimitRules(defaults); err != nil {
+		t.Fatal(err)
+	}
+	f := &engineFixture{repos: memtest.New(), now: testNow}
+	for _, u := range []struct {
+		name, role string
+		id         *int64
+	}{{"alice", "user", &f.alice}, {"bob", "user", &f.bob}, {"carol", "vip", &f.carol}} {
+		user := &domain.User{Username: u.name, Email: u.name + "@example.com", Password: "hash", Role: u.role}
+		if err := f.repos.Users.Create(user); err != nil {
+			t.Fatal(err)
+		}
+		*u.id = user.ID
+	}
+	f.engine = NewEngine(defaults, f.repos.LimitOverrides, f.repos.Users, f.repos.Transactions)
+	f.engine.now = func() time.Time { return f.now }
+	return f
+}
+
+// Tamamlanmış bir işlemi testNow'dan age kadar önce oluşturulmuş olarak kaydeder; from veya to 0 ise o taraf yoktur
+func (f *engineFixture) record(t *testing.T, txType domain.TransactionType, from, to int64, amount domain.Money, age time.Duration) *domain.Transaction {
+	t.Helper()
+	tx := &domain.Transaction{Type: txType, Amount: amount, Status: domain.TransactionCompleted, CreatedAt: testNow.Add(-age)}
+	if from != 0 {
+		tx.FromUserID = &from
+	}
+	if to != 0 {
+		tx.ToUserID = &to
+	}
+	if err := f.repos.Transactions.Create(tx); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// Orijinal işlemi ters yönde, verilen tutar kadar geri alan veya iade eden işlemi kaydeder
+func (f *engineFixture) refund(t *testing.T, txType domain.TransactionType, original *domain.Transaction, amount domain.Money, age time.Duration) *domain.Transaction {
+	t.Helper()
+	var from, to int64
+	if original.ToUserID != nil {
+		from = *original.ToUserID
+	}
+	if original.FromUserID != nil {
+		to = *original.FromUserID
+	}
+	tx := f.record(t, txType, from, to, amount, age)
+	tx.OriginalID = &original.ID
+	return tx
+}
+
+func (f *engineFixture) headroom(t *testing.T, userID int64, txType domain.TransactionType, counterpartyID *int64) domain.LimitHeadroom {
+	t.Helper()
+	headroom, err := f.engine.Headroom(userID, "TRY", counterpartyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range headroom {
+		if h.Type == txType {
+			return h
+		}
+	}
+	t.Fatalf("%s için limit bulunamadı: %+v", txType, headroom)
+	return domain.LimitHeadroom{}
+}
+
+func expectUsed(t *testing.T, name string, usage *domain.LimitUsage, used, remaining int64) {
+	t.Helper()
+	if usage == nil || usage.Used != *try(used) || usage.Remaining != *try(remaining) {
+		t.Fatalf("%s: %+v; beklenen kullanım %d, kalan %d", name, usage, used, remaining)
+	}
+}
+
+func TestUsageRollingWindows(t *testing.T) {
+	f := newEngineFixture(t, domain.LimitRule{Type: domain.TransactionTransfer, Currency: "TRY", Daily: try(1000), Monthly: try(5000)})
+	f.record(t, domain.TransactionTransfer, f.alice, f.bob, *try(300), time.Hour)
+	f.record(t, domain.TransactionTransfer, f.alice, f.bob, *try(100), domain.DailyLimitWindow) // Pencere başı dahildir
+	f.record(t, domain.TransactionTransfer, f.alice, f.bob, *try(400), 25*time.Hour)
+	f.record(t, domain.TransactionTransfer, f.alice, f.bob, *try(200), domain.MonthlyLimitWindow+time.Second)
+	// Sayılmayanlar: gelen transfer, başka tür, başka para birimi, tamamlanmamış işlem
+	f.record(t, domain.TransactionTransfer, f.bob, f.alice, *try(700), time.Hour)
+	f.record(t, domain.TransactionWithdraw, f.alice, 0, *try(900), time.Hour)
+	f.record(t, domain.TransactionTransfer, f.alice, f.bob, domain.NewMoney(900, "EUR"), time.Hour)
+	pending := f.record(t, domain.TransactionTransfer, f.alice, f.bob, *try(900), time.Hour)
+	pending.Status = domain.TransactionPending
+
+	h := f.headroom(t, f.alice, domain.TransactionTransfer, nil)
+	expectUsed(t, "günlük", h.Daily, 400, 600)
+	expectUsed(t, "aylık", h.Monthly, 800, 4200)
+
+	transfer := domain.LimitMovement{UserID: f.alice, Type: domain.TransactionTransfer, Amount: *try(600), CounterpartyID: &f.bob}
+	if err := f.engine.Check(f.repos.Transactions, transfer); err != nil {
+		t.Fatalf("kalan limit kadar transfer: %v", err)
+	}
+	transfer.Amount = *try(601)
+	if err := f.engine.Check(f.repos.Transactions, transfer); !errors.Is(err, domain.ErrLimitExceeded) || errorKey(err) != "limit_exceeded.daily" {
+		t.Fatalf("limit aşımı: %v, beklenen limit_exceeded.daily", err)
+	}
+
+	// Pencere kayar: bir saniye sonra pencere başındaki işlem düşer
+	f.now = testNow.Add(time.Second)
+	expectUsed(t, "bir saniye sonra günlük", f.headroom(t, f.alice, domain.TransactionTransfer, nil).Daily, 300, 700)
+	if err := f.engine.Check(f.repos.Transactions, transfer); err != nil {
+		t.Fatalf("pencere kaydıktan sonra: %v", err)
+	}
+}
+
+func TestUsageNetsReversalsAndRefunds(t *testing.T) {
+	f := newEngineFixture(t,
+		domain.LimitRule{Type: domain.TransactionTransfer, Currency: "TRY", Daily: try(10000), Monthly: try(10000), CounterpartyDaily: try(10000)},
+		domain.LimitRule{Type: domain.TransactionDeposit, Currency: "TRY", Daily: try(10000)})
+
+	partly := f.record(t, domain.TransactionTransfer, f.alice, f.bob, *try(800), time.Hour)
+	f.refund(t, domain.TransactionRefund, partly, *try(300), 30*time.Minute)
+	// Pencereden önceki işlemin iadesi günlük kullanımı azaltmaz, aylık kullanımı azaltır
+	older := f.record(t, domain.TransactionTransfer, f.alice, f.bob, *try(1000), 48*time.Hour)
+	f.refund(t, domain.TransactionRefund, older, *try(400), time.Hour)
+	// Çevirili transferin iadesi alıcının para birimindedir; gönderene dönen TRY tutarı düşülür
+	converted := f.record(t, domain.TransactionTransfer, f.alice, f.carol, *try(500), time.Hour)
+	converted.Conversion = &domain.FXConversion{Target: domain.NewMoney(15, "EUR")}
+	back := f.refund(t, domain.TransactionRefund, converted, domain.NewMoney(6, "EUR"), time.Hour)
+	back.Conversion = &domain.FXConversion{Target: *try(200)}
+	// Geri alınan transfer hiç kullanılmamış sayılır
+	reversed := f.record(t, domain.TransactionTransfer, f.alice, f.bob, *try(2000), time.Hour)
+	f.refund(t, domain.TransactionReversal, reversed, *try(2000), time.Hour)
+	// Tamamlanmamış iade düşülmez
+	f.refund(t, domain.TransactionRefund, partly, *try(100), time.Minute).Status = domain.TransactionPending
+
+	h := f.headroom(t, f.alice, domain.TransactionTransfer, &f.bob)
+	expectUsed(t, "günlük", h.Daily, 500+300, 10000-800)
+	expectUsed(t, "aylık", h.Monthly, 500+600+300, 10000-1400)
+	expectUsed(t, "bob'a günlük", h.CounterpartyDaily, 500, 10000-500)
+
+	// Para yatırmanın geri alınması kullanıcıdan para çıkışıdır
+	deposit := f.record(t, domain.TransactionDeposit, 0, f.alice, *try(1000), time.Hour)
+	f.refund(t, domain.TransactionReversal, deposit, *try(1000), time.Minute)
+	f.record(t, domain.TransactionDeposit, 0, f.alice, *try(250), time.Hour)
+	expectUsed(t, "para yatırma", f.headroom(t, f.alice, domain.TransactionDeposit, nil).Daily, 250, 10000-250)
+}
+
+func TestSelfTransfersAreExcluded(t *testing.T) {
+	f := newEngineFixture(t, domain.LimitRule{Type: domain.TransactionTransfer, Currency: "TRY", PerTransaction: try(500), Daily: try(1000)})
+	self := f.record(t, domain.TransactionTransfer, f.alice, f.alice, *try(5000), time.Hour)
+	f.refund(t, domain.TransactionReversal, self, *try(5000), time.Minute)
+	f.record(t, domain.TransactionTransfer, f.alice, f.bob, *try(400), time.Hour)
+
+	expectUsed(t, "günlük", f.headroom(t, f.alice, domain.TransactionTransfer, nil).Daily, 400, 600)
+	own := domain.LimitMovement{UserID: f.alice, Type: domain.TransactionTransfer, Amount: *try(5000), CounterpartyID: &f.alice}
+	if err := f.engine.Check(f.repos.Transactions, own); err != nil {
+		t.Fatalf("kendi hesapları arası transfer: %v", err)
+	}
+	other := domain.LimitMovement{UserID: f.alice, Type: domain.TransactionTransfer, Amount: *try(501), CounterpartyID: &f.bob}
+	if err := f.engine.Check(f.repos.Transactions, other); errorKey(err) != "limit_exceeded.per_transaction" {
+		t.Fatalf("başkasına transfer: %v, beklenen limit_exceeded.per_transaction", err)
+	}
+}
+
+// Varsayılanların üzerine önce rol, sonra kullanıcı override'ı uygulanır; boş alanlar alttaki katmandan gelir
+func TestOverrideMergeOrder(t *testing.T) {
+	f := newEngineFixture(t,
+		domain.LimitRule{Type: domain.TransactionTransfer, Currency: "TRY", PerTransaction: try(100), Daily: try(1000), Monthly: try(5000)},
+		domain.LimitRule{Type: domain.TransactionWithdraw, Currency: "TRY", Daily: try(50)})
+	overrides := []*domain.LimitOverride{
+		{Scope: domain.LimitScopeRole, Subject: "user", Rules: []domain.LimitRule{
+			{Type: domain.TransactionTransfer, Currency: "TRY", Daily: try(2000), CounterpartyDaily: try(300)},
+			{Type: domain.TransactionDeposit, Currency: "TRY", Daily: try(700)},
+		}},
+		{Scope: domain.LimitScopeUser, Subject: strconv.FormatInt(f.alice, 10), Rules: []domain.LimitRule{
+			{Type: domain.TransactionTransfer, Currency: "TRY", Daily: try(3000), Monthly: try(4000)},
+		}},
+	}
+	for _, o := range overrides {
+		if err := f.engine.SetOverride(o); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type limits struct{ perTx, daily, monthly, counterpartyDaily, deposit int64 }
+	expect := func(step string, userID int64, want limits) {
+		t.Helper()
+		h := f.headroom(t, userID, domain.TransactionTransfer, &f.carol)
+		got := limits{perTx: h.PerTransaction.Amount, daily: h.Daily.Limit.Amount, monthly: h.Monthly.Limit.Amount}
+		if h.CounterpartyDaily != nil {
+			got.counterpartyDaily = h.CounterpartyDaily.Limit.Amount
+		}
+		headroom, err := f.engine.Headroom(userID, "TRY", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range headroom {
+			if r.Type == domain.TransactionDeposit {
+				got.deposit = r.Daily.Limit.Amount
+			}
+		}
+		if got != want {
+			t.Fatalf("%s: %+v, beklenen %+v", step, got, want)
+		}
+		if w := f.headroom(t, userID, domain.TransactionWithdraw, nil); w.Daily.Limit != *try(50) {
+			t.Fatalf("%s: para çekme limiti %s, beklenen varsayılan", step, w.Daily.Limit)
+		}
+	}
+	expect("alice: kullanıcı > rol > varsayılan", f.alice, limits{perTx: 100, daily: 3000, monthly: 4000, counterpartyDaily: 300, deposit: 700})
+	expect("bob: rol > varsayılan", f.bob, limits{perTx: 100, daily: 2000, monthly: 5000, counterpartyDaily: 300, deposit: 700})
+	// Override'lar varsayılan kuralları değiştirmez
+	expect("carol: varsayılan", f.carol, limits{perTx: 100, daily: 1000, monthly: 5000})
+
+	if err := f.engine.DeleteOverride(domain.LimitScopeUser, strconv.FormatInt(f.alice, 10)); err != nil {
+		t.Fatal(err)
+	}
+	expect("alice override'ı silinince rol", f.alice, limits{perTx: 100, daily: 2000, monthly: 5000, counterpartyDaily: 300, deposit: 700})
+	if err := f.engine.DeleteOverride(domain.LimitScopeRole, "user"); err != nil {
+		t.Fatal(err)
+	}
+	expect("rol override'ı silinince varsayılan", f.alice, limits{perTx: 100, daily: 1000, monthly: 5000})
+}
+
+func errorKey(err error) string {
+	if e, ok := domain.AsError(err); ok {
+		return e.MessageKey()
+	}
+	return ""
+}
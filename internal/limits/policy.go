@@ -0,0 +1,66 @@
+package limits
+
+import (
+	"encoding/json"
+	"fmt"
+	"gofinancialsystem/internal/domain"
+	"io"
+	"os"
+)
+
+// PolicyFile, LIMITS_FILE ile verilen varsayılan limitlerin JSON biçimidir:
+//
+//	{"rules": [{"type": "withdraw", "currency": "TRY", "per_transaction": "50000.00", "daily": "100000.00"}]}
+//
+// TRY dışındaki para birimlerinde tutarlar {"amount": "...", "currency": "USD"} biçiminde yazılmalıdır.
+type PolicyFile struct {
+	Rules []domain.LimitRule `json:"rules"`
+}
+
+// Varsayılan limit dosyasını okur
+func LoadFile(path string) ([]domain.LimitRule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseJSON(f)
+}
+
+// JSON biçimindeki varsayılan limitleri okur ve doğrular (bkz. PolicyFile)
+func ParseJSON(r io.Reader) ([]domain.LimitRule, error) {
+	var file PolicyFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("limits: limit dosyası okunamadı: %w", err)
+	}
+	if err := domain.NormalizeLimitRules(file.Rules); err != nil {
+		return nil, fmt.Errorf("limits: limit dosyası geçersiz: %w", err)
+	}
+	return file.Rules, nil
+}
+
+// LIMITS_FILE verilmediğinde kullanılan varsayılan limitler. Sadece varsayılan para birimi için
+// tanımlıdır; diğer para birimlerinde limit dosyası veya override olmadan limit uygulanmaz.
+func DefaultRules() []domain.LimitRule {
+	try := func(amount int64) *domain.Money {
+		m := domain.NewMoney(amount*100, domain.DefaultCurrency)
+		return &m
+	}
+	return []domain.LimitRule{
+		{
+			Type:           domain.TransactionWithdraw,
+			Currency:       domain.DefaultCurrency,
+			PerTransaction: try(50_000),
+			Daily:          try(100_000),
+			Monthly:        try(500_000),
+		},
+		{
+			Type:              domain.TransactionTransfer,
+			Currency:          domain.DefaultCurrency,
+			PerTransaction:    try(100_000),
+			Daily:             try(250_000),
+			Monthly:           try(1_000_000),
+			CounterpartyDaily: try(100_000),
+		},
+	}
+}
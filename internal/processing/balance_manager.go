@@ -12,14 +12,15 @@ type BalanceManager struct {
 	balances map[int64]*domain.Balance // Kullanıcı ID -> Balance
 	mu       sync.RWMutex              // Okuma/yazma için RWMutex
 
-	totalTransactions uint64 // Toplam işlenen transaction sayısı (atomic)
-	totalAmount       uint64 // Toplam işlenen miktar (atomic, kuruş cinsinden)
+	totalTransactions uint64                  // Toplam işlenen transaction sayısı (atomic)
+	volumes           map[string]domain.Money // Para birimi -> toplam işlenen hacim (mutlak değer, mu ile korunur)
 }
 
 // Yeni bir BalanceManager oluşturur
 func NewBalanceManager() *BalanceManager {
 	return &BalanceManager{
 		balances: make(map[int64]*domain.Balance),
+		volumes:  make(map[string]domain.Money),
 	}
 }
 
@@ -31,23 +32,41 @@ func (bm *BalanceManager) GetBalance(userID int64) *domain.Balance {
 }
 
 // Kullanıcıya ait bakiyeyi thread-safe şekilde günceller
-func (bm *BalanceManager) UpdateBalance(userID int64, amount float64) {
+func (bm *BalanceManager) UpdateBalance(userID int64, amount domain.Money) error {
 	bm.mu.Lock()
 	defer bm.mu.Unlock()
 	bal, ok := bm.balances[userID]
 	if !ok {
-		bal = &domain.Balance{UserID: userID}
+		bal = &domain.Balance{UserID: userID, Amount: domain.NewMoney(0, amount.Currency)}
 		bm.balances[userID] = bal
 	}
-	bal.Add(amount)
+	// Hacim taşarsa bakiye de değişmeden hata dönülür
+	currency := domain.NormalizeCurrency(amount.Currency)
+	volume, ok := bm.volumes[currency]
+	if !ok {
+		volume = domain.NewMoney(0, currency)
+	}
+	volume, err := volume.Add(amount.Abs())
+	if err != nil {
+		return err
+	}
+	if err := bal.Add(amount); err != nil {
+		return err
+	}
 	// Toplam transaction sayaçlarını güncelle
+	bm.volumes[currency] = volume
 	atomic.AddUint64(&bm.totalTransactions, 1)
-	atomic.AddUint64(&bm.totalAmount, uint64(amount*100)) // kuruş cinsinden
+	return nil
 }
 
-// Transaction istatistiklerini thread-safe şekilde döndürür
-func (bm *BalanceManager) Stats() (totalTx uint64, totalAmount float64) {
-	tx := atomic.LoadUint64(&bm.totalTransactions)
-	amt := atomic.LoadUint64(&bm.totalAmount)
-	return tx, float64(amt) / 100.0
+// Transaction istatistiklerini thread-safe şekilde döndürür. Farklı para birimlerindeki tutarlar
+// toplanamayacağı için hacim para birimi başına ayrı döner.
+func (bm *BalanceManager) Stats() (totalTx uint64, volumes map[string]domain.Money) {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+	volumes = make(map[string]domain.Money, len(bm.volumes))
+	for currency, volume := range bm.volumes {
+		volumes[currency] = volume
+	}
+	return atomic.LoadUint64(&bm.totalTransactions), volumes
 }
@@ -0,0 +1,77 @@
+package processing
+
+import (
+	"gofinancialsystem/internal/domain"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Stop, kuyruktaki tüm işler işlenene kadar bekler
+func TestWorkerPoolProcessesQueuedJobsBeforeStop(t *testing.T) {
+	pool := NewWorkerPool(3, 10)
+	var mu sync.Mutex
+	var total int64
+	pool.Start(func(job TransactionJob) {
+		mu.Lock()
+		defer mu.Unlock()
+		total += job.Transaction.Amount.Amount
+	})
+	for i := 1; i <= 5; i++ {
+		pool.Enqueue(TransactionJob{Transaction: &domain.Transaction{ID: int64(i), Amount: domain.MinorUnits(int64(1000 * i))}})
+	}
+	pool.Stop()
+	if total != 15000 {
+		t.Fatalf("işlenen toplam %d, beklenen 15000", total)
+	}
+}
+
+// ProcessBatch, tüm işler bitene kadar döner
+func TestBatchProcessorWaitsForAllJobs(t *testing.T) {
+	var batch BatchProcessor
+	var done atomic.Int32
+	jobs := make([]func(), 3)
+	for i := range jobs {
+		jobs[i] = func() { done.Add(1) }
+	}
+	batch.ProcessBatch(jobs)
+	if n := done.Load(); n != 3 {
+		t.Fatalf("%d iş tamamlandı, beklenen 3", n)
+	}
+}
+
+// Hacim para birimi başına tutulur; başka birimdeki bakiyeye yazılamayan tutar istatistiğe girmez
+func TestBalanceManagerStatsPerCurrency(t *testing.T) {
+	bm := NewBalanceManager()
+	for _, update := range []struct {
+		userID int64
+		amount domain.Money
+	}{
+		{1, domain.NewMoney(1000, "TRY")},
+		{1, domain.NewMoney(-250, "TRY")},
+		{2, domain.NewMoney(500, "USD")},
+		{3, domain.NewMoney(7, "JPY")},
+	} {
+		if err := bm.UpdateBalance(update.userID, update.amount); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := bm.UpdateBalance(1, domain.NewMoney(100, "USD")); err == nil {
+		t.Fatal("TRY bakiyesine USD eklenmemeli")
+	}
+
+	tx, volumes := bm.Stats()
+	want := map[string]domain.Money{
+		"TRY": domain.NewMoney(1250, "TRY"),
+		"USD": domain.NewMoney(500, "USD"),
+		"JPY": domain.NewMoney(7, "JPY"),
+	}
+	if tx != 4 || len(volumes) != len(want) {
+		t.Fatalf("Stats = %d, %v; beklenen 4 işlem, %v", tx, volumes, want)
+	}
+	for currency, volume := range want {
+		if volumes[currency] != volume {
+			t.Errorf("%s hacmi %v, beklenen %v", currency, volumes[currency], volume)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"gofinancialsystem/internal/domain"
+	"sort"
+	"sync"
+)
+
+// Kullanıcının aynı para biriminde ikinci bir varsayılan hesabı olamaz
+var errDefaultAccountExists = domain.ErrConflict.WithMessage("default_account_exists", "bu para biriminde varsayılan hesap zaten var")
+
+// AccountRepositoryImpl, AccountRepository arayüzünün in-memory implementasyonudur
+type AccountRepositoryImpl struct {
+	accounts map[int64]*domain.Account
+	mu       sync.RWMutex
+	nextID   int64
+}
+
+// Yeni bir AccountRepositoryImpl oluşturur
+func NewAccountRepository() *AccountRepositoryImpl {
+	return &AccountRepositoryImpl{
+		accounts: make(map[int64]*domain.Account),
+		nextID:   1,
+	}
+}
+
+func (r *AccountRepositoryImpl) Create(a *domain.Account) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if a.Default {
+		for _, existing := range r.accounts {
+			if isOpenDefault(existing, a.OwnerID, a.Currency) {
+				return errDefaultAccountExists
+			}
+		}
+	}
+	a.ID = r.nextID
+	r.nextID++
+	stored := *a
+	r.accounts[a.ID] = &stored
+	return nil
+}
+
+func (r *AccountRepositoryImpl) FindByID(id int64) (*domain.Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if a, exists := r.accounts[id]; exists {
+		copied := *a
+		return &copied, nil
+	}
+	return nil, domain.ErrAccountNotFound
+}
+
+func (r *AccountRepositoryImpl) FindDefault(ownerID int64, currency string) (*domain.Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	currency = domain.NormalizeCurrency(currency)
+	for _, a := range r.accounts {
+		if isOpenDefault(a, ownerID, currency) {
+			copied := *a
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrAccountNotFound
+}
+
+func (r *AccountRepositoryImpl) ListByOwner(ownerID int64) ([]*domain.Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []*domain.Account
+	for _, a := range r.accounts {
+		if a.OwnerID == ownerID {
+			copied := *a
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *AccountRepositoryImpl) ListOpenByType(accountType domain.AccountType) ([]*domain.Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []*domain.Account
+	for _, a := range r.accounts {
+		if a.Type == accountType && a.Status != domain.AccountClosed {
+			copied := *a
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *AccountRepositoryImpl) Update(a *domain.Account) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, exists := r.accounts[a.ID]
+	if !exists {
+		return domain.ErrAccountNotFound
+	}
+	stored.Nickname = a.Nickname
+	stored.Status = a.Status
+	stored.UpdatedAt = a.UpdatedAt
+	return nil
+}
+
+func isOpenDefault(a *domain.Account, ownerID int64, currency string) bool {
+	return a.Default && a.OwnerID == ownerID && a.Currency == currency && a.Status != domain.AccountClosed
+}
@@ -1,13 +1,13 @@
 package repository
 
 import (
-	"errors"
 	"gofinancialsystem/internal/domain"
 	"sync"
+	"time"
 )
 
 type BalanceRepositoryImpl struct {
-	balances map[int64]*domain.Balance
+	balances map[int64]*domain.Balance // Hesap ID -> Balance
 	mu       sync.RWMutex
 }
 
@@ -17,27 +17,153 @@ func NewBalanceRepository() *BalanceRepositoryImpl {
 	}
 }
 
-func (r *BalanceRepositoryImpl) GetByUserID(userID int64) (*domain.Balance, error) {
+func (r *BalanceRepositoryImpl) Get(accountID int64) (*domain.Balance, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	if bal, exists := r.balances[userID]; exists {
+	if bal, exists := r.balances[accountID]; exists {
 		return bal, nil
 	}
-	return nil, errors.New("bakiye bulunamadı")
+	return nil, domain.ErrAccountNotFound
 }
 
-func (r *BalanceRepositoryImpl) Update(userID int64, amount float64) error {
+func (r *BalanceRepositoryImpl) Update(accountID int64, amount domain.Money) error {
+	return r.change(accountID, balanceDelta{amount: amount})
+}
+
+func (r *BalanceRepositoryImpl) Hold(accountID int64, amount domain.Money) error {
+	return r.change(accountID, balanceDelta{held: amount})
+}
+
+func (r *BalanceRepositoryImpl) SetOverdraftLimit(accountID int64, limit domain.Money) error {
+	return r.change(accountID, balanceDelta{overdraft: &limit})
+}
+
+func (r *BalanceRepositoryImpl) change(accountID int64, delta balanceDelta) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, _ := r.currentLocked(accountID, delta.currency())
+	next, err := state.apply(delta)
+	if err != nil {
+		return err
+	}
+	r.store(accountID, next, time.Now())
+	return nil
+}
+
+// balanceState, bir hesabın kayıtlı ledger bakiyesi, provizyon tutarı ve kredi limitidir
+type balanceState struct {
+	amount    domain.Money
+	held      domain.Money
+	overdraft domain.Money
+}
+
+// Değişikliği uygular; yeni kredi limiti verilmişse bakiye kontrolü bu limitle yapılır
+func (s balanceState) apply(delta balanceDelta) (balanceState, error) {
+	overdraft := s.overdraft
+	if delta.overdraft != nil {
+		if domain.NormalizeCurrency(delta.overdraft.Currency) != s.amount.Currency {
+			return balanceState{}, domain.ErrCurrencyMismatch
+		}
+		overdraft = domain.NewMoney(delta.overdraft.Amount, s.amount.Currency)
+	}
+	amount, held, err := domain.ApplyBalanceChange(s.amount, s.held, overdraft, delta.amount, delta.held)
+	if err != nil {
+		return balanceState{}, err
+	}
+	return balanceState{amount: amount, held: held, overdraft: overdraft}, nil
+}
+
+// balanceDelta, bir hesabın ledger bakiyesi ve provizyon tutarındaki bekleyen değişikliktir.
+// overdraft bir fark değil, varsa hesabın yeni kredi limitidir.
+type balanceDelta struct {
+	amount    domain.Money
+	held      domain.Money
+	overdraft *domain.Money
+}
+
+func (d balanceDelta) currency() string {
+	if d.amount.Currency != "" {
+		return d.amount.Currency
+	}
+	if d.held.Currency != "" || d.overdraft == nil {
+		return d.held.Currency
+	}
+	return d.overdraft.Currency
+}
+
+// İki değişikliği toplar; para birimleri hesabınkiyle aynı olmalıdır
+func (d balanceDelta) add(o balanceDelta) (balanceDelta, error) {
+	currency := d.currency()
+	if currency == "" {
+		currency = o.currency()
+	}
+	amount, err := domain.NewMoney(d.amount.Amount, currency).Add(withCurrency(o.amount, currency))
+	if err != nil {
+		return balanceDelta{}, err
+	}
+	held, err := domain.NewMoney(d.held.Amount, currency).Add(withCurrency(o.held, currency))
+	if err != nil {
+		return balanceDelta{}, err
+	}
+	overdraft := d.overdraft
+	if o.overdraft != nil {
+		overdraft = o.overdraft
+	}
+	return balanceDelta{amount: amount, held: held, overdraft: overdraft}, nil
+}
+
+// Sıfır tutarlı (para birimi boş olabilen) değerlere hesabın para birimini atar
+func withCurrency(m domain.Money, currency string) domain.Money {
+	if m.IsZero() {
+		return domain.NewMoney(0, currency)
+	}
+	return m
+}
+
+// Hesabın mevcut ledger bakiyesini, provizyon tutarını ve kredi limitini döndürür
+func (r *BalanceRepositoryImpl) current(accountID int64, currency string) (balanceState, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.currentLocked(accountID, currency)
+}
+
+// current gibidir; çağıran kilidi tutmalıdır. Bakiye satırı yoksa verilen para biriminde sıfır döner.
+func (r *BalanceRepositoryImpl) currentLocked(accountID int64, currency string) (balanceState, bool) {
+	if bal, exists := r.balances[accountID]; exists {
+		return balanceState{amount: bal.Amount, held: bal.Held, overdraft: bal.OverdraftLimit}, true
+	}
+	currency = domain.NormalizeCurrency(currency)
+	zero := domain.NewMoney(0, currency)
+	return balanceState{amount: zero, held: zero, overdraft: zero}, false
+}
+
+func (r *BalanceRepositoryImpl) store(accountID int64, state balanceState, now time.Time) {
+	bal, exists := r.balances[accountID]
+	if !exists {
+		bal = &domain.Balance{AccountID: accountID}
+		r.balances[accountID] = bal
+	}
+	bal.SetAmounts(state.amount, state.held, state.overdraft)
+	bal.LastUpdatedAt = now
+}
+
+// Unit of work'te biriken değişiklikleri uygular; herhangi bir kullanılabilir bakiye negatife düşecekse hiçbirini uygulamaz
+func (r *BalanceRepositoryImpl) applyDeltas(order []int64, deltas map[int64]balanceDelta) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	bal, ok := r.balances[userID]
-	if !ok {
-		bal = &domain.Balance{UserID: userID}
-		r.balances[userID] = bal
+	results := make(map[int64]balanceState, len(deltas))
+	for _, accountID := range order {
+		delta := deltas[accountID]
+		state, _ := r.currentLocked(accountID, delta.currency())
+		next, err := state.apply(delta)
+		if err != nil {
+			return err
+		}
+		results[accountID] = next
 	}
-	if amount < 0 && bal.Amount < -amount {
-		return errors.New("yetersiz bakiye")
+	now := time.Now()
+	for _, accountID := range order {
+		r.store(accountID, results[accountID], now)
 	}
-	bal.Amount += amount
-	bal.LastUpdatedAt = bal.LastUpdatedAt.Add(0) // Sadece örnek için
 	return nil
 }
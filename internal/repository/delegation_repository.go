@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"gofinancialsystem/internal/domain"
+	"sort"
+	"sync"
+)
+
+// DelegationRepositoryImpl, DelegationRepository arayüzünün in-memory implementasyonudur
+type DelegationRepositoryImpl struct {
+	delegations map[int64]*domain.Delegation
+	mu          sync.RWMutex
+	nextID      int64
+}
+
+// Yeni bir DelegationRepositoryImpl oluşturur
+func NewDelegationRepository() *DelegationRepositoryImpl {
+	return &DelegationRepositoryImpl{
+		delegations: make(map[int64]*domain.Delegation),
+		nextID:      1,
+	}
+}
+
+func (r *DelegationRepositoryImpl) Create(d *domain.Delegation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d.ID = r.nextID
+	r.nextID++
+	r.delegations[d.ID] = d
+	return nil
+}
+
+func (r *DelegationRepositoryImpl) FindByID(id int64) (*domain.Delegation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if d, exists := r.delegations[id]; exists {
+		return d, nil
+	}
+	return nil, domain.ErrDelegationNotFound
+}
+
+func (r *DelegationRepositoryImpl) ListBetween(ownerID, delegateID int64) ([]*domain.Delegation, error) {
+	return r.list(func(d *domain.Delegation) bool {
+		return d.OwnerID == ownerID && d.DelegateID == delegateID
+	}), nil
+}
+
+func (r *DelegationRepositoryImpl) ListByUser(userID int64) ([]*domain.Delegation, error) {
+	return r.list(func(d *domain.Delegation) bool {
+		return d.OwnerID == userID || d.DelegateID == userID
+	}), nil
+}
+
+func (r *DelegationRepositoryImpl) Delete(id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.delegations[id]; !exists {
+		return domain.ErrDelegationNotFound
+	}
+	delete(r.delegations, id)
+	return nil
+}
+
+func (r *DelegationRepositoryImpl) list(match func(*domain.Delegation) bool) []*domain.Delegation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []*domain.Delegation
+	for _, d := range r.delegations {
+		if match(d) {
+			result = append(result, d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"gofinancialsystem/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HoldRepositoryImpl, HoldRepository arayüzünün in-memory implementasyonudur
+type HoldRepositoryImpl struct {
+	holds  map[int64]*domain.Hold
+	mu     sync.RWMutex
+	nextID int64
+}
+
+// Yeni bir HoldRepositoryImpl oluşturur
+func NewHoldRepository() *HoldRepositoryImpl {
+	return &HoldRepositoryImpl{
+		holds:  make(map[int64]*domain.Hold),
+		nextID: 1,
+	}
+}
+
+func (r *HoldRepositoryImpl) Create(h *domain.Hold) error {
+	h.ID = r.reserveID()
+	r.applyStaged(map[int64]*domain.Hold{h.ID: h})
+	return nil
+}
+
+func (r *HoldRepositoryImpl) reserveID() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := r.nextID
+	r.nextID++
+	return id
+}
+
+func (r *HoldRepositoryImpl) FindByID(id int64) (*domain.Hold, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if h, exists := r.holds[id]; exists {
+		copied := *h
+		return &copied, nil
+	}
+	return nil, domain.ErrHoldNotFound
+}
+
+// In-memory unit of work'ler zaten sıralı çalıştığı için ayrıca kilit gerekmez
+func (r *HoldRepositoryImpl) FindByIDForUpdate(id int64) (*domain.Hold, error) {
+	return r.FindByID(id)
+}
+
+func (r *HoldRepositoryImpl) Update(h *domain.Hold) error {
+	if _, err := r.FindByID(h.ID); err != nil {
+		return err
+	}
+	r.applyStaged(map[int64]*domain.Hold{h.ID: h})
+	return nil
+}
+
+func (r *HoldRepositoryImpl) ListByAccount(accountID int64) ([]*domain.Hold, error) {
+	return r.list(func(h *domain.Hold) bool { return h.AccountID == accountID }), nil
+}
+
+func (r *HoldRepositoryImpl) ListExpired(now time.Time) ([]*domain.Hold, error) {
+	return r.list(func(h *domain.Hold) bool {
+		return h.Status == domain.HoldAuthorized && h.Expired(now)
+	}), nil
+}
+
+func (r *HoldRepositoryImpl) list(match func(h *domain.Hold) bool) []*domain.Hold {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []*domain.Hold
+	for _, h := range r.holds {
+		if match(h) {
+			copied := *h
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// Unit of work'te biriken yeni ve güncellenmiş provizyonları kaydeder
+func (r *HoldRepositoryImpl) applyStaged(holds map[int64]*domain.Hold) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, h := range holds {
+		stored := *h
+		r.holds[id] = &stored
+	}
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"sync"
+	"time"
+)
+
+// IdempotencyRepositoryImpl, IdempotencyRepository arayüzünün in-memory implementasyonudur
+type IdempotencyRepositoryImpl struct {
+	records map[string]*domain.IdempotencyRecord
+	mu      sync.Mutex
+}
+
+// Yeni bir IdempotencyRepositoryImpl oluşturur
+func NewIdempotencyRepository() *IdempotencyRepositoryImpl {
+	return &IdempotencyRepositoryImpl{records: make(map[string]*domain.IdempotencyRecord)}
+}
+
+func (r *IdempotencyRepositoryImpl) Reserve(rec *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.records[rec.Key]; ok && existing.ExpiresAt.After(rec.CreatedAt) {
+		copied := *existing
+		return &copied, nil
+	}
+	stored := *rec
+	r.records[rec.Key] = &stored
+	return nil, nil
+}
+
+func (r *IdempotencyRepositoryImpl) Complete(key string, statusCode int, contentType string, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec, ok := r.records[key]
+	if !ok {
+		return errors.New("idempotency anahtarı bulunamadı")
+	}
+	rec.StatusCode = statusCode
+	rec.ContentType = contentType
+	rec.Body = append([]byte(nil), body...)
+	return nil
+}
+
+func (r *IdempotencyRepositoryImpl) Release(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rec, ok := r.records[key]; ok && !rec.Completed() {
+		delete(r.records, key)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepositoryImpl) DeleteExpired(now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, rec := range r.records {
+		if !rec.ExpiresAt.After(now) {
+			delete(r.records, key)
+		}
+	}
+	return nil
+}
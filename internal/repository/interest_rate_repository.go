@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"gofinancialsystem/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+type interestRateKey struct {
+	accountType   domain.AccountType
+	currency      string
+	effectiveFrom time.Time
+}
+
+// InterestRateRepositoryImpl, InterestRateRepository arayüzünün in-memory implementasyonudur
+type InterestRateRepositoryImpl struct {
+	rates map[interestRateKey]domain.InterestRate
+	mu    sync.RWMutex
+}
+
+// Yeni bir InterestRateRepositoryImpl oluşturur
+func NewInterestRateRepository() *InterestRateRepositoryImpl {
+	return &InterestRateRepositoryImpl{rates: make(map[interestRateKey]domain.InterestRate)}
+}
+
+func (r *InterestRateRepositoryImpl) Save(rate domain.InterestRate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rates[interestRateKey{rate.AccountType, rate.Currency, rate.EffectiveFrom}] = rate
+	return nil
+}
+
+func (r *InterestRateRepositoryImpl) List() ([]domain.InterestRate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]domain.InterestRate, 0, len(r.rates))
+	for _, rate := range r.rates {
+		result = append(result, rate)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return domain.InterestRateLess(result[i], result[j])
+	})
+	return result, nil
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"gofinancialsystem/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+type accrualKey struct {
+	accountID int64
+	date      time.Time
+}
+
+// InterestRepositoryImpl, InterestRepository arayüzünün in-memory implementasyonudur
+type InterestRepositoryImpl struct {
+	accruals map[int64]*domain.InterestAccrual
+	days     map[accrualKey]int64 // Hesap ve gün başına tek tahakkuk
+	mu       sync.RWMutex
+	nextID   int64
+}
+
+// Yeni bir InterestRepositoryImpl oluşturur
+func NewInterestRepository() *InterestRepositoryImpl {
+	return &InterestRepositoryImpl{
+		accruals: make(map[int64]*domain.InterestAccrual),
+		days:     make(map[accrualKey]int64),
+		nextID:   1,
+	}
+}
+
+func (r *InterestRepositoryImpl) CreateAccrual(a *domain.InterestAccrual) error {
+	if r.exists(a.AccountID, a.Date) {
+		return domain.ErrAccrualExists
+	}
+	a.ID = r.reserveID()
+	r.applyStaged([]*domain.InterestAccrual{a}, nil)
+	return nil
+}
+
+func (r *InterestRepositoryImpl) exists(accountID int64, date time.Time) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, exists := r.days[accrualKey{accountID, date}]
+	return exists
+}
+
+func (r *InterestRepositoryImpl) reserveID() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := r.nextID
+	r.nextID++
+	return id
+}
+
+func (r *InterestRepositoryImpl) LastAccrualDate(accountID int64) (*time.Time, error) {
+	return lastAccrualDate(r.list(func(a *domain.InterestAccrual) bool { return a.AccountID == accountID })), nil
+}
+
+func lastAccrualDate(accruals []*domain.InterestAccrual) *time.Time {
+	if len(accruals) == 0 {
+		return nil
+	}
+	last := accruals[len(accruals)-1].Date
+	return &last
+}
+
+func (r *InterestRepositoryImpl) ListUnposted(accountID int64, before time.Time) ([]*domain.InterestAccrual, error) {
+	return r.list(func(a *domain.InterestAccrual) bool {
+		return a.AccountID == accountID && a.PostedTransactionID == nil && a.Date.Before(before)
+	}), nil
+}
+
+func (r *InterestRepositoryImpl) MarkPosted(ids []int64, txID int64) error {
+	r.applyStaged(nil, postedLinks(ids, txID))
+	return nil
+}
+
+func postedLinks(ids []int64, txID int64) map[int64]int64 {
+	links := make(map[int64]int64, len(ids))
+	for _, id := range ids {
+		links[id] = txID
+	}
+	return links
+}
+
+func (r *InterestRepositoryImpl) ListByAccount(accountID int64, from, to time.Time) ([]*domain.InterestAccrual, error) {
+	return r.list(func(a *domain.InterestAccrual) bool {
+		return a.AccountID == accountID && !a.Date.Before(from) && a.Date.Before(to)
+	}), nil
+}
+
+func (r *InterestRepositoryImpl) list(match func(a *domain.InterestAccrual) bool) []*domain.InterestAccrual {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []*domain.InterestAccrual
+	for _, a := range r.accruals {
+		if match(a) {
+			result = append(result, copyAccrual(a))
+		}
+	}
+	sortAccruals(result)
+	return result
+}
+
+func sortAccruals(accruals []*domain.InterestAccrual) {
+	sort.Slice(accruals, func(i, j int) bool {
+		if !accruals[i].Date.Equal(accruals[j].Date) {
+			return accruals[i].Date.Before(accruals[j].Date)
+		}
+		return accruals[i].AccountID < accruals[j].AccountID
+	})
+}
+
+func copyAccrual(a *domain.InterestAccrual) *domain.InterestAccrual {
+	copied := *a
+	if a.PostedTransactionID != nil {
+		txID := *a.PostedTransactionID
+		copied.PostedTransactionID = &txID
+	}
+	return &copied
+}
+
+// Unit of work'te biriken yeni tahakkukları ve işlem bağlantılarını (tahakkuk ID -> işlem ID) kaydeder.
+// Gün tekilliği tahakkuk oluşturulurken kontrol edildiği için burada tekrar kontrol edilmez.
+func (r *InterestRepositoryImpl) applyStaged(created []*domain.InterestAccrual, posted map[int64]int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range created {
+		r.accruals[a.ID] = copyAccrual(a)
+		r.days[accrualKey{a.AccountID, a.Date}] = a.ID
+	}
+	for id, txID := range posted {
+		if a, exists := r.accruals[id]; exists {
+			txID := txID
+			a.PostedTransactionID = &txID
+		}
+	}
+}
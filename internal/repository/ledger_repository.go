@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"sync"
+	"time"
+)
+
+// LedgerRepositoryImpl, LedgerRepository arayüzünün in-memory implementasyonudur
+type LedgerRepositoryImpl struct {
+	entries []*domain.JournalEntry
+	mu      sync.RWMutex
+	nextID  int64
+}
+
+// Yeni bir LedgerRepositoryImpl oluşturur
+func NewLedgerRepository() *LedgerRepositoryImpl {
+	return &LedgerRepositoryImpl{nextID: 1}
+}
+
+// Yevmiye kaydını ekler; kayıtlar eklendikten sonra değiştirilmez
+func (r *LedgerRepositoryImpl) Append(entry *domain.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.ID = r.nextID
+	r.nextID++
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+// Bir transaction'a ait yevmiye kayıtlarını listeler
+func (r *LedgerRepositoryImpl) ListByTransaction(txID int64) ([]*domain.JournalEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return filterByTransaction(r.entries, txID), nil
+}
+
+// Bir hesaba posting içeren yevmiye kayıtlarını listeler
+func (r *LedgerRepositoryImpl) ListByAccount(accountID string) ([]*domain.JournalEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return filterByAccount(r.entries, accountID), nil
+}
+
+// Hesabın bakiyesini postingleri toplayarak hesaplar
+func (r *LedgerRepositoryImpl) AccountBalance(accountID, currency string) (domain.Money, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return sumPostings(r.entries, accountID, currency)
+}
+
+// Hesabın at anından önceki ve valör tarihi valueDay veya öncesi olan kayıtlarla bakiyesini hesaplar
+func (r *LedgerRepositoryImpl) AccountBalanceAt(accountID, currency string, at, valueDay time.Time) (domain.Money, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return sumPostings(entriesBefore(r.entries, at, valueDay), accountID, currency)
+}
+
+func entriesBefore(entries []*domain.JournalEntry, at, valueDay time.Time) []*domain.JournalEntry {
+	var result []*domain.JournalEntry
+	for _, e := range entries {
+		if e.CreatedAt.Before(at) && !e.ValueDate.After(valueDay) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func filterByTransaction(entries []*domain.JournalEntry, txID int64) []*domain.JournalEntry {
+	var result []*domain.JournalEntry
+	for _, e := range entries {
+		if e.TransactionID != nil && *e.TransactionID == txID {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func filterByAccount(entries []*domain.JournalEntry, accountID string) []*domain.JournalEntry {
+	var result []*domain.JournalEntry
+	for _, e := range entries {
+		for _, p := range e.Postings {
+			if p.AccountID == accountID {
+				result = append(result, e)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func sumPostings(entries []*domain.JournalEntry, accountID, currency string) (domain.Money, error) {
+	total := domain.NewMoney(0, currency)
+	for _, e := range entries {
+		for _, p := range e.Postings {
+			if p.AccountID != accountID || domain.NormalizeCurrency(p.Amount.Currency) != total.Currency {
+				continue
+			}
+			var err error
+			if total, err = total.Add(p.Amount); err != nil {
+				return domain.Money{}, errors.New("hesap bakiyesi hesaplanamadı: " + err.Error())
+			}
+		}
+	}
+	return total, nil
+}
+
+// Unit of work'te biriken kayıtları ekler ve ID atar
+func (r *LedgerRepositoryImpl) applyStaged(entries []*domain.JournalEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, entry := range entries {
+		entry.ID = r.nextID
+		r.nextID++
+		r.entries = append(r.entries, entry)
+	}
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"gofinancialsystem/internal/domain"
+	"sort"
+	"sync"
+)
+
+type limitOverrideKey struct {
+	scope   domain.LimitScope
+	subject string
+}
+
+// LimitOverrideRepositoryImpl, LimitOverrideRepository arayüzünün in-memory implementasyonudur
+type LimitOverrideRepositoryImpl struct {
+	overrides map[limitOverrideKey]*domain.LimitOverride
+	mu        sync.RWMutex
+}
+
+// Yeni bir LimitOverrideRepositoryImpl oluşturur
+func NewLimitOverrideRepository() *LimitOverrideRepositoryImpl {
+	return &LimitOverrideRepositoryImpl{overrides: make(map[limitOverrideKey]*domain.LimitOverride)}
+}
+
+func (r *LimitOverrideRepositoryImpl) Find(scope domain.LimitScope, subject string) (*domain.LimitOverride, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if o, exists := r.overrides[limitOverrideKey{scope, subject}]; exists {
+		return copyLimitOverride(o), nil
+	}
+	return nil, domain.ErrLimitOverrideNotFound
+}
+
+func (r *LimitOverrideRepositoryImpl) Save(o *domain.LimitOverride) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.overrides[limitOverrideKey{o.Scope, o.Subject}] = copyLimitOverride(o)
+	return nil
+}
+
+func (r *LimitOverrideRepositoryImpl) Delete(scope domain.LimitScope, subject string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := limitOverrideKey{scope, subject}
+	if _, exists := r.overrides[key]; !exists {
+		return domain.ErrLimitOverrideNotFound
+	}
+	delete(r.overrides, key)
+	return nil
+}
+
+// Override'ları kapsam ve konu sırasıyla listeler
+func (r *LimitOverrideRepositoryImpl) List() ([]*domain.LimitOverride, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.LimitOverride, 0, len(r.overrides))
+	for _, o := range r.overrides {
+		result = append(result, copyLimitOverride(o))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Scope != result[j].Scope {
+			return result[i].Scope < result[j].Scope
+		}
+		return result[i].Subject < result[j].Subject
+	})
+	return result, nil
+}
+
+func copyLimitOverride(o *domain.LimitOverride) *domain.LimitOverride {
+	copied := *o
+	copied.Rules = append([]domain.LimitRule(nil), o.Rules...)
+	return &copied
+}
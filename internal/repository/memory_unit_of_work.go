@@ -0,0 +1,408 @@
+package repository
+
+import (
+	"gofinancialsystem/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryUnitOfWork, in-memory repository'ler için UnitOfWork implementasyonudur.
+// Değişiklikler önce ayrı bir alanda biriktirilir, fn başarılı olursa tek seferde uygulanır.
+type MemoryUnitOfWork struct {
+	balances     *BalanceRepositoryImpl
+	transactions *TransactionRepositoryImpl
+	ledger       *LedgerRepositoryImpl
+	holds        *HoldRepositoryImpl
+	interest     *InterestRepositoryImpl
+	mu           sync.Mutex // Unit of work'leri sıraya koyar
+}
+
+// Yeni bir MemoryUnitOfWork oluşturur
+func NewMemoryUnitOfWork(balances *BalanceRepositoryImpl, transactions *TransactionRepositoryImpl, ledger *LedgerRepositoryImpl,
+	holds *HoldRepositoryImpl, interest *InterestRepositoryImpl) *MemoryUnitOfWork {
+	return &MemoryUnitOfWork{balances: balances, transactions: transactions, ledger: ledger, holds: holds, interest: interest}
+}
+
+// fn'i çalıştırır; hata yoksa biriken tüm değişiklikleri atomik olarak uygular
+func (u *MemoryUnitOfWork) Do(fn func(repos domain.Repositories) error) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	balances := &stagedBalanceRepository{base: u.balances, deltas: make(map[int64]balanceDelta)}
+	transactions := &stagedTransactionRepository{base: u.transactions, statuses: make(map[int64]domain.TransactionStatus)}
+	ledger := &stagedLedgerRepository{base: u.ledger}
+	holds := &stagedHoldRepository{base: u.holds, holds: make(map[int64]*domain.Hold)}
+	interest := &stagedInterestRepository{base: u.interest, posted: make(map[int64]int64)}
+	repos := domain.Repositories{Balances: balances, Transactions: transactions, Ledger: ledger, Holds: holds, Interest: interest}
+	if err := fn(repos); err != nil {
+		return err
+	}
+	if err := u.balances.applyDeltas(balances.order, balances.deltas); err != nil {
+		return err
+	}
+	u.transactions.applyStaged(transactions.created, transactions.statuses)
+	u.ledger.applyStaged(ledger.entries)
+	u.holds.applyStaged(holds.holds)
+	u.interest.applyStaged(interest.created, interest.posted)
+	return nil
+}
+
+// stagedBalanceRepository, bakiye ve provizyon değişikliklerini commit edilene kadar hesap bazında delta olarak tutar
+type stagedBalanceRepository struct {
+	base   *BalanceRepositoryImpl
+	deltas map[int64]balanceDelta
+	order  []int64
+}
+
+func (r *stagedBalanceRepository) Get(accountID int64) (*domain.Balance, error) {
+	delta, staged := r.deltas[accountID]
+	state, exists := r.base.current(accountID, delta.currency())
+	if !exists && !staged {
+		return nil, domain.ErrAccountNotFound
+	}
+	if staged {
+		var err error
+		if state, err = state.apply(delta); err != nil {
+			return nil, err
+		}
+	}
+	bal := &domain.Balance{AccountID: accountID, LastUpdatedAt: time.Now()}
+	bal.SetAmounts(state.amount, state.held, state.overdraft)
+	return bal, nil
+}
+
+func (r *stagedBalanceRepository) Update(accountID int64, amount domain.Money) error {
+	return r.change(accountID, balanceDelta{amount: amount})
+}
+
+func (r *stagedBalanceRepository) Hold(accountID int64, amount domain.Money) error {
+	return r.change(accountID, balanceDelta{held: amount})
+}
+
+func (r *stagedBalanceRepository) SetOverdraftLimit(accountID int64, limit domain.Money) error {
+	return r.change(accountID, balanceDelta{overdraft: &limit})
+}
+
+func (r *stagedBalanceRepository) change(accountID int64, change balanceDelta) error {
+	delta, staged := r.deltas[accountID]
+	newDelta, err := delta.add(change)
+	if err != nil {
+		return err
+	}
+	state, _ := r.base.current(accountID, newDelta.currency())
+	if _, err := state.apply(newDelta); err != nil {
+		return err
+	}
+	if !staged {
+		r.order = append(r.order, accountID)
+	}
+	r.deltas[accountID] = newDelta
+	return nil
+}
+
+// stagedTransactionRepository, yeni kayıtları ve durum değişikliklerini commit'e kadar bekletir
+type stagedTransactionRepository struct {
+	base     *TransactionRepositoryImpl
+	created  []*domain.Transaction
+	statuses map[int64]domain.TransactionStatus
+}
+
+func (r *stagedTransactionRepository) Create(tx *domain.Transaction) error {
+	tx.ID = r.base.reserveID()
+	r.created = append(r.created, tx)
+	return nil
+}
+
+func (r *stagedTransactionRepository) FindByID(id int64) (*domain.Transaction, error) {
+	for _, tx := range r.created {
+		if tx.ID == id {
+			return tx, nil
+		}
+	}
+	tx, err := r.base.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if status, ok := r.statuses[id]; ok {
+		copied := *tx
+		copied.Status = status
+		return &copied, nil
+	}
+	return tx, nil
+}
+
+func (r *stagedTransactionRepository) FindByIDForUpdate(id int64) (*domain.Transaction, error) {
+	return r.FindByID(id)
+}
+
+func (r *stagedTransactionRepository) LockUser(userID int64) error {
+	return nil
+}
+
+func (r *stagedTransactionRepository) ListByOriginal(originalID int64) ([]*domain.Transaction, error) {
+	result, err := r.base.ListByOriginal(originalID)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range r.created {
+		if tx.OriginalID != nil && *tx.OriginalID == originalID {
+			result = append(result, tx)
+		}
+	}
+	return result, nil
+}
+
+func (r *stagedTransactionRepository) ListByUser(userID int64) ([]*domain.Transaction, error) {
+	result, err := r.base.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range r.created {
+		if (tx.FromUserID != nil && *tx.FromUserID == userID) || (tx.ToUserID != nil && *tx.ToUserID == userID) {
+			result = append(result, tx)
+		}
+	}
+	sortTransactions(result, false)
+	return result, nil
+}
+
+// Kayıtlı işlemlerden sayfaya girebilecek ilk limit+1 kayıt, unit of work'te eklenenlerle birleştirilir
+func (r *stagedTransactionRepository) Search(filter domain.TransactionFilter) (*domain.TransactionPage, error) {
+	baseFilter := filter
+	baseFilter.Limit = filter.Limit + 1
+	base, err := r.base.Search(baseFilter)
+	if err != nil {
+		return nil, err
+	}
+	page := newPageCollector(filter)
+	for _, tx := range base.Transactions {
+		page.insert(tx)
+	}
+	for _, tx := range r.created {
+		page.add(tx)
+	}
+	page.total += *base.Total
+	return page.result(), nil
+}
+
+func (r *stagedTransactionRepository) Sum(filter domain.TransactionFilter) (domain.Money, error) {
+	candidates, err := r.ListByUser(filter.UserID)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	return sumMatching(candidates, filter), nil
+}
+
+func (r *stagedTransactionRepository) Count(filter domain.TransactionFilter) (int64, error) {
+	candidates, err := r.ListByUser(filter.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return countMatching(candidates, filter), nil
+}
+
+func (r *stagedTransactionRepository) UpdateStatus(id int64, status domain.TransactionStatus) error {
+	for _, tx := range r.created {
+		if tx.ID == id {
+			tx.Status = status
+			return nil
+		}
+	}
+	if _, err := r.base.FindByID(id); err != nil {
+		return err
+	}
+	r.statuses[id] = status
+	return nil
+}
+
+// stagedLedgerRepository, yeni yevmiye kayıtlarını commit'e kadar bekletir
+type stagedLedgerRepository struct {
+	base    *LedgerRepositoryImpl
+	entries []*domain.JournalEntry
+}
+
+func (r *stagedLedgerRepository) Append(entry *domain.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *stagedLedgerRepository) ListByTransaction(txID int64) ([]*domain.JournalEntry, error) {
+	result, err := r.base.ListByTransaction(txID)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, filterByTransaction(r.entries, txID)...), nil
+}
+
+func (r *stagedLedgerRepository) ListByAccount(accountID string) ([]*domain.JournalEntry, error) {
+	result, err := r.base.ListByAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, filterByAccount(r.entries, accountID)...), nil
+}
+
+func (r *stagedLedgerRepository) AccountBalance(accountID, currency string) (domain.Money, error) {
+	committed, err := r.base.AccountBalance(accountID, currency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	staged, err := sumPostings(r.entries, accountID, currency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	return committed.Add(staged)
+}
+
+func (r *stagedLedgerRepository) AccountBalanceAt(accountID, currency string, at, valueDay time.Time) (domain.Money, error) {
+	committed, err := r.base.AccountBalanceAt(accountID, currency, at, valueDay)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	staged, err := sumPostings(entriesBefore(r.entries, at, valueDay), accountID, currency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	return committed.Add(staged)
+}
+
+// stagedHoldRepository, yeni ve güncellenmiş provizyonları commit'e kadar bekletir
+type stagedHoldRepository struct {
+	base  *HoldRepositoryImpl
+	holds map[int64]*domain.Hold
+}
+
+func (r *stagedHoldRepository) Create(h *domain.Hold) error {
+	h.ID = r.base.reserveID()
+	copied := *h
+	r.holds[h.ID] = &copied
+	return nil
+}
+
+func (r *stagedHoldRepository) FindByID(id int64) (*domain.Hold, error) {
+	if h, ok := r.holds[id]; ok {
+		copied := *h
+		return &copied, nil
+	}
+	return r.base.FindByID(id)
+}
+
+func (r *stagedHoldRepository) FindByIDForUpdate(id int64) (*domain.Hold, error) {
+	return r.FindByID(id)
+}
+
+func (r *stagedHoldRepository) Update(h *domain.Hold) error {
+	if _, err := r.FindByID(h.ID); err != nil {
+		return err
+	}
+	copied := *h
+	r.holds[h.ID] = &copied
+	return nil
+}
+
+func (r *stagedHoldRepository) ListByAccount(accountID int64) ([]*domain.Hold, error) {
+	committed, err := r.base.ListByAccount(accountID)
+	return r.merge(committed, err, func(h *domain.Hold) bool { return h.AccountID == accountID })
+}
+
+func (r *stagedHoldRepository) ListExpired(now time.Time) ([]*domain.Hold, error) {
+	committed, err := r.base.ListExpired(now)
+	return r.merge(committed, err, func(h *domain.Hold) bool {
+		return h.Status == domain.HoldAuthorized && h.Expired(now)
+	})
+}
+
+// Commit edilmiş sonuçları bu unit of work'te oluşturulan veya güncellenen provizyonlarla birleştirir
+func (r *stagedHoldRepository) merge(committed []*domain.Hold, err error, match func(h *domain.Hold) bool) ([]*domain.Hold, error) {
+	if err != nil {
+		return nil, err
+	}
+	var result []*domain.Hold
+	for _, h := range committed {
+		if _, ok := r.holds[h.ID]; !ok {
+			result = append(result, h)
+		}
+	}
+	for _, h := range r.holds {
+		if match(h) {
+			copied := *h
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// stagedInterestRepository, yeni tahakkukları ve işlem bağlantılarını commit'e kadar bekletir
+type stagedInterestRepository struct {
+	base    *InterestRepositoryImpl
+	created []*domain.InterestAccrual
+	posted  map[int64]int64 // Tahakkuk ID -> interest işlemi ID
+}
+
+func (r *stagedInterestRepository) CreateAccrual(a *domain.InterestAccrual) error {
+	if r.base.exists(a.AccountID, a.Date) {
+		return domain.ErrAccrualExists
+	}
+	for _, created := range r.created {
+		if created.AccountID == a.AccountID && created.Date.Equal(a.Date) {
+			return domain.ErrAccrualExists
+		}
+	}
+	a.ID = r.base.reserveID()
+	r.created = append(r.created, copyAccrual(a))
+	return nil
+}
+
+func (r *stagedInterestRepository) LastAccrualDate(accountID int64) (*time.Time, error) {
+	accruals, err := r.ListByAccount(accountID, time.Time{}, time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return nil, err
+	}
+	return lastAccrualDate(accruals), nil
+}
+
+func (r *stagedInterestRepository) ListUnposted(accountID int64, before time.Time) ([]*domain.InterestAccrual, error) {
+	committed, err := r.base.ListUnposted(accountID, before)
+	return r.merge(committed, err, func(a *domain.InterestAccrual) bool {
+		return a.AccountID == accountID && a.PostedTransactionID == nil && a.Date.Before(before)
+	})
+}
+
+func (r *stagedInterestRepository) MarkPosted(ids []int64, txID int64) error {
+	for _, id := range ids {
+		r.posted[id] = txID
+	}
+	return nil
+}
+
+func (r *stagedInterestRepository) ListByAccount(accountID int64, from, to time.Time) ([]*domain.InterestAccrual, error) {
+	committed, err := r.base.ListByAccount(accountID, from, to)
+	return r.merge(committed, err, func(a *domain.InterestAccrual) bool {
+		return a.AccountID == accountID && !a.Date.Before(from) && a.Date.Before(to)
+	})
+}
+
+// Commit edilmiş sonuçlara bu unit of work'te oluşturulan tahakkukları ekler ve işlem bağlantılarını uygular;
+// match bağlantı uygulandıktan sonra tekrar değerlendirilir
+func (r *stagedInterestRepository) merge(committed []*domain.InterestAccrual, err error, match func(a *domain.InterestAccrual) bool) ([]*domain.InterestAccrual, error) {
+	if err != nil {
+		return nil, err
+	}
+	var result []*domain.InterestAccrual
+	for _, a := range append(committed, r.created...) {
+		a = copyAccrual(a)
+		if txID, ok := r.posted[a.ID]; ok {
+			a.PostedTransactionID = &txID
+		}
+		if match(a) {
+			result = append(result, a)
+		}
+	}
+	sortAccruals(result)
+	return result, nil
+}
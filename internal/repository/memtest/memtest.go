@@ -0,0 +1,65 @@
+// Package memtest, servis ve API testleri için birbirine bağlanmış in-memory repository'ler sağlar.
+//
+// Servisler (limit, ücret, takvim seçimleri teste göre değiştiği için) her testte ayrıca kurulur; bu paket
+// service paketini içe aktarmaz, böylece service paketinin kendi testleri de kullanabilir.
+package memtest
+
+import (
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/repository"
+	"testing"
+)
+
+// Repositories, aynı unit of work'e bağlı in-memory repository'lerdir
+type Repositories struct {
+	Users          *repository.UserRepositoryImpl
+	Accounts       *repository.AccountRepositoryImpl
+	Balances       *repository.BalanceRepositoryImpl
+	Transactions   *repository.TransactionRepositoryImpl
+	Ledger         *repository.LedgerRepositoryImpl
+	Holds          *repository.HoldRepositoryImpl
+	Interest       *repository.InterestRepositoryImpl
+	InterestRates  *repository.InterestRateRepositoryImpl
+	Schedules      *repository.ScheduleRepositoryImpl
+	Delegations    *repository.DelegationRepositoryImpl
+	LimitOverrides *repository.LimitOverrideRepositoryImpl
+	Sessions       *repository.SessionRepositoryImpl
+	Idempotency    *repository.IdempotencyRepositoryImpl
+	UnitOfWork     *repository.MemoryUnitOfWork
+}
+
+// New, boş repository'leri ve bakiye, işlem, defter, provizyon ve faiz repository'lerini kapsayan unit of work'ü oluşturur
+func New() *Repositories {
+	r := &Repositories{
+		Users:          repository.NewUserRepository(),
+		Accounts:       repository.NewAccountRepository(),
+		Balances:       repository.NewBalanceRepository(),
+		Transactions:   repository.NewTransactionRepository(),
+		Ledger:         repository.NewLedgerRepository(),
+		Holds:          repository.NewHoldRepository(),
+		Interest:       repository.NewInterestRepository(),
+		InterestRates:  repository.NewInterestRateRepository(),
+		Schedules:      repository.NewScheduleRepository(),
+		Delegations:    repository.NewDelegationRepository(),
+		LimitOverrides: repository.NewLimitOverrideRepository(),
+		Sessions:       repository.NewSessionRepository(),
+		Idempotency:    repository.NewIdempotencyRepository(),
+	}
+	r.UnitOfWork = repository.NewMemoryUnitOfWork(r.Balances, r.Transactions, r.Ledger, r.Holds, r.Interest)
+	return r
+}
+
+// NoLimits, limit denetimi yapmayan LimitService'tir
+type NoLimits struct{ domain.LimitService }
+
+func (NoLimits) Check(domain.TransactionRepository, domain.LimitMovement) error { return nil }
+
+// OpenAccount, sahibine verilen para biriminde aktif bir vadesiz hesap açar
+func OpenAccount(t testing.TB, accounts domain.AccountRepository, ownerID int64, currency string) *domain.Account {
+	t.Helper()
+	account := &domain.Account{OwnerID: ownerID, Type: domain.AccountChecking, Currency: currency, Status: domain.AccountActive}
+	if err := accounts.Create(account); err != nil {
+		t.Fatal(err)
+	}
+	return account
+}
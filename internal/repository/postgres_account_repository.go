@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PostgresAccountRepository, AccountRepository arayüzünün PostgreSQL implementasyonudur
+type PostgresAccountRepository struct {
+	db *sql.DB
+}
+
+// Yeni bir PostgresAccountRepository oluşturur
+func NewPostgresAccountRepository(db *sql.DB) *PostgresAccountRepository {
+	return &PostgresAccountRepository{db: db}
+}
+
+const accountColumns = `id, owner_id, parent_id, type, currency, status, nickname, is_default, created_at, updated_at`
+
+// Benzersizlik kısıtı ihlalinin PostgreSQL hata kodu
+const uniqueViolation = "23505"
+
+func (r *PostgresAccountRepository) Create(a *domain.Account) error {
+	now := time.Now()
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = now
+	}
+	if a.UpdatedAt.IsZero() {
+		a.UpdatedAt = a.CreatedAt
+	}
+	err := r.db.QueryRow(
+		`INSERT INTO accounts (owner_id, parent_id, type, currency, status, nickname, is_default, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
+		a.OwnerID, nullableID(a.ParentID), string(a.Type), domain.NormalizeCurrency(a.Currency), string(a.Status),
+		a.Nickname, a.Default, a.CreatedAt, a.UpdatedAt,
+	).Scan(&a.ID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return errDefaultAccountExists
+	}
+	return err
+}
+
+func (r *PostgresAccountRepository) FindByID(id int64) (*domain.Account, error) {
+	a, err := scanAccount(r.db.QueryRow(`SELECT `+accountColumns+` FROM accounts WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAccountNotFound
+	}
+	return a, err
+}
+
+func (r *PostgresAccountRepository) FindDefault(ownerID int64, currency string) (*domain.Account, error) {
+	a, err := scanAccount(r.db.QueryRow(
+		`SELECT `+accountColumns+` FROM accounts
+		 WHERE owner_id = $1 AND currency = $2 AND is_default AND status <> 'closed'`,
+		ownerID, domain.NormalizeCurrency(currency),
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAccountNotFound
+	}
+	return a, err
+}
+
+func (r *PostgresAccountRepository) ListByOwner(ownerID int64) ([]*domain.Account, error) {
+	return r.list(`SELECT `+accountColumns+` FROM accounts WHERE owner_id = $1 ORDER BY id`, ownerID)
+}
+
+func (r *PostgresAccountRepository) ListOpenByType(accountType domain.AccountType) ([]*domain.Account, error) {
+	return r.list(`SELECT `+accountColumns+` FROM accounts WHERE type = $1 AND status <> 'closed' ORDER BY id`, string(accountType))
+}
+
+func (r *PostgresAccountRepository) list(query string, arg interface{}) ([]*domain.Account, error) {
+	rows, err := r.db.Query(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.Account
+	for rows.Next() {
+		a, err := scanAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
+
+func (r *PostgresAccountRepository) Update(a *domain.Account) error {
+	res, err := r.db.Exec(
+		`UPDATE accounts SET nickname = $2, status = $3, updated_at = $4 WHERE id = $1`,
+		a.ID, a.Nickname, string(a.Status), a.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrAccountNotFound
+	}
+	return nil
+}
+
+func scanAccount(row rowScanner) (*domain.Account, error) {
+	var (
+		a                     domain.Account
+		parentID              sql.NullInt64
+		accType, status, curr string
+	)
+	if err := row.Scan(&a.ID, &a.OwnerID, &parentID, &accType, &curr, &status, &a.Nickname, &a.Default, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		a.ParentID = &parentID.Int64
+	}
+	a.Type = domain.AccountType(accType)
+	a.Currency = domain.NormalizeCurrency(curr)
+	a.Status = domain.AccountStatus(status)
+	return &a, nil
+}
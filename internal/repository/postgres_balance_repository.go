@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"gofinancialsystem/internal/domain"
+)
+
+// PostgresBalanceRepository, BalanceRepository arayüzünün PostgreSQL implementasyonudur
+type PostgresBalanceRepository struct {
+	db *sql.DB
+	tx *sql.Tx // Unit of work içinde kullanılıyorsa dış transaction
+}
+
+// Yeni bir PostgresBalanceRepository oluşturur
+func NewPostgresBalanceRepository(db *sql.DB) *PostgresBalanceRepository {
+	return &PostgresBalanceRepository{db: db}
+}
+
+// Hesabın bakiyesini getirir
+func (r *PostgresBalanceRepository) Get(accountID int64) (*domain.Balance, error) {
+	var (
+		amount, held, overdraft int64
+		currency                string
+		bal                     = &domain.Balance{AccountID: accountID}
+	)
+	err := r.querier().QueryRow(
+		`SELECT b.amount, b.held, b.overdraft_limit, b.currency, b.last_updated_at, a.owner_id
+		 FROM balances b JOIN accounts a ON a.id = b.account_id WHERE b.account_id = $1`, accountID,
+	).Scan(&amount, &held, &overdraft, &currency, &bal.LastUpdatedAt, &bal.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAccountNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	bal.SetAmounts(domain.NewMoney(amount, currency), domain.NewMoney(held, currency), domain.NewMoney(overdraft, currency))
+	return bal, nil
+}
+
+// Hesabın bakiyesini tutar kadar değiştirir; bakiye satırı yoksa açılır, kullanılabilir bakiye negatife düşecekse hata döner.
+// Satır kilitlendiği için eşzamanlı güncellemeler birbirini ezmez.
+func (r *PostgresBalanceRepository) Update(accountID int64, amount domain.Money) error {
+	return r.change(accountID, amount, domain.NewMoney(0, amount.Currency))
+}
+
+// Hesabın provizyon tutarını değiştirir; pozitif tutar kullanılabilir bakiyeden ayrılır, negatif tutar serbest bırakılır
+func (r *PostgresBalanceRepository) Hold(accountID int64, amount domain.Money) error {
+	return r.change(accountID, domain.NewMoney(0, amount.Currency), amount)
+}
+
+// Hesabın kredi limitini değiştirir; bakiye satırı yoksa limitin para biriminde açılır
+func (r *PostgresBalanceRepository) SetOverdraftLimit(accountID int64, limit domain.Money) error {
+	res, err := r.querier().Exec(
+		`INSERT INTO balances (account_id, amount, currency, overdraft_limit, last_updated_at)
+		 VALUES ($1, 0, $2, $3, NOW())
+		 ON CONFLICT (account_id) DO UPDATE SET overdraft_limit = EXCLUDED.overdraft_limit, last_updated_at = NOW()
+		 WHERE balances.currency = EXCLUDED.currency`,
+		accountID, domain.NormalizeCurrency(limit.Currency), limit.Amount,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrCurrencyMismatch
+	}
+	return nil
+}
+
+func (r *PostgresBalanceRepository) change(accountID int64, amount, held domain.Money) error {
+	if r.tx != nil {
+		return r.update(r.tx, accountID, amount, held)
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := r.update(tx, accountID, amount, held); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresBalanceRepository) querier() querier {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+func (r *PostgresBalanceRepository) update(tx *sql.Tx, accountID int64, amount, held domain.Money) error {
+	if _, err := tx.Exec(
+		`INSERT INTO balances (account_id, amount, currency, last_updated_at)
+		 VALUES ($1, 0, $2, NOW()) ON CONFLICT (account_id) DO NOTHING`,
+		accountID, domain.NormalizeCurrency(amount.Currency),
+	); err != nil {
+		return err
+	}
+
+	var (
+		current, currentHeld, overdraft int64
+		currency                        string
+	)
+	if err := tx.QueryRow(
+		`SELECT amount, held, overdraft_limit, currency FROM balances WHERE account_id = $1 FOR UPDATE`, accountID,
+	).Scan(&current, &currentHeld, &overdraft, &currency); err != nil {
+		return err
+	}
+	newAmount, newHeld, err := domain.ApplyBalanceChange(domain.NewMoney(current, currency), domain.NewMoney(currentHeld, currency),
+		domain.NewMoney(overdraft, currency), amount, held)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`UPDATE balances SET amount = $2, held = $3, last_updated_at = NOW() WHERE account_id = $1`,
+		accountID, newAmount.Amount, newHeld.Amount,
+	)
+	return err
+}
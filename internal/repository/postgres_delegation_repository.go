@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"time"
+)
+
+// PostgresDelegationRepository, DelegationRepository arayüzünün PostgreSQL implementasyonudur
+type PostgresDelegationRepository struct {
+	db *sql.DB
+}
+
+// Yeni bir PostgresDelegationRepository oluşturur
+func NewPostgresDelegationRepository(db *sql.DB) *PostgresDelegationRepository {
+	return &PostgresDelegationRepository{db: db}
+}
+
+const delegationColumns = `id, owner_id, delegate_id, scope, created_at, expires_at`
+
+func (r *PostgresDelegationRepository) Create(d *domain.Delegation) error {
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now()
+	}
+	return r.db.QueryRow(
+		`INSERT INTO delegations (owner_id, delegate_id, scope, created_at, expires_at)
+		 VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		d.OwnerID, d.DelegateID, string(d.Scope), d.CreatedAt, d.ExpiresAt,
+	).Scan(&d.ID)
+}
+
+func (r *PostgresDelegationRepository) FindByID(id int64) (*domain.Delegation, error) {
+	d, err := scanDelegation(r.db.QueryRow(`SELECT `+delegationColumns+` FROM delegations WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrDelegationNotFound
+	}
+	return d, err
+}
+
+func (r *PostgresDelegationRepository) ListBetween(ownerID, delegateID int64) ([]*domain.Delegation, error) {
+	return r.list(`WHERE owner_id = $1 AND delegate_id = $2`, ownerID, delegateID)
+}
+
+func (r *PostgresDelegationRepository) ListByUser(userID int64) ([]*domain.Delegation, error) {
+	return r.list(`WHERE owner_id = $1 OR delegate_id = $1`, userID)
+}
+
+func (r *PostgresDelegationRepository) Delete(id int64) error {
+	res, err := r.db.Exec(`DELETE FROM delegations WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrDelegationNotFound
+	}
+	return nil
+}
+
+func (r *PostgresDelegationRepository) list(where string, args ...interface{}) ([]*domain.Delegation, error) {
+	rows, err := r.db.Query(`SELECT `+delegationColumns+` FROM delegations `+where+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*domain.Delegation
+	for rows.Next() {
+		d, err := scanDelegation(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, d)
+	}
+	return result, rows.Err()
+}
+
+func scanDelegation(row rowScanner) (*domain.Delegation, error) {
+	var (
+		d         domain.Delegation
+		scope     string
+		expiresAt sql.NullTime
+	)
+	if err := row.Scan(&d.ID, &d.OwnerID, &d.DelegateID, &scope, &d.CreatedAt, &expiresAt); err != nil {
+		return nil, err
+	}
+	d.Scope = domain.DelegationScope(scope)
+	if expiresAt.Valid {
+		d.ExpiresAt = &expiresAt.Time
+	}
+	return &d, nil
+}
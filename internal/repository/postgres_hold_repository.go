@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PostgresHoldRepository, HoldRepository arayüzünün PostgreSQL implementasyonudur
+type PostgresHoldRepository struct {
+	db querier
+}
+
+// Yeni bir PostgresHoldRepository oluşturur
+func NewPostgresHoldRepository(db *sql.DB) *PostgresHoldRepository {
+	return &PostgresHoldRepository{db: db}
+}
+
+const holdColumns = `id, account_id, owner_id, to_account_id, amount, captured_amount, currency, status, transaction_id,
+	expires_at, created_at, updated_at, description, external_reference, tags, metadata`
+
+func (r *PostgresHoldRepository) Create(h *domain.Hold) error {
+	metadata, err := metadataJSON(h.Metadata)
+	if err != nil {
+		return err
+	}
+	return r.db.QueryRow(
+		`INSERT INTO holds (account_id, owner_id, to_account_id, amount, captured_amount, currency, status, transaction_id,
+		 expires_at, created_at, updated_at, description, external_reference, tags, metadata)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id`,
+		h.AccountID, h.OwnerID, nullableID(h.ToAccountID), h.Amount.Amount, h.CapturedAmount.Amount, domain.NormalizeCurrency(h.Amount.Currency),
+		string(h.Status), nullableID(h.TransactionID), h.ExpiresAt, h.CreatedAt, h.UpdatedAt,
+		h.Description, h.ExternalReference, pq.Array(h.Tags), metadata,
+	).Scan(&h.ID)
+}
+
+func (r *PostgresHoldRepository) FindByID(id int64) (*domain.Hold, error) {
+	return r.find(`SELECT `+holdColumns+` FROM holds WHERE id = $1`, id)
+}
+
+func (r *PostgresHoldRepository) FindByIDForUpdate(id int64) (*domain.Hold, error) {
+	return r.find(`SELECT `+holdColumns+` FROM holds WHERE id = $1 FOR UPDATE`, id)
+}
+
+func (r *PostgresHoldRepository) find(query string, id int64) (*domain.Hold, error) {
+	h, err := scanHold(r.db.QueryRow(query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrHoldNotFound
+	}
+	return h, err
+}
+
+func (r *PostgresHoldRepository) Update(h *domain.Hold) error {
+	res, err := r.db.Exec(
+		`UPDATE holds SET status = $2, captured_amount = $3, transaction_id = $4, updated_at = $5 WHERE id = $1`,
+		h.ID, string(h.Status), h.CapturedAmount.Amount, nullableID(h.TransactionID), h.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrHoldNotFound
+	}
+	return nil
+}
+
+func (r *PostgresHoldRepository) ListByAccount(accountID int64) ([]*domain.Hold, error) {
+	return r.list(`SELECT `+holdColumns+` FROM holds WHERE account_id = $1 ORDER BY id`, accountID)
+}
+
+func (r *PostgresHoldRepository) ListExpired(now time.Time) ([]*domain.Hold, error) {
+	return r.list(`SELECT `+holdColumns+` FROM holds WHERE status = 'authorized' AND expires_at <= $1 ORDER BY id`, now)
+}
+
+func (r *PostgresHoldRepository) list(query string, arg interface{}) ([]*domain.Hold, error) {
+	rows, err := r.db.Query(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.Hold
+	for rows.Next() {
+		h, err := scanHold(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, h)
+	}
+	return result, rows.Err()
+}
+
+func scanHold(row rowScanner) (*domain.Hold, error) {
+	var (
+		h                domain.Hold
+		toAccount, txID  sql.NullInt64
+		amount, captured int64
+		currency, status string
+		tags             pq.StringArray
+		metadata         []byte
+	)
+	if err := row.Scan(&h.ID, &h.AccountID, &h.OwnerID, &toAccount, &amount, &captured, &currency, &status, &txID,
+		&h.ExpiresAt, &h.CreatedAt, &h.UpdatedAt, &h.Description, &h.ExternalReference, &tags, &metadata); err != nil {
+		return nil, err
+	}
+	if toAccount.Valid {
+		h.ToAccountID = &toAccount.Int64
+	}
+	if txID.Valid {
+		h.TransactionID = &txID.Int64
+	}
+	currency = domain.NormalizeCurrency(currency)
+	h.Amount = domain.NewMoney(amount, currency)
+	h.CapturedAmount = domain.NewMoney(captured, currency)
+	h.Status = domain.HoldStatus(status)
+	if len(tags) > 0 {
+		h.Tags = tags
+	}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &h.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	return &h, nil
+}
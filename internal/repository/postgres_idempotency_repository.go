@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"time"
+)
+
+// PostgresIdempotencyRepository, IdempotencyRepository arayüzünün PostgreSQL implementasyonudur
+type PostgresIdempotencyRepository struct {
+	db *sql.DB
+}
+
+// Yeni bir PostgresIdempotencyRepository oluşturur
+func NewPostgresIdempotencyRepository(db *sql.DB) *PostgresIdempotencyRepository {
+	return &PostgresIdempotencyRepository{db: db}
+}
+
+func (r *PostgresIdempotencyRepository) Reserve(rec *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	// Süresi dolmuş bir kayıt varsa yerine yenisi yazılır; PRIMARY KEY eşzamanlı rezervasyonları engeller
+	res, err := r.db.Exec(
+		`INSERT INTO idempotency_keys (key, fingerprint, created_at, expires_at)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (key) DO UPDATE SET
+		     fingerprint = EXCLUDED.fingerprint, status_code = NULL, content_type = NULL,
+		     response_body = NULL, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		 WHERE idempotency_keys.expires_at <= EXCLUDED.created_at`,
+		rec.Key, rec.Fingerprint, rec.CreatedAt, rec.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 1 {
+		return nil, nil
+	}
+
+	existing := &domain.IdempotencyRecord{Key: rec.Key}
+	var statusCode sql.NullInt64
+	var contentType sql.NullString
+	err = r.db.QueryRow(
+		`SELECT fingerprint, status_code, content_type, response_body, created_at, expires_at
+		 FROM idempotency_keys WHERE key = $1`, rec.Key,
+	).Scan(&existing.Fingerprint, &statusCode, &contentType, &existing.Body, &existing.CreatedAt, &existing.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Kayıt bu arada serbest bırakıldı; istemci tekrar denemeli
+		return nil, errors.New("idempotency anahtarı rezerve edilemedi")
+	}
+	if err != nil {
+		return nil, err
+	}
+	existing.StatusCode = int(statusCode.Int64)
+	existing.ContentType = contentType.String
+	return existing, nil
+}
+
+func (r *PostgresIdempotencyRepository) Complete(key string, statusCode int, contentType string, body []byte) error {
+	_, err := r.db.Exec(
+		`UPDATE idempotency_keys SET status_code = $2, content_type = $3, response_body = $4 WHERE key = $1`,
+		key, statusCode, contentType, body,
+	)
+	return err
+}
+
+func (r *PostgresIdempotencyRepository) Release(key string) error {
+	_, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE key = $1 AND status_code IS NULL`, key)
+	return err
+}
+
+func (r *PostgresIdempotencyRepository) DeleteExpired(now time.Time) error {
+	_, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	return err
+}
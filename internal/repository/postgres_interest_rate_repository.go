@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"database/sql"
+	"gofinancialsystem/internal/domain"
+)
+
+// PostgresInterestRateRepository, InterestRateRepository arayüzünün PostgreSQL implementasyonudur
+type PostgresInterestRateRepository struct {
+	db *sql.DB
+}
+
+// Yeni bir PostgresInterestRateRepository oluşturur
+func NewPostgresInterestRateRepository(db *sql.DB) *PostgresInterestRateRepository {
+	return &PostgresInterestRateRepository{db: db}
+}
+
+func (r *PostgresInterestRateRepository) Save(rate domain.InterestRate) error {
+	_, err := r.db.Exec(
+		`INSERT INTO interest_rates (account_type, currency, effective_from, annual_rate_bps, overdraft_rate_bps, mode, day_count)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (account_type, currency, effective_from) DO UPDATE SET
+		     annual_rate_bps = EXCLUDED.annual_rate_bps, overdraft_rate_bps = EXCLUDED.overdraft_rate_bps,
+		     mode = EXCLUDED.mode, day_count = EXCLUDED.day_count, updated_at = NOW()`,
+		string(rate.AccountType), rate.Currency, rate.EffectiveFrom, rate.AnnualRateBps, rate.OverdraftRateBps,
+		string(rate.Mode), string(rate.DayCount),
+	)
+	return err
+}
+
+func (r *PostgresInterestRateRepository) List() ([]domain.InterestRate, error) {
+	rows, err := r.db.Query(
+		`SELECT account_type, currency, effective_from, annual_rate_bps, overdraft_rate_bps, mode, day_count
+		 FROM interest_rates ORDER BY account_type, currency, effective_from`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.InterestRate{}
+	for rows.Next() {
+		var (
+			rate                        domain.InterestRate
+			accountType, mode, dayCount string
+		)
+		if err := rows.Scan(&accountType, &rate.Currency, &rate.EffectiveFrom, &rate.AnnualRateBps, &rate.OverdraftRateBps, &mode, &dayCount); err != nil {
+			return nil, err
+		}
+		rate.AccountType, rate.Mode, rate.DayCount = domain.AccountType(accountType), domain.InterestMode(mode), domain.DayCount(dayCount)
+		rate.EffectiveFrom = domain.StartOfDay(rate.EffectiveFrom)
+		result = append(result, rate)
+	}
+	return result, rows.Err()
+}
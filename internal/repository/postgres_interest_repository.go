@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PostgresInterestRepository, InterestRepository arayüzünün PostgreSQL implementasyonudur
+type PostgresInterestRepository struct {
+	db querier
+}
+
+// Yeni bir PostgresInterestRepository oluşturur
+func NewPostgresInterestRepository(db *sql.DB) *PostgresInterestRepository {
+	return &PostgresInterestRepository{db: db}
+}
+
+const accrualColumns = `id, account_id, accrual_date, balance, currency, annual_rate_bps, mode, day_count,
+	accrued_micros, posted_transaction_id, created_at`
+
+// (account_id, accrual_date) tekil olduğu için aynı gün ikinci kez tahakkuk edilemez. Çakışma
+// transaction'ı bozmasın diye ON CONFLICT DO NOTHING kullanılır.
+func (r *PostgresInterestRepository) CreateAccrual(a *domain.InterestAccrual) error {
+	err := r.db.QueryRow(
+		`INSERT INTO interest_accruals (account_id, accrual_date, balance, currency, annual_rate_bps, mode, day_count, accrued_micros, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 ON CONFLICT (account_id, accrual_date) DO NOTHING RETURNING id`,
+		a.AccountID, a.Date, a.Balance.Amount, domain.NormalizeCurrency(a.Balance.Currency), a.AnnualRateBps,
+		string(a.Mode), string(a.DayCount), a.AccruedMicros, a.CreatedAt,
+	).Scan(&a.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrAccrualExists
+	}
+	return err
+}
+
+func (r *PostgresInterestRepository) LastAccrualDate(accountID int64) (*time.Time, error) {
+	var last sql.NullTime
+	if err := r.db.QueryRow(`SELECT MAX(accrual_date) FROM interest_accruals WHERE account_id = $1`, accountID).Scan(&last); err != nil {
+		return nil, err
+	}
+	if !last.Valid {
+		return nil, nil
+	}
+	date := domain.StartOfDay(last.Time)
+	return &date, nil
+}
+
+// Tahakkuklar aynı transaction'da bakiyeye eklenip işaretleneceği için kilitlenir
+func (r *PostgresInterestRepository) ListUnposted(accountID int64, before time.Time) ([]*domain.InterestAccrual, error) {
+	return r.list(`SELECT `+accrualColumns+` FROM interest_accruals
+		WHERE account_id = $1 AND posted_transaction_id IS NULL AND accrual_date < $2
+		ORDER BY accrual_date FOR UPDATE`, accountID, before)
+}
+
+func (r *PostgresInterestRepository) MarkPosted(ids []int64, txID int64) error {
+	_, err := r.db.Exec(`UPDATE interest_accruals SET posted_transaction_id = $2 WHERE id = ANY($1)`, pq.Array(ids), txID)
+	return err
+}
+
+func (r *PostgresInterestRepository) ListByAccount(accountID int64, from, to time.Time) ([]*domain.InterestAccrual, error) {
+	return r.list(`SELECT `+accrualColumns+` FROM interest_accruals
+		WHERE account_id = $1 AND accrual_date >= $2 AND accrual_date < $3 ORDER BY accrual_date`, accountID, from, to)
+}
+
+func (r *PostgresInterestRepository) list(query string, args ...interface{}) ([]*domain.InterestAccrual, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.InterestAccrual
+	for rows.Next() {
+		var (
+			a        domain.InterestAccrual
+			balance  int64
+			currency string
+			mode     string
+			dayCount string
+			postedTx sql.NullInt64
+		)
+		if err := rows.Scan(&a.ID, &a.AccountID, &a.Date, &balance, &currency, &a.AnnualRateBps, &mode, &dayCount,
+			&a.AccruedMicros, &postedTx, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		a.Date = domain.StartOfDay(a.Date)
+		a.Balance = domain.NewMoney(balance, currency)
+		a.Mode = domain.InterestMode(mode)
+		a.DayCount = domain.DayCount(dayCount)
+		if postedTx.Valid {
+			a.PostedTransactionID = &postedTx.Int64
+		}
+		result = append(result, &a)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"database/sql"
+	"gofinancialsystem/internal/domain"
+	"time"
+)
+
+// PostgresLedgerRepository, LedgerRepository arayüzünün PostgreSQL implementasyonudur
+type PostgresLedgerRepository struct {
+	db querier
+}
+
+// Yeni bir PostgresLedgerRepository oluşturur
+func NewPostgresLedgerRepository(db *sql.DB) *PostgresLedgerRepository {
+	return &PostgresLedgerRepository{db: db}
+}
+
+// Yevmiye kaydını ve postinglerini ekler. Tek bir tablo yazımı olmadığı için
+// unit of work dışında çağrıldığında kayıt yarım kalabilir; servisler her zaman UnitOfWork kullanır.
+func (r *PostgresLedgerRepository) Append(entry *domain.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if entry.ValueDate.IsZero() {
+		entry.ValueDate = domain.StartOfDay(entry.CreatedAt)
+	}
+	if err := r.db.QueryRow(
+		`INSERT INTO journal_entries (transaction_id, description, created_at, value_date)
+		 VALUES ($1, $2, $3, $4) RETURNING id`,
+		nullableID(entry.TransactionID), entry.Description, entry.CreatedAt, entry.ValueDate,
+	).Scan(&entry.ID); err != nil {
+		return err
+	}
+	for _, p := range entry.Postings {
+		if _, err := r.db.Exec(
+			`INSERT INTO postings (entry_id, account_id, amount, currency) VALUES ($1, $2, $3, $4)`,
+			entry.ID, p.AccountID, p.Amount.Amount, domain.NormalizeCurrency(p.Amount.Currency),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bir transaction'a ait yevmiye kayıtlarını listeler
+func (r *PostgresLedgerRepository) ListByTransaction(txID int64) ([]*domain.JournalEntry, error) {
+	return r.list(`WHERE e.transaction_id = $1`, txID)
+}
+
+// Bir hesaba posting içeren yevmiye kayıtlarını listeler
+func (r *PostgresLedgerRepository) ListByAccount(accountID string) ([]*domain.JournalEntry, error) {
+	return r.list(`WHERE e.id IN (SELECT entry_id FROM postings WHERE account_id = $1)`, accountID)
+}
+
+// Hesabın bakiyesini postingleri toplayarak hesaplar
+func (r *PostgresLedgerRepository) AccountBalance(accountID, currency string) (domain.Money, error) {
+	currency = domain.NormalizeCurrency(currency)
+	var total int64
+	err := r.db.QueryRow(
+		`SELECT COALESCE(SUM(amount), 0) FROM postings WHERE account_id = $1 AND currency = $2`,
+		accountID, currency,
+	).Scan(&total)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	return domain.NewMoney(total, currency), nil
+}
+
+// Hesabın at anından önce oluşturulmuş ve valör tarihi valueDay veya öncesi olan kayıtlarla bakiyesini hesaplar
+func (r *PostgresLedgerRepository) AccountBalanceAt(accountID, currency string, at, valueDay time.Time) (domain.Money, error) {
+	currency = domain.NormalizeCurrency(currency)
+	var total int64
+	err := r.db.QueryRow(
+		`SELECT COALESCE(SUM(p.amount), 0) FROM postings p JOIN journal_entries e ON e.id = p.entry_id
+		 WHERE p.account_id = $1 AND p.currency = $2 AND e.created_at < $3 AND e.value_date <= $4`,
+		accountID, currency, at, valueDay,
+	).Scan(&total)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	return domain.NewMoney(total, currency), nil
+}
+
+func (r *PostgresLedgerRepository) list(where string, arg interface{}) ([]*domain.JournalEntry, error) {
+	rows, err := r.db.Query(
+		`SELECT e.id, e.transaction_id, e.description, e.created_at, e.value_date, p.account_id, p.amount, p.currency
+		 FROM journal_entries e JOIN postings p ON p.entry_id = e.id `+where+`
+		 ORDER BY e.id, p.id`,
+		arg,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.JournalEntry
+	var current *domain.JournalEntry
+	for rows.Next() {
+		var (
+			id          int64
+			txID        sql.NullInt64
+			description string
+			createdAt   time.Time
+			valueDate   time.Time
+			posting     domain.Posting
+			amount      int64
+			currency    string
+		)
+		if err := rows.Scan(&id, &txID, &description, &createdAt, &valueDate, &posting.AccountID, &amount, &currency); err != nil {
+			return nil, err
+		}
+		if current == nil || current.ID != id {
+			current = &domain.JournalEntry{ID: id, Description: description, CreatedAt: createdAt, ValueDate: domain.StartOfDay(valueDate)}
+			if txID.Valid {
+				current.TransactionID = &txID.Int64
+			}
+			result = append(result, current)
+		}
+		posting.Amount = domain.NewMoney(amount, currency)
+		current.Postings = append(current.Postings, posting)
+	}
+	return result, rows.Err()
+}
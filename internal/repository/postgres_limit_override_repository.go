@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"gofinancialsystem/internal/domain"
+)
+
+// PostgresLimitOverrideRepository, LimitOverrideRepository arayüzünün PostgreSQL implementasyonudur.
+// Kurallar JSONB olarak saklanır; override her zaman bir bütün olarak okunup yazılır.
+type PostgresLimitOverrideRepository struct {
+	db *sql.DB
+}
+
+// Yeni bir PostgresLimitOverrideRepository oluşturur
+func NewPostgresLimitOverrideRepository(db *sql.DB) *PostgresLimitOverrideRepository {
+	return &PostgresLimitOverrideRepository{db: db}
+}
+
+func (r *PostgresLimitOverrideRepository) Find(scope domain.LimitScope, subject string) (*domain.LimitOverride, error) {
+	o, err := scanLimitOverride(r.db.QueryRow(
+		`SELECT scope, subject, rules, updated_at FROM limit_overrides WHERE scope = $1 AND subject = $2`,
+		string(scope), subject,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrLimitOverrideNotFound
+	}
+	return o, err
+}
+
+func (r *PostgresLimitOverrideRepository) Save(o *domain.LimitOverride) error {
+	rules, err := json.Marshal(o.Rules)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(
+		`INSERT INTO limit_overrides (scope, subject, rules, updated_at) VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (scope, subject) DO UPDATE SET rules = EXCLUDED.rules, updated_at = EXCLUDED.updated_at`,
+		string(o.Scope), o.Subject, string(rules), o.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PostgresLimitOverrideRepository) Delete(scope domain.LimitScope, subject string) error {
+	res, err := r.db.Exec(`DELETE FROM limit_overrides WHERE scope = $1 AND subject = $2`, string(scope), subject)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrLimitOverrideNotFound
+	}
+	return nil
+}
+
+func (r *PostgresLimitOverrideRepository) List() ([]*domain.LimitOverride, error) {
+	rows, err := r.db.Query(`SELECT scope, subject, rules, updated_at FROM limit_overrides ORDER BY scope, subject`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*domain.LimitOverride{}
+	for rows.Next() {
+		o, err := scanLimitOverride(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, o)
+	}
+	return result, rows.Err()
+}
+
+func scanLimitOverride(row rowScanner) (*domain.LimitOverride, error) {
+	var (
+		o     domain.LimitOverride
+		scope string
+		rules []byte
+	)
+	if err := row.Scan(&scope, &o.Subject, &rules, &o.UpdatedAt); err != nil {
+		return nil, err
+	}
+	o.Scope = domain.LimitScope(scope)
+	if err := json.Unmarshal(rules, &o.Rules); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
@@ -0,0 +1,487 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"gofinancialsystem/internal/db/dbtest"
+	"gofinancialsystem/internal/domain"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(dbtest.Main(m))
+}
+
+// Testlerde kullanılan sabit zaman; TIMESTAMP sütunları mikro saniye hassasiyetindedir
+var testTime = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+func createTestUser(t *testing.T, db *sql.DB, username string) *domain.User {
+	t.Helper()
+	user := &domain.User{Username: username, Email: username + "@example.com", Password: "hash", Role: "user"}
+	if err := NewPostgresUserRepository(db).Create(user); err != nil {
+		t.Fatalf("kullanıcı oluşturulamadı: %v", err)
+	}
+	return user
+}
+
+func createTestAccount(t *testing.T, db *sql.DB, ownerID int64, currency string) *domain.Account {
+	t.Helper()
+	account := &domain.Account{OwnerID: ownerID, Type: domain.AccountChecking, Currency: currency, Status: domain.AccountActive}
+	if err := NewPostgresAccountRepository(db).Create(account); err != nil {
+		t.Fatalf("hesap oluşturulamadı: %v", err)
+	}
+	return account
+}
+
+func createTestDeposit(t *testing.T, repo domain.TransactionRepository, userID int64, amount int64, createdAt time.Time) *domain.Transaction {
+	t.Helper()
+	tx := &domain.Transaction{ToUserID: &userID, Amount: domain.NewMoney(amount, "TRY"), Type: domain.TransactionDeposit,
+		Status: domain.TransactionCompleted, CreatedAt: createdAt}
+	if err := repo.Create(tx); err != nil {
+		t.Fatalf("işlem oluşturulamadı: %v", err)
+	}
+	return tx
+}
+
+func tableExists(t *testing.T, db *sql.DB, table string) bool {
+	t.Helper()
+	var name sql.NullString
+	if err := db.QueryRow(`SELECT to_regclass($1)::TEXT`, table).Scan(&name); err != nil {
+		t.Fatalf("tablo sorgulanamadı: %v", err)
+	}
+	return name.Valid
+}
+
+func TestPostgresMigrationsUpDown(t *testing.T) {
+	dbURL := dbtest.Empty(t)
+	m := dbtest.Migrator(t, dbURL)
+	if err := m.Up(); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, table := range []string{"users", "accounts", "balances", "transactions", "journal_entries", "holds", "scheduled_transfer_runs", "interest_rates"} {
+		if !tableExists(t, db, table) {
+			t.Fatalf("up sonrası %s tablosu yok", table)
+		}
+	}
+
+	// Veri taşıyan migration'ların down adımları dolu tablolarda da çalışmalı
+	user := createTestUser(t, db, "alice")
+	account := createTestAccount(t, db, user.ID, "TRY")
+	if err := NewPostgresBalanceRepository(db).Update(account.ID, domain.NewMoney(1000, "TRY")); err != nil {
+		t.Fatal(err)
+	}
+	createTestDeposit(t, NewPostgresTransactionRepository(db), user.ID, 1000, testTime)
+
+	if err := m.Down(); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	for _, table := range []string{"users", "transactions", "balances"} {
+		if tableExists(t, db, table) {
+			t.Fatalf("down sonrası %s tablosu hâlâ var", table)
+		}
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("down sonrası tekrar up: %v", err)
+	}
+}
+
+func TestPostgresUserRepository(t *testing.T) {
+	db := dbtest.New(t)
+	repo := NewPostgresUserRepository(db)
+
+	user := createTestUser(t, db, "alice")
+	found, err := repo.FindByID(user.ID)
+	if err != nil || found.Username != "alice" || found.Email != "alice@example.com" || found.Locale != "" {
+		t.Fatalf("FindByID = %+v, %v", found, err)
+	}
+	if err := repo.UpdateLocale(user.ID, "en"); err != nil {
+		t.Fatal(err)
+	}
+	if found, err = repo.FindByUsername("alice"); err != nil || found.ID != user.ID || found.Locale != "en" {
+		t.Fatalf("FindByUsername = %+v, %v", found, err)
+	}
+	if err := repo.Create(&domain.User{Username: "alice", Email: "other@example.com", Password: "hash", Role: "user"}); err == nil {
+		t.Fatal("aynı kullanıcı adıyla ikinci kayıt hata vermeli")
+	}
+	if _, err := repo.FindByID(user.ID + 100); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("olmayan kullanıcı: %v, beklenen ErrUserNotFound", err)
+	}
+	if err := repo.UpdateLocale(user.ID+100, "tr"); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("olmayan kullanıcının dili: %v, beklenen ErrUserNotFound", err)
+	}
+
+	// Oturumu ve yetki devri olan kullanıcı silinir; hesabı olan kullanıcı silinmez ve oturumları kalır
+	bob := createTestUser(t, db, "bob")
+	sessions := NewPostgresSessionRepository(db)
+	for _, owner := range []*domain.User{user, bob} {
+		session := &domain.Session{ID: fmt.Sprintf("s-%d", owner.ID), UserID: owner.ID, CreatedAt: testTime, ExpiresAt: testTime.Add(time.Hour)}
+		token := &domain.RefreshToken{Hash: fmt.Sprintf("%064d", owner.ID), SessionID: session.ID, CreatedAt: testTime, ExpiresAt: testTime.Add(time.Hour)}
+		if err := sessions.CreateSession(session, token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := NewPostgresDelegationRepository(db).Create(&domain.Delegation{OwnerID: bob.ID, DelegateID: user.ID, Scope: domain.DelegationRead}); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewPostgresAccountRepository(db).Create(&domain.Account{OwnerID: user.ID, Type: domain.AccountChecking, Currency: "TRY", Status: domain.AccountActive}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Delete(user.ID); !errors.Is(err, domain.ErrUserHasRecords) {
+		t.Fatalf("hesabı olan kullanıcı: %v, beklenen ErrUserHasRecords", err)
+	}
+	if _, err := sessions.FindSession(fmt.Sprintf("s-%d", user.ID)); err != nil {
+		t.Fatalf("silinemeyen kullanıcının oturumu: %v", err)
+	}
+	if err := repo.Delete(bob.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindByID(bob.ID); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("silinen kullanıcı: %v, beklenen ErrUserNotFound", err)
+	}
+	if err := repo.Delete(bob.ID); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("olmayan kullanıcıyı silme: %v, beklenen ErrUserNotFound", err)
+	}
+}
+
+func TestPostgresInterestRateRepository(t *testing.T) {
+	db := dbtest.New(t)
+	repo := NewPostgresInterestRateRepository(db)
+
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rate := domain.InterestRate{AccountType: domain.AccountSavings, Currency: "TRY", AnnualRateBps: 3650, OverdraftRateBps: 4000,
+		Mode: domain.InterestCompound, DayCount: domain.DayCount30360, EffectiveFrom: march}
+	earlier := domain.InterestRate{AccountType: domain.AccountSavings, Currency: "TRY", AnnualRateBps: 1000,
+		Mode: domain.InterestSimple, DayCount: domain.DayCountACT365, EffectiveFrom: march.AddDate(0, -1, 0)}
+	for _, r := range []domain.InterestRate{rate, earlier} {
+		if err := repo.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rates, err := repo.List()
+	if err != nil || len(rates) != 2 || rates[0] != earlier || rates[1] != rate {
+		t.Fatalf("List = %+v, %v", rates, err)
+	}
+
+	// Aynı hesap türü, para birimi ve gün için kaydedilen oran öncekinin yerine geçer
+	rate.AnnualRateBps = 4200
+	if err := repo.Save(rate); err != nil {
+		t.Fatal(err)
+	}
+	if rates, err = repo.List(); err != nil || len(rates) != 2 || rates[1] != rate {
+		t.Fatalf("güncelleme sonrası List = %+v, %v", rates, err)
+	}
+}
+
+func TestPostgresBalanceRepository(t *testing.T) {
+	db := dbtest.New(t)
+	repo := NewPostgresBalanceRepository(db)
+	user := createTestUser(t, db, "alice")
+	account := createTestAccount(t, db, user.ID, "TRY")
+
+	if _, err := repo.Get(account.ID); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Fatalf("hareketsiz hesap: %v, beklenen ErrAccountNotFound", err)
+	}
+	if err := repo.Update(account.ID, domain.NewMoney(10000, "TRY")); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Hold(account.ID, domain.NewMoney(3000, "TRY")); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Update(account.ID, domain.NewMoney(-8000, "TRY")); !errors.Is(err, domain.ErrInsufficientFunds) {
+		t.Fatalf("provizyonlu bakiyeden fazla çekim: %v, beklenen ErrInsufficientFunds", err)
+	}
+	if err := repo.SetOverdraftLimit(account.ID, domain.NewMoney(1000, "USD")); !errors.Is(err, domain.ErrCurrencyMismatch) {
+		t.Fatalf("farklı para biriminde limit: %v, beklenen ErrCurrencyMismatch", err)
+	}
+	if err := repo.SetOverdraftLimit(account.ID, domain.NewMoney(1000, "TRY")); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Update(account.ID, domain.NewMoney(-7500, "TRY")); err != nil {
+		t.Fatalf("kredi limiti içinde çekim: %v", err)
+	}
+
+	balance, err := repo.Get(account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.UserID != user.ID || balance.Amount.Amount != 2500 || balance.Held.Amount != 3000 ||
+		balance.Available.Amount != -500 || balance.OverdraftLimit.Amount != 1000 {
+		t.Fatalf("bakiye = %+v", balance)
+	}
+}
+
+// Eşzamanlı bakiye güncellemeleri satır kilidiyle sıraya girer; hiçbiri diğerini ezmez
+func TestPostgresBalanceConcurrentUpdates(t *testing.T) {
+	db := dbtest.New(t)
+	repo := NewPostgresBalanceRepository(db)
+	user := createTestUser(t, db, "alice")
+	account := createTestAccount(t, db, user.ID, "TRY")
+
+	const workers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repo.Update(account.ID, domain.NewMoney(100, "TRY"))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	balance, err := repo.Get(account.ID)
+	if err != nil || balance.Amount.Amount != workers*100 {
+		t.Fatalf("bakiye = %+v, %v; beklenen %d", balance, err, workers*100)
+	}
+}
+
+func TestPostgresTransactionRepository(t *testing.T) {
+	db := dbtest.New(t)
+	repo := NewPostgresTransactionRepository(db)
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	from := createTestAccount(t, db, alice.ID, "EUR")
+	to := createTestAccount(t, db, bob.ID, "TRY")
+
+	tx := &domain.Transaction{
+		FromUserID: &alice.ID, ToUserID: &bob.ID, FromAccountID: &from.ID, ToAccountID: &to.ID,
+		Amount: domain.NewMoney(10000, "EUR"), Type: domain.TransactionTransfer, Status: domain.TransactionPending,
+		CreatedAt: testTime, BookingDate: domain.StartOfDay(testTime), ValueDate: domain.StartOfDay(testTime).AddDate(0, 0, 1),
+		Conversion: &domain.FXConversion{QuoteID: "q-1", Rate: "32.45", MidRate: "32.5", SpreadBps: 15, RateVersion: testTime,
+			Target: domain.NewMoney(324500, "TRY")},
+		TransactionDetails: domain.TransactionDetails{Description: "kira", ExternalReference: "ref-1", Tags: []string{"ev"},
+			Metadata: map[string]string{"invoice": "42"}},
+	}
+	if err := repo.Create(tx); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := repo.FindByID(tx.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *found.FromAccountID != from.ID || *found.ToAccountID != to.ID || found.Amount != tx.Amount || !found.CreatedAt.Equal(testTime) ||
+		!found.ValueDate.Equal(tx.ValueDate) || found.Description != "kira" || found.Tags[0] != "ev" || found.Metadata["invoice"] != "42" {
+		t.Fatalf("FindByID = %+v", found)
+	}
+	if c := found.Conversion; c == nil || c.Rate != "32.45" || c.MidRate != "32.5" || c.Target.Amount != 324500 || c.Target.Currency != "TRY" {
+		t.Fatalf("döviz çevirisi = %+v", found.Conversion)
+	}
+
+	if err := repo.UpdateStatus(tx.ID, domain.TransactionCompleted); err != nil {
+		t.Fatal(err)
+	}
+	if found, _ = repo.FindByIDForUpdate(tx.ID); found.Status != domain.TransactionCompleted {
+		t.Fatalf("durum = %s, beklenen completed", found.Status)
+	}
+	if err := repo.UpdateStatus(tx.ID+100, domain.TransactionFailed); !errors.Is(err, domain.ErrTransactionNotFound) {
+		t.Fatalf("olmayan işlemin durumu: %v, beklenen ErrTransactionNotFound", err)
+	}
+	if _, err := repo.FindByID(tx.ID + 100); !errors.Is(err, domain.ErrTransactionNotFound) {
+		t.Fatalf("olmayan işlem: %v, beklenen ErrTransactionNotFound", err)
+	}
+
+	refund := &domain.Transaction{FromUserID: &bob.ID, ToUserID: &alice.ID, Amount: domain.NewMoney(1000, "EUR"),
+		Type: domain.TransactionRefund, Status: domain.TransactionCompleted, OriginalID: &tx.ID, CreatedAt: testTime.Add(time.Minute)}
+	if err := repo.Create(refund); err != nil {
+		t.Fatal(err)
+	}
+	linked, err := repo.ListByOriginal(tx.ID)
+	if err != nil || len(linked) != 1 || linked[0].ID != refund.ID {
+		t.Fatalf("ListByOriginal = %v, %v", linked, err)
+	}
+	if list, err := repo.ListByUser(bob.ID); err != nil || len(list) != 2 || list[0].ID != tx.ID {
+		t.Fatalf("ListByUser = %v, %v", list, err)
+	}
+	count, err := repo.Count(domain.TransactionFilter{UserID: alice.ID, Direction: domain.DirectionOut, Types: []domain.TransactionType{domain.TransactionTransfer}})
+	if err != nil || count != 1 {
+		t.Fatalf("Count = %d, %v; beklenen 1", count, err)
+	}
+	sum, err := repo.Sum(domain.TransactionFilter{UserID: alice.ID, Direction: domain.DirectionIn, Currency: "EUR"})
+	if err != nil || sum.Amount != 1000 {
+		t.Fatalf("Sum = %v, %v; beklenen 1000", sum, err)
+	}
+	// Kendi hesapları arasındaki transfer ExcludeSelf ile sayılmaz
+	self := &domain.Transaction{FromUserID: &alice.ID, ToUserID: &alice.ID, Amount: domain.NewMoney(500, "EUR"),
+		Type: domain.TransactionTransfer, Status: domain.TransactionCompleted, CreatedAt: testTime}
+	if err := repo.Create(self); err != nil {
+		t.Fatal(err)
+	}
+	transfers := domain.TransactionFilter{UserID: alice.ID, Direction: domain.DirectionOut, Types: []domain.TransactionType{domain.TransactionTransfer}}
+	if count, err := repo.Count(transfers); err != nil || count != 2 {
+		t.Fatalf("Count = %d, %v; beklenen 2", count, err)
+	}
+	transfers.ExcludeSelf = true
+	if count, err := repo.Count(transfers); err != nil || count != 1 {
+		t.Fatalf("ExcludeSelf ile Count = %d, %v; beklenen 1", count, err)
+	}
+
+	// Çevirili işlemin iadeleri kur teklifi olmadan ters kurla kaydedilir; birden fazla iade olabilir
+	for i := 0; i < 2; i++ {
+		reverse, err := tx.Conversion.Reverse(domain.NewMoney(1000, "EUR"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		refund := &domain.Transaction{FromUserID: &bob.ID, ToUserID: &alice.ID, FromAccountID: &to.ID, ToAccountID: &from.ID,
+			Amount: domain.NewMoney(32450, "TRY"), Type: domain.TransactionRefund, Status: domain.TransactionCompleted, OriginalID: &tx.ID,
+			CreatedAt: testTime.Add(2 * time.Minute), Conversion: &reverse}
+		if err := repo.Create(refund); err != nil {
+			t.Fatal(err)
+		}
+		found, err := repo.FindByID(refund.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := found.Conversion; c == nil || c.QuoteID != "" || c.Rate != reverse.Rate || c.Target != reverse.Target {
+			t.Fatalf("ters çeviri = %+v, beklenen %+v", found.Conversion, reverse)
+		}
+	}
+}
+
+// Keyset sayfalama aynı created_at'e sahip işlemleri ID ile ayırır; sayfalar arasında kayıt atlanmaz veya tekrarlanmaz
+func TestPostgresTransactionSearchKeyset(t *testing.T) {
+	db := dbtest.New(t)
+	repo := NewPostgresTransactionRepository(db)
+	user := createTestUser(t, db, "alice")
+	other := createTestUser(t, db, "bob")
+
+	var want []int64
+	for i, minute := range []int{0, 1, 1, 1, 2} {
+		tx := createTestDeposit(t, repo, user.ID, int64(100*(i+1)), testTime.Add(time.Duration(minute)*time.Minute))
+		want = append(want, tx.ID)
+	}
+	createTestDeposit(t, repo, other.ID, 100, testTime)
+
+	for _, descending := range []bool{false, true} {
+		filter := domain.TransactionFilter{UserID: user.ID, Descending: descending, Limit: 2}
+		var got []int64
+		for page := 0; ; page++ {
+			result, err := repo.Search(filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if page == 0 && (result.Total == nil || *result.Total != int64(len(want))) {
+				t.Fatalf("ilk sayfada toplam = %v, beklenen %d", result.Total, len(want))
+			}
+			if page > 0 && result.Total != nil {
+				t.Fatal("sonraki sayfalarda toplam hesaplanmamalı")
+			}
+			for _, tx := range result.Transactions {
+				got = append(got, tx.ID)
+			}
+			if result.NextCursor == "" {
+				break
+			}
+			if filter.Cursor, err = domain.DecodeTransactionCursor(result.NextCursor); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		expected := append([]int64{}, want...)
+		if descending {
+			for i, j := 0, len(expected)-1; i < j; i, j = i+1, j-1 {
+				expected[i], expected[j] = expected[j], expected[i]
+			}
+		}
+		if len(got) != len(expected) {
+			t.Fatalf("descending=%v: sayfalar %v, beklenen %v", descending, got, expected)
+		}
+		for i := range got {
+			if got[i] != expected[i] {
+				t.Fatalf("descending=%v: sayfalar %v, beklenen %v", descending, got, expected)
+			}
+		}
+	}
+}
+
+// lock ile alınan kilit, ilk transaction bitene kadar ikinci transaction'ı bekletmelidir
+func assertSerialized(t *testing.T, db *sql.DB, lock func(q *sql.Tx) error) {
+	t.Helper()
+	first, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Rollback()
+	if err := lock(first); err != nil {
+		t.Fatalf("ilk kilit: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		second, err := db.Begin()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer second.Rollback()
+		done <- lock(second)
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("ikinci transaction kilidi beklemeden aldı (hata: %v)", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if err := first.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("ikinci kilit: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ilk transaction bittikten sonra ikinci kilit alınamadı")
+	}
+}
+
+func TestPostgresForUpdateLocks(t *testing.T) {
+	db := dbtest.New(t)
+	user := createTestUser(t, db, "alice")
+	account := createTestAccount(t, db, user.ID, "TRY")
+	tx := createTestDeposit(t, NewPostgresTransactionRepository(db), user.ID, 1000, testTime)
+	if err := NewPostgresBalanceRepository(db).Update(account.ID, domain.NewMoney(1000, "TRY")); err != nil {
+		t.Fatal(err)
+	}
+	hold := &domain.Hold{AccountID: account.ID, OwnerID: user.ID, Amount: domain.NewMoney(100, "TRY"), Status: domain.HoldAuthorized,
+		ExpiresAt: testTime.Add(time.Hour), CreatedAt: testTime, UpdatedAt: testTime}
+	if err := NewPostgresHoldRepository(db).Create(hold); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("transaction", func(t *testing.T) {
+		assertSerialized(t, db, func(q *sql.Tx) error {
+			_, err := (&PostgresTransactionRepository{db: q}).FindByIDForUpdate(tx.ID)
+			return err
+		})
+	})
+	t.Run("user", func(t *testing.T) {
+		assertSerialized(t, db, func(q *sql.Tx) error {
+			return (&PostgresTransactionRepository{db: q}).LockUser(user.ID)
+		})
+	})
+	t.Run("hold", func(t *testing.T) {
+		assertSerialized(t, db, func(q *sql.Tx) error {
+			_, err := (&PostgresHoldRepository{db: q}).FindByIDForUpdate(hold.ID)
+			return err
+		})
+	})
+	t.Run("balance", func(t *testing.T) {
+		assertSerialized(t, db, func(q *sql.Tx) error {
+			return (&PostgresBalanceRepository{tx: q}).Update(account.ID, domain.NewMoney(1, "TRY"))
+		})
+	})
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PostgresScheduleRepository, ScheduleRepository arayüzünün PostgreSQL implementasyonudur
+type PostgresScheduleRepository struct {
+	db querier
+}
+
+// Yeni bir PostgresScheduleRepository oluşturur
+func NewPostgresScheduleRepository(db *sql.DB) *PostgresScheduleRepository {
+	return &PostgresScheduleRepository{db: db}
+}
+
+const scheduleColumns = `id, owner_id, created_by, from_account_id, to_account_id, amount, currency, frequency, day_of_month,
+	next_run_at, due_at, end_date, attempts, status, created_at, updated_at, description, external_reference, tags, metadata`
+
+func (r *PostgresScheduleRepository) Create(s *domain.ScheduledTransfer) error {
+	metadata, err := metadataJSON(s.Metadata)
+	if err != nil {
+		return err
+	}
+	return r.db.QueryRow(
+		`INSERT INTO scheduled_transfers (owner_id, created_by, from_account_id, to_account_id, amount, currency, frequency,
+		 day_of_month, next_run_at, due_at, end_date, attempts, status, created_at, updated_at, description,
+		 external_reference, tags, metadata)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19) RETURNING id`,
+		s.OwnerID, s.CreatedBy, s.FromAccountID, s.ToAccountID, s.Amount.Amount, domain.NormalizeCurrency(s.Amount.Currency),
+		string(s.Frequency), s.DayOfMonth, s.NextRunAt, s.DueAt, s.EndDate, s.Attempts, string(s.Status),
+		s.CreatedAt, s.UpdatedAt, s.Description, s.ExternalReference, pq.Array(s.Tags), metadata,
+	).Scan(&s.ID)
+}
+
+func (r *PostgresScheduleRepository) FindByID(id int64) (*domain.ScheduledTransfer, error) {
+	s, err := scanSchedule(r.db.QueryRow(`SELECT `+scheduleColumns+` FROM scheduled_transfers WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrScheduleNotFound
+	}
+	return s, err
+}
+
+func (r *PostgresScheduleRepository) ListByOwner(ownerID int64) ([]*domain.ScheduledTransfer, error) {
+	return r.list(`SELECT `+scheduleColumns+` FROM scheduled_transfers WHERE owner_id = $1 ORDER BY id`, ownerID)
+}
+
+// Tutar, açıklama, planlama ve durum alanlarını günceller
+func (r *PostgresScheduleRepository) Update(s *domain.ScheduledTransfer) error {
+	res, err := r.db.Exec(
+		`UPDATE scheduled_transfers SET amount = $2, description = $3, next_run_at = $4, due_at = $5, end_date = $6,
+		 attempts = $7, status = $8, updated_at = $9 WHERE id = $1`,
+		s.ID, s.Amount.Amount, s.Description, s.NextRunAt, s.DueAt, s.EndDate, s.Attempts,
+		string(s.Status), s.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (r *PostgresScheduleRepository) ListDue(now time.Time) ([]*domain.ScheduledTransfer, error) {
+	return r.list(`SELECT `+scheduleColumns+` FROM scheduled_transfers
+		WHERE status = 'active' AND due_at <= $1 ORDER BY due_at, id`, now)
+}
+
+func (r *PostgresScheduleRepository) list(query string, arg interface{}) ([]*domain.ScheduledTransfer, error) {
+	rows, err := r.db.Query(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.ScheduledTransfer
+	for rows.Next() {
+		s, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+func (r *PostgresScheduleRepository) AddRun(run *domain.ScheduleRun) error {
+	return r.db.QueryRow(
+		`INSERT INTO scheduled_transfer_runs (schedule_id, scheduled_for, attempt, status, transaction_id, error_code, error, ran_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		run.ScheduleID, run.ScheduledFor, run.Attempt, string(run.Status), nullableID(run.TransactionID),
+		run.ErrorCode, run.Error, run.RanAt,
+	).Scan(&run.ID)
+}
+
+func (r *PostgresScheduleRepository) ListRuns(scheduleID int64) ([]*domain.ScheduleRun, error) {
+	rows, err := r.db.Query(
+		`SELECT id, schedule_id, scheduled_for, attempt, status, transaction_id, error_code, error, ran_at
+		 FROM scheduled_transfer_runs WHERE schedule_id = $1 ORDER BY id`, scheduleID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*domain.ScheduleRun{}
+	for rows.Next() {
+		var (
+			run    domain.ScheduleRun
+			status string
+			txID   sql.NullInt64
+		)
+		if err := rows.Scan(&run.ID, &run.ScheduleID, &run.ScheduledFor, &run.Attempt, &status, &txID,
+			&run.ErrorCode, &run.Error, &run.RanAt); err != nil {
+			return nil, err
+		}
+		run.Status = domain.ScheduleRunStatus(status)
+		if txID.Valid {
+			run.TransactionID = &txID.Int64
+		}
+		result = append(result, &run)
+	}
+	return result, rows.Err()
+}
+
+func scanSchedule(row rowScanner) (*domain.ScheduledTransfer, error) {
+	var (
+		s                           domain.ScheduledTransfer
+		amount                      int64
+		currency, frequency, status string
+		endDate                     sql.NullTime
+		tags                        pq.StringArray
+		metadata                    []byte
+	)
+	if err := row.Scan(&s.ID, &s.OwnerID, &s.CreatedBy, &s.FromAccountID, &s.ToAccountID, &amount, &currency, &frequency,
+		&s.DayOfMonth, &s.NextRunAt, &s.DueAt, &endDate, &s.Attempts, &status, &s.CreatedAt, &s.UpdatedAt,
+		&s.Description, &s.ExternalReference, &tags, &metadata); err != nil {
+		return nil, err
+	}
+	s.Amount = domain.NewMoney(amount, domain.NormalizeCurrency(currency))
+	s.Frequency = domain.ScheduleFrequency(frequency)
+	s.Status = domain.ScheduleStatus(status)
+	if endDate.Valid {
+		s.EndDate = &endDate.Time
+	}
+	if len(tags) > 0 {
+		s.Tags = tags
+	}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &s.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	return &s, nil
+}
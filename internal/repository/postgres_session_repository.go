@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"time"
+)
+
+// PostgresSessionRepository, SessionRepository arayüzünün PostgreSQL implementasyonudur
+type PostgresSessionRepository struct {
+	db *sql.DB
+}
+
+// Yeni bir PostgresSessionRepository oluşturur
+func NewPostgresSessionRepository(db *sql.DB) *PostgresSessionRepository {
+	return &PostgresSessionRepository{db: db}
+}
+
+func (r *PostgresSessionRepository) CreateSession(session *domain.Session, token *domain.RefreshToken) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(
+		`INSERT INTO sessions (id, user_id, created_at, expires_at) VALUES ($1, $2, $3, $4)`,
+		session.ID, session.UserID, session.CreatedAt, session.ExpiresAt,
+	); err != nil {
+		return err
+	}
+	if err := insertRefreshToken(tx, token); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresSessionRepository) FindSession(id string) (*domain.Session, error) {
+	s := &domain.Session{ID: id}
+	var revokedAt sql.NullTime
+	err := r.db.QueryRow(
+		`SELECT user_id, created_at, expires_at, revoked_at FROM sessions WHERE id = $1`, id,
+	).Scan(&s.UserID, &s.CreatedAt, &s.ExpiresAt, &revokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("oturum bulunamadı")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		s.RevokedAt = &revokedAt.Time
+	}
+	return s, nil
+}
+
+func (r *PostgresSessionRepository) FindRefreshToken(hash string) (*domain.RefreshToken, error) {
+	t := &domain.RefreshToken{Hash: hash}
+	var usedAt sql.NullTime
+	err := r.db.QueryRow(
+		`SELECT session_id, created_at, expires_at, used_at FROM refresh_tokens WHERE hash = $1`, hash,
+	).Scan(&t.SessionID, &t.CreatedAt, &t.ExpiresAt, &usedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("refresh token bulunamadı")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if usedAt.Valid {
+		t.UsedAt = &usedAt.Time
+	}
+	return t, nil
+}
+
+func (r *PostgresSessionRepository) RotateRefreshToken(oldHash string, next *domain.RefreshToken, now time.Time) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	// Koşullu güncelleme sayesinde aynı token iki eşzamanlı istekte kullanılamaz
+	res, err := tx.Exec(`UPDATE refresh_tokens SET used_at = $2 WHERE hash = $1 AND used_at IS NULL`, oldHash, now)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	if err := insertRefreshToken(tx, next); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *PostgresSessionRepository) RevokeSession(id string, now time.Time) error {
+	_, err := r.db.Exec(`UPDATE sessions SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`, id, now)
+	return err
+}
+
+func (r *PostgresSessionRepository) RevokeUserSessions(userID int64, now time.Time) error {
+	_, err := r.db.Exec(`UPDATE sessions SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`, userID, now)
+	return err
+}
+
+func insertRefreshToken(tx *sql.Tx, token *domain.RefreshToken) error {
+	_, err := tx.Exec(
+		`INSERT INTO refresh_tokens (hash, session_id, created_at, expires_at) VALUES ($1, $2, $3, $4)`,
+		token.Hash, token.SessionID, token.CreatedAt, token.ExpiresAt,
+	)
+	return err
+}
@@ -0,0 +1,414 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gofinancialsystem/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PostgresTransactionRepository, TransactionRepository arayüzünün PostgreSQL implementasyonudur
+type PostgresTransactionRepository struct {
+	db querier
+}
+
+// Yeni bir PostgresTransactionRepository oluşturur
+func NewPostgresTransactionRepository(db *sql.DB) *PostgresTransactionRepository {
+	return &PostgresTransactionRepository{db: db}
+}
+
+// Transaction'lar döviz çevirisi denetim kaydıyla (varsa) birlikte okunur
+const transactionSelect = `SELECT t.id, t.from_user_id, t.to_user_id, t.from_account_id, t.to_account_id, t.amount, t.currency, t.type, t.status, t.original_transaction_id, t.created_at,
+	t.booking_date, t.value_date, t.description, t.external_reference, t.tags, t.metadata,
+	c.quote_id, c.rate::TEXT, c.mid_rate::TEXT, c.spread_bps, c.rate_version, c.target_amount, c.target_currency
+	FROM transactions t LEFT JOIN fx_conversions c ON c.transaction_id = t.id`
+
+// Transaction kaydeder ve veritabanının verdiği ID'yi atar.
+// Döviz çevirisi varsa kullanılan kur aynı transaction içinde fx_conversions tablosuna yazılır.
+func (r *PostgresTransactionRepository) Create(tx *domain.Transaction) error {
+	if tx.CreatedAt.IsZero() {
+		tx.CreatedAt = time.Now()
+	}
+	if tx.BookingDate.IsZero() {
+		tx.BookingDate = domain.StartOfDay(tx.CreatedAt)
+	}
+	if tx.ValueDate.IsZero() {
+		tx.ValueDate = tx.BookingDate
+	}
+	metadata, err := metadataJSON(tx.Metadata)
+	if err != nil {
+		return err
+	}
+	if err := r.db.QueryRow(
+		`INSERT INTO transactions (from_user_id, to_user_id, from_account_id, to_account_id, amount, currency, type, status, original_transaction_id, created_at,
+		 booking_date, value_date, description, external_reference, tags, metadata)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16) RETURNING id`,
+		nullableID(tx.FromUserID), nullableID(tx.ToUserID), nullableID(tx.FromAccountID), nullableID(tx.ToAccountID), tx.Amount.Amount, domain.NormalizeCurrency(tx.Amount.Currency),
+		string(tx.Type), string(tx.Status), nullableID(tx.OriginalID), tx.CreatedAt,
+		tx.BookingDate, tx.ValueDate, tx.Description, tx.ExternalReference, pq.Array(tx.Tags), metadata,
+	).Scan(&tx.ID); err != nil {
+		return err
+	}
+	if c := tx.Conversion; c != nil {
+		_, err := r.db.Exec(
+			`INSERT INTO fx_conversions (transaction_id, quote_id, rate, mid_rate, spread_bps, rate_version, target_amount, target_currency)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			tx.ID, sql.NullString{String: c.QuoteID, Valid: c.QuoteID != ""}, c.Rate, c.MidRate, c.SpreadBps, c.RateVersion,
+			c.Target.Amount, domain.NormalizeCurrency(c.Target.Currency),
+		)
+		return err
+	}
+	return nil
+}
+
+// ID ile transaction bulur
+func (r *PostgresTransactionRepository) FindByID(id int64) (*domain.Transaction, error) {
+	row := r.db.QueryRow(transactionSelect+` WHERE t.id = $1`, id)
+	tx, err := scanTransaction(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTransactionNotFound
+	}
+	return tx, err
+}
+
+// ID ile transaction bulur ve satırı içinde bulunulan veritabanı transaction'ı bitene kadar kilitler.
+// Aynı işlem için eşzamanlı geri alma/iade isteklerini sıraya koymak için kullanılır.
+func (r *PostgresTransactionRepository) FindByIDForUpdate(id int64) (*domain.Transaction, error) {
+	row := r.db.QueryRow(transactionSelect+` WHERE t.id = $1 FOR UPDATE OF t`, id)
+	tx, err := scanTransaction(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTransactionNotFound
+	}
+	return tx, err
+}
+
+// Kullanıcı ID'siyle transaction kapsamlı advisory lock alır; kilit commit veya rollback ile bırakılır.
+// READ COMMITTED altında kilitten sonraki kullanım sorguları, önceki sahibin commit ettiği işlemleri görür.
+func (r *PostgresTransactionRepository) LockUser(userID int64) error {
+	_, err := r.db.Exec(`SELECT pg_advisory_xact_lock($1)`, userID)
+	return err
+}
+
+// Orijinal işleme bağlı geri alma ve iade işlemlerini listeler
+func (r *PostgresTransactionRepository) ListByOriginal(originalID int64) ([]*domain.Transaction, error) {
+	rows, err := r.db.Query(transactionSelect+` WHERE t.original_transaction_id = $1 ORDER BY t.created_at, t.id`, originalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTransactions(rows)
+}
+
+// Transaction'ın durumunu günceller
+func (r *PostgresTransactionRepository) UpdateStatus(id int64, status domain.TransactionStatus) error {
+	res, err := r.db.Exec(`UPDATE transactions SET status = $2 WHERE id = $1`, id, string(status))
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrTransactionNotFound
+	}
+	return nil
+}
+
+// Kullanıcının gönderen ya da alıcı olduğu transaction'ları listeler
+func (r *PostgresTransactionRepository) ListByUser(userID int64) ([]*domain.Transaction, error) {
+	rows, err := r.db.Query(
+		transactionSelect+`
+		 WHERE t.from_user_id = $1 OR t.to_user_id = $1
+		 ORDER BY t.created_at, t.id`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTransactions(rows)
+}
+
+// Filtreye uyan işlemlerden bir sayfa döndürür. (from_user_id, created_at, id) ve (to_user_id, created_at, id)
+// indeksleri sayesinde sayfalama, imleçten başlayan bir indeks taramasıdır (keyset sayfalama).
+// Toplam sayı ayrı bir COUNT sorgusu gerektirdiğinden sadece ilk sayfada hesaplanır.
+func (r *PostgresTransactionRepository) Search(filter domain.TransactionFilter) (*domain.TransactionPage, error) {
+	where, args := transactionConditions(filter)
+
+	pageWhere := where
+	pageArgs := args
+	order := "ASC"
+	comparison := ">"
+	if filter.Descending {
+		order = "DESC"
+		comparison = "<"
+	}
+	if c := filter.Cursor; c != nil {
+		pageArgs = append(append([]interface{}{}, args...), c.CreatedAt, c.ID)
+		pageWhere = append(append([]string{}, where...),
+			fmt.Sprintf("(t.created_at, t.id) %s ($%d, $%d)", comparison, len(pageArgs)-1, len(pageArgs)))
+	}
+	pageArgs = append(pageArgs, filter.Limit+1)
+
+	rows, err := r.db.Query(
+		transactionSelect+` WHERE `+strings.Join(pageWhere, " AND ")+
+			fmt.Sprintf(` ORDER BY t.created_at %s, t.id %s LIMIT $%d`, order, order, len(pageArgs)),
+		pageArgs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	transactions, err := scanTransactions(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.TransactionPage{Transactions: transactions}
+	if page.Transactions == nil {
+		page.Transactions = []*domain.Transaction{}
+	}
+	// Bir fazla kayıt okunduysa sonraki sayfa vardır
+	if len(page.Transactions) > filter.Limit {
+		page.Transactions = page.Transactions[:filter.Limit]
+		page.NextCursor = domain.EncodeTransactionCursor(page.Transactions[filter.Limit-1])
+	}
+	if filter.Cursor == nil {
+		var total int64
+		if err := r.db.QueryRow(`SELECT COUNT(*) FROM transactions t WHERE `+strings.Join(where, " AND "), args...).Scan(&total); err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+	return page, nil
+}
+
+// Filtreye uyan işlemlerin tutarlarını toplar
+func (r *PostgresTransactionRepository) Sum(filter domain.TransactionFilter) (domain.Money, error) {
+	where, args := transactionConditions(filter)
+	var total int64
+	if err := r.db.QueryRow(`SELECT COALESCE(SUM(t.amount), 0) FROM transactions t WHERE `+strings.Join(where, " AND "), args...).Scan(&total); err != nil {
+		return domain.Money{}, err
+	}
+	return domain.NewMoney(total, filter.Currency), nil
+}
+
+// Filtreye uyan işlemleri sayar
+func (r *PostgresTransactionRepository) Count(filter domain.TransactionFilter) (int64, error) {
+	where, args := transactionConditions(filter)
+	var count int64
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM transactions t WHERE `+strings.Join(where, " AND "), args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Filtreyi (imleç hariç) WHERE koşullarına ve parametrelerine çevirir
+func transactionConditions(filter domain.TransactionFilter) ([]string, []interface{}) {
+	args := []interface{}{filter.UserID}
+	next := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var where []string
+	switch filter.Direction {
+	case domain.DirectionIn:
+		where = append(where, "t.to_user_id = $1")
+	case domain.DirectionOut:
+		where = append(where, "t.from_user_id = $1")
+	default:
+		where = append(where, "(t.from_user_id = $1 OR t.to_user_id = $1)")
+	}
+	if filter.CounterpartyID != nil {
+		cp := next(*filter.CounterpartyID)
+		where = append(where, fmt.Sprintf("((t.from_user_id = $1 AND t.to_user_id = %[1]s) OR (t.to_user_id = $1 AND t.from_user_id = %[1]s))", cp))
+	}
+	if filter.ExcludeSelf {
+		where = append(where, "t.from_user_id IS DISTINCT FROM t.to_user_id")
+	}
+	if filter.CreatedFrom != nil {
+		where = append(where, "t.created_at >= "+next(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		where = append(where, "t.created_at < "+next(*filter.CreatedTo))
+	}
+	if len(filter.Types) > 0 {
+		types := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			types[i] = string(t)
+		}
+		where = append(where, "t.type = ANY("+next(pq.Array(types))+")")
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, s := range filter.Statuses {
+			statuses[i] = string(s)
+		}
+		where = append(where, "t.status = ANY("+next(pq.Array(statuses))+")")
+	}
+	if filter.Currency != "" {
+		where = append(where, "t.currency = "+next(filter.Currency))
+	}
+	if filter.MinAmount != nil {
+		where = append(where, "t.amount >= "+next(*filter.MinAmount))
+	}
+	if filter.MaxAmount != nil {
+		where = append(where, "t.amount <= "+next(*filter.MaxAmount))
+	}
+	if filter.Converted != nil {
+		// COUNT ve SUM sorguları fx_conversions ile join yapmadığı için alt sorgu kullanılır
+		exists := "EXISTS (SELECT 1 FROM fx_conversions fc WHERE fc.transaction_id = t.id)"
+		if !*filter.Converted {
+			exists = "NOT " + exists
+		}
+		where = append(where, exists)
+	}
+	if filter.Text != "" {
+		where = append(where, "t.description ILIKE '%' || "+next(escapeLike(filter.Text))+" || '%'")
+	}
+	if filter.Reference != "" {
+		where = append(where, "t.external_reference = "+next(filter.Reference))
+	}
+	if len(filter.Tags) > 0 {
+		where = append(where, "t.tags @> "+next(pq.Array(filter.Tags)))
+	}
+	if len(filter.Metadata) > 0 {
+		// Map JSON'a her zaman çevrilebilir
+		metadata, _ := json.Marshal(filter.Metadata)
+		where = append(where, "t.metadata @> "+next(string(metadata))+"::jsonb")
+	}
+	return where, args
+}
+
+func scanTransactions(rows *sql.Rows) ([]*domain.Transaction, error) {
+	var result []*domain.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, tx)
+	}
+	return result, rows.Err()
+}
+
+// querier, *sql.DB ve *sql.Tx için ortak sorgu arayüzüdür
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// rowScanner, *sql.Row ve *sql.Rows için ortak Scan arayüzüdür
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTransaction(row rowScanner) (*domain.Transaction, error) {
+	var (
+		tx               domain.Transaction
+		fromID, toID     sql.NullInt64
+		fromAcc, toAcc   sql.NullInt64
+		originalID       sql.NullInt64
+		tags             pq.StringArray
+		metadata         []byte
+		amount           int64
+		currency, txType string
+		status           string
+		conv             fxConversionRow
+	)
+	if err := row.Scan(&tx.ID, &fromID, &toID, &fromAcc, &toAcc, &amount, &currency, &txType, &status, &originalID, &tx.CreatedAt,
+		&tx.BookingDate, &tx.ValueDate, &tx.Description, &tx.ExternalReference, &tags, &metadata,
+		&conv.quoteID, &conv.rate, &conv.midRate, &conv.spreadBps, &conv.rateVersion, &conv.targetAmount, &conv.targetCurrency); err != nil {
+		return nil, err
+	}
+	tx.Conversion = conv.conversion()
+	tx.BookingDate = domain.StartOfDay(tx.BookingDate)
+	tx.ValueDate = domain.StartOfDay(tx.ValueDate)
+	if len(tags) > 0 {
+		tx.Tags = tags
+	}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &tx.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	if fromID.Valid {
+		tx.FromUserID = &fromID.Int64
+	}
+	if toID.Valid {
+		tx.ToUserID = &toID.Int64
+	}
+	if fromAcc.Valid {
+		tx.FromAccountID = &fromAcc.Int64
+	}
+	if toAcc.Valid {
+		tx.ToAccountID = &toAcc.Int64
+	}
+	if originalID.Valid {
+		tx.OriginalID = &originalID.Int64
+	}
+	tx.Amount = domain.NewMoney(amount, currency)
+	tx.Type = domain.TransactionType(txType)
+	tx.Status = domain.TransactionStatus(status)
+	return &tx, nil
+}
+
+// fxConversionRow, LEFT JOIN ile okunan (boş olabilen) fx_conversions sütunlarını tutar
+type fxConversionRow struct {
+	quoteID, rate, midRate sql.NullString
+	spreadBps              sql.NullInt64
+	rateVersion            sql.NullTime
+	targetAmount           sql.NullInt64
+	targetCurrency         sql.NullString
+}
+
+func (c fxConversionRow) conversion() *domain.FXConversion {
+	// Geri alma ve iadelerin ters çevirilerinde quote_id boştur; satırın varlığı kurdan anlaşılır
+	if !c.rate.Valid {
+		return nil
+	}
+	return &domain.FXConversion{
+		QuoteID:     c.quoteID.String,
+		Rate:        trimDecimal(c.rate.String),
+		MidRate:     trimDecimal(c.midRate.String),
+		SpreadBps:   c.spreadBps.Int64,
+		RateVersion: c.rateVersion.Time,
+		Target:      domain.NewMoney(c.targetAmount.Int64, c.targetCurrency.String),
+	}
+}
+
+// NUMERIC sütunlarının sondaki anlamsız sıfırlarını atar (32.4500000000 -> 32.45)
+func trimDecimal(s string) string {
+	if !strings.Contains(s, ".") {
+		return s
+	}
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// Metadata'yı JSONB sütunu için serileştirir; boş metadata NULL olarak saklanır
+func metadataJSON(metadata map[string]string) (interface{}, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// ILIKE desenindeki joker karakterleri (% ve _) düz metin olarak aranacak şekilde kaçışlar
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func nullableID(id *int64) sql.NullInt64 {
+	if id == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *id, Valid: true}
+}
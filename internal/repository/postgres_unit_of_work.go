@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"database/sql"
+	"gofinancialsystem/internal/domain"
+)
+
+// PostgresUnitOfWork, repository işlemlerini tek bir veritabanı transaction'ında çalıştırır
+type PostgresUnitOfWork struct {
+	db *sql.DB
+}
+
+// Yeni bir PostgresUnitOfWork oluşturur
+func NewPostgresUnitOfWork(db *sql.DB) *PostgresUnitOfWork {
+	return &PostgresUnitOfWork{db: db}
+}
+
+// fn'i bir transaction içinde çalıştırır; fn hata dönerse transaction geri alınır
+func (u *PostgresUnitOfWork) Do(fn func(repos domain.Repositories) error) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	repos := domain.Repositories{
+		Balances:     &PostgresBalanceRepository{tx: tx},
+		Transactions: &PostgresTransactionRepository{db: tx},
+		Ledger:       &PostgresLedgerRepository{db: tx},
+		Holds:        &PostgresHoldRepository{db: tx},
+		Interest:     &PostgresInterestRepository{db: tx},
+	}
+	if err := fn(repos); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"gofinancialsystem/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// Yabancı anahtar kısıtı ihlalinin PostgreSQL hata kodu
+const foreignKeyViolation = "23503"
+
+// PostgresUserRepository, UserRepository arayüzünün PostgreSQL implementasyonudur
+type PostgresUserRepository struct {
+	db *sql.DB
+}
+
+// Yeni bir PostgresUserRepository oluşturur
+func NewPostgresUserRepository(db *sql.DB) *PostgresUserRepository {
+	return &PostgresUserRepository{db: db}
+}
+
+// Kullanıcı oluşturur (Password alanı hash'lenmiş olarak gelmelidir)
+func (r *PostgresUserRepository) Create(user *domain.User) error {
+	return r.db.QueryRow(
+		`INSERT INTO users (username, email, password_hash, role, locale)
+		 VALUES ($1, $2, $3, $4, NULLIF($5, '')) RETURNING id`,
+		user.Username, user.Email, user.Password, user.Role, user.Locale,
+	).Scan(&user.ID)
+}
+
+// ID ile kullanıcı bulur
+func (r *PostgresUserRepository) FindByID(id int64) (*domain.User, error) {
+	return r.findOne(`SELECT id, username, email, password_hash, role, COALESCE(locale, '') FROM users WHERE id = $1`, id)
+}
+
+// Kullanıcı adı ile kullanıcı bulur
+func (r *PostgresUserRepository) FindByUsername(username string) (*domain.User, error) {
+	return r.findOne(`SELECT id, username, email, password_hash, role, COALESCE(locale, '') FROM users WHERE username = $1`, username)
+}
+
+// Kullanıcının dil tercihini günceller
+func (r *PostgresUserRepository) UpdateLocale(id int64, locale string) error {
+	res, err := r.db.Exec(`UPDATE users SET locale = NULLIF($2, ''), updated_at = NOW() WHERE id = $1`, id, locale)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// Kullanıcıyı oturumları ve yetki devirleriyle birlikte siler; kullanıcıya bağlı hesap, işlem,
+// provizyon veya talimat varsa yabancı anahtar kısıtı silmeyi engeller ve hiçbir şey silinmez
+func (r *PostgresUserRepository) Delete(id int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, query := range []string{
+		`DELETE FROM refresh_tokens WHERE session_id IN (SELECT id FROM sessions WHERE user_id = $1)`,
+		`DELETE FROM sessions WHERE user_id = $1`,
+		`DELETE FROM delegations WHERE owner_id = $1 OR delegate_id = $1`,
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			return err
+		}
+	}
+	res, err := tx.Exec(`DELETE FROM users WHERE id = $1`, id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return domain.ErrUserHasRecords
+	}
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrUserNotFound
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresUserRepository) findOne(query string, arg interface{}) (*domain.User, error) {
+	user := &domain.User{}
+	err := r.db.QueryRow(query, arg).Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.Role, &user.Locale)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
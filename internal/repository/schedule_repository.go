@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"gofinancialsystem/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ScheduleRepositoryImpl, ScheduleRepository arayüzünün in-memory implementasyonudur
+type ScheduleRepositoryImpl struct {
+	schedules map[int64]*domain.ScheduledTransfer
+	runs      map[int64][]*domain.ScheduleRun // Talimat ID -> çalıştırmalar
+	mu        sync.RWMutex
+	nextID    int64
+	nextRunID int64
+}
+
+// Yeni bir ScheduleRepositoryImpl oluşturur
+func NewScheduleRepository() *ScheduleRepositoryImpl {
+	return &ScheduleRepositoryImpl{
+		schedules: make(map[int64]*domain.ScheduledTransfer),
+		runs:      make(map[int64][]*domain.ScheduleRun),
+		nextID:    1,
+		nextRunID: 1,
+	}
+}
+
+func (r *ScheduleRepositoryImpl) Create(s *domain.ScheduledTransfer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s.ID = r.nextID
+	r.nextID++
+	r.schedules[s.ID] = copySchedule(s)
+	return nil
+}
+
+func (r *ScheduleRepositoryImpl) FindByID(id int64) (*domain.ScheduledTransfer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if s, exists := r.schedules[id]; exists {
+		return copySchedule(s), nil
+	}
+	return nil, domain.ErrScheduleNotFound
+}
+
+func (r *ScheduleRepositoryImpl) ListByOwner(ownerID int64) ([]*domain.ScheduledTransfer, error) {
+	result := r.list(func(s *domain.ScheduledTransfer) bool { return s.OwnerID == ownerID })
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *ScheduleRepositoryImpl) Update(s *domain.ScheduledTransfer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.schedules[s.ID]; !exists {
+		return domain.ErrScheduleNotFound
+	}
+	r.schedules[s.ID] = copySchedule(s)
+	return nil
+}
+
+func (r *ScheduleRepositoryImpl) ListDue(now time.Time) ([]*domain.ScheduledTransfer, error) {
+	result := r.list(func(s *domain.ScheduledTransfer) bool {
+		return s.Status == domain.ScheduleActive && !s.DueAt.After(now)
+	})
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].DueAt.Equal(result[j].DueAt) {
+			return result[i].DueAt.Before(result[j].DueAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+func (r *ScheduleRepositoryImpl) list(match func(s *domain.ScheduledTransfer) bool) []*domain.ScheduledTransfer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []*domain.ScheduledTransfer
+	for _, s := range r.schedules {
+		if match(s) {
+			result = append(result, copySchedule(s))
+		}
+	}
+	return result
+}
+
+func (r *ScheduleRepositoryImpl) AddRun(run *domain.ScheduleRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.schedules[run.ScheduleID]; !exists {
+		return domain.ErrScheduleNotFound
+	}
+	run.ID = r.nextRunID
+	r.nextRunID++
+	stored := *run
+	r.runs[run.ScheduleID] = append(r.runs[run.ScheduleID], &stored)
+	return nil
+}
+
+func (r *ScheduleRepositoryImpl) ListRuns(scheduleID int64) ([]*domain.ScheduleRun, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.ScheduleRun, 0, len(r.runs[scheduleID]))
+	for _, run := range r.runs[scheduleID] {
+		copied := *run
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+// Talimatın, saklanan kaydı dışarıdan değiştirilemeyecek bir kopyasını döndürür
+func copySchedule(s *domain.ScheduledTransfer) *domain.ScheduledTransfer {
+	copied := *s
+	if s.EndDate != nil {
+		end := *s.EndDate
+		copied.EndDate = &end
+	}
+	copied.Tags = append([]string(nil), s.Tags...)
+	if s.Metadata != nil {
+		copied.Metadata = make(map[string]string, len(s.Metadata))
+		for k, v := range s.Metadata {
+			copied.Metadata[k] = v
+		}
+	}
+	return &copied
+}
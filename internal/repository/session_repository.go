@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"sync"
+	"time"
+)
+
+// SessionRepositoryImpl, SessionRepository arayüzünün in-memory implementasyonudur
+type SessionRepositoryImpl struct {
+	sessions map[string]*domain.Session
+	tokens   map[string]*domain.RefreshToken
+	mu       sync.RWMutex
+}
+
+// Yeni bir SessionRepositoryImpl oluşturur
+func NewSessionRepository() *SessionRepositoryImpl {
+	return &SessionRepositoryImpl{
+		sessions: make(map[string]*domain.Session),
+		tokens:   make(map[string]*domain.RefreshToken),
+	}
+}
+
+func (r *SessionRepositoryImpl) CreateSession(session *domain.Session, token *domain.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.sessions[session.ID]; exists {
+		return errors.New("oturum zaten var")
+	}
+	s := *session
+	t := *token
+	r.sessions[s.ID] = &s
+	r.tokens[t.Hash] = &t
+	return nil
+}
+
+func (r *SessionRepositoryImpl) FindSession(id string) (*domain.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, exists := r.sessions[id]
+	if !exists {
+		return nil, errors.New("oturum bulunamadı")
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (r *SessionRepositoryImpl) FindRefreshToken(hash string) (*domain.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, exists := r.tokens[hash]
+	if !exists {
+		return nil, errors.New("refresh token bulunamadı")
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (r *SessionRepositoryImpl) RotateRefreshToken(oldHash string, next *domain.RefreshToken, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, exists := r.tokens[oldHash]
+	if !exists {
+		return false, errors.New("refresh token bulunamadı")
+	}
+	if old.UsedAt != nil {
+		return false, nil
+	}
+	usedAt := now
+	old.UsedAt = &usedAt
+	t := *next
+	r.tokens[t.Hash] = &t
+	return true, nil
+}
+
+func (r *SessionRepositoryImpl) RevokeSession(id string, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, exists := r.sessions[id]
+	if !exists {
+		return errors.New("oturum bulunamadı")
+	}
+	if s.RevokedAt == nil {
+		revokedAt := now
+		s.RevokedAt = &revokedAt
+	}
+	return nil
+}
+
+func (r *SessionRepositoryImpl) RevokeUserSessions(userID int64, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.sessions {
+		if s.UserID == userID && s.RevokedAt == nil {
+			revokedAt := now
+			s.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
@@ -1,13 +1,14 @@
 package repository
 
 import (
-	"errors"
 	"gofinancialsystem/internal/domain"
+	"sort"
 	"sync"
 )
 
 type TransactionRepositoryImpl struct {
 	transactions map[int64]*domain.Transaction
+	byUser       map[int64][]int64 // Kullanıcının taraf olduğu işlem ID'leri; geçmiş sorguları tüm map'i taramaz
 	mu           sync.RWMutex
 	nextID       int64
 }
@@ -15,6 +16,7 @@ type TransactionRepositoryImpl struct {
 func NewTransactionRepository() *TransactionRepositoryImpl {
 	return &TransactionRepositoryImpl{
 		transactions: make(map[int64]*domain.Transaction),
+		byUser:       make(map[int64][]int64),
 		nextID:       1,
 	}
 }
@@ -25,27 +27,205 @@ func (r *TransactionRepositoryImpl) Create(tx *domain.Transaction) error {
 
 	tx.ID = r.nextID
 	r.nextID++
+	r.insert(tx)
+	return nil
+}
+
+// Kaydı map'e ve kullanıcı indeksine ekler; çağıran kilidi tutmalıdır
+func (r *TransactionRepositoryImpl) insert(tx *domain.Transaction) {
 	r.transactions[tx.ID] = tx
+	if tx.FromUserID != nil {
+		r.byUser[*tx.FromUserID] = append(r.byUser[*tx.FromUserID], tx.ID)
+	}
+	if tx.ToUserID != nil && (tx.FromUserID == nil || *tx.ToUserID != *tx.FromUserID) {
+		r.byUser[*tx.ToUserID] = append(r.byUser[*tx.ToUserID], tx.ID)
+	}
+}
+
+func (r *TransactionRepositoryImpl) UpdateStatus(id int64, status domain.TransactionStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tx, exists := r.transactions[id]
+	if !exists {
+		return domain.ErrTransactionNotFound
+	}
+	tx.Status = status
 	return nil
 }
 
+// Unit of work için ID ayırır; kayıt commit sırasında eklenir
+func (r *TransactionRepositoryImpl) reserveID() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := r.nextID
+	r.nextID++
+	return id
+}
+
 func (r *TransactionRepositoryImpl) FindByID(id int64) (*domain.Transaction, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	if tx, exists := r.transactions[id]; exists {
 		return tx, nil
 	}
-	return nil, errors.New("işlem bulunamadı")
+	return nil, domain.ErrTransactionNotFound
 }
 
-func (r *TransactionRepositoryImpl) ListByUser(userID int64) ([]*domain.Transaction, error) {
+// Bellekte satır kilidi yoktur; unit of work'ler zaten sıraya konur
+func (r *TransactionRepositoryImpl) FindByIDForUpdate(id int64) (*domain.Transaction, error) {
+	return r.FindByID(id)
+}
+
+// Bellekte kilit gerekmez; unit of work'ler zaten sıraya konur
+func (r *TransactionRepositoryImpl) LockUser(userID int64) error {
+	return nil
+}
+
+func (r *TransactionRepositoryImpl) ListByOriginal(originalID int64) ([]*domain.Transaction, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	var result []*domain.Transaction
 	for _, tx := range r.transactions {
-		if (tx.FromUserID != nil && *tx.FromUserID == userID) || (tx.ToUserID != nil && *tx.ToUserID == userID) {
+		if tx.OriginalID != nil && *tx.OriginalID == originalID {
 			result = append(result, tx)
 		}
 	}
+	sortTransactions(result, false)
 	return result, nil
 }
+
+func (r *TransactionRepositoryImpl) ListByUser(userID int64) ([]*domain.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.userTransactions(userID), nil
+}
+
+// Kullanıcının işlemlerini (created_at, id) sırasıyla döndürür; çağıran okuma kilidini tutmalıdır
+func (r *TransactionRepositoryImpl) userTransactions(userID int64) []*domain.Transaction {
+	ids := r.byUser[userID]
+	result := make([]*domain.Transaction, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, r.transactions[id])
+	}
+	sortTransactions(result, false)
+	return result
+}
+
+// Kullanıcının işlemlerini filtreler ve imleçten sonraki sayfayı döndürür.
+// İndeksteki işlemler tek geçişte taranır; imleç ve filtre her kayda sıralamadan önce uygulanır ve sadece
+// sayfaya girecek kayıtlar tutulur. Bellekte sayım ucuz olduğundan toplam her zaman döner.
+func (r *TransactionRepositoryImpl) Search(filter domain.TransactionFilter) (*domain.TransactionPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	page := newPageCollector(filter)
+	for _, id := range r.byUser[filter.UserID] {
+		page.add(r.transactions[id])
+	}
+	return page.result(), nil
+}
+
+func (r *TransactionRepositoryImpl) Sum(filter domain.TransactionFilter) (domain.Money, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return sumMatching(r.userTransactions(filter.UserID), filter), nil
+}
+
+func (r *TransactionRepositoryImpl) Count(filter domain.TransactionFilter) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return countMatching(r.userTransactions(filter.UserID), filter), nil
+}
+
+// Filtreye uyan işlemlerin tutarlarını filtrenin para biriminde toplar
+func sumMatching(candidates []*domain.Transaction, filter domain.TransactionFilter) domain.Money {
+	total := domain.NewMoney(0, filter.Currency)
+	for _, tx := range candidates {
+		if filter.Matches(tx) {
+			total.Amount += tx.Amount.Amount
+		}
+	}
+	return total
+}
+
+func countMatching(candidates []*domain.Transaction, filter domain.TransactionFilter) int64 {
+	var count int64
+	for _, tx := range candidates {
+		if filter.Matches(tx) {
+			count++
+		}
+	}
+	return count
+}
+
+// pageCollector, sırasız gelen işlemlerden filtreye uyanları sayar ve imlecin ötesindeki ilk limit+1 kaydı
+// sayfa sırasıyla tutar; bir fazla kayıt sonraki sayfanın olduğunu gösterir
+type pageCollector struct {
+	filter domain.TransactionFilter
+	head   []*domain.Transaction
+	total  int64
+}
+
+func newPageCollector(filter domain.TransactionFilter) *pageCollector {
+	return &pageCollector{filter: filter, head: make([]*domain.Transaction, 0, filter.Limit+1)}
+}
+
+// İşlem filtreye uyuyorsa sayar, imlecin ötesindeyse sayfaya ekler
+func (p *pageCollector) add(tx *domain.Transaction) {
+	if !p.filter.Matches(tx) {
+		return
+	}
+	p.total++
+	if p.filter.AfterCursor(tx) {
+		p.insert(tx)
+	}
+}
+
+// Filtreye uyduğu ve imlecin ötesinde olduğu bilinen işlemi sırasına yerleştirir; limit+1'i aşan son kayıt düşer
+func (p *pageCollector) insert(tx *domain.Transaction) {
+	i := sort.Search(len(p.head), func(i int) bool { return transactionLess(tx, p.head[i], p.filter.Descending) })
+	if i > p.filter.Limit {
+		return
+	}
+	if len(p.head) <= p.filter.Limit {
+		p.head = append(p.head, nil)
+	}
+	copy(p.head[i+1:], p.head[i:])
+	p.head[i] = tx
+}
+
+func (p *pageCollector) result() *domain.TransactionPage {
+	total := p.total
+	page := &domain.TransactionPage{Transactions: p.head, Total: &total}
+	if len(p.head) > p.filter.Limit {
+		page.Transactions = p.head[:p.filter.Limit]
+		page.NextCursor = domain.EncodeTransactionCursor(page.Transactions[p.filter.Limit-1])
+	}
+	return page
+}
+
+func sortTransactions(txs []*domain.Transaction, descending bool) {
+	sort.Slice(txs, func(i, j int) bool { return transactionLess(txs[i], txs[j], descending) })
+}
+
+// a'nın sayfalama sırasında b'den önce gelip gelmediğini döndürür: (created_at, id), descending ise tersi
+func transactionLess(a, b *domain.Transaction, descending bool) bool {
+	if descending {
+		a, b = b, a
+	}
+	return a.CreatedAt.Before(b.CreatedAt) || (a.CreatedAt.Equal(b.CreatedAt) && a.ID < b.ID)
+}
+
+// Unit of work'te biriken kayıtları ve durum değişikliklerini uygular
+func (r *TransactionRepositoryImpl) applyStaged(created []*domain.Transaction, statuses map[int64]domain.TransactionStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tx := range created {
+		r.insert(tx)
+	}
+	for id, status := range statuses {
+		if tx, exists := r.transactions[id]; exists {
+			tx.Status = status
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"gofinancialsystem/internal/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// İlk sayfa okunduktan sonra imlecin önüne, arkasına ve imleçle aynı zamana eklenen işlemler sayfalamayı bozmamalı:
+// imlecin ötesindekiler sonraki sayfalarda bir kez görünür, önündekiler görünmez, daha önce okunanlar tekrarlanmaz
+func TestTransactionSearchCursorStability(t *testing.T) {
+	cases := []struct {
+		name       string
+		descending bool
+		staged     bool // Sonraki sayfalar, eklemelerle aynı unit of work içinde okunur
+		want       []int64
+	}{
+		{"eskiden yeniye", false, false, []int64{1, 2, 3, 4, 8, 5, 9}},
+		{"yeniden eskiye", true, false, []int64{5, 4, 3, 2, 1, 7}},
+		{"unit of work içinde eskiden yeniye", false, true, []int64{1, 2, 3, 4, 8, 5, 9}},
+		{"unit of work içinde yeniden eskiye", true, true, []int64{5, 4, 3, 2, 1, 7}},
+	}
+	at := func(minute int) time.Time { return testTime.Add(time.Duration(minute) * time.Minute) }
+	for _, c := range cases {
+		transactions := NewTransactionRepository()
+		uow := NewMemoryUnitOfWork(NewBalanceRepository(), transactions, NewLedgerRepository(), NewHoldRepository(), NewInterestRepository())
+		const userID, otherID = 1, 2
+		// 1-5 numaralı işlemler; 2, 3 ve 4 aynı anda
+		for _, minute := range []int{0, 1, 1, 1, 2} {
+			createTestDeposit(t, transactions, userID, 100, at(minute))
+		}
+		createTestDeposit(t, transactions, otherID, 100, at(1))
+
+		filter := domain.TransactionFilter{UserID: userID, Descending: c.descending, Limit: 2}
+		first, err := transactions.Search(filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first.Total == nil || *first.Total != 5 {
+			t.Fatalf("%s: ilk sayfada toplam %v, beklenen 5", c.name, first.Total)
+		}
+		got := ids(first.Transactions)
+
+		// 7: en eski, 8: imleçle aynı dakikada ama daha büyük ID, 9: en yeni
+		rest := func(repo domain.TransactionRepository) error {
+			for _, minute := range []int{-1, 1, 3} {
+				createTestDeposit(t, repo, userID, 100, at(minute))
+			}
+			next := first.NextCursor
+			for next != "" {
+				if filter.Cursor, err = domain.DecodeTransactionCursor(next); err != nil {
+					return err
+				}
+				page, err := repo.Search(filter)
+				if err != nil {
+					return err
+				}
+				if len(page.Transactions) > filter.Limit || page.Total == nil || *page.Total != 8 {
+					t.Errorf("%s: sayfada %d kayıt, toplam %v; beklenen en fazla %d kayıt, toplam 8", c.name, len(page.Transactions), page.Total, filter.Limit)
+				}
+				got = append(got, ids(page.Transactions)...)
+				next = page.NextCursor
+			}
+			return nil
+		}
+		if c.staged {
+			err = uow.Do(func(repos domain.Repositories) error { return rest(repos.Transactions) })
+		} else {
+			err = rest(transactions)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: sayfalar %v, beklenen %v", c.name, got, c.want)
+		}
+	}
+}
+
+func ids(txs []*domain.Transaction) []int64 {
+	result := make([]int64, 0, len(txs))
+	for _, tx := range txs {
+		result = append(result, tx.ID)
+	}
+	return result
+}
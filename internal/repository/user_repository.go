@@ -1,7 +1,6 @@
 package repository
 
 import (
-	"errors"
 	"sync"
 	"gofinancialsystem/internal/domain"
 )
@@ -40,7 +39,32 @@ func (r *UserRepositoryImpl) FindByID(id int64) (*domain.User, error) {
 	if user, exists := r.users[id]; exists {
 		return user, nil
 	}
-	return nil, errors.New("kullanıcı bulunamadı")
+	return nil, domain.ErrUserNotFound
+}
+
+// Kullanıcının dil tercihini günceller
+func (r *UserRepositoryImpl) UpdateLocale(id int64, locale string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, exists := r.users[id]
+	if !exists {
+		return domain.ErrUserNotFound
+	}
+	user.Locale = locale
+	return nil
+}
+
+// Kullanıcıyı siler
+func (r *UserRepositoryImpl) Delete(id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.users[id]; !exists {
+		return domain.ErrUserNotFound
+	}
+	delete(r.users, id)
+	return nil
 }
 
 // Kullanıcı adı ile kullanıcı bulur
@@ -53,5 +77,5 @@ func (r *UserRepositoryImpl) FindByUsername(username string) (*domain.User, erro
 			return user, nil
 		}
 	}
-	return nil, errors.New("kullanıcı bulunamadı")
+	return nil, domain.ErrUserNotFound
 } 
\ No newline at end of file
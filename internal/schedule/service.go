@@ -0,0 +1,277 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"gofinancialsystem/internal/domain"
+	"strconv"
+	"time"
+)
+
+// Service, zamanlanmış transfer talimatlarını saklar ve zamanı gelenleri TransactionService.Transfer ile çalıştırır.
+// Yetersiz bakiye nedeniyle başarısız olan çalıştırmalar politikaya göre tekrar denenir; her deneme çalıştırma
+// geçmişine yazılır. Oluşan transferler talimat ID'si ve planlanan zamanla etiketlenir; böylece bir deneme
+// transferi yaptıktan sonra kaydedilemezse sonraki deneme transferi tekrarlamaz.
+type Service struct {
+	schedules domain.ScheduleRepository
+	accounts  domain.AccountRepository
+	transfers domain.TransactionService
+	policy    domain.ScheduleRetryPolicy
+	now       func() time.Time
+}
+
+// Yeni bir Service oluşturur; clock geçerli zamanı döndürür (testlerde zamanı ileri almak için değiştirilebilir)
+func NewService(schedules domain.ScheduleRepository, accounts domain.AccountRepository, transfers domain.TransactionService,
+	policy domain.ScheduleRetryPolicy, clock func() time.Time) *Service {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &Service{schedules: schedules, accounts: accounts, transfers: transfers, policy: policy, now: clock}
+}
+
+// Talimatı doğrular, start zamanından itibaren planlar ve kaydeder. Hesaplar etkin ve tutarın para biriminde
+// olmalıdır; start geçmişte olamaz, sıfırsa ilk çalıştırma zamanlayıcının bir sonraki turunda yapılır.
+func (s *Service) Create(order *domain.ScheduledTransfer, start time.Time) error {
+	if !order.Amount.IsPositive() {
+		return domain.ErrInvalidAmount
+	}
+	from, err := s.account(order.FromAccountID, order.Amount)
+	if err != nil {
+		return err
+	}
+	if _, err := s.account(order.ToAccountID, order.Amount); err != nil {
+		return err
+	}
+
+	now := s.now()
+	if start.IsZero() {
+		start = now
+	}
+	if start.Before(now) {
+		return domain.NewValidationError("schedule_start_in_past", "talimatın başlangıç zamanı geçmişte olamaz")
+	}
+	if err := order.Schedule(start); err != nil {
+		return err
+	}
+	if err := order.TransactionDetails.Normalize(); err != nil {
+		return err
+	}
+	// Çalıştırmada eklenecek metadata alanlarıyla birlikte de geçerli olmalı
+	details := order.RunDetails()
+	if err := details.Normalize(); err != nil {
+		return err
+	}
+
+	order.OwnerID = from.OwnerID
+	order.Amount.Currency = from.Currency
+	order.Status = domain.ScheduleActive
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	return s.schedules.Create(order)
+}
+
+// Talimat hesabını getirir; hesap para hareketine açık ve tutarın para biriminde olmalıdır
+func (s *Service) account(id int64, amount domain.Money) (*domain.Account, error) {
+	account, err := s.accounts.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := account.EnsureActive(); err != nil {
+		return nil, err
+	}
+	if err := account.EnsureCurrency(amount); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func (s *Service) GetByID(id int64) (*domain.ScheduledTransfer, error) {
+	return s.schedules.FindByID(id)
+}
+
+func (s *Service) ListByOwner(ownerID int64) ([]*domain.ScheduledTransfer, error) {
+	return s.schedules.ListByOwner(ownerID)
+}
+
+// Talimatın tutarını, açıklamasını, planını veya durumunu değiştirir. Çalışması bitmiş talimatlar değiştirilemez.
+// Durdurulmuş bir talimat tekrar etkinleştirildiğinde arada kaçırılan çalıştırmalar yapılmaz.
+func (s *Service) Update(id int64, update domain.ScheduleUpdate) (*domain.ScheduledTransfer, error) {
+	order, err := s.schedules.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status.IsFinal() {
+		return nil, domain.ErrInvalidScheduleState.WithDetails(map[string]interface{}{"status": order.Status})
+	}
+	now := s.now()
+
+	if update.Amount != nil {
+		if !update.Amount.IsPositive() {
+			return nil, domain.ErrInvalidAmount
+		}
+		if domain.NormalizeCurrency(update.Amount.Currency) != order.Amount.Currency {
+			return nil, domain.ErrCurrencyMismatch.WithMessage("schedule_currency", "tutar talimatın para biriminde olmalı: {currency}").
+				WithParams(map[string]interface{}{"currency": order.Amount.Currency})
+		}
+		order.Amount = domain.NewMoney(update.Amount.Amount, order.Amount.Currency)
+	}
+	if update.Description != nil {
+		order.Description = *update.Description
+		if err := order.TransactionDetails.Normalize(); err != nil {
+			return nil, err
+		}
+	}
+	if update.EndDate != nil {
+		end := update.EndDate.UTC()
+		order.EndDate = &end
+	}
+	if update.StartAt != nil {
+		if update.StartAt.Before(now) {
+			return nil, domain.NewValidationError("schedule_start_in_past", "talimatın başlangıç zamanı geçmişte olamaz")
+		}
+		if err := order.Schedule(*update.StartAt); err != nil {
+			return nil, err
+		}
+	}
+	if update.Status != nil {
+		switch *update.Status {
+		case domain.ScheduleActive:
+			if order.Status == domain.SchedulePaused && update.StartAt == nil {
+				skipMissed(order, now)
+			}
+		case domain.SchedulePaused:
+		default:
+			return nil, domain.NewValidationError("invalid_schedule_status", "talimat durumu sadece active veya paused yapılabilir")
+		}
+		order.Status = *update.Status
+	}
+	if order.EndDate != nil && order.EndDate.Before(order.NextRunAt) {
+		return nil, domain.NewValidationError("end_before_start", "bitiş tarihi ilk çalıştırmadan önce olamaz")
+	}
+
+	order.UpdatedAt = now
+	if err := s.schedules.Update(order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Tekrarlanan talimatın now'dan önceki çalıştırmalarını atlar; tek seferlik talimat gecikmeli de olsa çalışır
+func skipMissed(order *domain.ScheduledTransfer, now time.Time) {
+	for order.NextRunAt.Before(now) {
+		next, ok := order.NextOccurrence()
+		if !ok {
+			break
+		}
+		order.NextRunAt = next
+	}
+	order.DueAt = order.NextRunAt
+	order.Attempts = 0
+}
+
+// Talimatı iptal eder; çalıştırma geçmişi korunur
+func (s *Service) Cancel(id int64) (*domain.ScheduledTransfer, error) {
+	order, err := s.schedules.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status.IsFinal() {
+		return nil, domain.ErrInvalidScheduleState.WithDetails(map[string]interface{}{"status": order.Status})
+	}
+	order.Status = domain.ScheduleCancelled
+	order.UpdatedAt = s.now()
+	if err := s.schedules.Update(order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Talimatın çalıştırma geçmişini eskiden yeniye döndürür
+func (s *Service) Runs(id int64) ([]*domain.ScheduleRun, error) {
+	if _, err := s.schedules.FindByID(id); err != nil {
+		return nil, err
+	}
+	return s.schedules.ListRuns(id)
+}
+
+// Zamanı now itibarıyla gelmiş talimatları çalıştırır; kaydedilen deneme sayısını döner.
+// Tekrarlanan bir talimatın geride kalmış birden fazla çalıştırması varsa her turda biri yapılır.
+// Bir talimatın denemesi kaydedilemezse diğer talimatlar yine çalıştırılır; hatalar talimat ID'leriyle birlikte döner
+// ve kaydedilemeyen deneme sonraki turda tekrarlanır.
+func (s *Service) RunDue(now time.Time) (int, error) {
+	due, err := s.schedules.ListDue(now)
+	if err != nil {
+		return 0, err
+	}
+	ran := 0
+	var errs []error
+	for _, order := range due {
+		if err := s.run(order, now); err != nil {
+			errs = append(errs, fmt.Errorf("talimat #%d: %w", order.ID, err))
+			continue
+		}
+		ran++
+	}
+	return ran, errors.Join(errs...)
+}
+
+// Talimatın sıradaki çalıştırmasını dener, sonucu geçmişe yazar ve talimatı sonraki denemeye veya çalıştırmaya taşır
+func (s *Service) run(order *domain.ScheduledTransfer, now time.Time) error {
+	run := &domain.ScheduleRun{ScheduleID: order.ID, ScheduledFor: order.NextRunAt, Attempt: order.Attempts + 1, RanAt: now}
+	txID, err := s.executed(order)
+	if err == nil && txID == nil {
+		if err = s.transfers.Transfer(order.FromAccountID, order.ToAccountID, order.Amount, order.RunDetails()); err == nil {
+			txID, _ = s.executed(order)
+		}
+	}
+
+	switch {
+	case err == nil:
+		run.Status = domain.ScheduleRunSucceeded
+		run.TransactionID = txID
+		order.Advance(domain.ScheduleCompleted)
+	case errors.Is(err, domain.ErrInsufficientFunds) && run.Attempt < s.policy.MaxAttempts:
+		run.Status = domain.ScheduleRunRetrying
+		order.Attempts = run.Attempt
+		order.DueAt = now.Add(s.policy.Interval)
+	default:
+		run.Status = domain.ScheduleRunFailed
+		final := domain.ScheduleCompleted
+		if order.Frequency == domain.ScheduleOnce {
+			final = domain.ScheduleFailed
+		}
+		order.Advance(final)
+	}
+	if err != nil {
+		run.ErrorCode = string(domain.CodeInternal)
+		var derr *domain.Error
+		if errors.As(err, &derr) {
+			run.ErrorCode = string(derr.Code)
+		}
+		run.Error = err.Error()
+	}
+
+	order.UpdatedAt = now
+	if err := s.schedules.AddRun(run); err != nil {
+		return err
+	}
+	return s.schedules.Update(order)
+}
+
+// Talimatın sıradaki çalıştırması için daha önce oluşturulmuş transferin ID'sini döndürür; yoksa nil
+func (s *Service) executed(order *domain.ScheduledTransfer) (*int64, error) {
+	page, err := s.transfers.History(domain.TransactionFilter{
+		UserID:    order.OwnerID,
+		Direction: domain.DirectionOut,
+		Types:     []domain.TransactionType{domain.TransactionTransfer},
+		Metadata: map[string]string{
+			domain.ScheduleMetadataID:  strconv.FormatInt(order.ID, 10),
+			domain.ScheduleMetadataFor: order.NextRunAt.Format(time.RFC3339),
+		},
+		Limit: 1,
+	})
+	if err != nil || len(page.Transactions) == 0 {
+		return nil, err
+	}
+	return &page.Transactions[0].ID, nil
+}
@@ -0,0 +1,412 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"gofinancialsystem/internal/calendar"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/fees"
+	"gofinancialsystem/internal/repository/memtest"
+	"gofinancialsystem/internal/service"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errInjected = errors.New("enjekte edilen hata")
+
+// fakeClock, testin ileri aldığı zamandır
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+// flakySchedules, verilen talimatların sıradaki çalıştırma kaydına veya güncellemesine hata enjekte eder
+type flakySchedules struct {
+	domain.ScheduleRepository
+	failAddRun, failUpdate map[int64]int // Talimat ID -> hata verecek çağrı sayısı
+}
+
+func (r *flakySchedules) AddRun(run *domain.ScheduleRun) error {
+	if r.failAddRun[run.ScheduleID] > 0 {
+		r.failAddRun[run.ScheduleID]--
+		return errInjected
+	}
+	return r.ScheduleRepository.AddRun(run)
+}
+
+func (r *flakySchedules) Update(s *domain.ScheduledTransfer) error {
+	if r.failUpdate[s.ID] > 0 {
+		r.failUpdate[s.ID]--
+		return errInjected
+	}
+	return r.ScheduleRepository.Update(s)
+}
+
+// scheduleFixture, iki EUR hesabı arasında talimat testleri için servis ve repository'leri tutar
+type scheduleFixture struct {
+	clock        *fakeClock
+	schedules    *flakySchedules
+	balances     domain.BalanceRepository
+	transactions domain.TransactionRepository
+	transfers    *service.TransactionServiceImpl
+	service      *Service
+	from, to     *domain.Account
+}
+
+// Yetersiz bakiye 3 kez, birer saat arayla denenir
+func newScheduleFixture(t *testing.T, now time.Time) *scheduleFixture {
+	t.Helper()
+	repos := memtest.New()
+	f := &scheduleFixture{
+		clock:        &fakeClock{now: now},
+		schedules:    &flakySchedules{ScheduleRepository: repos.Schedules, failAddRun: map[int64]int{}, failUpdate: map[int64]int{}},
+		balances:     repos.Balances,
+		transactions: repos.Transactions,
+		transfers: service.NewTransactionService(repos.Transactions, repos.Accounts, repos.UnitOfWork, memtest.NoLimits{},
+			fees.NewEngine(nil, repos.Transactions), calendar.Default()),
+		from: memtest.OpenAccount(t, repos.Accounts, 1, "EUR"),
+		to:   memtest.OpenAccount(t, repos.Accounts, 2, "EUR"),
+	}
+	f.service = NewService(f.schedules, repos.Accounts, f.transfers, domain.ScheduleRetryPolicy{MaxAttempts: 3, Interval: time.Hour}, f.clock.Now)
+	return f
+}
+
+func (f *scheduleFixture) fund(t *testing.T, amount int64) {
+	t.Helper()
+	if err := f.transfers.Credit(f.from.ID, domain.NewMoney(amount, "EUR"), domain.TransactionDetails{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Talimatı 100,00 EUR için şimdiki zamandan itibaren oluşturur
+func (f *scheduleFixture) create(t *testing.T, order domain.ScheduledTransfer) *domain.ScheduledTransfer {
+	t.Helper()
+	order.FromAccountID, order.ToAccountID = f.from.ID, f.to.ID
+	order.Amount = domain.NewMoney(10000, "EUR")
+	if err := f.service.Create(&order, f.clock.now); err != nil {
+		t.Fatal(err)
+	}
+	return &order
+}
+
+// Saati at'e alır ve zamanı gelen talimatları çalıştırır
+func (f *scheduleFixture) runAt(t *testing.T, at time.Time) (int, error) {
+	t.Helper()
+	f.clock.now = at
+	return f.service.RunDue(at)
+}
+
+func (f *scheduleFixture) get(t *testing.T, id int64) *domain.ScheduledTransfer {
+	t.Helper()
+	order, err := f.service.GetByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return order
+}
+
+func (f *scheduleFixture) runs(t *testing.T, id int64) []*domain.ScheduleRun {
+	t.Helper()
+	runs, err := f.service.Runs(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return runs
+}
+
+// Alıcı hesabın bakiyesi; hesaba hiç para gelmemişse 0
+func (f *scheduleFixture) received(t *testing.T) int64 {
+	t.Helper()
+	balance, err := f.balances.Get(f.to.ID)
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		return 0
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return balance.Amount.Amount
+}
+
+func at(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+// Ayın 31'i kısa aylarda ayın son gününe kayar, sonraki aylarda tekrar 31'inde çalışır
+func TestMonthlyScheduleAcrossFebruary(t *testing.T) {
+	cases := []struct {
+		name  string
+		order domain.ScheduledTransfer
+		start time.Time
+		want  []time.Time
+	}{
+		{"ayın 31'i", domain.ScheduledTransfer{Frequency: domain.ScheduleMonthly, DayOfMonth: 31}, at(2027, 1, 10, 9),
+			[]time.Time{at(2027, 1, 31, 9), at(2027, 2, 28, 9), at(2027, 3, 31, 9), at(2027, 4, 30, 9), at(2027, 5, 31, 9)}},
+		{"ayın 30'u", domain.ScheduledTransfer{Frequency: domain.ScheduleMonthly, DayOfMonth: 30}, at(2027, 1, 10, 9),
+			[]time.Time{at(2027, 1, 30, 9), at(2027, 2, 28, 9), at(2027, 3, 30, 9)}},
+		{"ay sonu, artık yıl", domain.ScheduledTransfer{Frequency: domain.ScheduleMonthEnd}, at(2028, 1, 15, 9),
+			[]time.Time{at(2028, 1, 31, 9), at(2028, 2, 29, 9), at(2028, 3, 31, 9), at(2028, 4, 30, 9)}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newScheduleFixture(t, c.start)
+			f.fund(t, 100000)
+			order := f.create(t, c.order)
+
+			for _, want := range c.want {
+				if next := f.get(t, order.ID).NextRunAt; !next.Equal(want) {
+					t.Fatalf("sıradaki çalıştırma %s, beklenen %s", next, want)
+				}
+				if n, err := f.runAt(t, want.Add(-time.Second)); err != nil || n != 0 {
+					t.Fatalf("zamanından önce %d çalıştırma, hata %v", n, err)
+				}
+				if n, err := f.runAt(t, want); err != nil || n != 1 {
+					t.Fatalf("%s: %d çalıştırma, hata %v", want, n, err)
+				}
+			}
+			runs := f.runs(t, order.ID)
+			for i, run := range runs {
+				if run.Status != domain.ScheduleRunSucceeded || !run.ScheduledFor.Equal(c.want[i]) {
+					t.Fatalf("çalıştırma %d: %s %s, beklenen succeeded %s", i+1, run.Status, run.ScheduledFor, c.want[i])
+				}
+			}
+			if got, want := f.received(t), int64(len(c.want))*10000; got != want {
+				t.Fatalf("alıcı bakiyesi %d, beklenen %d", got, want)
+			}
+		})
+	}
+}
+
+// Yetersiz bakiye MaxAttempts denemeye kadar tekrar denenir; denemeler tükenince tek seferlik talimat
+// başarısız olur, tekrarlanan talimat sonraki çalıştırmaya geçer veya son çalıştırmasıysa tamamlanır
+func TestInsufficientFundsRetries(t *testing.T) {
+	start := at(2027, 1, 4, 9)
+	end := start.AddDate(0, 0, 1)
+	cases := []struct {
+		name       string
+		order      domain.ScheduledTransfer
+		fundBefore int // Bu denemeden önce hesaba para yatırılır; 0 ise yatırılmaz
+		want       []domain.ScheduleRunStatus
+		status     domain.ScheduleStatus
+		next       time.Time
+	}{
+		{"tek seferlik", domain.ScheduledTransfer{Frequency: domain.ScheduleOnce}, 0,
+			[]domain.ScheduleRunStatus{domain.ScheduleRunRetrying, domain.ScheduleRunRetrying, domain.ScheduleRunFailed},
+			domain.ScheduleFailed, start},
+		{"son çalıştırması olan haftalık", domain.ScheduledTransfer{Frequency: domain.ScheduleWeekly, EndDate: &end}, 0,
+			[]domain.ScheduleRunStatus{domain.ScheduleRunRetrying, domain.ScheduleRunRetrying, domain.ScheduleRunFailed},
+			domain.ScheduleCompleted, start},
+		{"haftalık", domain.ScheduledTransfer{Frequency: domain.ScheduleWeekly}, 0,
+			[]domain.ScheduleRunStatus{domain.ScheduleRunRetrying, domain.ScheduleRunRetrying, domain.ScheduleRunFailed},
+			domain.ScheduleActive, start.AddDate(0, 0, 7)},
+		{"tekrar denemede bakiye yeterli", domain.ScheduledTransfer{Frequency: domain.ScheduleOnce}, 2,
+			[]domain.ScheduleRunStatus{domain.ScheduleRunRetrying, domain.ScheduleRunSucceeded},
+			domain.ScheduleCompleted, start},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newScheduleFixture(t, start)
+			f.fund(t, 5000)
+			order := f.create(t, c.order)
+
+			for i := range c.want {
+				due := start.Add(time.Duration(i) * time.Hour)
+				if i > 0 {
+					if n, err := f.runAt(t, due.Add(-time.Second)); err != nil || n != 0 {
+						t.Fatalf("deneme zamanından önce %d çalıştırma, hata %v", n, err)
+					}
+				}
+				if i+1 == c.fundBefore {
+					f.fund(t, 10000)
+				}
+				if n, err := f.runAt(t, due); err != nil || n != 1 {
+					t.Fatalf("deneme %d: %d çalıştırma, hata %v", i+1, n, err)
+				}
+			}
+
+			runs := f.runs(t, order.ID)
+			if len(runs) != len(c.want) {
+				t.Fatalf("%d çalıştırma kaydı, beklenen %d", len(runs), len(c.want))
+			}
+			for i, run := range runs {
+				if run.Attempt != i+1 || run.Status != c.want[i] || !run.ScheduledFor.Equal(start) {
+					t.Fatalf("çalıştırma %d: deneme %d %s %s, beklenen deneme %d %s %s", i+1, run.Attempt, run.Status, run.ScheduledFor, i+1, c.want[i], start)
+				}
+				if failed := run.Status != domain.ScheduleRunSucceeded; failed != (run.ErrorCode == string(domain.CodeInsufficientFunds)) {
+					t.Fatalf("çalıştırma %d: hata kodu %q", i+1, run.ErrorCode)
+				}
+			}
+			current := f.get(t, order.ID)
+			if current.Status != c.status || !current.NextRunAt.Equal(c.next) || current.Attempts != 0 {
+				t.Fatalf("talimat %s, sıradaki %s, deneme %d; beklenen %s, %s, 0", current.Status, current.NextRunAt, current.Attempts, c.status, c.next)
+			}
+			var want int64
+			if c.fundBefore > 0 {
+				want = 10000
+			}
+			if got := f.received(t); got != want {
+				t.Fatalf("alıcı bakiyesi %d, beklenen %d", got, want)
+			}
+			// Denemeleri tükenen çalıştırma bir daha denenmez
+			if n, err := f.runAt(t, start.Add(time.Duration(len(c.want))*time.Hour)); err != nil || n != 0 {
+				t.Fatalf("denemeler bittikten sonra %d çalıştırma, hata %v", n, err)
+			}
+		})
+	}
+}
+
+// Durdurulan tekrarlanan talimat etkinleştirildiğinde aradaki çalıştırmalar yapılmaz;
+// tek seferlik talimat ise gecikmeli de olsa çalışır
+func TestPauseResumeSkipsMissedRuns(t *testing.T) {
+	paused, active := domain.SchedulePaused, domain.ScheduleActive
+	cases := []struct {
+		name      string
+		order     domain.ScheduledTransfer
+		first     time.Time // Durdurulmadan önce yapılan çalıştırma; sıfırsa yapılmaz
+		resume    time.Time
+		next      time.Time // Etkinleştirmeden sonraki çalıştırma
+		scheduled []time.Time
+	}{
+		{"haftalık", domain.ScheduledTransfer{Frequency: domain.ScheduleWeekly}, at(2027, 1, 4, 9), at(2027, 1, 26, 10), at(2027, 2, 1, 9),
+			[]time.Time{at(2027, 1, 4, 9), at(2027, 2, 1, 9)}},
+		{"aylık", domain.ScheduledTransfer{Frequency: domain.ScheduleMonthly, DayOfMonth: 31}, at(2027, 1, 31, 9), at(2027, 4, 2, 10), at(2027, 4, 30, 9),
+			[]time.Time{at(2027, 1, 31, 9), at(2027, 4, 30, 9)}},
+		{"tek seferlik", domain.ScheduledTransfer{Frequency: domain.ScheduleOnce}, time.Time{}, at(2027, 1, 26, 10), at(2027, 1, 4, 9),
+			[]time.Time{at(2027, 1, 4, 9)}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newScheduleFixture(t, at(2027, 1, 4, 9))
+			f.fund(t, 100000)
+			order := f.create(t, c.order)
+			if !c.first.IsZero() {
+				if n, err := f.runAt(t, c.first); err != nil || n != 1 {
+					t.Fatalf("ilk çalıştırma: %d, hata %v", n, err)
+				}
+			}
+			if _, err := f.service.Update(order.ID, domain.ScheduleUpdate{Status: &paused}); err != nil {
+				t.Fatal(err)
+			}
+			// Durdurulmuş talimat zamanı gelse de çalışmaz
+			if n, err := f.runAt(t, c.resume); err != nil || n != 0 {
+				t.Fatalf("durdurulmuş talimat: %d çalıştırma, hata %v", n, err)
+			}
+
+			resumed, err := f.service.Update(order.ID, domain.ScheduleUpdate{Status: &active})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !resumed.NextRunAt.Equal(c.next) || !resumed.DueAt.Equal(c.next) {
+				t.Fatalf("etkinleştirme sonrası sıradaki %s, deneme %s; beklenen %s", resumed.NextRunAt, resumed.DueAt, c.next)
+			}
+			if c.next.After(c.resume) {
+				if n, err := f.runAt(t, c.resume); err != nil || n != 0 {
+					t.Fatalf("kaçırılan çalıştırma yapıldı: %d, hata %v", n, err)
+				}
+			}
+			if n, err := f.runAt(t, later(c.next, c.resume)); err != nil || n != 1 {
+				t.Fatalf("etkinleştirme sonrası: %d çalıştırma, hata %v", n, err)
+			}
+
+			runs := f.runs(t, order.ID)
+			if len(runs) != len(c.scheduled) {
+				t.Fatalf("%d çalıştırma kaydı, beklenen %d", len(runs), len(c.scheduled))
+			}
+			for i, run := range runs {
+				if !run.ScheduledFor.Equal(c.scheduled[i]) || run.Status != domain.ScheduleRunSucceeded {
+					t.Fatalf("çalıştırma %d: %s %s, beklenen succeeded %s", i+1, run.Status, run.ScheduledFor, c.scheduled[i])
+				}
+			}
+			if got, want := f.received(t), int64(len(c.scheduled))*10000; got != want {
+				t.Fatalf("alıcı bakiyesi %d, beklenen %d", got, want)
+			}
+		})
+	}
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// Transfer yapıldıktan sonra çalıştırma kaydedilemezse sonraki tur transferi tekrarlamaz,
+// yalnızca mevcut transferle çalıştırmayı kaydeder
+func TestExecutedGuardPreventsDoubleTransfer(t *testing.T) {
+	cases := []struct {
+		name                   string
+		failAddRun, failUpdate int
+		runs                   int // Sonuçta kaydedilen çalıştırma sayısı
+	}{
+		{"çalıştırma kaydı", 1, 0, 1},
+		{"talimat güncellemesi", 0, 1, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			due := at(2027, 1, 4, 9)
+			f := newScheduleFixture(t, due)
+			f.fund(t, 100000)
+			order := f.create(t, domain.ScheduledTransfer{Frequency: domain.ScheduleOnce})
+
+			f.schedules.failAddRun[order.ID], f.schedules.failUpdate[order.ID] = c.failAddRun, c.failUpdate
+			if n, err := f.runAt(t, due); !errors.Is(err, errInjected) || n != 0 {
+				t.Fatalf("%d çalıştırma, hata %v; beklenen enjekte edilen hata", n, err)
+			}
+			if got := f.received(t); got != 10000 {
+				t.Fatalf("ilk denemeden sonra alıcı bakiyesi %d, beklenen 10000", got)
+			}
+			if current := f.get(t, order.ID); current.Status != domain.ScheduleActive {
+				t.Fatalf("kaydedilemeyen deneme sonrası talimat %s, beklenen active", current.Status)
+			}
+
+			if n, err := f.runAt(t, due.Add(time.Minute)); err != nil || n != 1 {
+				t.Fatalf("ikinci tur: %d çalıştırma, hata %v", n, err)
+			}
+			if got := f.received(t); got != 10000 {
+				t.Fatalf("transfer tekrarlandı: alıcı bakiyesi %d, beklenen 10000", got)
+			}
+			txs, err := f.transactions.ListByUser(f.to.OwnerID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(txs) != 1 {
+				t.Fatalf("alıcının %d işlemi var, beklenen 1", len(txs))
+			}
+			runs := f.runs(t, order.ID)
+			last := runs[len(runs)-1]
+			if len(runs) != c.runs || last.Status != domain.ScheduleRunSucceeded || last.TransactionID == nil || *last.TransactionID != txs[0].ID {
+				t.Fatalf("%d çalıştırma kaydı, son %+v; beklenen %d, işlem #%d", len(runs), last, c.runs, txs[0].ID)
+			}
+			if current := f.get(t, order.ID); current.Status != domain.ScheduleCompleted {
+				t.Fatalf("talimat %s, beklenen completed", current.Status)
+			}
+		})
+	}
+}
+
+// Bir talimatın denemesi kaydedilemezse aynı turdaki diğer talimatlar yine çalışır
+func TestRunDueContinuesAfterFailingOrder(t *testing.T) {
+	due := at(2027, 1, 4, 9)
+	f := newScheduleFixture(t, due)
+	f.fund(t, 100000)
+	failing := f.create(t, domain.ScheduledTransfer{Frequency: domain.ScheduleOnce})
+	healthy := f.create(t, domain.ScheduledTransfer{Frequency: domain.ScheduleOnce})
+
+	f.schedules.failUpdate[failing.ID] = 1
+	n, err := f.runAt(t, due)
+	if n != 1 || !errors.Is(err, errInjected) || !strings.Contains(err.Error(), fmt.Sprintf("talimat #%d", failing.ID)) {
+		t.Fatalf("%d çalıştırma, hata %v; beklenen 1 çalıştırma ve talimat #1 hatası", n, err)
+	}
+	if current := f.get(t, healthy.ID); current.Status != domain.ScheduleCompleted {
+		t.Fatalf("sağlam talimat %s, beklenen completed", current.Status)
+	}
+	if n, err := f.runAt(t, due.Add(time.Minute)); err != nil || n != 1 {
+		t.Fatalf("sonraki tur: %d çalıştırma, hata %v", n, err)
+	}
+	if current := f.get(t, failing.ID); current.Status != domain.ScheduleCompleted {
+		t.Fatalf("hatalı talimat %s, beklenen completed", current.Status)
+	}
+	if got := f.received(t); got != 20000 {
+		t.Fatalf("alıcı bakiyesi %d, beklenen 20000", got)
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"time"
+)
+
+// AccountServiceImpl, AccountService arayüzünün gerçek implementasyonudur
+type AccountServiceImpl struct {
+	accounts domain.AccountRepository
+	balances domain.BalanceRepository // Kapatılacak hesabın bakiyesini kontrol etmek için
+}
+
+// Yeni bir AccountServiceImpl oluşturur
+func NewAccountService(accounts domain.AccountRepository, balances domain.BalanceRepository) *AccountServiceImpl {
+	return &AccountServiceImpl{accounts: accounts, balances: balances}
+}
+
+// Yeni hesap açar. Alt hesaplar ana hesabın sahibine ve para birimine bağlıdır.
+// Kullanıcının bir para biriminde açtığı ilk (alt hesap olmayan) hesap varsayılan hesap olur.
+func (s *AccountServiceImpl) Open(account *domain.Account) error {
+	if account.Type == domain.AccountSub && account.ParentID != nil {
+		parent, err := s.accounts.FindByID(*account.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent.OwnerID != account.OwnerID || parent.Type == domain.AccountSub {
+			return domain.NewValidationError("invalid_parent_account", "alt hesabın ana hesabı aynı kullanıcının alt hesap olmayan bir hesabı olmalı")
+		}
+		if err := parent.EnsureActive(); err != nil {
+			return err
+		}
+		if account.Currency == "" {
+			account.Currency = parent.Currency
+		}
+		if domain.NormalizeCurrency(account.Currency) != parent.Currency {
+			return domain.ErrCurrencyMismatch.WithMessage("parent_account_currency", "alt hesap ana hesapla aynı para biriminde olmalı")
+		}
+	}
+	account.Currency = domain.NormalizeCurrency(account.Currency)
+	if err := account.Validate(); err != nil {
+		return err
+	}
+	if account.Type != domain.AccountSub && !account.Default {
+		if _, err := s.accounts.FindDefault(account.OwnerID, account.Currency); errors.Is(err, domain.ErrAccountNotFound) {
+			account.Default = true
+		} else if err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	account.Status = domain.AccountActive
+	account.CreatedAt = now
+	account.UpdatedAt = now
+	return s.accounts.Create(account)
+}
+
+// ID ile hesap getirir
+func (s *AccountServiceImpl) GetByID(id int64) (*domain.Account, error) {
+	return s.accounts.FindByID(id)
+}
+
+// Kullanıcının tüm hesaplarını listeler
+func (s *AccountServiceImpl) ListByOwner(ownerID int64) ([]*domain.Account, error) {
+	return s.accounts.ListByOwner(ownerID)
+}
+
+// Hesabın adını ve/veya durumunu günceller. Durum sadece active ile frozen arasında değişebilir;
+// kapatma Close ile yapılır ve kapatılmış hesap değiştirilemez.
+func (s *AccountServiceImpl) Update(id int64, nickname *string, status *domain.AccountStatus) (*domain.Account, error) {
+	account, err := s.accounts.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if account.Status == domain.AccountClosed {
+		return nil, domain.ErrAccountClosed
+	}
+	if nickname != nil {
+		account.Nickname = *nickname
+	}
+	if status != nil {
+		if *status != domain.AccountActive && *status != domain.AccountFrozen {
+			return nil, domain.NewValidationError("invalid_account_status", "hesap durumu active veya frozen olmalı")
+		}
+		account.Status = *status
+	}
+	if err := account.Validate(); err != nil {
+		return nil, err
+	}
+	account.UpdatedAt = time.Now()
+	if err := s.accounts.Update(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// Bakiyesi sıfır olan hesabı kalıcı olarak kapatır. Varsayılan hesaplar ve
+// açık alt hesabı olan hesaplar kapatılamaz.
+func (s *AccountServiceImpl) Close(id int64) (*domain.Account, error) {
+	account, err := s.accounts.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if account.Status == domain.AccountClosed {
+		return nil, domain.ErrAccountClosed
+	}
+	if account.Default {
+		return nil, domain.ErrConflict.WithMessage("default_account", "varsayılan hesap kapatılamaz")
+	}
+	siblings, err := s.accounts.ListByOwner(account.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range siblings {
+		if a.ParentID != nil && *a.ParentID == account.ID && a.Status != domain.AccountClosed {
+			return nil, domain.ErrConflict.WithMessage("has_open_sub_accounts", "açık alt hesapları olan hesap kapatılamaz")
+		}
+	}
+	balance, err := s.balances.Get(account.ID)
+	if err != nil && !errors.Is(err, domain.ErrAccountNotFound) {
+		return nil, err
+	}
+	if balance != nil && !balance.Amount.IsZero() {
+		return nil, domain.ErrConflict.WithMessage("account_not_empty", "bakiyesi sıfır olmayan hesap kapatılamaz")
+	}
+	account.Status = domain.AccountClosed
+	account.UpdatedAt = time.Now()
+	if err := s.accounts.Update(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// Kullanıcının para birimindeki varsayılan hesabını döndürür; create true ise yoksa vadesiz hesap açar
+func (s *AccountServiceImpl) DefaultAccount(ownerID int64, currency string, create bool) (*domain.Account, error) {
+	currency, err := domain.ValidateCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.accounts.FindDefault(ownerID, currency)
+	if !create || !errors.Is(err, domain.ErrAccountNotFound) {
+		return account, err
+	}
+	account = &domain.Account{OwnerID: ownerID, Type: domain.AccountChecking, Currency: currency, Default: true}
+	if err := s.Open(account); err != nil {
+		// Eşzamanlı bir istek aynı varsayılan hesabı açmış olabilir
+		if errors.Is(err, domain.ErrConflict) {
+			return s.accounts.FindDefault(ownerID, currency)
+		}
+		return nil, err
+	}
+	return account, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/ledger"
 	"sync"
 	"time"
 )
@@ -10,96 +11,143 @@ import (
 // BalanceServiceImpl, BalanceService interface'ini implement eder
 type BalanceServiceImpl struct {
 	balanceRepo domain.BalanceRepository
-	// Thread-safe balance cache
-	balanceCache map[int64]*domain.Balance
-	cacheMutex   sync.RWMutex
-	// Historical balance tracking
+	accountRepo domain.AccountRepository
+	ledgerRepo  domain.LedgerRepository
+	uow         domain.UnitOfWork
+	calendar    domain.BusinessCalendar // Düzeltmelerin ve bakiye sorgularının valör günlerini belirlemek için
+	// Historical balance tracking (hesap ID -> geçmiş)
 	balanceHistory map[int64][]*domain.Balance
 	historyMutex   sync.RWMutex
 }
 
 // NewBalanceService, yeni bir BalanceService instance'ı oluşturur
-func NewBalanceService(balanceRepo domain.BalanceRepository) domain.BalanceService {
+func NewBalanceService(balanceRepo domain.BalanceRepository, accountRepo domain.AccountRepository, ledgerRepo domain.LedgerRepository, uow domain.UnitOfWork, calendar domain.BusinessCalendar) domain.BalanceService {
 	return &BalanceServiceImpl{
 		balanceRepo:    balanceRepo,
-		balanceCache:   make(map[int64]*domain.Balance),
+		accountRepo:    accountRepo,
+		ledgerRepo:     ledgerRepo,
+		uow:            uow,
+		calendar:       calendar,
 		balanceHistory: make(map[int64][]*domain.Balance),
 	}
 }
 
-// GetBalance, kullanıcının mevcut bakiyesini getirir
-func (s *BalanceServiceImpl) GetBalance(userID int64) (*domain.Balance, error) {
-	// Önce cache'den kontrol et
-	s.cacheMutex.RLock()
-	if balance, exists := s.balanceCache[userID]; exists {
-		s.cacheMutex.RUnlock()
-		return balance, nil
+// GetBalance, hesabın mevcut bakiyesini getirir; henüz hareket görmemiş hesaplar için sıfır döner.
+// Bakiye projeksiyonu her para hareketinde ledger.Post ile güncellendiği için ayrıca cache'lenmez.
+func (s *BalanceServiceImpl) GetBalance(accountID int64) (*domain.Balance, error) {
+	account, err := s.accountRepo.FindByID(accountID)
+	if err != nil {
+		return nil, err
 	}
-	s.cacheMutex.RUnlock()
+	return s.accountBalance(account)
+}
 
-	// Cache'de yoksa repository'den al
-	balance, err := s.balanceRepo.GetByUserID(userID)
+// ListBalances, kullanıcının tüm hesaplarının bakiyelerini getirir
+func (s *BalanceServiceImpl) ListBalances(userID int64) ([]*domain.Balance, error) {
+	accounts, err := s.accountRepo.ListByOwner(userID)
 	if err != nil {
 		return nil, err
 	}
+	balances := make([]*domain.Balance, 0, len(accounts))
+	for _, account := range accounts {
+		balance, err := s.accountBalance(account)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, balance)
+	}
+	return balances, nil
+}
 
-	// Cache'e ekle
-	s.cacheMutex.Lock()
-	s.balanceCache[userID] = balance
-	s.cacheMutex.Unlock()
-
-	return balance, nil
+// Hesabın bakiye projeksiyonunu okur; bakiye satırı yoksa hesabın para biriminde sıfır döner
+func (s *BalanceServiceImpl) accountBalance(account *domain.Account) (*domain.Balance, error) {
+	balance, err := s.balanceRepo.Get(account.ID)
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		zero := &domain.Balance{AccountID: account.ID, UserID: account.OwnerID, LastUpdatedAt: account.CreatedAt}
+		zero.SetAmounts(domain.NewMoney(0, account.Currency), domain.NewMoney(0, account.Currency), domain.NewMoney(0, account.Currency))
+		return zero, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.Balance{AccountID: account.ID, UserID: account.OwnerID, LastUpdatedAt: balance.LastUpdatedAt}
+	result.SetAmounts(balance.Amount, balance.Held, balance.OverdraftLimit)
+	return result, nil
 }
 
-// UpdateBalance, kullanıcının bakiyesini günceller
-func (s *BalanceServiceImpl) UpdateBalance(userID int64, amount float64) error {
-	// Thread-safe balance update
-	s.cacheMutex.Lock()
-	defer s.cacheMutex.Unlock()
+// SetOverdraftLimit, hesabın kredi limitini değiştirir ve güncel bakiyeyi döndürür.
+// Limit hesabın para biriminde ve negatif olmayan bir tutar olmalıdır; sıfır kredili kullanımı kapatır.
+func (s *BalanceServiceImpl) SetOverdraftLimit(accountID int64, limit domain.Money) (*domain.Balance, error) {
+	account, err := s.accountRepo.FindByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Status == domain.AccountClosed {
+		return nil, domain.ErrAccountClosed.WithDetails(map[string]interface{}{"account_id": account.ID})
+	}
+	if err := account.EnsureCurrency(limit); err != nil {
+		return nil, err
+	}
+	if limit.IsNegative() {
+		return nil, domain.NewValidationError("negative_overdraft_limit", "kredi limiti negatif olamaz")
+	}
 
-	// Mevcut balance'ı al veya oluştur
-	balance, exists := s.balanceCache[userID]
-	if !exists {
-		balance = &domain.Balance{
-			UserID:        userID,
-			Amount:        0,
-			LastUpdatedAt: time.Now(),
-		}
-		s.balanceCache[userID] = balance
+	if err := s.uow.Do(func(repos domain.Repositories) error {
+		return repos.Balances.SetOverdraftLimit(accountID, limit)
+	}); err != nil {
+		return nil, err
+	}
+	return s.accountBalance(account)
+}
+
+// UpdateBalance, hesabın bakiyesini düzeltme kaydıyla günceller
+func (s *BalanceServiceImpl) UpdateBalance(accountID int64, amount domain.Money) error {
+	account, err := s.accountRepo.FindByID(accountID)
+	if err != nil {
+		return err
+	}
+	if err := account.EnsureCurrency(amount); err != nil {
+		return err
 	}
 
-	// Balance'ı güncelle
-	balance.Add(amount)
-	balance.LastUpdatedAt = time.Now()
+	// Düzeltme suspense hesabı karşılığıyla ledger'a yazılır ve kaydedildiği gün valörlenir
+	entry := ledger.AdjustmentEntry(accountID, amount)
+	entry.ValueDate = s.calendar.Date(entry.CreatedAt)
+	if err := s.uow.Do(func(repos domain.Repositories) error {
+		return ledger.Post(repos, entry)
+	}); err != nil {
+		return err
+	}
 
-	// Repository'yi güncelle
-	if err := s.balanceRepo.Update(userID, amount); err != nil {
+	balance, err := s.accountBalance(account)
+	if err != nil {
 		return err
 	}
 
 	// Historical tracking
 	s.historyMutex.Lock()
-	if s.balanceHistory[userID] == nil {
-		s.balanceHistory[userID] = make([]*domain.Balance, 0)
+	if s.balanceHistory[accountID] == nil {
+		s.balanceHistory[accountID] = make([]*domain.Balance, 0)
 	}
 	// Historical copy oluştur
 	historicalBalance := &domain.Balance{
+		AccountID:     balance.AccountID,
 		UserID:        balance.UserID,
-		Amount:        balance.Amount,
 		LastUpdatedAt: balance.LastUpdatedAt,
 	}
-	s.balanceHistory[userID] = append(s.balanceHistory[userID], historicalBalance)
+	historicalBalance.SetAmounts(balance.Amount, balance.Held, balance.OverdraftLimit)
+	s.balanceHistory[accountID] = append(s.balanceHistory[accountID], historicalBalance)
 	s.historyMutex.Unlock()
 
 	return nil
 }
 
-// GetBalanceHistory, kullanıcının bakiye geçmişini getirir
-func (s *BalanceServiceImpl) GetBalanceHistory(userID int64) ([]*domain.Balance, error) {
+// GetBalanceHistory, hesabın bakiye geçmişini getirir
+func (s *BalanceServiceImpl) GetBalanceHistory(accountID int64) ([]*domain.Balance, error) {
 	s.historyMutex.RLock()
 	defer s.historyMutex.RUnlock()
 
-	history, exists := s.balanceHistory[userID]
+	history, exists := s.balanceHistory[accountID]
 	if !exists {
 		return []*domain.Balance{}, nil
 	}
@@ -107,43 +155,43 @@ func (s *BalanceServiceImpl) GetBalanceHistory(userID int64) ([]*domain.Balance,
 	return history, nil
 }
 
-// GetBalanceAtTime, belirli bir zamandaki bakiyeyi getirir (basit implementasyon)
-func (s *BalanceServiceImpl) GetBalanceAtTime(userID int64, targetTime time.Time) (*domain.Balance, error) {
-	s.historyMutex.RLock()
-	defer s.historyMutex.RUnlock()
-
-	history, exists := s.balanceHistory[userID]
-	if !exists {
-		return nil, errors.New("bakiye geçmişi bulunamadı")
+// GetBalanceAtTime, hesabın targetTime anındaki valörlü bakiyesini getirir: targetTime'dan önce kaydedilmiş
+// ve valör tarihi takvime göre targetTime günü veya öncesi olan işlemler sayılır. Provizyon ve kredi limiti
+// geçmişi tutulmadığı için dönen bakiyede bunlar sıfırdır.
+func (s *BalanceServiceImpl) GetBalanceAtTime(accountID int64, targetTime time.Time) (*domain.Balance, error) {
+	account, err := s.accountRepo.FindByID(accountID)
+	if err != nil {
+		return nil, err
 	}
-
-	// En yakın zamandaki balance'ı bul
-	var closestBalance *domain.Balance
-	var minDiff time.Duration
-
-	for _, balance := range history {
-		diff := targetTime.Sub(balance.LastUpdatedAt)
-		if diff < 0 {
-			diff = -diff
-		}
-		if closestBalance == nil || diff < minDiff {
-			closestBalance = balance
-			minDiff = diff
-		}
+	if targetTime.Before(account.CreatedAt) {
+		return nil, domain.ErrAccountNotFound.WithMessage("no_balance_at_time", "belirtilen zamanda bakiye bulunamadı")
 	}
 
-	if closestBalance == nil {
-		return nil, errors.New("belirtilen zamanda bakiye bulunamadı")
+	amount, err := s.ledgerRepo.AccountBalanceAt(ledger.CustomerAccount(accountID), account.Currency, targetTime, s.calendar.Date(targetTime))
+	if err != nil {
+		return nil, err
 	}
+	zero := domain.NewMoney(0, account.Currency)
+	balance := &domain.Balance{AccountID: account.ID, UserID: account.OwnerID, LastUpdatedAt: targetTime}
+	balance.SetAmounts(amount, zero, zero)
+	return balance, nil
+}
 
-	return closestBalance, nil
+// CalculateBalance, hesabın bakiyesini ledger postinglerinden yeniden hesaplar
+func (s *BalanceServiceImpl) CalculateBalance(accountID int64) (domain.Money, error) {
+	account, err := s.accountRepo.FindByID(accountID)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	return s.ledgerRepo.AccountBalance(ledger.CustomerAccount(accountID), account.Currency)
 }
 
-// CalculateBalance, kullanıcının toplam bakiyesini hesaplar (optimizasyon için)
-func (s *BalanceServiceImpl) CalculateBalance(userID int64) (float64, error) {
-	balance, err := s.GetBalance(userID)
+// ValueDatedBalance, hesabın at anından önce kaydedilmiş ve valör tarihi day veya öncesi olan ledger kayıtlarıyla
+// bakiyesini hesaplar; faiz tahakkuku gün sonu bakiyesi için kullanır
+func (s *BalanceServiceImpl) ValueDatedBalance(accountID int64, at, day time.Time) (domain.Money, error) {
+	account, err := s.accountRepo.FindByID(accountID)
 	if err != nil {
-		return 0, err
+		return domain.Money{}, err
 	}
-	return balance.Amount, nil
+	return s.ledgerRepo.AccountBalanceAt(ledger.CustomerAccount(accountID), account.Currency, at, domain.StartOfDay(day))
 }
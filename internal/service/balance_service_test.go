@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"gofinancialsystem/internal/calendar"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/fees"
+	"gofinancialsystem/internal/repository/memtest"
+	"testing"
+)
+
+func TestOverdraftLimit(t *testing.T) {
+	repos := memtest.New()
+	balanceService := NewBalanceService(repos.Balances, repos.Accounts, repos.Ledger, repos.UnitOfWork, calendar.Default())
+	transactionService := NewTransactionService(repos.Transactions, repos.Accounts, repos.UnitOfWork, memtest.NoLimits{}, fees.NewEngine(nil, repos.Transactions), calendar.Default())
+	account := memtest.OpenAccount(t, repos.Accounts, 1, "USD")
+	usd := func(cents int64) domain.Money { return domain.NewMoney(cents, "USD") }
+	expect := func(step string, amount, credit int64) {
+		t.Helper()
+		balance, err := balanceService.GetBalance(account.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if balance.Amount != usd(amount) || balance.AvailableCredit != usd(credit) {
+			t.Fatalf("%s: bakiye %s, kullanılabilir kredi %s; beklenen %s ve %s", step, balance.Amount, balance.AvailableCredit, usd(amount), usd(credit))
+		}
+	}
+
+	if err := transactionService.Debit(account.ID, usd(100), domain.TransactionDetails{}); !errors.Is(err, domain.ErrInsufficientFunds) {
+		t.Fatalf("limitsiz hesapta eksiye düşme: %v, beklenen ErrInsufficientFunds", err)
+	}
+	if _, err := balanceService.SetOverdraftLimit(account.ID, usd(-100)); err == nil {
+		t.Fatal("negatif kredi limiti reddedilmeli")
+	}
+	if _, err := balanceService.SetOverdraftLimit(account.ID, domain.NewMoney(50000, "TRY")); err == nil {
+		t.Fatal("hesabın para biriminde olmayan kredi limiti reddedilmeli")
+	}
+
+	balance, err := balanceService.SetOverdraftLimit(account.ID, usd(50000))
+	if err != nil || balance.OverdraftLimit != usd(50000) || balance.AvailableCredit != usd(50000) {
+		t.Fatalf("SetOverdraftLimit = %+v, %v", balance, err)
+	}
+	if err := transactionService.Debit(account.ID, usd(40000), domain.TransactionDetails{}); err != nil {
+		t.Fatal(err)
+	}
+	expect("kredili para çekme", -40000, 10000)
+	if err := transactionService.Debit(account.ID, usd(20000), domain.TransactionDetails{}); !errors.Is(err, domain.ErrInsufficientFunds) {
+		t.Fatalf("limiti aşan para çekme: %v, beklenen ErrInsufficientFunds", err)
+	}
+	expect("reddedilen para çekme", -40000, 10000)
+
+	// Limit kullanılan tutarın altına düşürülebilir; hesaba para girişi yine yapılır ama çıkış yapılamaz
+	if _, err := balanceService.SetOverdraftLimit(account.ID, usd(10000)); err != nil {
+		t.Fatal(err)
+	}
+	if err := transactionService.Credit(account.ID, usd(5000), domain.TransactionDetails{}); err != nil {
+		t.Fatal(err)
+	}
+	expect("limit düşürüldükten sonra", -35000, 0)
+	if err := transactionService.Debit(account.ID, usd(1), domain.TransactionDetails{}); !errors.Is(err, domain.ErrInsufficientFunds) {
+		t.Fatalf("limit üstündeki hesaptan para çekme: %v, beklenen ErrInsufficientFunds", err)
+	}
+}
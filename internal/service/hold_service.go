@@ -0,0 +1,223 @@
+package service
+
+import (
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/ledger"
+	"time"
+)
+
+// HoldServiceImpl, HoldService arayüzünün gerçek implementasyonudur
+type HoldServiceImpl struct {
+	holds      domain.HoldRepository    // Provizyon okuma işlemleri için repository
+	accounts   domain.AccountRepository // Hesap sahibi, para birimi ve durum kontrolleri için
+	uow        domain.UnitOfWork        // Provizyon kaydını, bakiyeyi ve tahsilat işlemini birlikte commit etmek için
+	limits     domain.LimitService      // Provizyon tahsil edildiğinde oluşacak para çıkışını önceden denetlemek için
+	calendar   domain.BusinessCalendar  // Tahsilat işleminin muhasebe ve valör tarihlerini belirlemek için
+	defaultTTL time.Duration
+}
+
+// Yeni bir HoldServiceImpl oluşturur; defaultTTL süresi belirtilmeyen provizyonlara uygulanır
+func NewHoldService(holds domain.HoldRepository, accounts domain.AccountRepository, uow domain.UnitOfWork, limits domain.LimitService, defaultTTL time.Duration, calendar domain.BusinessCalendar) *HoldServiceImpl {
+	return &HoldServiceImpl{holds: holds, accounts: accounts, uow: uow, limits: limits, calendar: calendar, defaultTTL: defaultTTL}
+}
+
+// Hesabın kullanılabilir bakiyesinden tutarı ayırır. Ledger bakiyesi değişmez; kullanılabilir bakiye
+// yetmiyorsa ErrInsufficientFunds döner. Provizyon ttl sonunda tahsil edilmemişse serbest bırakılır.
+// İşlem limitleri provizyon konurken denetlenir; tahsilatta tekrar denetlenmez.
+func (s *HoldServiceImpl) Place(hold *domain.Hold, ttl time.Duration) error {
+	if err := validateMovement(hold.Amount, &hold.TransactionDetails); err != nil {
+		return err
+	}
+	if ttl == 0 {
+		ttl = s.defaultTTL
+	}
+	if ttl < 0 || ttl > domain.MaxHoldTTL {
+		return domain.NewValidationError("invalid_hold_ttl", "provizyon süresi en fazla {max_hours} saat olabilir").
+			WithParams(map[string]interface{}{"max_hours": int(domain.MaxHoldTTL.Hours())})
+	}
+	account, err := movableAccount(s.accounts, hold.AccountID, hold.Amount)
+	if err != nil {
+		return err
+	}
+	movement := domain.LimitMovement{UserID: account.OwnerID, Type: domain.TransactionWithdraw, Amount: hold.Amount}
+	if hold.ToAccountID != nil {
+		if *hold.ToAccountID == account.ID {
+			return domain.NewValidationError("same_account_transfer", "gönderen ve alıcı hesap aynı olamaz")
+		}
+		to, err := movableAccount(s.accounts, *hold.ToAccountID, hold.Amount)
+		if err != nil {
+			return err
+		}
+		movement.Type = domain.TransactionTransfer
+		movement.CounterpartyID = &to.OwnerID
+	}
+
+	now := time.Now()
+	hold.OwnerID = account.OwnerID
+	hold.Amount.Currency = account.Currency
+	hold.CapturedAmount = domain.NewMoney(0, account.Currency)
+	hold.Status = domain.HoldAuthorized
+	hold.TransactionID = nil
+	hold.ExpiresAt = now.Add(ttl)
+	hold.CreatedAt = now
+	hold.UpdatedAt = now
+	return s.uow.Do(func(repos domain.Repositories) error {
+		if err := s.limits.Check(repos.Transactions, movement); err != nil {
+			return err
+		}
+		if err := repos.Balances.Hold(account.ID, hold.Amount); err != nil {
+			return err
+		}
+		return repos.Holds.Create(hold)
+	})
+}
+
+// ID ile provizyon getirir
+func (s *HoldServiceImpl) GetByID(id int64) (*domain.Hold, error) {
+	return s.holds.FindByID(id)
+}
+
+// Hesabın tüm provizyonlarını listeler
+func (s *HoldServiceImpl) ListByAccount(accountID int64) ([]*domain.Hold, error) {
+	return s.holds.ListByAccount(accountID)
+}
+
+// Provizyonun amount kadarını tahsil eder (nil ise tamamını). Provizyonun tamamı serbest bırakılır ve
+// tahsil edilen tutar için tamamlanmış bir para çekme (alıcı hesap varsa transfer) işlemi oluşturulur;
+// kalan tutar kullanılabilir bakiyeye geri döner. Bir provizyon sadece bir kez tahsil edilebilir.
+func (s *HoldServiceImpl) Capture(id int64, amount *domain.Money) (*domain.Hold, error) {
+	var result *domain.Hold
+	err := s.uow.Do(func(repos domain.Repositories) error {
+		hold, err := repos.Holds.FindByIDForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if err := hold.EnsureAuthorized(); err != nil {
+			return err
+		}
+		if hold.Expired(time.Now()) {
+			return domain.ErrHoldExpired
+		}
+		captured := hold.Amount
+		if amount != nil {
+			if err := validateAmount(*amount); err != nil {
+				return err
+			}
+			if domain.NormalizeCurrency(amount.Currency) != hold.Amount.Currency {
+				return domain.ErrCurrencyMismatch.WithMessage("hold_currency", "tahsil tutarı provizyonun para biriminde olmalı: {currency}").
+					WithParams(map[string]interface{}{"currency": hold.Amount.Currency})
+			}
+			if amount.Amount > hold.Amount.Amount {
+				return domain.NewValidationError("capture_exceeds_hold", "tahsil tutarı provizyon tutarını aşamaz: {amount}").
+					WithParams(map[string]interface{}{"amount": hold.Amount})
+			}
+			captured = domain.NewMoney(amount.Amount, hold.Amount.Currency)
+		}
+		from, err := movableAccount(s.accounts, hold.AccountID, captured)
+		if err != nil {
+			return err
+		}
+		if err := repos.Balances.Hold(hold.AccountID, hold.Amount.Neg()); err != nil {
+			return err
+		}
+
+		tx := &domain.Transaction{
+			FromUserID:         &from.OwnerID,
+			FromAccountID:      &from.ID,
+			Amount:             captured,
+			Type:               domain.TransactionWithdraw,
+			Status:             domain.TransactionPending,
+			CreatedAt:          time.Now(),
+			TransactionDetails: hold.TransactionDetails,
+		}
+		entry := func() *domain.JournalEntry { return ledger.DebitEntry(tx.ID, from.ID, captured) }
+		if hold.ToAccountID != nil {
+			to, err := movableAccount(s.accounts, *hold.ToAccountID, captured)
+			if err != nil {
+				return err
+			}
+			tx.Type = domain.TransactionTransfer
+			tx.ToUserID = &to.OwnerID
+			tx.ToAccountID = &to.ID
+			entry = func() *domain.JournalEntry { return ledger.TransferEntry(tx.ID, from.ID, to.ID, captured) }
+		}
+		tx.BookingDate, tx.ValueDate = s.calendar.Dates(tx.Type, tx.CreatedAt)
+		if err := complete(repos, tx); err != nil {
+			return err
+		}
+		if err := post(repos, tx, entry()); err != nil {
+			return err
+		}
+
+		hold.Status = domain.HoldCaptured
+		hold.CapturedAmount = captured
+		hold.TransactionID = &tx.ID
+		hold.UpdatedAt = time.Now()
+		if err := repos.Holds.Update(hold); err != nil {
+			return err
+		}
+		result = hold
+		return nil
+	})
+	return result, err
+}
+
+// Provizyonu iptal eder ve tutarın tamamını kullanılabilir bakiyeye geri verir
+func (s *HoldServiceImpl) Void(id int64) (*domain.Hold, error) {
+	var result *domain.Hold
+	err := s.uow.Do(func(repos domain.Repositories) error {
+		hold, err := repos.Holds.FindByIDForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if err := hold.EnsureAuthorized(); err != nil {
+			return err
+		}
+		if err := release(repos, hold, domain.HoldVoided); err != nil {
+			return err
+		}
+		result = hold
+		return nil
+	})
+	return result, err
+}
+
+// Süresi now itibarıyla dolmuş provizyonları tek tek serbest bırakır. Her provizyon kendi unit of work'ünde
+// kilitlenip yeniden kontrol edilir; böylece aynı anda tahsil veya iptal edilen provizyon atlanır.
+func (s *HoldServiceImpl) ExpireDue(now time.Time) (int, error) {
+	due, err := s.holds.ListExpired(now)
+	if err != nil {
+		return 0, err
+	}
+	expired := 0
+	for _, candidate := range due {
+		err := s.uow.Do(func(repos domain.Repositories) error {
+			hold, err := repos.Holds.FindByIDForUpdate(candidate.ID)
+			if err != nil {
+				return err
+			}
+			if hold.Status != domain.HoldAuthorized || !hold.Expired(now) {
+				return nil
+			}
+			if err := release(repos, hold, domain.HoldExpired); err != nil {
+				return err
+			}
+			expired++
+			return nil
+		})
+		if err != nil {
+			return expired, err
+		}
+	}
+	return expired, nil
+}
+
+// Provizyonun tamamını serbest bırakır ve provizyonu verilen son duruma geçirir
+func release(repos domain.Repositories, hold *domain.Hold, status domain.HoldStatus) error {
+	if err := repos.Balances.Hold(hold.AccountID, hold.Amount.Neg()); err != nil {
+		return err
+	}
+	hold.Status = status
+	hold.UpdatedAt = time.Now()
+	return repos.Holds.Update(hold)
+}
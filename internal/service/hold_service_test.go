@@ -0,0 +1,210 @@
+package service
+
+import (
+	"errors"
+	"gofinancialsystem/internal/calendar"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/fees"
+	"gofinancialsystem/internal/repository/memtest"
+	"testing"
+	"time"
+)
+
+// errValidation, errors.Is ile herhangi bir doğrulama hatasını eşleştirir
+var errValidation = domain.NewError(domain.CodeValidationFailed, "")
+
+type holdFixture struct {
+	repos      *memtest.Repositories
+	holds      *HoldServiceImpl
+	alice, bob *domain.Account
+}
+
+// Alice'in hesabına 1000,00 TRY yatırılmış, varsayılan provizyon süresi bir saat olan bir ortam kurar
+func newHoldFixture(t *testing.T) *holdFixture {
+	t.Helper()
+	repos := memtest.New()
+	f := &holdFixture{
+		repos: repos,
+		holds: NewHoldService(repos.Holds, repos.Accounts, repos.UnitOfWork, memtest.NoLimits{}, time.Hour, calendar.Default()),
+		alice: memtest.OpenAccount(t, repos.Accounts, 1, "TRY"),
+		bob:   memtest.OpenAccount(t, repos.Accounts, 2, "TRY"),
+	}
+	transactions := NewTransactionService(repos.Transactions, repos.Accounts, repos.UnitOfWork, memtest.NoLimits{}, fees.NewEngine(nil, repos.Transactions), calendar.Default())
+	if err := transactions.Credit(f.alice.ID, domain.NewMoney(100000, "TRY"), domain.TransactionDetails{}); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func (f *holdFixture) place(t *testing.T, amount int64, toBob bool, ttl time.Duration) *domain.Hold {
+	t.Helper()
+	hold := &domain.Hold{AccountID: f.alice.ID, Amount: domain.NewMoney(amount, "TRY")}
+	if toBob {
+		hold.ToAccountID = &f.bob.ID
+	}
+	if err := f.holds.Place(hold, ttl); err != nil {
+		t.Fatal(err)
+	}
+	return hold
+}
+
+// Hesabın ledger bakiyesini ve provizyondaki tutarını döndürür; bakiyesi hiç oluşmamış hesap için sıfırdır
+func (f *holdFixture) balance(account *domain.Account) (ledger, held int64) {
+	balance, err := f.repos.Balances.Get(account.ID)
+	if err != nil {
+		return 0, 0
+	}
+	return balance.Amount.Amount, balance.Held.Amount
+}
+
+func TestHoldLifecycle(t *testing.T) {
+	try := func(minor int64) *domain.Money { m := domain.NewMoney(minor, "TRY"); return &m }
+	capture := func(amount *domain.Money) func(*HoldServiceImpl, int64) error {
+		return func(s *HoldServiceImpl, id int64) error { _, err := s.Capture(id, amount); return err }
+	}
+	void := func(s *HoldServiceImpl, id int64) error { _, err := s.Void(id); return err }
+	expireAt := func(after time.Duration) func(*HoldServiceImpl, int64) error {
+		return func(s *HoldServiceImpl, id int64) error { _, err := s.ExpireDue(time.Now().Add(after)); return err }
+	}
+
+	// Her senaryoda Alice'in 1000,00 TRY'sinin 300,00 TRY'si provizyona alınır
+	cases := []struct {
+		name     string
+		toBob    bool
+		ttl      time.Duration
+		actions  []func(*HoldServiceImpl, int64) error // Son işlem dışındakiler hatasız tamamlanmalı
+		err      error
+		status   domain.HoldStatus
+		captured int64
+		alice    int64 // Alice'in ledger bakiyesi
+		held     int64 // Alice'in provizyondaki tutarı
+		bob      int64
+	}{
+		{"tamamı tahsil", false, 0, []func(*HoldServiceImpl, int64) error{capture(nil)}, nil, domain.HoldCaptured, 30000, 70000, 0, 0},
+		// Tahsil edilmeyen kısım kullanılabilir bakiyeye geri döner
+		{"kısmi tahsil", false, 0, []func(*HoldServiceImpl, int64) error{capture(try(10000))}, nil, domain.HoldCaptured, 10000, 90000, 0, 0},
+		{"transfer olarak tahsil", true, 0, []func(*HoldServiceImpl, int64) error{capture(try(25000))}, nil, domain.HoldCaptured, 25000, 75000, 0, 25000},
+		{"iptal", false, 0, []func(*HoldServiceImpl, int64) error{void}, nil, domain.HoldVoided, 0, 100000, 0, 0},
+		{"süre aşımı", true, 0, []func(*HoldServiceImpl, int64) error{expireAt(time.Hour)}, nil, domain.HoldExpired, 0, 100000, 0, 0},
+		{"süresi dolmamış provizyon", false, 0, []func(*HoldServiceImpl, int64) error{expireAt(59 * time.Minute)}, nil, domain.HoldAuthorized, 0, 100000, 30000, 0},
+
+		// Reddedilen işlemler provizyonu ve bakiyeleri değiştirmez
+		{"provizyonu aşan tahsil", false, 0, []func(*HoldServiceImpl, int64) error{capture(try(30001))}, errValidation, domain.HoldAuthorized, 0, 100000, 30000, 0},
+		{"farklı para biriminde tahsil", false, 0, []func(*HoldServiceImpl, int64) error{capture(&domain.Money{Amount: 100, Currency: "USD"})}, domain.ErrCurrencyMismatch, domain.HoldAuthorized, 0, 100000, 30000, 0},
+		{"sıfır tahsil", false, 0, []func(*HoldServiceImpl, int64) error{capture(try(0))}, domain.ErrInvalidAmount, domain.HoldAuthorized, 0, 100000, 30000, 0},
+		// Süresi dolan provizyon tahsil edilemez; tutarı ExpireDue çalışana kadar ayrılmış kalır
+		{"süresi dolmuş provizyonu tahsil", false, time.Nanosecond, []func(*HoldServiceImpl, int64) error{capture(nil)}, domain.ErrHoldExpired, domain.HoldAuthorized, 0, 100000, 30000, 0},
+		{"iptalden sonra tahsil", false, 0, []func(*HoldServiceImpl, int64) error{void, capture(nil)}, domain.ErrInvalidHoldState, domain.HoldVoided, 0, 100000, 0, 0},
+		{"tahsilden sonra iptal", false, 0, []func(*HoldServiceImpl, int64) error{capture(nil), void}, domain.ErrInvalidHoldState, domain.HoldCaptured, 30000, 70000, 0, 0},
+		{"ikinci tahsil", false, 0, []func(*HoldServiceImpl, int64) error{capture(try(100)), capture(try(100))}, domain.ErrInvalidHoldState, domain.HoldCaptured, 100, 99900, 0, 0},
+		{"süre aşımından sonra iptal", false, 0, []func(*HoldServiceImpl, int64) error{expireAt(2 * time.Hour), void}, domain.ErrInvalidHoldState, domain.HoldExpired, 0, 100000, 0, 0},
+		// Tahsil veya iptal edilmiş provizyon süre aşımında tekrar serbest bırakılmaz
+		{"tahsilden sonra süre aşımı", false, 0, []func(*HoldServiceImpl, int64) error{capture(nil), expireAt(2 * time.Hour)}, nil, domain.HoldCaptured, 30000, 70000, 0, 0},
+	}
+	for _, c := range cases {
+		f := newHoldFixture(t)
+		placed := f.place(t, 30000, c.toBob, c.ttl)
+		var err error
+		for i, action := range c.actions {
+			if err = action(f.holds, placed.ID); err != nil && i < len(c.actions)-1 {
+				t.Fatalf("%s: %d. işlem: %v", c.name, i+1, err)
+			}
+		}
+		if (c.err == nil && err != nil) || (c.err != nil && !errors.Is(err, c.err)) {
+			t.Errorf("%s: %v, beklenen %v", c.name, err, c.err)
+		}
+
+		hold, err := f.holds.GetByID(placed.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hold.Status != c.status || hold.CapturedAmount != domain.NewMoney(c.captured, "TRY") {
+			t.Errorf("%s: provizyon %s, tahsil edilen %s; beklenen %s, %d", c.name, hold.Status, hold.CapturedAmount, c.status, c.captured)
+		}
+		alice, held := f.balance(f.alice)
+		bob, _ := f.balance(f.bob)
+		if alice != c.alice || held != c.held || bob != c.bob {
+			t.Errorf("%s: Alice %d (provizyon %d), Bob %d; beklenen %d (%d), %d", c.name, alice, held, bob, c.alice, c.held, c.bob)
+		}
+
+		// Tahsilat, tahsil edilen tutar kadar tamamlanmış bir işlem oluşturur
+		if c.status != domain.HoldCaptured {
+			if hold.TransactionID != nil {
+				t.Errorf("%s: tahsil edilmeyen provizyonun işlemi var", c.name)
+			}
+			continue
+		}
+		if hold.TransactionID == nil {
+			t.Errorf("%s: tahsilat işlemi yok", c.name)
+			continue
+		}
+		tx, err := f.repos.Transactions.FindByID(*hold.TransactionID)
+		wantType := domain.TransactionWithdraw
+		if c.toBob {
+			wantType = domain.TransactionTransfer
+		}
+		if err != nil || tx.Type != wantType || tx.Status != domain.TransactionCompleted || tx.Amount != hold.CapturedAmount {
+			t.Errorf("%s: tahsilat işlemi %+v, %v", c.name, tx, err)
+		}
+	}
+}
+
+func TestPlaceHold(t *testing.T) {
+	cases := []struct {
+		name   string
+		amount domain.Money
+		toBob  bool
+		self   bool // Alıcı hesap provizyonun hesabıyla aynı
+		ttl    time.Duration
+		err    error
+	}{
+		{"varsayılan süre", domain.NewMoney(30000, "TRY"), false, false, 0, nil},
+		{"en uzun süre", domain.NewMoney(30000, "TRY"), true, false, domain.MaxHoldTTL, nil},
+		// Önceki provizyonlar kullanılabilir bakiyeden düşülür: 700,00 TRY zaten ayrılmış
+		{"kullanılabilir bakiyeyi aşan", domain.NewMoney(30001, "TRY"), false, false, 0, domain.ErrInsufficientFunds},
+		{"süre sınırını aşan", domain.NewMoney(100, "TRY"), false, false, domain.MaxHoldTTL + time.Second, errValidation},
+		{"negatif süre", domain.NewMoney(100, "TRY"), false, false, -time.Minute, errValidation},
+		{"aynı hesaba", domain.NewMoney(100, "TRY"), false, true, 0, errValidation},
+		{"sıfır tutar", domain.NewMoney(0, "TRY"), false, false, 0, domain.ErrInvalidAmount},
+		{"hesabın para biriminde olmayan", domain.NewMoney(100, "USD"), false, false, 0, domain.ErrCurrencyMismatch},
+	}
+	for _, c := range cases {
+		f := newHoldFixture(t)
+		f.place(t, 70000, false, 0)
+
+		hold := &domain.Hold{AccountID: f.alice.ID, Amount: c.amount}
+		if c.toBob {
+			hold.ToAccountID = &f.bob.ID
+		}
+		if c.self {
+			hold.ToAccountID = &f.alice.ID
+		}
+		before := time.Now()
+		err := f.holds.Place(hold, c.ttl)
+		if c.err != nil {
+			if !errors.Is(err, c.err) {
+				t.Errorf("%s: %v, beklenen %v", c.name, err, c.err)
+			}
+			if _, held := f.balance(f.alice); held != 70000 {
+				t.Errorf("%s: reddedilen provizyon sonrası ayrılan tutar %d, beklenen 70000", c.name, held)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		ttl := c.ttl
+		if ttl == 0 {
+			ttl = time.Hour
+		}
+		if hold.Status != domain.HoldAuthorized || hold.OwnerID != f.alice.OwnerID ||
+			hold.ExpiresAt.Before(before.Add(ttl)) || hold.ExpiresAt.After(time.Now().Add(ttl)) {
+			t.Errorf("%s: provizyon %+v", c.name, hold)
+		}
+		// Provizyon ledger bakiyesini değiştirmez, sadece kullanılabilir bakiyeyi azaltır
+		if alice, held := f.balance(f.alice); alice != 100000 || held != 70000+c.amount.Amount {
+			t.Errorf("%s: Alice %d, provizyon %d", c.name, alice, held)
+		}
+	}
+}
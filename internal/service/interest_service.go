@@ -0,0 +1,261 @@
+package service
+
+import (
+	"errors"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/ledger"
+	"sort"
+	"time"
+)
+
+// InterestServiceImpl, InterestService arayüzünün implementasyonudur. Her gün için valörlü gün sonu ledger
+// bakiyesinden (BalanceService) faiz tahakkuk ettirir; ay kapandığında tahakkukları tek bir interest
+// işlemiyle bakiyeye ekler ya da (kredili hesap faizi ağır basıyorsa) bakiyeden alır. Tahakkuk ve aktarım tekrar çalıştırılabilir: her hesap ve gün için tek
+// tahakkuk oluşur, aktarılan tahakkuklar interest işlemine bağlanır.
+type InterestServiceImpl struct {
+	rates    []domain.InterestRate         // INTEREST_FILE'dan yüklenen oranlar
+	stored   domain.InterestRateRepository // Yönetim API'siyle eklenen oranlar
+	accounts domain.AccountRepository      // Faiz işleyen türdeki hesapları bulmak için
+	balances domain.BalanceService         // Gün sonu bakiyeleri için
+	interest domain.InterestRepository     // Unit of work dışındaki okumalar için
+	uow      domain.UnitOfWork             // Tahakkukları, interest işlemini ve ledger kaydını birlikte commit etmek için
+	calendar domain.BusinessCalendar       // Interest işlemlerinin muhasebe tarihi için
+	now      func() time.Time
+}
+
+// Yeni bir InterestServiceImpl oluşturur; rates doğrulanmış olmalıdır (bkz. interest.ParseJSON)
+func NewInterestService(rates []domain.InterestRate, stored domain.InterestRateRepository, accounts domain.AccountRepository, balances domain.BalanceService,
+	interest domain.InterestRepository, uow domain.UnitOfWork, calendar domain.BusinessCalendar) *InterestServiceImpl {
+	return &InterestServiceImpl{
+		rates:    append([]domain.InterestRate{}, rates...),
+		stored:   stored,
+		accounts: accounts,
+		balances: balances,
+		interest: interest,
+		uow:      uow,
+		calendar: calendar,
+		now:      time.Now,
+	}
+}
+
+// Faiz işleyen tüm hesaplar için son tahakkuktan (yoksa hesabın açılışından veya ilk oranın geçerli olduğu
+// günden) now gününe kadar, now hariç her gün için faiz tahakkuk ettirir
+func (s *InterestServiceImpl) AccrueDue(now time.Time) (int, error) {
+	today := domain.StartOfDay(now)
+	accrued := 0
+	err := s.eachAccount(func(account *domain.Account, rates []domain.InterestRate) error {
+		start, err := s.accrualStart(account, rates)
+		if err != nil || start == nil {
+			return err
+		}
+		for day := *start; day.Before(today); day = day.AddDate(0, 0, 1) {
+			created, err := s.accrueDay(account, rates, day)
+			if err != nil {
+				return err
+			}
+			if created {
+				accrued++
+			}
+		}
+		return nil
+	})
+	return accrued, err
+}
+
+// Hesabın tahakkuk edilmemiş ilk gününü döndürür; hesap için hiç oran yoksa nil
+func (s *InterestServiceImpl) accrualStart(account *domain.Account, rates []domain.InterestRate) (*time.Time, error) {
+	last, err := s.interest.LastAccrualDate(account.ID)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		next := last.AddDate(0, 0, 1)
+		return &next, nil
+	}
+	var start *time.Time
+	for i := range rates {
+		if rates[i].AccountType == account.Type && rates[i].Currency == account.Currency && (start == nil || rates[i].EffectiveFrom.Before(*start)) {
+			start = &rates[i].EffectiveFrom
+		}
+	}
+	if start == nil {
+		return nil, nil
+	}
+	opened := domain.StartOfDay(account.CreatedAt)
+	if opened.After(*start) {
+		start = &opened
+	}
+	return start, nil
+}
+
+// Hesabın day günü için tahakkukunu oluşturur; gün zaten tahakkuk etmişse false döner.
+// Pozitif bakiyeye mevduat oranı, negatif bakiyeye kredili hesap oranıyla (negatif) faiz işler;
+// o gün oran yoksa veya bakiye sıfırsa sıfır tahakkuk kaydedilir.
+func (s *InterestServiceImpl) accrueDay(account *domain.Account, rates []domain.InterestRate, day time.Time) (bool, error) {
+	balance, err := s.balances.ValueDatedBalance(account.ID, day.AddDate(0, 0, 1), day)
+	if err != nil {
+		return false, err
+	}
+	accrual := &domain.InterestAccrual{AccountID: account.ID, Date: day, Balance: balance, CreatedAt: s.now()}
+	err = s.uow.Do(func(repos domain.Repositories) error {
+		if rate := domain.InterestRateOn(rates, account.Type, account.Currency, day); rate != nil {
+			accrual.Mode = rate.Mode
+			accrual.DayCount = rate.DayCount
+			base := balance.Amount * domain.AccrualScale
+			if rate.Mode == domain.InterestCompound {
+				// Dönem içinde tahakkuk etmiş ama henüz bakiyeye eklenmemiş faiz de faiz getirir
+				unposted, err := repos.Interest.ListUnposted(account.ID, day)
+				if err != nil {
+					return err
+				}
+				base += sumAccrued(unposted)
+			}
+			accrual.AnnualRateBps = rate.RateBps(base)
+			accrual.AccruedMicros = rate.DailyAccrual(base, day)
+		}
+		return repos.Interest.CreateAccrual(accrual)
+	})
+	if errors.Is(err, domain.ErrAccrualExists) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// now ayından önceki günlerin bakiyeye eklenmemiş tahakkuklarını hesap başına tek bir interest işlemiyle
+// bakiyeye ekler; toplam negatifse (kredili hesap faizi) aynı tutar hesaptan alınır. Toplam, alt birime
+// yarım değerler çifte yuvarlanarak çevrilir; bir alt birime ulaşmayan tahakkuklar sonraki aya devreder.
+// Alınacak faiz hesabın kredi limitine sığmıyorsa tahakkuklar, limit yettiğinde alınmak üzere devreder.
+func (s *InterestServiceImpl) PostDue(now time.Time) (int, error) {
+	now = now.UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	posted := 0
+	err := s.eachAccount(func(account *domain.Account, _ []domain.InterestRate) error {
+		var created bool
+		err := s.uow.Do(func(repos domain.Repositories) error {
+			accruals, err := repos.Interest.ListUnposted(account.ID, monthStart)
+			if err != nil || len(accruals) == 0 {
+				return err
+			}
+			amount, err := domain.NewMoney(sumAccrued(accruals), account.Currency).MulRat(1, domain.AccrualScale, domain.RoundHalfEven)
+			if err != nil || amount.IsZero() {
+				return err
+			}
+			tx := &domain.Transaction{
+				Amount:    amount,
+				Type:      domain.TransactionInterest,
+				Status:    domain.TransactionPending,
+				CreatedAt: s.now(),
+				TransactionDetails: domain.TransactionDetails{Metadata: map[string]string{
+					"accrual_from": accruals[0].Date.Format("2006-01-02"),
+					"accrual_to":   accruals[len(accruals)-1].Date.Format("2006-01-02"),
+				}},
+			}
+			entry := ledger.InterestEntry
+			if amount.IsNegative() {
+				tx.Amount = amount.Neg()
+				tx.FromUserID, tx.FromAccountID = &account.OwnerID, &account.ID
+				entry = ledger.OverdraftInterestEntry
+			} else {
+				tx.ToUserID, tx.ToAccountID = &account.OwnerID, &account.ID
+			}
+			// Faiz, son tahakkuk gününün ertesinden itibaren valörlenir; aktarımın ne zaman çalıştığına bağlı değildir
+			tx.BookingDate, tx.ValueDate = s.calendar.Date(tx.CreatedAt), accruals[len(accruals)-1].Date.AddDate(0, 0, 1)
+			if err := complete(repos, tx); err != nil {
+				return err
+			}
+			if err := post(repos, tx, entry(tx.ID, account.ID, tx.Amount)); err != nil {
+				return err
+			}
+			ids := make([]int64, len(accruals))
+			for i, a := range accruals {
+				ids[i] = a.ID
+			}
+			created = true
+			return repos.Interest.MarkPosted(ids, tx.ID)
+		})
+		if errors.Is(err, domain.ErrInsufficientFunds) {
+			return nil
+		}
+		if created && err == nil {
+			posted++
+		}
+		return err
+	})
+	return posted, err
+}
+
+// Faiz oranı tanımlı her hesap türünün açık hesaplarında fn'i çalıştırır
+func (s *InterestServiceImpl) eachAccount(fn func(account *domain.Account, rates []domain.InterestRate) error) error {
+	rates, err := s.Rates()
+	if err != nil {
+		return err
+	}
+	seen := make(map[domain.AccountType]bool)
+	for _, rate := range rates {
+		if seen[rate.AccountType] {
+			continue
+		}
+		seen[rate.AccountType] = true
+		accounts, err := s.accounts.ListOpenByType(rate.AccountType)
+		if err != nil {
+			return err
+		}
+		for _, account := range accounts {
+			if err := fn(account, rates); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func sumAccrued(accruals []*domain.InterestAccrual) int64 {
+	var total int64
+	for _, a := range accruals {
+		total += a.AccruedMicros
+	}
+	return total
+}
+
+// Hesabın [from, to) aralığındaki günlük tahakkuklarını döndürür
+func (s *InterestServiceImpl) ListAccruals(accountID int64, from, to time.Time) ([]*domain.InterestAccrual, error) {
+	return s.interest.ListByAccount(accountID, domain.StartOfDay(from), domain.StartOfDay(to))
+}
+
+// Dosyadaki oranları ve sonradan eklenen oranları hesap türü, para birimi ve geçerlilik gününe göre sıralı döndürür.
+// Aynı hesap türü, para birimi ve gün için eklenmiş oran dosyadakinin yerine geçer.
+func (s *InterestServiceImpl) Rates() ([]domain.InterestRate, error) {
+	stored, err := s.stored.List()
+	if err != nil {
+		return nil, err
+	}
+	rates := append([]domain.InterestRate{}, s.rates...)
+	for _, rate := range stored {
+		replaced := false
+		for i := range rates {
+			if rates[i].AccountType == rate.AccountType && rates[i].Currency == rate.Currency && rates[i].EffectiveFrom.Equal(rate.EffectiveFrom) {
+				rates[i], replaced = rate, true
+				break
+			}
+		}
+		if !replaced {
+			rates = append(rates, rate)
+		}
+	}
+	sort.SliceStable(rates, func(i, j int) bool {
+		return domain.InterestRateLess(rates[i], rates[j])
+	})
+	return rates, nil
+}
+
+// Oranı doğrular ve kaydeder. Tahakkuk etmiş günler yeniden hesaplanmadığı için oran bugünden önce
+// geçerli olamaz; bugün veya ileri bir gün geçerli olan oran dönem ortasında da eklenebilir.
+func (s *InterestServiceImpl) SetRate(rate domain.InterestRate) error {
+	if err := rate.Normalize(); err != nil {
+		return err
+	}
+	if rate.EffectiveFrom.Before(domain.StartOfDay(s.now())) {
+		return domain.NewValidationError("rate_effective_in_past", "faiz oranı geçmiş bir günden itibaren geçerli olamaz")
+	}
+	return s.stored.Save(rate)
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"gofinancialsystem/internal/calendar"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/repository/memtest"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// dailyBalances, gün sonu bakiyelerini ledger yerine verilen fonksiyondan okuyan BalanceService'tir
+type dailyBalances struct {
+	domain.BalanceService
+	amount func(day time.Time) int64
+}
+
+func (b dailyBalances) ValueDatedBalance(_ int64, _, day time.Time) (domain.Money, error) {
+	return domain.NewMoney(b.amount(day), "TRY"), nil
+}
+
+// interestFixture, gün sonu bakiyeleri sabit olan TRY hesapları için faiz servisini tutar
+type interestFixture struct {
+	service      *InterestServiceImpl
+	accounts     domain.AccountRepository
+	balances     domain.BalanceRepository
+	transactions domain.TransactionRepository
+	rates        domain.InterestRateRepository
+}
+
+func newInterestFixture(t *testing.T, balance func(day time.Time) int64, rates ...domain.InterestRate) *interestFixture {
+	t.Helper()
+	if err := domain.NormalizeInterestRates(rates); err != nil {
+		t.Fatal(err)
+	}
+	repos := memtest.New()
+	f := &interestFixture{accounts: repos.Accounts, balances: repos.Balances, transactions: repos.Transactions, rates: repos.InterestRates}
+	f.service = NewInterestService(rates, f.rates, f.accounts, dailyBalances{amount: balance}, repos.Interest, repos.UnitOfWork, calendar.Default())
+	return f
+}
+
+// 2024 başında açılmış bir TRY hesabı oluşturur
+func (f *interestFixture) open(t *testing.T, accountType domain.AccountType) *domain.Account {
+	t.Helper()
+	account := &domain.Account{OwnerID: 1, Type: accountType, Currency: "TRY", Status: domain.AccountActive, CreatedAt: date(2024, 1, 1)}
+	if err := f.accounts.Create(account); err != nil {
+		t.Fatal(err)
+	}
+	return account
+}
+
+// Hesabın [from, to) aralığındaki tahakkuklarını gün sırasıyla döndürür
+func (f *interestFixture) accrued(t *testing.T, account *domain.Account, from, to time.Time) []*domain.InterestAccrual {
+	t.Helper()
+	accruals, err := f.service.ListAccruals(account.ID, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return accruals
+}
+
+func constantBalance(amount int64) func(time.Time) int64 {
+	return func(time.Time) int64 { return amount }
+}
+
+// %36,5 yıllık oranla 1000,00 TL'nin ACT/365 günlük faizi 1,00 TL'dir (milyonda bir alt birim cinsinden)
+const oneLira = 100 * domain.AccrualScale
+
+func TestInterestLeapDayACT365(t *testing.T) {
+	rate := domain.InterestRate{AccountType: domain.AccountChecking, AnnualRateBps: 3650, DayCount: domain.DayCountACT365, EffectiveFrom: date(2024, 2, 1)}
+	f := newInterestFixture(t, constantBalance(100000), rate)
+	account := f.open(t, domain.AccountChecking)
+
+	if n, err := f.service.AccrueDue(date(2024, 3, 1)); err != nil || n != 29 {
+		t.Fatalf("AccrueDue = %d, %v; beklenen 29 gün", n, err)
+	}
+	accruals := f.accrued(t, account, date(2024, 2, 1), date(2024, 3, 1))
+	leapDay := accruals[len(accruals)-1]
+	if !leapDay.Date.Equal(date(2024, 2, 29)) || leapDay.AccruedMicros != oneLira || leapDay.DayCount != domain.DayCountACT365 {
+		t.Fatalf("29 Şubat tahakkuku = %+v, beklenen %d", leapDay, oneLira)
+	}
+	// Tekrar çalıştırma aynı günler için yeni tahakkuk oluşturmaz
+	if n, err := f.service.AccrueDue(date(2024, 3, 1)); err != nil || n != 0 {
+		t.Fatalf("tekrar AccrueDue = %d, %v; beklenen 0", n, err)
+	}
+
+	// ACT/365'te artık yıl 366/365 faiz işler
+	var year int64
+	for day := date(2024, 1, 1); day.Year() == 2024; day = day.AddDate(0, 0, 1) {
+		year += rate.DailyAccrual(100000*domain.AccrualScale, day)
+	}
+	if year != 366*oneLira {
+		t.Fatalf("2024 toplam faizi %d, beklenen %d", year, 366*oneLira)
+	}
+}
+
+func TestInterestRateChangeMidMonth(t *testing.T) {
+	rate := domain.InterestRate{AccountType: domain.AccountChecking, AnnualRateBps: 3650, EffectiveFrom: date(2024, 3, 1)}
+	f := newInterestFixture(t, constantBalance(100000), rate)
+	account := f.open(t, domain.AccountChecking)
+
+	f.service.now = func() time.Time { return date(2024, 3, 10) }
+	change := domain.InterestRate{AccountType: domain.AccountChecking, AnnualRateBps: 7300, EffectiveFrom: date(2024, 3, 15)}
+	if err := f.service.SetRate(change); err != nil {
+		t.Fatal(err)
+	}
+	past := domain.InterestRate{AccountType: domain.AccountChecking, AnnualRateBps: 100, EffectiveFrom: date(2024, 3, 9)}
+	if err := f.service.SetRate(past); err == nil {
+		t.Fatal("geçmiş bir günden geçerli oran reddedilmeli")
+	}
+
+	if _, err := f.service.AccrueDue(date(2024, 4, 1)); err != nil {
+		t.Fatal(err)
+	}
+	accruals := f.accrued(t, account, date(2024, 3, 1), date(2024, 4, 1))
+	if len(accruals) != 31 {
+		t.Fatalf("%d tahakkuk, beklenen 31", len(accruals))
+	}
+	for _, a := range accruals {
+		want, bps := int64(oneLira), int64(3650)
+		if a.Date.Day() >= 15 {
+			want, bps = 2*oneLira, 7300
+		}
+		if a.AccruedMicros != want || a.AnnualRateBps != bps {
+			t.Fatalf("%s: tahakkuk %d (%d bps), beklenen %d (%d bps)", a.Date.Format(time.DateOnly), a.AccruedMicros, a.AnnualRateBps, want, bps)
+		}
+	}
+
+	// Eklenen oran servis yeniden oluşturulduğunda da geçerlidir
+	restarted := NewInterestService([]domain.InterestRate{rate}, f.rates, f.accounts, f.service.balances, f.service.interest, f.service.uow, calendar.Default())
+	rates, err := restarted.Rates()
+	if err != nil || len(rates) != 2 || rates[1].AnnualRateBps != 7300 {
+		t.Fatalf("yeniden başlatma sonrası oranlar = %+v, %v", rates, err)
+	}
+}
+
+func TestInterestCompoundVersusSimple(t *testing.T) {
+	f := newInterestFixture(t, constantBalance(100000),
+		domain.InterestRate{AccountType: domain.AccountChecking, AnnualRateBps: 3650, Mode: domain.InterestSimple, EffectiveFrom: date(2024, 3, 1)},
+		domain.InterestRate{AccountType: domain.AccountSavings, AnnualRateBps: 3650, Mode: domain.InterestCompound, EffectiveFrom: date(2024, 3, 1)})
+	simple := f.open(t, domain.AccountChecking)
+	compound := f.open(t, domain.AccountSavings)
+
+	if _, err := f.service.AccrueDue(date(2024, 3, 4)); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		account *domain.Account
+		want    []int64
+	}{
+		{simple, []int64{oneLira, oneLira, oneLira}},
+		// Bileşik faizde dönem içinde tahakkuk etmiş faiz de faiz getirir: 1000,00; 1001,00; 1002,001 TL
+		{compound, []int64{oneLira, 100_100_000, 100_200_100}},
+	} {
+		accruals := f.accrued(t, c.account, date(2024, 3, 1), date(2024, 3, 4))
+		if len(accruals) != len(c.want) {
+			t.Fatalf("%s: %d tahakkuk, beklenen %d", c.account.Type, len(accruals), len(c.want))
+		}
+		for i, a := range accruals {
+			if a.AccruedMicros != c.want[i] {
+				t.Fatalf("%s %s: tahakkuk %d, beklenen %d", c.account.Type, a.Date.Format(time.DateOnly), a.AccruedMicros, c.want[i])
+			}
+		}
+	}
+}
+
+func TestInterestNegativeBalanceUsesOverdraftRate(t *testing.T) {
+	rate := domain.InterestRate{AccountType: domain.AccountChecking, AnnualRateBps: 3650, OverdraftRateBps: 7300, EffectiveFrom: date(2024, 3, 1)}
+	// 2 Mart'tan itibaren hesap 500,00 TL eksidedir
+	f := newInterestFixture(t, func(day time.Time) int64 {
+		if day.Before(date(2024, 3, 2)) {
+			return 100000
+		}
+		return -50000
+	}, rate)
+	account := f.open(t, domain.AccountChecking)
+
+	if _, err := f.service.AccrueDue(date(2024, 3, 3)); err != nil {
+		t.Fatal(err)
+	}
+	accruals := f.accrued(t, account, date(2024, 3, 1), date(2024, 3, 3))
+	if len(accruals) != 2 {
+		t.Fatalf("%d tahakkuk, beklenen 2", len(accruals))
+	}
+	if a := accruals[0]; a.AccruedMicros != oneLira || a.AnnualRateBps != 3650 {
+		t.Fatalf("pozitif bakiye tahakkuku = %+v", a)
+	}
+	// -500,00 TL'nin %73 yıllık oranla günlük faizi -1,00 TL'dir; hesaptan alınır
+	if a := accruals[1]; a.AccruedMicros != -oneLira || a.AnnualRateBps != 7300 || a.Balance.Amount != -50000 {
+		t.Fatalf("negatif bakiye tahakkuku = %+v, beklenen %d", a, -oneLira)
+	}
+}
+
+// Aktarım geç çalışsa da faiz işlemi son tahakkuk gününün ertesinden valörlenir
+func TestInterestPostingValueDate(t *testing.T) {
+	rate := domain.InterestRate{AccountType: domain.AccountChecking, AnnualRateBps: 3650, EffectiveFrom: date(2024, 2, 1)}
+	f := newInterestFixture(t, constantBalance(100000), rate)
+	account := f.open(t, domain.AccountChecking)
+	if _, err := f.service.AccrueDue(date(2024, 3, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	f.service.now = func() time.Time { return date(2024, 4, 15).Add(10 * time.Hour) }
+	if n, err := f.service.PostDue(date(2024, 4, 15)); err != nil || n != 1 {
+		t.Fatalf("PostDue = %d, %v; beklenen 1", n, err)
+	}
+	txs, err := f.transactions.ListByUser(account.OwnerID)
+	if err != nil || len(txs) != 1 {
+		t.Fatalf("işlemler = %v, %v", txs, err)
+	}
+	tx := txs[0]
+	if tx.Type != domain.TransactionInterest || tx.Amount != domain.NewMoney(2900, "TRY") {
+		t.Fatalf("faiz işlemi = %+v, beklenen 29,00 TL", tx)
+	}
+	if !tx.ValueDate.Equal(date(2024, 3, 1)) || !tx.BookingDate.Equal(date(2024, 4, 15)) {
+		t.Fatalf("valör %s, muhasebe tarihi %s; beklenen 2024-03-01 ve 2024-04-15", tx.ValueDate, tx.BookingDate)
+	}
+	if n, err := f.service.PostDue(date(2024, 4, 15)); err != nil || n != 0 {
+		t.Fatalf("tekrar PostDue = %d, %v; beklenen 0", n, err)
+	}
+	if accruals := f.accrued(t, account, date(2024, 2, 1), date(2024, 3, 1)); accruals[0].PostedTransactionID == nil || *accruals[0].PostedTransactionID != tx.ID {
+		t.Fatal("tahakkuklar faiz işlemine bağlanmalı")
+	}
+}
+
+// Alınacak faiz kredi limitine sığmıyorsa tahakkuklar devreder ve limit yettiğinde alınır
+func TestOverdraftInterestWaitsForCredit(t *testing.T) {
+	rate := domain.InterestRate{AccountType: domain.AccountChecking, OverdraftRateBps: 3650, EffectiveFrom: date(2024, 2, 1)}
+	f := newInterestFixture(t, constantBalance(-35000), rate)
+	account := f.open(t, domain.AccountChecking)
+	// Hesap 350,00 TL eksidedir ve kredi limiti 100,00 TL'ye düşürülmüştür
+	for _, step := range []func() error{
+		func() error { return f.balances.SetOverdraftLimit(account.ID, domain.NewMoney(50000, "TRY")) },
+		func() error { return f.balances.Update(account.ID, domain.NewMoney(-35000, "TRY")) },
+		func() error { return f.balances.SetOverdraftLimit(account.ID, domain.NewMoney(10000, "TRY")) },
+	} {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := f.service.AccrueDue(date(2024, 3, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := f.service.PostDue(date(2024, 3, 2)); err != nil || n != 0 {
+		t.Fatalf("limit yetmezken PostDue = %d, %v; beklenen 0", n, err)
+	}
+	if accruals := f.accrued(t, account, date(2024, 2, 1), date(2024, 3, 1)); accruals[0].PostedTransactionID != nil {
+		t.Fatal("alınamayan faizin tahakkukları sonraki aktarıma devretmeli")
+	}
+
+	if err := f.balances.SetOverdraftLimit(account.ID, domain.NewMoney(50000, "TRY")); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := f.service.PostDue(date(2024, 3, 2)); err != nil || n != 1 {
+		t.Fatalf("limit artırıldıktan sonra PostDue = %d, %v; beklenen 1", n, err)
+	}
+	// 29 gün boyunca günde 0,35 TL: 10,15 TL hesaptan alınır
+	txs, err := f.transactions.ListByUser(account.OwnerID)
+	if err != nil || len(txs) != 1 {
+		t.Fatalf("işlemler = %v, %v", txs, err)
+	}
+	if tx := txs[0]; tx.FromAccountID == nil || *tx.FromAccountID != account.ID || tx.Amount != domain.NewMoney(1015, "TRY") {
+		t.Fatalf("kredili hesap faizi işlemi = %+v, beklenen hesaptan 10,15 TL", tx)
+	}
+	balance, err := f.balances.Get(account.ID)
+	if err != nil || balance.Amount != domain.NewMoney(-36015, "TRY") {
+		t.Fatalf("faiz sonrası bakiye = %+v, %v; beklenen -360,15 TL", balance, err)
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"gofinancialsystem/internal/calendar"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/fees"
+	"gofinancialsystem/internal/ledger"
+	"gofinancialsystem/internal/repository/memtest"
+	"testing"
+)
+
+func TestReverseConvertedTransfer(t *testing.T) {
+	refund := func(amount int64) func(s *TransactionServiceImpl, id int64) (*domain.Transaction, error) {
+		return func(s *TransactionServiceImpl, id int64) (*domain.Transaction, error) {
+			return s.Refund(id, domain.NewMoney(amount, "EUR"))
+		}
+	}
+	reverse := func(s *TransactionServiceImpl, id int64) (*domain.Transaction, error) { return s.Reverse(id) }
+	cases := []struct {
+		name  string
+		moves []func(s *TransactionServiceImpl, id int64) (*domain.Transaction, error)
+		paid  []int64 // Her harekette alıcının TRY hesabından çıkan tutar
+	}{
+		{"geri alma", []func(s *TransactionServiceImpl, id int64) (*domain.Transaction, error){reverse}, []int64{324500}},
+		{"kısmi iadeler", []func(s *TransactionServiceImpl, id int64) (*domain.Transaction, error){refund(3333), refund(6667)}, []int64{108155, 216345}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			repos := memtest.New()
+			s := NewTransactionService(repos.Transactions, repos.Accounts, repos.UnitOfWork, memtest.NoLimits{}, fees.NewEngine(nil, repos.Transactions), calendar.Default())
+			alice := memtest.OpenAccount(t, repos.Accounts, 1, "EUR")
+			bob := memtest.OpenAccount(t, repos.Accounts, 2, "TRY")
+			if err := s.Credit(alice.ID, domain.NewMoney(10000, "EUR"), domain.TransactionDetails{}); err != nil {
+				t.Fatal(err)
+			}
+			conversion := domain.FXConversion{QuoteID: "q-1", Rate: "32.45", MidRate: "32.5", SpreadBps: 15, Target: domain.NewMoney(324500, "TRY")}
+			if err := s.TransferWithConversion(alice.ID, bob.ID, domain.NewMoney(10000, "EUR"), conversion, domain.TransactionDetails{}); err != nil {
+				t.Fatal(err)
+			}
+			txs, err := repos.Transactions.ListByUser(2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			original := txs[0]
+
+			for i, move := range c.moves {
+				tx, err := move(s, original.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tx.Amount != domain.NewMoney(c.paid[i], "TRY") || *tx.FromAccountID != bob.ID || tx.Conversion == nil ||
+					tx.Conversion.QuoteID != "" || tx.Conversion.Rate != "0.030816641" || tx.Conversion.Target.Currency != "EUR" {
+					t.Fatalf("hareket %d: tutar %s, çeviri %+v; beklenen %d TRY", i+1, tx.Amount, tx.Conversion, c.paid[i])
+				}
+			}
+			for _, b := range []struct {
+				account *domain.Account
+				want    domain.Money
+			}{{alice, domain.NewMoney(10000, "EUR")}, {bob, domain.NewMoney(0, "TRY")}} {
+				balance, err := repos.Balances.Get(b.account.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if balance.Amount != b.want {
+					t.Fatalf("hesap #%d bakiyesi %s, beklenen %s", b.account.ID, balance.Amount, b.want)
+				}
+			}
+			// Döviz pozisyonunda kuruş artığı kalmaz
+			for _, currency := range []string{"EUR", "TRY"} {
+				if balance, err := repos.Ledger.AccountBalance(ledger.FX, currency); err != nil || !balance.IsZero() {
+					t.Fatalf("döviz pozisyonu %s: %s, %v; beklenen 0", currency, balance, err)
+				}
+			}
+			if _, err := s.Refund(original.ID, domain.NewMoney(1, "EUR")); !errors.Is(err, domain.ErrConflict) {
+				t.Fatalf("tamamı iade edilmiş işlemin iadesi: %v, beklenen çakışma", err)
+			}
+		})
+	}
+}
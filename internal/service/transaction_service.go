@@ -1,79 +1,299 @@
 package service
 
 import (
-	"errors"
 	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/ledger"
+	"time"
 )
 
 // TransactionServiceImpl, TransactionService arayüzünün gerçek implementasyonudur
 type TransactionServiceImpl struct {
-	transactionRepo domain.TransactionRepository // Transaction veritabanı işlemleri için repository
-	balanceRepo     domain.BalanceRepository     // Bakiye işlemleri için repository
+	transactionRepo domain.TransactionRepository // Transaction okuma işlemleri için repository
+	accountRepo     domain.AccountRepository     // Hesap sahibi, para birimi ve durum kontrolleri için
+	uow             domain.UnitOfWork            // Bakiye ve transaction kaydını birlikte commit etmek için
+	limits          domain.LimitService          // Para hareketinden önce kullanıcının limitlerini denetlemek için
+	fees            domain.FeeService            // Para çıkışlarının ücretini hesaplamak için
+	calendar        domain.BusinessCalendar      // İşlemlerin muhasebe ve valör tarihlerini belirlemek için
 }
 
 // Yeni bir TransactionServiceImpl oluşturur
-func NewTransactionService(txRepo domain.TransactionRepository, balRepo domain.BalanceRepository) *TransactionServiceImpl {
+func NewTransactionService(txRepo domain.TransactionRepository, accountRepo domain.AccountRepository, uow domain.UnitOfWork, limits domain.LimitService, fees domain.FeeService, calendar domain.BusinessCalendar) *TransactionServiceImpl {
 	return &TransactionServiceImpl{
 		transactionRepo: txRepo,
-		balanceRepo:     balRepo,
+		accountRepo:     accountRepo,
+		uow:             uow,
+		limits:          limits,
+		fees:            fees,
+		calendar:        calendar,
 	}
 }
 
-// Kullanıcıya kredi (para ekleme) işlemi
-func (s *TransactionServiceImpl) Credit(userID int64, amount float64) error {
-	tx := &domain.Transaction{
-		ToUserID: &userID,
-		Amount:   amount,
-		Type:     domain.TransactionDeposit,
-		Status:   domain.TransactionPending,
+// Hesaba kredi (para ekleme) işlemi
+func (s *TransactionServiceImpl) Credit(accountID int64, amount domain.Money, details domain.TransactionDetails) error {
+	if err := validateMovement(amount, &details); err != nil {
+		return err
 	}
-	if err := s.balanceRepo.Update(userID, amount); err != nil {
-		tx.Fail()
+	account, err := s.movableAccount(accountID, amount)
+	if err != nil {
 		return err
 	}
-	tx.Complete()
-	return s.transactionRepo.Create(tx)
+	tx := &domain.Transaction{
+		ToUserID:           &account.OwnerID,
+		ToAccountID:        &account.ID,
+		Amount:             amount,
+		Type:               domain.TransactionDeposit,
+		Status:             domain.TransactionPending,
+		CreatedAt:          time.Now(),
+		TransactionDetails: details,
+	}
+	tx.BookingDate, tx.ValueDate = s.calendar.Dates(tx.Type, tx.CreatedAt)
+	return s.uow.Do(func(repos domain.Repositories) error {
+		if err := checkLimits(s.limits, repos, tx); err != nil {
+			return err
+		}
+		if err := complete(repos, tx); err != nil {
+			return err
+		}
+		return post(repos, tx, ledger.CreditEntry(tx.ID, account.ID, amount))
+	})
 }
 
-// Kullanıcıdan debit (para çekme) işlemi
-func (s *TransactionServiceImpl) Debit(userID int64, amount float64) error {
+// Hesaptan debit (para çekme) işlemi; ücret tarifesi varsa ücret ayrı bir bağlı işlem olarak alınır
+func (s *TransactionServiceImpl) Debit(accountID int64, amount domain.Money, details domain.TransactionDetails) error {
+	if err := validateMovement(amount, &details); err != nil {
+		return err
+	}
+	account, err := s.movableAccount(accountID, amount)
+	if err != nil {
+		return err
+	}
 	tx := &domain.Transaction{
-		FromUserID: &userID,
-		Amount:     amount,
-		Type:       domain.TransactionWithdraw,
-		Status:     domain.TransactionPending,
+		FromUserID:         &account.OwnerID,
+		FromAccountID:      &account.ID,
+		Amount:             amount,
+		Type:               domain.TransactionWithdraw,
+		Status:             domain.TransactionPending,
+		CreatedAt:          time.Now(),
+		TransactionDetails: details,
 	}
-	if err := s.balanceRepo.Update(userID, -amount); err != nil {
-		tx.Fail()
+	tx.BookingDate, tx.ValueDate = s.calendar.Dates(tx.Type, tx.CreatedAt)
+	return s.uow.Do(func(repos domain.Repositories) error {
+		if err := checkLimits(s.limits, repos, tx); err != nil {
+			return err
+		}
+		fee, err := quoteFee(s.fees, repos, tx)
+		if err != nil {
+			return err
+		}
+		if err := complete(repos, tx); err != nil {
+			return err
+		}
+		if err := post(repos, tx, ledger.DebitEntry(tx.ID, account.ID, amount)); err != nil {
+			return err
+		}
+		return chargeFee(repos, tx, fee)
+	})
+}
+
+// Hesaplar arası transfer işlemi; iki bacak ve kayıt ya birlikte uygulanır ya hiç uygulanmaz.
+// İki hesap da tutarın para biriminde olmalıdır; farklı para birimleri için TransferWithConversion kullanılır.
+// Ücret, gönderen hesaptan ayrı bir bağlı işlem olarak alınır.
+func (s *TransactionServiceImpl) Transfer(fromAccountID, toAccountID int64, amount domain.Money, details domain.TransactionDetails) error {
+	if err := validateMovement(amount, &details); err != nil {
 		return err
 	}
-	tx.Complete()
-	return s.transactionRepo.Create(tx)
+	from, to, err := s.transferAccounts(fromAccountID, toAccountID, amount, amount)
+	if err != nil {
+		return err
+	}
+	tx := &domain.Transaction{
+		FromUserID:         &from.OwnerID,
+		ToUserID:           &to.OwnerID,
+		FromAccountID:      &from.ID,
+		ToAccountID:        &to.ID,
+		Amount:             amount,
+		Type:               domain.TransactionTransfer,
+		Status:             domain.TransactionPending,
+		CreatedAt:          time.Now(),
+		TransactionDetails: details,
+	}
+	tx.BookingDate, tx.ValueDate = s.calendar.Dates(tx.Type, tx.CreatedAt)
+	return s.uow.Do(func(repos domain.Repositories) error {
+		if err := checkLimits(s.limits, repos, tx); err != nil {
+			return err
+		}
+		fee, err := quoteFee(s.fees, repos, tx)
+		if err != nil {
+			return err
+		}
+		if err := complete(repos, tx); err != nil {
+			return err
+		}
+		// Gönderenden düşen tutar aynı kayıtta alıcıya eklenir
+		if err := post(repos, tx, ledger.TransferEntry(tx.ID, from.ID, to.ID, amount)); err != nil {
+			return err
+		}
+		return chargeFee(repos, tx, fee)
+	})
 }
 
-// Hesaplar arası transfer işlemi
-func (s *TransactionServiceImpl) Transfer(fromUserID, toUserID int64, amount float64) error {
+// Farklı para birimleri arasında, önceden kilitlenmiş kurla transfer; kullanılan kur işlem kaydına yazılır.
+// Döviz ücreti gönderilen tutarın para biriminde, gönderen hesaptan alınır.
+func (s *TransactionServiceImpl) TransferWithConversion(fromAccountID, toAccountID int64, amount domain.Money, conversion domain.FXConversion, details domain.TransactionDetails) error {
+	if err := validateMovement(amount, &details); err != nil {
+		return err
+	}
+	if err := validateAmount(conversion.Target); err != nil {
+		return err
+	}
+	if domain.NormalizeCurrency(amount.Currency) == domain.NormalizeCurrency(conversion.Target.Currency) {
+		return domain.ErrCurrencyMismatch.WithMessage("same_currency_conversion", "döviz çevirisi için farklı para birimleri gerekli")
+	}
+	from, to, err := s.transferAccounts(fromAccountID, toAccountID, amount, conversion.Target)
+	if err != nil {
+		return err
+	}
 	tx := &domain.Transaction{
-		FromUserID: &fromUserID,
-		ToUserID:   &toUserID,
-		Amount:     amount,
-		Type:       domain.TransactionTransfer,
-		Status:     domain.TransactionPending,
-	}
-	// Önce gönderenin bakiyesinden düş
-	if err := s.balanceRepo.Update(fromUserID, -amount); err != nil {
-		tx.Fail()
+		FromUserID:         &from.OwnerID,
+		ToUserID:           &to.OwnerID,
+		FromAccountID:      &from.ID,
+		ToAccountID:        &to.ID,
+		Amount:             amount,
+		Type:               domain.TransactionTransfer,
+		Status:             domain.TransactionPending,
+		CreatedAt:          time.Now(),
+		Conversion:         &conversion,
+		TransactionDetails: details,
+	}
+	tx.BookingDate, tx.ValueDate = s.calendar.Dates(tx.Type, tx.CreatedAt)
+	return s.uow.Do(func(repos domain.Repositories) error {
+		if err := checkLimits(s.limits, repos, tx); err != nil {
+			return err
+		}
+		fee, err := quoteFee(s.fees, repos, tx)
+		if err != nil {
+			return err
+		}
+		if err := complete(repos, tx); err != nil {
+			return err
+		}
+		if err := post(repos, tx, ledger.ExchangeEntry(tx.ID, from.ID, to.ID, amount, conversion.Target)); err != nil {
+			return err
+		}
+		return chargeFee(repos, tx, fee)
+	})
+}
+
+// Para hareketinin tutarını ve kullanıcının eklediği açıklayıcı bilgileri doğrular
+func validateMovement(amount domain.Money, details *domain.TransactionDetails) error {
+	if err := validateAmount(amount); err != nil {
 		return err
 	}
-	// Sonra alıcının bakiyesine ekle
-	if err := s.balanceRepo.Update(toUserID, amount); err != nil {
-		// Rollback: Gönderenin bakiyesini geri yükle
-		s.balanceRepo.Update(fromUserID, amount)
-		tx.Fail()
+	return details.Normalize()
+}
+
+// İşlem tutarının pozitif ve para biriminin desteklenen bir ISO 4217 kodu olduğunu kontrol eder
+func validateAmount(amount domain.Money) error {
+	if !amount.IsPositive() {
+		return domain.ErrInvalidAmount
+	}
+	_, err := domain.ValidateCurrency(amount.Currency)
+	return err
+}
+
+// Hesabı getirir; hesap para hareketine açık ve tutarla aynı para biriminde olmalıdır
+func (s *TransactionServiceImpl) movableAccount(accountID int64, amount domain.Money) (*domain.Account, error) {
+	return movableAccount(s.accountRepo, accountID, amount)
+}
+
+func movableAccount(accounts domain.AccountRepository, accountID int64, amount domain.Money) (*domain.Account, error) {
+	account, err := accounts.FindByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := account.EnsureActive(); err != nil {
+		return nil, err
+	}
+	if err := account.EnsureCurrency(amount); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// Transferin iki hesabını doğrular: gönderen hesap sent, alıcı hesap received para biriminde olmalıdır
+func (s *TransactionServiceImpl) transferAccounts(fromAccountID, toAccountID int64, sent, received domain.Money) (*domain.Account, *domain.Account, error) {
+	if fromAccountID == toAccountID {
+		return nil, nil, domain.NewValidationError("same_account_transfer", "gönderen ve alıcı hesap aynı olamaz")
+	}
+	from, err := s.movableAccount(fromAccountID, sent)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := s.movableAccount(toAccountID, received)
+	if err != nil {
+		return nil, nil, err
+	}
+	return from, to, nil
+}
+
+// İşlemi kullanıcının limitlerine göre denetler; kullanım aynı unit of work içinden okunur.
+// Para yatırmada alıcının, para çıkışlarında gönderenin limitleri uygulanır.
+func checkLimits(limits domain.LimitService, repos domain.Repositories, tx *domain.Transaction) error {
+	movement := domain.LimitMovement{Type: tx.Type, Amount: tx.Amount}
+	if tx.Type == domain.TransactionDeposit {
+		movement.UserID = *tx.ToUserID
+	} else {
+		movement.UserID = *tx.FromUserID
+		movement.CounterpartyID = tx.ToUserID
+	}
+	return limits.Check(repos.Transactions, movement)
+}
+
+// İşlemin ücretini hesaplar; ücretsiz kullanım hakkı aynı unit of work içinden, işlem kaydedilmeden önce sayılır
+func quoteFee(fees domain.FeeService, repos domain.Repositories, tx *domain.Transaction) (*domain.FeeQuote, error) {
+	operation, ok := domain.FeeOperationOf(tx)
+	if !ok {
+		return nil, nil
+	}
+	return fees.Calculate(repos.Transactions, domain.FeeMovement{UserID: *tx.FromUserID, Operation: operation, Amount: tx.Amount})
+}
+
+// Ücret varsa ödeyenin hesabından ücretler hesabına, işleme bağlı bir ücret işlemi kaydeder; ücret işlemi
+// işlemle aynı gün valörlenir.
+// Hesapta işlem tutarı ve ücret için yeterli bakiye yoksa unit of work tamamen geri alınır.
+func chargeFee(repos domain.Repositories, tx *domain.Transaction, quote *domain.FeeQuote) error {
+	if quote == nil || quote.Fee.IsZero() {
+		return nil
+	}
+	fee := &domain.Transaction{
+		FromUserID:    tx.FromUserID,
+		FromAccountID: tx.FromAccountID,
+		Amount:        quote.Fee,
+		Type:          domain.TransactionFee,
+		Status:        domain.TransactionPending,
+		OriginalID:    &tx.ID,
+		CreatedAt:     tx.CreatedAt,
+		BookingDate:   tx.BookingDate,
+		ValueDate:     tx.ValueDate,
+	}
+	if err := complete(repos, fee); err != nil {
 		return err
 	}
-	tx.Complete()
-	return s.transactionRepo.Create(tx)
+	return post(repos, fee, ledger.FeeEntry(fee.ID, *tx.FromAccountID, quote.Fee))
+}
+
+// Transaction'ı tamamlandı olarak işaretler ve unit of work içinde kaydeder (ID yevmiye kaydı için gerekir)
+func complete(repos domain.Repositories, tx *domain.Transaction) error {
+	if err := tx.Complete(); err != nil {
+		return err
+	}
+	return repos.Transactions.Create(tx)
+}
+
+// İşlemin yevmiye kaydını işlemin valör tarihiyle ledger'a işler
+func post(repos domain.Repositories, tx *domain.Transaction, entry *domain.JournalEntry) error {
+	entry.ValueDate = tx.ValueDate
+	return ledger.Post(repos, entry)
 }
 
 // Transaction oluşturur
@@ -81,32 +301,202 @@ func (s *TransactionServiceImpl) Create(tx *domain.Transaction) error {
 	return s.transactionRepo.Create(tx)
 }
 
-// Transaction rollback işlemi (örnek: işlemi geri almak)
-func (s *TransactionServiceImpl) Rollback(txID int64) error {
-	tx, err := s.transactionRepo.FindByID(txID)
+// İşlemin tamamını geri alır: orijinale bağlı bir reversal işlemi oluşturulur ve orijinalin yevmiye
+// kayıtları ters çevrilir. Orijinal işlem tamamlanmış olarak kalır; ikinci kez geri alınamaz.
+func (s *TransactionServiceImpl) Reverse(txID int64) (*domain.Transaction, error) {
+	return s.reverse(txID, domain.TransactionReversal, nil)
+}
+
+// İşlemin amount kadarını iade eder; aynı işlemin iadelerinin toplamı orijinal tutarı aşamaz.
+// Tutar orijinal işlemin (çevirili transferlerde gönderilen) para biriminde olmalıdır.
+func (s *TransactionServiceImpl) Refund(txID int64, amount domain.Money) (*domain.Transaction, error) {
+	if err := validateAmount(amount); err != nil {
+		return nil, err
+	}
+	return s.reverse(txID, domain.TransactionRefund, &amount)
+}
+
+// Geri alma ve iadenin ortak akışı. Orijinal işlem unit of work boyunca kilitlenir; böylece
+// eşzamanlı istekler kalan tutarı aynı anda okuyup çift geri alma veya fazla iade yapamaz.
+func (s *TransactionServiceImpl) reverse(txID int64, txType domain.TransactionType, refund *domain.Money) (*domain.Transaction, error) {
+	var result *domain.Transaction
+	err := s.uow.Do(func(repos domain.Repositories) error {
+		original, err := repos.Transactions.FindByIDForUpdate(txID)
+		if err != nil {
+			return err
+		}
+		if err := original.EnsureReversible(); err != nil {
+			return err
+		}
+		linked, err := repos.Transactions.ListByOriginal(original.ID)
+		if err != nil {
+			return err
+		}
+		remaining, err := reversibleAmount(original, linked)
+		if err != nil {
+			return err
+		}
+
+		amount := remaining
+		if refund == nil {
+			if remaining != original.Amount {
+				return domain.ErrConflict.WithMessage("already_refunded", "kısmen iade edilmiş işlem geri alınamaz; kalan tutar iade edilebilir")
+			}
+		} else {
+			if domain.NormalizeCurrency(refund.Currency) != remaining.Currency {
+				return domain.ErrCurrencyMismatch.WithMessage("refund_currency", "iade tutarı orijinal işlemin para biriminde olmalı: {currency}").
+					WithParams(map[string]interface{}{"currency": remaining.Currency})
+			}
+			if refund.Amount > remaining.Amount {
+				return domain.NewValidationError("refund_exceeds_remaining", "iade tutarı iade edilebilir kalan tutarı aşamaz: {remaining}").
+					WithParams(map[string]interface{}{"remaining": remaining})
+			}
+			amount = *refund
+		}
+		if err := s.ensureNotClosed(original.FromAccountID, original.ToAccountID); err != nil {
+			return err
+		}
+		paid, conversion, err := reversePayment(original, linked, amount, remaining)
+		if err != nil {
+			return err
+		}
+
+		// Para ters yönde hareket eder: orijinalin alıcısı öder, göndereni geri alır
+		tx := &domain.Transaction{
+			FromUserID:    original.ToUserID,
+			ToUserID:      original.FromUserID,
+			FromAccountID: original.ToAccountID,
+			ToAccountID:   original.FromAccountID,
+			Amount:        paid,
+			Type:          txType,
+			Status:        domain.TransactionPending,
+			OriginalID:    &original.ID,
+			CreatedAt:     time.Now(),
+			Conversion:    conversion,
+		}
+		tx.BookingDate, tx.ValueDate = s.calendar.Dates(tx.Type, tx.CreatedAt)
+		if err := complete(repos, tx); err != nil {
+			return err
+		}
+		entries, err := repos.Ledger.ListByTransaction(original.ID)
+		if err != nil {
+			return err
+		}
+		var entry *domain.JournalEntry
+		switch {
+		case txType == domain.TransactionReversal:
+			entry, err = ledger.ReversalEntry(tx.ID, original.ID, entries)
+		case amount == remaining:
+			var refunds []*domain.JournalEntry
+			if refunds, err = refundEntries(repos, linked); err == nil {
+				entry, err = ledger.FinalRefundEntry(tx.ID, original.ID, entries, refunds)
+			}
+		default:
+			entry, err = ledger.RefundEntry(tx.ID, original.ID, entries, amount, original.Amount)
+		}
+		if err != nil {
+			return err
+		}
+		if err := post(repos, tx, entry); err != nil {
+			return err
+		}
+		result = tx
+		return nil
+	})
+	return result, err
+}
+
+// Orijinalin alıcısından çıkacak tutarı döndürür. Çevirili işlemlerde alıcı hedef para biriminde, yevmiye
+// kaydındaki bacakla aynı şekilde sıfıra doğru kesilmiş tutarı öder; kalanın tamamı iade edilirken hedef tutardan
+// önceki iadelerde ödenmeyen kısım ödenir. Gönderene dönen amount ters çeviriye yazılır.
+func reversePayment(original *domain.Transaction, linked []*domain.Transaction, amount, remaining domain.Money) (domain.Money, *domain.FXConversion, error) {
+	if original.Conversion == nil {
+		return amount, nil, nil
+	}
+	paid := original.Conversion.Target
+	if amount == remaining {
+		for _, tx := range linked {
+			if tx.Status != domain.TransactionCompleted || tx.Type != domain.TransactionRefund {
+				continue
+			}
+			var err error
+			if paid, err = paid.Sub(tx.Amount); err != nil {
+				return domain.Money{}, nil, err
+			}
+		}
+	} else {
+		var err error
+		if paid, err = paid.MulRat(amount.Amount, original.Amount.Amount, domain.RoundDown); err != nil {
+			return domain.Money{}, nil, err
+		}
+	}
+	if !paid.IsPositive() {
+		return domain.Money{}, nil, domain.NewValidationError("refund_too_small", "iade tutarı alıcının para biriminde sıfıra yuvarlanıyor")
+	}
+	conversion, err := original.Conversion.Reverse(amount)
 	if err != nil {
-		return errors.New("işlem bulunamadı")
+		return domain.Money{}, nil, err
+	}
+	return paid, &conversion, nil
+}
+
+// Orijinal işlemin tamamlanmış iadelerinin yevmiye kayıtlarını döndürür
+func refundEntries(repos domain.Repositories, linked []*domain.Transaction) ([]*domain.JournalEntry, error) {
+	var result []*domain.JournalEntry
+	for _, tx := range linked {
+		if tx.Status != domain.TransactionCompleted || tx.Type != domain.TransactionRefund {
+			continue
+		}
+		entries, err := repos.Ledger.ListByTransaction(tx.ID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, entries...)
 	}
-	if tx.Status != domain.TransactionCompleted {
-		return errors.New("sadece tamamlanmış işlemler geri alınabilir")
+	return result, nil
+}
+
+// Orijinal işlemin henüz iade edilmemiş tutarını döndürür; işlem geri alınmışsa çakışma hatası döner
+func reversibleAmount(original *domain.Transaction, linked []*domain.Transaction) (domain.Money, error) {
+	remaining := original.Amount
+	for _, tx := range linked {
+		// Ücretler orijinal tutardan düşmez; gerekirse ücret işlemi ayrıca geri alınır
+		if tx.Status != domain.TransactionCompleted || tx.Type == domain.TransactionFee {
+			continue
+		}
+		if tx.Type == domain.TransactionReversal {
+			return domain.Money{}, domain.ErrConflict.WithMessage("already_reversed", "işlem zaten geri alınmış")
+		}
+		// Çevirili işlemin iadesi alıcının para biriminde kaydedilir; gönderene dönen tutar ters çevirinin hedefidir
+		refunded := tx.Amount
+		if tx.Conversion != nil {
+			refunded = tx.Conversion.Target
+		}
+		var err error
+		if remaining, err = remaining.Sub(refunded); err != nil {
+			return domain.Money{}, err
+		}
+	}
+	if !remaining.IsPositive() {
+		return domain.Money{}, domain.ErrConflict.WithMessage("already_refunded_in_full", "işlemin tamamı zaten iade edilmiş")
 	}
-	// Rollback işlemi: para hareketini tersine çevir
-	switch tx.Type {
-	case domain.TransactionDeposit:
-		if tx.ToUserID != nil {
-			s.balanceRepo.Update(*tx.ToUserID, -tx.Amount)
+	return remaining, nil
+}
+
+// Kapatılmış hesaba para geri yazılamaz; dondurulmuş hesaplarda geri alma ve iadeye izin verilir
+func (s *TransactionServiceImpl) ensureNotClosed(accountIDs ...*int64) error {
+	for _, id := range accountIDs {
+		if id == nil {
+			continue
 		}
-	case domain.TransactionWithdraw:
-		if tx.FromUserID != nil {
-			s.balanceRepo.Update(*tx.FromUserID, tx.Amount)
+		account, err := s.accountRepo.FindByID(*id)
+		if err != nil {
+			return err
 		}
-	case domain.TransactionTransfer:
-		if tx.FromUserID != nil && tx.ToUserID != nil {
-			s.balanceRepo.Update(*tx.FromUserID, tx.Amount)
-			s.balanceRepo.Update(*tx.ToUserID, -tx.Amount)
+		if account.Status == domain.AccountClosed {
+			return domain.ErrAccountClosed.WithDetails(map[string]interface{}{"account_id": account.ID})
 		}
 	}
-	tx.Status = domain.TransactionFailed
 	return nil
 }
 
@@ -115,6 +505,14 @@ func (s *TransactionServiceImpl) GetByID(id int64) (*domain.Transaction, error)
 	return s.transactionRepo.FindByID(id)
 }
 
+// Kullanıcının işlem geçmişini filtreler ve sayfalar; filtre önce doğrulanır
+func (s *TransactionServiceImpl) History(filter domain.TransactionFilter) (*domain.TransactionPage, error) {
+	if err := filter.Normalize(); err != nil {
+		return nil, err
+	}
+	return s.transactionRepo.Search(filter)
+}
+
 // Kullanıcının tüm transaction'larını listeler
 func (s *TransactionServiceImpl) ListByUser(userID int64) ([]*domain.Transaction, error) {
 	return s.transactionRepo.ListByUser(userID)
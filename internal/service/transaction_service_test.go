@@ -0,0 +1,213 @@
+package service
+
+import (
+	"errors"
+	"gofinancialsystem/internal/calendar"
+	"gofinancialsystem/internal/db/dbtest"
+	"gofinancialsystem/internal/domain"
+	"gofinancialsystem/internal/fees"
+	"gofinancialsystem/internal/ledger"
+	"gofinancialsystem/internal/repository"
+	"gofinancialsystem/internal/repository/memtest"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(dbtest.Main(m))
+}
+
+var errInjected = errors.New("enjekte edilen hata")
+
+// faultyUnitOfWork, sarmaladığı unit of work'ün repository'lerine hata enjekte eder:
+// her Do içinde n. bakiye güncellemesi veya n. yevmiye kaydı hata verir (0 ise hata verilmez)
+type faultyUnitOfWork struct {
+	domain.UnitOfWork
+	failBalanceUpdate int
+	failLedgerAppend  int
+}
+
+func (u *faultyUnitOfWork) Do(fn func(repos domain.Repositories) error) error {
+	return u.UnitOfWork.Do(func(repos domain.Repositories) error {
+		repos.Balances = &faultyBalances{BalanceRepository: repos.Balances, failAt: u.failBalanceUpdate}
+		repos.Ledger = &faultyLedger{LedgerRepository: repos.Ledger, failAt: u.failLedgerAppend}
+		return fn(repos)
+	})
+}
+
+type faultyBalances struct {
+	domain.BalanceRepository
+	calls, failAt int
+}
+
+func (r *faultyBalances) Update(accountID int64, amount domain.Money) error {
+	if r.calls++; r.calls == r.failAt {
+		return errInjected
+	}
+	return r.BalanceRepository.Update(accountID, amount)
+}
+
+type faultyLedger struct {
+	domain.LedgerRepository
+	calls, failAt int
+}
+
+func (r *faultyLedger) Append(entry *domain.JournalEntry) error {
+	if r.calls++; r.calls == r.failAt {
+		return errInjected
+	}
+	return r.LedgerRepository.Append(entry)
+}
+
+// movementFixture, iki TRY hesabı arasında para hareketi testleri için servis ve repository'leri tutar
+type movementFixture struct {
+	balances     domain.BalanceRepository
+	transactions domain.TransactionRepository
+	ledger       domain.LedgerRepository
+	uow          *faultyUnitOfWork
+	service      *TransactionServiceImpl
+	alice, bob   *domain.Account
+}
+
+// Transfer ve para çekme işlemlerinden 5,00 TL sabit ücret alınır
+func newMovementFixture(t *testing.T, accounts domain.AccountRepository, balances domain.BalanceRepository,
+	transactions domain.TransactionRepository, ledgerRepo domain.LedgerRepository, uow domain.UnitOfWork, aliceID, bobID int64) *movementFixture {
+	t.Helper()
+	flat := domain.NewMoney(500, "TRY")
+	rules := []domain.FeeRule{{Operation: domain.FeeTransfer, Currency: "TRY", Flat: &flat}, {Operation: domain.FeeWithdraw, Currency: "TRY", Flat: &flat}}
+	f := &movementFixture{balances: balances, transactions: transactions, ledger: ledgerRepo, uow: &faultyUnitOfWork{UnitOfWork: uow}}
+	f.service = NewTransactionService(transactions, accounts, f.uow, memtest.NoLimits{}, fees.NewEngine(rules, transactions), calendar.Default())
+	f.alice = memtest.OpenAccount(t, accounts, aliceID, "TRY")
+	f.bob = memtest.OpenAccount(t, accounts, bobID, "TRY")
+	if err := f.service.Credit(f.alice.ID, domain.NewMoney(100000, "TRY"), domain.TransactionDetails{}); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func newMemoryMovementFixture(t *testing.T) *movementFixture {
+	repos := memtest.New()
+	return newMovementFixture(t, repos.Accounts, repos.Balances, repos.Transactions, repos.Ledger, repos.UnitOfWork, 1, 2)
+}
+
+func newPostgresMovementFixture(t *testing.T) *movementFixture {
+	db := dbtest.New(t)
+	users := repository.NewPostgresUserRepository(db)
+	var ids []int64
+	for _, name := range []string{"alice", "bob"} {
+		user := &domain.User{Username: name, Email: name + "@example.com", Password: "hash", Role: "user"}
+		if err := users.Create(user); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, user.ID)
+	}
+	return newMovementFixture(t, repository.NewPostgresAccountRepository(db), repository.NewPostgresBalanceRepository(db),
+		repository.NewPostgresTransactionRepository(db), repository.NewPostgresLedgerRepository(db), repository.NewPostgresUnitOfWork(db), ids[0], ids[1])
+}
+
+// movementState, hareketin etkileyebileceği bakiyelerin, işlemlerin ve yevmiye kayıtlarının özetidir
+type movementState struct {
+	alice, bob, fees, cashOut int64 // Ledger bakiyeleri
+	aliceProjection           int64 // Bakiye tablosundaki tutar
+	bobProjection             int64
+	transactions, entries     int
+}
+
+func (f *movementFixture) state(t *testing.T) movementState {
+	t.Helper()
+	var s movementState
+	for _, b := range []struct {
+		account string
+		target  *int64
+	}{{ledger.CustomerAccount(f.alice.ID), &s.alice}, {ledger.CustomerAccount(f.bob.ID), &s.bob}, {ledger.Fees, &s.fees}, {ledger.CashOut, &s.cashOut}} {
+		balance, err := f.ledger.AccountBalance(b.account, "TRY")
+		if err != nil {
+			t.Fatal(err)
+		}
+		*b.target = balance.Amount
+	}
+	for _, b := range []struct {
+		account *domain.Account
+		target  *int64
+	}{{f.alice, &s.aliceProjection}, {f.bob, &s.bobProjection}} {
+		balance, err := f.balances.Get(b.account.ID)
+		if errors.Is(err, domain.ErrAccountNotFound) {
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		*b.target = balance.Amount.Amount
+	}
+	txs, err := f.transactions.ListByUser(f.alice.OwnerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.transactions = len(txs)
+	entries, err := f.ledger.ListByAccount(ledger.CustomerAccount(f.alice.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.entries = len(entries)
+	return s
+}
+
+// Hareketin herhangi bir bacağı başarısız olursa hiçbir bakiye, işlem veya yevmiye kaydı kalmamalıdır
+func TestMovementFailuresLeaveNoTrace(t *testing.T) {
+	backends := []struct {
+		name    string
+		fixture func(t *testing.T) *movementFixture
+	}{
+		{"memory", newMemoryMovementFixture},
+		{"postgres", newPostgresMovementFixture},
+	}
+	cases := []struct {
+		name                    string
+		failBalance, failLedger int
+		move                    func(f *movementFixture) error
+	}{
+		{"transfer alıcı bacağı", 2, 0, transferMovement},
+		{"transfer yevmiye kaydı", 0, 1, transferMovement},
+		{"transfer ücret kaydı", 0, 2, transferMovement},
+		{"transfer ücret bakiyesi", 3, 0, transferMovement},
+		{"para çekme ücret bakiyesi", 2, 0, debitMovement},
+		{"para çekme ücret kaydı", 0, 2, debitMovement},
+	}
+	for _, backend := range backends {
+		for _, c := range cases {
+			t.Run(backend.name+"/"+c.name, func(t *testing.T) {
+				f := backend.fixture(t)
+				before := f.state(t)
+
+				f.uow.failBalanceUpdate, f.uow.failLedgerAppend = c.failBalance, c.failLedger
+				if err := c.move(f); !errors.Is(err, errInjected) {
+					t.Fatalf("hata = %v, beklenen enjekte edilen hata", err)
+				}
+				if after := f.state(t); after != before {
+					t.Fatalf("başarısız hareket iz bıraktı:\nönce  %+v\nsonra %+v", before, after)
+				}
+
+				// Aynı hareket hatasız tekrarlandığında para yaratılmaz veya kaybolmaz
+				f.uow.failBalanceUpdate, f.uow.failLedgerAppend = 0, 0
+				if err := c.move(f); err != nil {
+					t.Fatal(err)
+				}
+				after := f.state(t)
+				if total, want := after.alice+after.bob+after.fees+after.cashOut, before.alice+before.bob+before.fees+before.cashOut; total != want {
+					t.Fatalf("hareket sonrası toplam %d, beklenen %d", total, want)
+				}
+				if after.fees-before.fees != 500 || after.alice != after.aliceProjection || after.bob != after.bobProjection {
+					t.Fatalf("hareket sonrası durum tutarsız: %+v", after)
+				}
+			})
+		}
+	}
+}
+
+func transferMovement(f *movementFixture) error {
+	return f.service.Transfer(f.alice.ID, f.bob.ID, domain.NewMoney(30000, "TRY"), domain.TransactionDetails{})
+}
+
+func debitMovement(f *movementFixture) error {
+	return f.service.Debit(f.alice.ID, domain.NewMoney(30000, "TRY"), domain.TransactionDetails{})
+}
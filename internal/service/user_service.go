@@ -34,10 +34,10 @@ func (s *UserServiceImpl) Register(user *domain.User) error {
 func (s *UserServiceImpl) Authenticate(username, password string) (*domain.User, error) {
 	user, err := s.userRepo.FindByUsername(username)
 	if err != nil {
-		return nil, errors.New("kullanıcı bulunamadı")
+		return nil, domain.ErrInvalidCredentials
 	}
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return nil, errors.New("şifre hatalı")
+		return nil, domain.ErrInvalidCredentials
 	}
 	return user, nil
 }
@@ -47,6 +47,16 @@ func (s *UserServiceImpl) Authorize(user *domain.User, requiredRole string) bool
 	return user.Role == requiredRole
 }
 
+// Kullanıcının dil tercihini günceller; boş değer tercihi kaldırır
+func (s *UserServiceImpl) SetLocale(userID int64, locale string) error {
+	return s.userRepo.UpdateLocale(userID, locale)
+}
+
+// Kullanıcıyı siler
+func (s *UserServiceImpl) Delete(id int64) error {
+	return s.userRepo.Delete(id)
+}
+
 // Kullanıcıyı ID ile getirir
 func (s *UserServiceImpl) GetByID(id int64) (*domain.User, error) {
 	return s.userRepo.FindByID(id)
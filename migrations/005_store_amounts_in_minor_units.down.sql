@@ -0,0 +1,7 @@
+ALTER TABLE transactions
+    ALTER COLUMN amount TYPE NUMERIC(18,2) USING amount / 100.0,
+    DROP COLUMN currency;
+
+ALTER TABLE balances
+    ALTER COLUMN amount TYPE NUMERIC(18,2) USING amount / 100.0,
+    DROP COLUMN currency;
@@ -0,0 +1,5 @@
+-- Tek taraflı işlemler (para yatırma ve çekme) eski şemada tutulamaz
+DELETE FROM transactions WHERE from_user_id IS NULL OR to_user_id IS NULL;
+ALTER TABLE transactions
+    ALTER COLUMN from_user_id SET NOT NULL,
+    ALTER COLUMN to_user_id SET NOT NULL;
@@ -0,0 +1,4 @@
+-- Para yatırma işlemlerinde gönderen, para çekme işlemlerinde alıcı yoktur
+ALTER TABLE transactions
+    ALTER COLUMN from_user_id DROP NOT NULL,
+    ALTER COLUMN to_user_id DROP NOT NULL;
@@ -0,0 +1,32 @@
+CREATE TABLE journal_entries (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER REFERENCES transactions(id),
+    description VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE postings (
+    id SERIAL PRIMARY KEY,
+    entry_id INTEGER NOT NULL REFERENCES journal_entries(id),
+    account_id VARCHAR(64) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount <> 0),
+    currency CHAR(3) NOT NULL
+);
+
+CREATE INDEX idx_journal_entries_transaction_id ON journal_entries(transaction_id);
+CREATE INDEX idx_postings_account_id ON postings(account_id, currency);
+
+-- Mevcut bakiyeler için suspense karşılıklı açılış kayıtları
+DO $$
+DECLARE
+    b RECORD;
+    new_entry_id INTEGER;
+BEGIN
+    FOR b IN SELECT user_id, amount, currency FROM balances WHERE amount <> 0 LOOP
+        INSERT INTO journal_entries (description) VALUES ('opening balance user ' || b.user_id)
+            RETURNING id INTO new_entry_id;
+        INSERT INTO postings (entry_id, account_id, amount, currency) VALUES
+            (new_entry_id, 'system:suspense', -b.amount, b.currency),
+            (new_entry_id, 'user:' || b.user_id, b.amount, b.currency);
+    END LOOP;
+END $$;
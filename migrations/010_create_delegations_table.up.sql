@@ -0,0 +1,12 @@
+CREATE TABLE delegations (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES users(id),
+    delegate_id INTEGER NOT NULL REFERENCES users(id),
+    scope VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP,
+    CHECK (owner_id <> delegate_id)
+);
+
+CREATE INDEX idx_delegations_owner_delegate ON delegations(owner_id, delegate_id);
+CREATE INDEX idx_delegations_delegate_id ON delegations(delegate_id);
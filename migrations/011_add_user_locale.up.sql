@@ -0,0 +1,2 @@
+-- Kullanıcının tercih ettiği API dili (ör: tr, en); NULL ise Accept-Language başlığı kullanılır
+ALTER TABLE users ADD COLUMN locale VARCHAR(10);
@@ -0,0 +1,7 @@
+-- Kullanıcı başına tek cüzdan kalır; varsa TRY cüzdanı, yoksa alfabetik ilk para birimi tutulur
+DELETE FROM balances b
+WHERE (b.user_id, b.currency) NOT IN (
+    SELECT DISTINCT ON (user_id) user_id, currency FROM balances ORDER BY user_id, currency <> 'TRY', currency
+);
+ALTER TABLE balances DROP CONSTRAINT balances_pkey;
+ALTER TABLE balances ADD PRIMARY KEY (user_id);
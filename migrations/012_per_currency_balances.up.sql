@@ -0,0 +1,3 @@
+-- Bakiyeler artık kullanıcı ve para birimi başına ayrı cüzdanlarda tutulur
+ALTER TABLE balances DROP CONSTRAINT balances_pkey;
+ALTER TABLE balances ADD PRIMARY KEY (user_id, currency);
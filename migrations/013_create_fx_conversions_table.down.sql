@@ -0,0 +1 @@
+DROP TABLE fx_conversions;
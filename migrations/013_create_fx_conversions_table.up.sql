@@ -0,0 +1,11 @@
+-- Döviz çevirili transferlerde kullanılan kurun denetim kaydı (transaction başına en fazla bir satır)
+CREATE TABLE fx_conversions (
+    transaction_id INTEGER PRIMARY KEY REFERENCES transactions(id),
+    quote_id VARCHAR(64) NOT NULL UNIQUE,
+    rate NUMERIC(30,10) NOT NULL,
+    mid_rate NUMERIC(30,10) NOT NULL,
+    spread_bps INTEGER NOT NULL,
+    rate_version TIMESTAMP NOT NULL,
+    target_amount BIGINT NOT NULL,
+    target_currency CHAR(3) NOT NULL
+);
@@ -0,0 +1,19 @@
+ALTER TABLE transactions DROP COLUMN from_account_id, DROP COLUMN to_account_id;
+
+UPDATE postings p SET account_id = 'user:' || a.owner_id
+FROM accounts a
+WHERE p.account_id = 'account:' || a.id;
+
+-- Bakiyeler tekrar kullanıcı ve para birimi başına tutulur; aynı para birimindeki hesaplar birleştirilir
+ALTER TABLE balances DROP CONSTRAINT balances_pkey;
+ALTER TABLE balances ADD COLUMN user_id INTEGER REFERENCES users(id);
+UPDATE balances b SET user_id = a.owner_id FROM accounts a WHERE a.id = b.account_id;
+UPDATE balances b SET amount = s.total
+FROM (SELECT MIN(account_id) AS account_id, SUM(amount) AS total FROM balances GROUP BY user_id, currency) s
+WHERE b.account_id = s.account_id;
+DELETE FROM balances WHERE account_id NOT IN (SELECT MIN(account_id) FROM balances GROUP BY user_id, currency);
+ALTER TABLE balances DROP COLUMN account_id;
+ALTER TABLE balances ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE balances ADD PRIMARY KEY (user_id, currency);
+
+DROP TABLE accounts;
@@ -0,0 +1,49 @@
+-- Kullanıcı başına birden fazla hesap: bakiyeler, ledger kayıtları ve işlemler artık hesaba bağlıdır
+CREATE TABLE accounts (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES users(id),
+    parent_id INTEGER REFERENCES accounts(id),
+    type VARCHAR(16) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'active',
+    nickname VARCHAR(64) NOT NULL DEFAULT '',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Kullanıcının her para biriminde en fazla bir açık varsayılan hesabı olabilir
+CREATE UNIQUE INDEX idx_accounts_default ON accounts(owner_id, currency) WHERE is_default AND status <> 'closed';
+CREATE INDEX idx_accounts_owner_id ON accounts(owner_id);
+
+-- Mevcut her cüzdan için varsayılan vadesiz hesap aç
+INSERT INTO accounts (owner_id, type, currency, is_default)
+SELECT user_id, 'checking', currency, TRUE FROM balances;
+
+-- Bakiyeler hesap başına tutulur
+ALTER TABLE balances ADD COLUMN account_id INTEGER REFERENCES accounts(id);
+UPDATE balances b SET account_id = a.id
+FROM accounts a WHERE a.owner_id = b.user_id AND a.currency = b.currency AND a.is_default;
+ALTER TABLE balances DROP CONSTRAINT balances_pkey;
+ALTER TABLE balances DROP COLUMN user_id;
+ALTER TABLE balances ALTER COLUMN account_id SET NOT NULL;
+ALTER TABLE balances ADD PRIMARY KEY (account_id);
+
+-- Müşteri ledger hesapları user:<id> yerine account:<id> olarak adlandırılır
+UPDATE postings p SET account_id = 'account:' || a.id
+FROM accounts a
+WHERE p.account_id = 'user:' || a.owner_id AND p.currency = a.currency AND a.is_default;
+
+-- İşlemlerin taraf hesapları; alıcı tarafı çevirili transferlerde hedef para birimindeki hesaptır
+ALTER TABLE transactions ADD COLUMN from_account_id INTEGER REFERENCES accounts(id);
+ALTER TABLE transactions ADD COLUMN to_account_id INTEGER REFERENCES accounts(id);
+UPDATE transactions t SET from_account_id = a.id
+FROM accounts a
+WHERE a.owner_id = t.from_user_id AND a.currency = t.currency AND a.is_default;
+UPDATE transactions t SET to_account_id = a.id
+FROM accounts a
+WHERE a.owner_id = t.to_user_id AND a.is_default
+  AND a.currency = COALESCE((SELECT f.target_currency FROM fx_conversions f WHERE f.transaction_id = t.id), t.currency);
+
+CREATE INDEX idx_transactions_from_account_id ON transactions(from_account_id);
+CREATE INDEX idx_transactions_to_account_id ON transactions(to_account_id);
@@ -0,0 +1,3 @@
+DELETE FROM fx_conversions WHERE quote_id IS NULL;
+ALTER TABLE fx_conversions ALTER COLUMN quote_id SET NOT NULL;
+ALTER TABLE transactions DROP COLUMN original_transaction_id;
@@ -0,0 +1,10 @@
+-- Geri alma ve iadeler, ters çevirdikleri orijinal işleme bağlı ayrı işlemler olarak kaydedilir
+ALTER TABLE transactions ADD COLUMN original_transaction_id INTEGER REFERENCES transactions(id);
+CREATE INDEX idx_transactions_original_id ON transactions(original_transaction_id);
+
+-- Bir işlem en fazla bir kez geri alınabilir
+CREATE UNIQUE INDEX idx_transactions_single_reversal ON transactions(original_transaction_id) WHERE type = 'reversal';
+
+-- Çevirili transferlerin geri alma ve iadeleri ters kurla kaydedilir; bunların kur teklifi yoktur.
+-- Kur teklifleri yine en fazla bir işlemde kullanılabilir (UNIQUE, NULL değerleri kapsamaz).
+ALTER TABLE fx_conversions ALTER COLUMN quote_id DROP NOT NULL;
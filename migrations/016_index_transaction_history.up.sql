@@ -0,0 +1,4 @@
+-- İşlem geçmişi kullanıcıya göre filtrelenip (created_at, id) sırasıyla sayfalanır.
+-- Kullanıcı gönderen veya alıcı olabildiğinden her taraf için ayrı bileşik indeks tutulur.
+CREATE INDEX idx_transactions_from_user_created ON transactions(from_user_id, created_at, id);
+CREATE INDEX idx_transactions_to_user_created ON transactions(to_user_id, created_at, id);
@@ -0,0 +1,5 @@
+ALTER TABLE transactions
+    DROP COLUMN description,
+    DROP COLUMN external_reference,
+    DROP COLUMN tags,
+    DROP COLUMN metadata;
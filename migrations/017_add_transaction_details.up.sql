@@ -0,0 +1,10 @@
+-- İşlemlere kullanıcı tarafından eklenen açıklama, dış referans, etiket ve metadata
+ALTER TABLE transactions ADD COLUMN description VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE transactions ADD COLUMN external_reference VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE transactions ADD COLUMN tags TEXT[];
+ALTER TABLE transactions ADD COLUMN metadata JSONB;
+
+-- İşlem geçmişinde dış referans, etiket ve metadata ile arama
+CREATE INDEX idx_transactions_external_reference ON transactions(external_reference) WHERE external_reference <> '';
+CREATE INDEX idx_transactions_tags ON transactions USING GIN (tags);
+CREATE INDEX idx_transactions_metadata ON transactions USING GIN (metadata jsonb_path_ops);
@@ -0,0 +1,26 @@
+-- Provizyonlar: kullanılabilir bakiye = ledger bakiyesi - aktif provizyonların toplamı
+ALTER TABLE balances ADD COLUMN held BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE balances ADD CONSTRAINT balances_held_check CHECK (held >= 0);
+
+CREATE TABLE holds (
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES accounts(id),
+    owner_id INTEGER NOT NULL REFERENCES users(id),
+    to_account_id INTEGER REFERENCES accounts(id),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    captured_amount BIGINT NOT NULL DEFAULT 0,
+    currency CHAR(3) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    transaction_id INTEGER REFERENCES transactions(id),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    external_reference VARCHAR(64) NOT NULL DEFAULT '',
+    tags TEXT[],
+    metadata JSONB
+);
+
+CREATE INDEX idx_holds_account_id ON holds(account_id);
+-- Süre aşımı taraması sadece onaylı provizyonlara bakar
+CREATE INDEX idx_holds_expiring ON holds(expires_at) WHERE status = 'authorized';
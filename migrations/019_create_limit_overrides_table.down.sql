@@ -0,0 +1,3 @@
+DROP INDEX idx_transactions_from_user_type;
+DROP INDEX idx_transactions_to_user_type;
+DROP TABLE limit_overrides;
@@ -0,0 +1,12 @@
+-- Rol veya kullanıcı bazında varsayılan işlem limitlerini değiştiren kurallar
+CREATE TABLE limit_overrides (
+    scope VARCHAR(8) NOT NULL,
+    subject VARCHAR(64) NOT NULL,
+    rules JSONB NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (scope, subject)
+);
+
+-- Kayan pencere limitleri gönderenin veya alıcının belirli türdeki son işlemlerini toplar
+CREATE INDEX idx_transactions_from_user_type ON transactions(from_user_id, type, created_at);
+CREATE INDEX idx_transactions_to_user_type ON transactions(to_user_id, type, created_at);
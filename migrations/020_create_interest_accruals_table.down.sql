@@ -0,0 +1,2 @@
+DROP TABLE interest_rates;
+DROP TABLE interest_accruals;
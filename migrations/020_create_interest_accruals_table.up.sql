@@ -0,0 +1,30 @@
+-- Günlük faiz tahakkukları; ay sonunda toplanıp interest işlemiyle bakiyeye eklenir
+CREATE TABLE interest_accruals (
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES accounts(id),
+    accrual_date DATE NOT NULL,
+    balance BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    annual_rate_bps BIGINT NOT NULL,
+    mode VARCHAR(16) NOT NULL DEFAULT '',
+    day_count VARCHAR(16) NOT NULL DEFAULT '',
+    accrued_micros BIGINT NOT NULL,
+    posted_transaction_id INTEGER REFERENCES transactions(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    -- Tahakkuk her hesap ve gün için tek seferlik (idempotent) yapılır
+    UNIQUE (account_id, accrual_date)
+);
+
+CREATE INDEX idx_interest_accruals_unposted ON interest_accruals(account_id, accrual_date) WHERE posted_transaction_id IS NULL;
+
+-- Yönetim API'siyle eklenen faiz oranları; INTEREST_FILE'daki aynı hesap türü, para birimi ve günkü oranın yerine geçer
+CREATE TABLE interest_rates (
+    account_type VARCHAR(16) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    effective_from DATE NOT NULL,
+    annual_rate_bps BIGINT NOT NULL,
+    mode VARCHAR(16) NOT NULL,
+    day_count VARCHAR(16) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_type, currency, effective_from)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE interest_rates DROP COLUMN overdraft_rate_bps;
+ALTER TABLE balances DROP COLUMN overdraft_limit;
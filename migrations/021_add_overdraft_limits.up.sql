@@ -0,0 +1,6 @@
+-- Hesap başına kredi limiti (overdraft): kullanılabilir bakiye -overdraft_limit'e kadar inebilir
+ALTER TABLE balances ADD COLUMN overdraft_limit BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE balances ADD CONSTRAINT balances_overdraft_limit_check CHECK (overdraft_limit >= 0);
+
+-- Yönetim API'siyle eklenen oranlar için kredili hesap (eksi bakiye) faizi
+ALTER TABLE interest_rates ADD COLUMN overdraft_rate_bps BIGINT NOT NULL DEFAULT 0;
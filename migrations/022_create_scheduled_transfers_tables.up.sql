@@ -0,0 +1,42 @@
+-- Zamanlanmış ve düzenli transfer talimatları
+CREATE TABLE scheduled_transfers (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES users(id),
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    from_account_id INTEGER NOT NULL REFERENCES accounts(id),
+    to_account_id INTEGER NOT NULL REFERENCES accounts(id),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    frequency VARCHAR(16) NOT NULL,
+    day_of_month SMALLINT NOT NULL DEFAULT 0,
+    next_run_at TIMESTAMP NOT NULL,
+    due_at TIMESTAMP NOT NULL,
+    end_date TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    external_reference VARCHAR(64) NOT NULL DEFAULT '',
+    tags TEXT[],
+    metadata JSONB
+);
+
+CREATE INDEX idx_scheduled_transfers_owner_id ON scheduled_transfers(owner_id);
+-- Zamanlayıcı sadece etkin talimatlara bakar
+CREATE INDEX idx_scheduled_transfers_due ON scheduled_transfers(due_at) WHERE status = 'active';
+
+-- Talimatların çalıştırma geçmişi: her deneme bir satırdır
+CREATE TABLE scheduled_transfer_runs (
+    id SERIAL PRIMARY KEY,
+    schedule_id INTEGER NOT NULL REFERENCES scheduled_transfers(id),
+    scheduled_for TIMESTAMP NOT NULL,
+    attempt INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    transaction_id INTEGER REFERENCES transactions(id),
+    error_code VARCHAR(64) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    ran_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_scheduled_transfer_runs_schedule_id ON scheduled_transfer_runs(schedule_id);
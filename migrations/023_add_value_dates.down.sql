@@ -0,0 +1,2 @@
+ALTER TABLE journal_entries DROP COLUMN value_date;
+ALTER TABLE transactions DROP COLUMN booking_date, DROP COLUMN value_date;
@@ -0,0 +1,11 @@
+-- İşlemlerin muhasebe ve valör tarihleri; mevcut işlemler oluşturuldukları gün valörlenir
+ALTER TABLE transactions ADD COLUMN booking_date DATE, ADD COLUMN value_date DATE;
+UPDATE transactions SET booking_date = created_at::DATE, value_date = created_at::DATE;
+ALTER TABLE transactions ALTER COLUMN booking_date SET NOT NULL, ALTER COLUMN value_date SET NOT NULL;
+
+-- Yevmiye kayıtları işlemlerinin valör tarihini taşır; valörlü bakiye ve faiz tahakkuku bu tarihe göre hesaplanır
+ALTER TABLE journal_entries ADD COLUMN value_date DATE;
+UPDATE journal_entries SET value_date = created_at::DATE;
+ALTER TABLE journal_entries ALTER COLUMN value_date SET NOT NULL;
+
+CREATE INDEX idx_journal_entries_value_date ON journal_entries(value_date);